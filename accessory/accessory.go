@@ -1,6 +1,9 @@
 package accessory
 
 import (
+	"sync"
+
+	"github.com/brutella/hc/characteristic"
 	"github.com/brutella/hc/service"
 )
 
@@ -9,6 +12,7 @@ type Info struct {
 	SerialNumber string
 	Manufacturer string
 	Model        string
+	Firmware     string
 }
 
 // Accessory implements the model.Accessory interface and contains the data
@@ -24,8 +28,31 @@ type Accessory struct {
 	Type AccessoryType                 `json:"-"`
 	Info *service.AccessoryInformation `json:"-"`
 
+	// CategoryOverride is the category which is published instead of the category of the
+	// accessory type, or TypeBridge for bridges, e.g. TypeAlarmSystem for a bridge of alarm sensors.
+	// Controllers show the category during pairing and Siri uses it as a hint for voice targeting.
+	// Only the category of the first accessory of a transport is published.
+	CategoryOverride AccessoryType `json:"-"`
+
+	// FailReadsWhenUnreachable is true when reading characteristics of an unreachable
+	// accessory fails with a communication failure instead of returning the last value.
+	FailReadsWhenUnreachable bool `json:"-"`
+
 	idCount    int64
 	onIdentify func()
+
+	unreachable    bool
+	reachableMutex sync.Mutex
+
+	// infoFuncs are called after UpdateInfo changed the accessory information
+	infoFuncs    []func()
+	updatingInfo bool
+	infoMutex    sync.Mutex
+
+	// metadata of the application, which is not sent to controllers
+	metadata      map[string]string
+	metadataFuncs []func(map[string]string)
+	metadataMutex sync.Mutex
 }
 
 // New returns an accessory which implements model.Accessory.
@@ -56,6 +83,12 @@ func New(info Info, typ AccessoryType) *Accessory {
 		svc.Model.SetValue("undefined")
 	}
 
+	if firmware := info.Firmware; len(firmware) > 0 {
+		svc.FirmwareRevision.SetValue(firmware)
+	} else {
+		svc.FirmwareRevision.SetValue("undefined")
+	}
+
 	acc := &Accessory{
 		idCount: 1,
 		Info:    svc,
@@ -87,6 +120,45 @@ func (a *Accessory) GetServices() []*service.Service {
 	return result
 }
 
+// SetLocalizedName sets the name of the accessory for a locale, e.g. "de".
+// Controllers which prefer the locale show the localized name.
+func (a *Accessory) SetLocalizedName(locale, name string) {
+	a.Info.Name.SetLocalizedValue(locale, name)
+}
+
+// ServiceByType returns the first service of type typ (e.g. service.TypeLightbulb), or nil if there is none.
+func (a *Accessory) ServiceByType(typ string) *service.Service {
+	for _, s := range a.Services {
+		if s.Type == typ {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// CharacteristicByIID returns the characteristic with the instance id iid, or nil if there is none.
+func (a *Accessory) CharacteristicByIID(iid int64) *characteristic.Characteristic {
+	for _, s := range a.Services {
+		for _, c := range s.Characteristics {
+			if c.GetID() == iid {
+				return c
+			}
+		}
+	}
+
+	return nil
+}
+
+// ForEachCharacteristic calls fn for every characteristic of every service.
+func (a *Accessory) ForEachCharacteristic(fn func(s *service.Service, c *characteristic.Characteristic)) {
+	for _, s := range a.Services {
+		for _, c := range s.Characteristics {
+			fn(s, c)
+		}
+	}
+}
+
 func (a *Accessory) OnIdentify(fn func()) {
 	a.onIdentify = fn
 }
@@ -97,6 +169,91 @@ func (a *Accessory) Identify() {
 	}
 }
 
+// UpdateInfo updates the model, serial number and firmware revision of the accessory.
+// Empty fields of info are ignored.
+//
+// The accessory information can be changed while the accessory is published (e.g. after
+// a firmware update). The transport makes sure that the change is propagated to clients.
+// The functions of OnInfoUpdate are called once, when one of the values changed.
+func (a *Accessory) UpdateInfo(info Info) {
+	a.infoMutex.Lock()
+	a.updatingInfo = true
+	a.infoMutex.Unlock()
+
+	changed := false
+	if model := info.Model; len(model) > 0 && model != a.Info.Model.GetValue() {
+		a.Info.Model.SetValue(model)
+		changed = true
+	}
+
+	if serial := info.SerialNumber; len(serial) > 0 && serial != a.Info.SerialNumber.GetValue() {
+		a.Info.SerialNumber.SetValue(serial)
+		changed = true
+	}
+
+	if firmware := info.Firmware; len(firmware) > 0 && firmware != a.Info.FirmwareRevision.GetValue() {
+		a.Info.FirmwareRevision.SetValue(firmware)
+		changed = true
+	}
+
+	a.infoMutex.Lock()
+	a.updatingInfo = false
+	funcs := a.infoFuncs
+	a.infoMutex.Unlock()
+
+	if changed == true {
+		for _, fn := range funcs {
+			fn()
+		}
+	}
+}
+
+// OnInfoUpdate calls fn after UpdateInfo changed the accessory information.
+func (a *Accessory) OnInfoUpdate(fn func()) {
+	a.infoMutex.Lock()
+	defer a.infoMutex.Unlock()
+
+	a.infoFuncs = append(a.infoFuncs, fn)
+}
+
+// IsUpdatingInfo returns true while UpdateInfo sets the values of the accessory information.
+func (a *Accessory) IsUpdatingInfo() bool {
+	a.infoMutex.Lock()
+	defer a.infoMutex.Unlock()
+
+	return a.updatingInfo
+}
+
+// SetReachable sets whether the accessory is reachable, e.g. when the device of a bridged
+// accessory goes offline. The Status Fault and Status Active characteristics of the
+// services are updated accordingly.
+func (a *Accessory) SetReachable(reachable bool) {
+	a.reachableMutex.Lock()
+	a.unreachable = reachable == false
+	a.reachableMutex.Unlock()
+
+	a.ForEachCharacteristic(func(s *service.Service, c *characteristic.Characteristic) {
+		switch c.Type {
+		case characteristic.TypeStatusFault:
+			if reachable == true {
+				c.UpdateValue(characteristic.StatusFaultNoFault)
+			} else {
+				c.UpdateValue(characteristic.StatusFaultGeneralFault)
+			}
+		case characteristic.TypeStatusActive:
+			c.UpdateValue(reachable)
+		}
+	})
+}
+
+// IsReachable returns true when the accessory is reachable.
+func (a *Accessory) IsReachable() bool {
+	a.reachableMutex.Lock()
+	defer a.reachableMutex.Unlock()
+
+	return a.unreachable == false
+}
+
 // Adds a service to the accessory and updates the ids of the service and the corresponding characteristics
 func (a *Accessory) AddService(s *service.Service) {
 	s.SetID(a.idCount)
@@ -110,6 +267,15 @@ func (a *Accessory) AddService(s *service.Service) {
 	a.Services = append(a.Services, s)
 }
 
+// AddCharacteristic adds c to the service s of the accessory and updates the id of c.
+// Use this method instead of service.Service.AddCharacteristic after s was added to the accessory.
+func (a *Accessory) AddCharacteristic(s *service.Service, c *characteristic.Characteristic) {
+	c.SetID(a.idCount)
+	a.idCount++
+
+	s.AddCharacteristic(c)
+}
+
 // Equal returns true when receiver has the same services and id as the argument.
 func (a *Accessory) Equal(other interface{}) bool {
 	if accessory, ok := other.(*Accessory); ok == true {
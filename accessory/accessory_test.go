@@ -0,0 +1,147 @@
+package accessory
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
+)
+
+func TestUpdateInfo(t *testing.T) {
+	a := New(Info{Name: "Accessory", Model: "A", Firmware: "1.0.0"}, TypeOther)
+
+	a.UpdateInfo(Info{Firmware: "1.0.1"})
+
+	if is, want := a.Info.FirmwareRevision.GetValue(), "1.0.1"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := a.Info.Model.GetValue(), "A"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestOnInfoUpdate(t *testing.T) {
+	a := New(Info{Name: "Accessory", Model: "A", Firmware: "1.0.0"}, TypeOther)
+
+	var updates int
+	a.OnInfoUpdate(func() {
+		updates++
+	})
+
+	a.UpdateInfo(Info{Model: "B", Firmware: "1.0.1"})
+	if is, want := updates, 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	a.UpdateInfo(Info{Model: "B"})
+	if is, want := updates, 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestLinkedServices(t *testing.T) {
+	a := New(Info{Name: "Accessory"}, TypeOther)
+	tv := service.New("D8")
+	input := service.New("D9")
+	tv.AddLinkedService(input)
+
+	a.AddService(tv)
+	a.AddService(input)
+
+	b, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var v struct {
+		Services []struct {
+			ID     int64   `json:"iid"`
+			Linked []int64 `json:"linked"`
+		} `json:"services"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	linked := v.Services[1].Linked
+	if is, want := len(linked), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := linked[0], input.ID; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := v.Services[2].Linked, []int64(nil); reflect.DeepEqual(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestPrimaryAndHiddenServices(t *testing.T) {
+	a := New(Info{Name: "Accessory"}, TypeOther)
+	primary := service.New("43")
+	primary.SetPrimary(true)
+	hidden := service.New("CC")
+	hidden.SetHidden(true)
+
+	a.AddService(primary)
+	a.AddService(hidden)
+
+	b, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var v struct {
+		Services []map[string]interface{} `json:"services"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := v.Services[0]["primary"]; ok == true {
+		t.Fatal("unexpected primary flag of accessory information service")
+	}
+
+	if is, want := v.Services[1]["primary"], true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := v.Services[2]["hidden"], true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestSetReachable(t *testing.T) {
+	a := New(Info{Name: "Sensor"}, TypeSensor)
+	s := service.New(service.TypeContactSensor)
+	fault := characteristic.NewStatusFault()
+	active := characteristic.NewStatusActive()
+	active.SetValue(true)
+	s.AddCharacteristic(fault.Characteristic)
+	s.AddCharacteristic(active.Characteristic)
+	a.AddService(s)
+
+	a.SetReachable(false)
+
+	if is, want := a.IsReachable(), false; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := fault.GetValue(), characteristic.StatusFaultGeneralFault; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := active.GetValue(), false; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	a.SetReachable(true)
+
+	if is, want := fault.GetValue(), characteristic.StatusFaultNoFault; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := active.GetValue(), true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,27 @@
+package accessory
+
+import (
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
+)
+
+// Blinds are a window covering, whose position is 0 (closed) to 100 (open).
+type Blinds struct {
+	*Accessory
+
+	WindowCovering *service.WindowCovering
+}
+
+// NewBlinds returns blinds which are open and stopped.
+func NewBlinds(info Info) *Blinds {
+	acc := Blinds{}
+	acc.Accessory = New(info, TypeWindowCovering)
+	acc.WindowCovering = service.NewWindowCovering()
+	acc.WindowCovering.CurrentPosition.SetValue(100)
+	acc.WindowCovering.TargetPosition.SetValue(100)
+	acc.WindowCovering.PositionState.SetValue(characteristic.PositionStateStopped)
+
+	acc.AddService(acc.WindowCovering.Service)
+
+	return &acc
+}
@@ -0,0 +1,107 @@
+package accessory
+
+import (
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
+
+	"fmt"
+	"strings"
+)
+
+// Builder builds an accessory from services and validates the characteristics of
+// every service (see service.Validate).
+//
+//	acc, err := accessory.Build("Living Room AC").
+//		Type(accessory.TypeThermostat).
+//		Service(service.TypeThermostat).Primary().
+//		With(current.Characteristic, target.Characteristic, ...).
+//		Accessory()
+type Builder struct {
+	info     Info
+	typ      AccessoryType
+	services []*service.Service
+	errs     []string
+}
+
+// Build returns a builder for an accessory with a name.
+func Build(name string) *Builder {
+	return &Builder{
+		info: Info{Name: name},
+		typ:  TypeOther,
+	}
+}
+
+// Info sets the accessory info. The name is kept when info has no name.
+func (b *Builder) Info(info Info) *Builder {
+	if len(info.Name) == 0 {
+		info.Name = b.info.Name
+	}
+	b.info = info
+
+	return b
+}
+
+// Type sets the accessory type.
+func (b *Builder) Type(typ AccessoryType) *Builder {
+	b.typ = typ
+	return b
+}
+
+// Service adds a service of type typ (e.g. service.TypeThermostat). The characteristics
+// of the service are added with With.
+func (b *Builder) Service(typ string) *Builder {
+	b.services = append(b.services, service.New(typ))
+	return b
+}
+
+// Primary marks the last added service as primary service.
+func (b *Builder) Primary() *Builder {
+	if s := b.current("Primary"); s != nil {
+		s.SetPrimary(true)
+	}
+
+	return b
+}
+
+// With adds characteristics to the last added service.
+func (b *Builder) With(cs ...*characteristic.Characteristic) *Builder {
+	if s := b.current("With"); s != nil {
+		for _, c := range cs {
+			s.AddCharacteristic(c)
+		}
+	}
+
+	return b
+}
+
+// Accessory returns the accessory, or an error which describes every invalid service.
+func (b *Builder) Accessory() (*Accessory, error) {
+	errs := b.errs
+	for _, s := range b.services {
+		if err := service.Validate(s); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s: %s", b.info.Name, strings.Join(errs, "; "))
+	}
+
+	a := New(b.info, b.typ)
+	for _, s := range b.services {
+		a.AddService(s)
+	}
+
+	return a, nil
+}
+
+// current returns the last added service, or nil when the method fn was called before
+// a service was added.
+func (b *Builder) current(fn string) *service.Service {
+	if len(b.services) == 0 {
+		b.errs = append(b.errs, fn+" called before Service")
+		return nil
+	}
+
+	return b.services[len(b.services)-1]
+}
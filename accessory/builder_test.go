@@ -0,0 +1,66 @@
+package accessory
+
+import (
+	"testing"
+
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
+)
+
+func TestBuilder(t *testing.T) {
+	on := characteristic.NewOn()
+	brightness := characteristic.NewBrightness()
+
+	a, err := Build("Lamp").
+		Info(Info{SerialNumber: "001"}).
+		Type(TypeLightbulb).
+		Service(service.TypeLightbulb).Primary().
+		With(on.Characteristic, brightness.Characteristic).
+		Accessory()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := a.Info.Name.GetValue(), "Lamp"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := a.Type, TypeLightbulb; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	s := a.ServiceByType(service.TypeLightbulb)
+	if s == nil {
+		t.Fatal("missing service")
+	}
+
+	if is, want := s.Primary, true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := len(s.Characteristics), 2; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestBuilderValidation(t *testing.T) {
+	_, err := Build("Living Room AC").
+		Service(service.TypeThermostat).
+		With(characteristic.NewCurrentTemperature().Characteristic).
+		Accessory()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	want := "Living Room AC: Thermostat service: missing required characteristics Current Heating Cooling State, Target Heating Cooling State, Target Temperature, Temperature Display Units"
+	if is := err.Error(); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestBuilderWithoutService(t *testing.T) {
+	_, err := Build("Lamp").With(characteristic.NewOn().Characteristic).Accessory()
+	if is, want := err.Error(), "Lamp: With called before Service"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,64 @@
+package accessory
+
+import (
+	"net"
+
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
+)
+
+// ConfiguredNameFunc is called when a controller changed the configured name of a service,
+// e.g. because the user renamed the accessory in the Home app. The service is the accessory
+// information service, when the accessory was renamed.
+type ConfiguredNameFunc func(s *service.Service, name string)
+
+// SyncConfiguredNames adds a Configured Name characteristic to the accessory information
+// service and to every service, which allows one, and returns the added characteristics.
+// Siri uses the configured names to address accessories and services by voice.
+//
+// The configured names are kept in sync with the names of the accessory and services,
+// e.g. when the application renames them. When a controller changes a configured name,
+// fn is called, which allows the application to update its naming. fn can be nil.
+//
+// The method must be called before the accessory is added to a transport.
+func (a *Accessory) SyncConfiguredNames(fn ConfiguredNameFunc) []*characteristic.ConfiguredName {
+	var result []*characteristic.ConfiguredName
+	for _, s := range a.Services {
+		spec, ok := service.SpecForType(s.Type)
+		if ok == false || spec.IsOptional(characteristic.TypeConfiguredName) == false {
+			continue
+		}
+
+		if s.CharacteristicByType(characteristic.TypeConfiguredName) != nil {
+			continue
+		}
+
+		name := s.CharacteristicByType(characteristic.TypeName)
+		if name == nil {
+			continue
+		}
+
+		configured := characteristic.NewConfiguredName()
+		if v, ok := name.Value.(string); ok == true {
+			configured.SetValue(v)
+		}
+		a.AddCharacteristic(s, configured.Characteristic)
+
+		name.OnValueUpdate(func(c *characteristic.Characteristic, new, old interface{}) {
+			if v, ok := new.(string); ok == true && v != configured.GetValue() {
+				configured.SetValue(v)
+			}
+		})
+
+		svc := s
+		configured.OnValueUpdateFromConn(func(conn net.Conn, c *characteristic.Characteristic, new, old interface{}) {
+			if v, ok := new.(string); ok == true && fn != nil {
+				fn(svc, v)
+			}
+		})
+
+		result = append(result, configured)
+	}
+
+	return result
+}
@@ -0,0 +1,72 @@
+package accessory
+
+import (
+	"net"
+	"testing"
+
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
+)
+
+func TestSyncConfiguredNames(t *testing.T) {
+	acc := NewPowerStrip(Info{Name: "Power Strip"}, 2)
+
+	var renamed *service.Service
+	var name string
+	names := acc.SyncConfiguredNames(func(s *service.Service, n string) {
+		renamed, name = s, n
+	})
+
+	// Accessory information and outlets
+	if is, want := len(names), 3; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if err := acc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	configured := acc.Outlets[1].CharacteristicByType(characteristic.TypeConfiguredName)
+	if configured == nil || configured.GetID() == 0 {
+		t.Fatal("missing configured name")
+	}
+
+	if is, want := configured.Value, "Outlet 2"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	c := NewContainer()
+	c.AddAccessory(acc.Accessory)
+	if err := c.RenameService(acc.GetID(), acc.Outlets[1].ID, "Fan"); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := configured.Value, "Fan"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	conn, _ := net.Pipe()
+	if err := configured.UpdateValueFromConnection("Heater", conn); err != nil {
+		t.Fatal(err)
+	}
+
+	if renamed != acc.Outlets[1].Service {
+		t.Fatal("service not renamed")
+	}
+
+	if is, want := name, "Heater"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestCategoryOverride(t *testing.T) {
+	c := NewContainer()
+	bridge := New(Info{Name: "Bridge"}, TypeBridge)
+	bridge.CategoryOverride = TypeAlarmSystem
+	c.AddAccessory(bridge)
+	c.AddAccessory(New(Info{Name: "Sensor"}, TypeSensor))
+
+	if is, want := c.AccessoryType(), TypeAlarmSystem; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -18,4 +18,7 @@ const (
 	TypeWindow             AccessoryType = 13
 	TypeWindowCovering     AccessoryType = 14
 	TypeProgrammableSwitch AccessoryType = 15
+	TypeSprinkler          AccessoryType = 28
+	TypeFaucet             AccessoryType = 29
+	TypeShowerSystem       AccessoryType = 30
 )
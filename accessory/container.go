@@ -1,5 +1,10 @@
 package accessory
 
+import (
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
+)
+
 // Container manages a list of accessories.
 type Container struct {
 	Accessories []*Accessory `json:"accessories"`
@@ -17,10 +22,26 @@ func NewContainer() *Container {
 
 // AddAccessory adds an accessory to the container.
 // This method ensures that the accessory ids are valid and unique withing the container.
-func (m *Container) AddAccessory(a *Accessory) {
-	a.SetID(m.idCount)
-	m.idCount++
+// An accessory keeps its id when it is set and not used by another accessory, which
+// allows stable ids across restarts (see plugin.Host).
+//
+// Names which are already used get an index, e.g. "Lamp 2", because controllers
+// (e.g. Siri) can't tell accessories or services with the same name apart.
+//
+// The accessory is validated (see Accessory.Validate). An invalid accessory is added
+// anyway and the validation error is returned.
+func (m *Container) AddAccessory(a *Accessory) error {
+	if id := a.GetID(); id <= 0 || m.AccessoryByAID(id) != nil {
+		for m.AccessoryByAID(m.idCount) != nil {
+			m.idCount++
+		}
+		a.SetID(m.idCount)
+		m.idCount++
+	}
+	m.uniqueNames(a)
 	m.Accessories = append(m.Accessories, a)
+
+	return a.Validate()
 }
 
 // RemoveAccessory removes an accessory from the container.
@@ -32,6 +53,36 @@ func (m *Container) RemoveAccessory(a *Accessory) {
 	}
 }
 
+// AccessoryByAID returns the accessory with the accessory id aid, or nil if there is none.
+func (m *Container) AccessoryByAID(aid int64) *Accessory {
+	for _, a := range m.Accessories {
+		if a.GetID() == aid {
+			return a
+		}
+	}
+
+	return nil
+}
+
+// CharacteristicByIDs returns the characteristic with the instance id iid of the accessory
+// with the accessory id aid, or nil if there is none.
+func (m *Container) CharacteristicByIDs(aid, iid int64) *characteristic.Characteristic {
+	if a := m.AccessoryByAID(aid); a != nil {
+		return a.CharacteristicByIID(iid)
+	}
+
+	return nil
+}
+
+// ForEachCharacteristic calls fn for every characteristic of every accessory.
+func (m *Container) ForEachCharacteristic(fn func(a *Accessory, s *service.Service, c *characteristic.Characteristic)) {
+	for _, a := range m.Accessories {
+		a.ForEachCharacteristic(func(s *service.Service, c *characteristic.Characteristic) {
+			fn(a, s, c)
+		})
+	}
+}
+
 // Equal returns true when receiver has the same accessories as the argument.
 func (m *Container) Equal(other interface{}) bool {
 	if container, ok := other.(*Container); ok == true {
@@ -51,8 +102,13 @@ func (m *Container) Equal(other interface{}) bool {
 }
 
 // AccessoryType returns the accessory type identifier for the accessories inside the container.
+// The category override of the first accessory takes precedence.
 func (m *Container) AccessoryType() AccessoryType {
 	if as := m.Accessories; len(as) > 0 {
+		if as[0].CategoryOverride > 0 {
+			return as[0].CategoryOverride
+		}
+
 		if len(as) > 1 {
 			return TypeBridge
 		}
@@ -2,6 +2,9 @@ package accessory
 
 import (
 	"testing"
+
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
 )
 
 var info = Info{
@@ -40,6 +43,34 @@ func TestContainer(t *testing.T) {
 	}
 }
 
+func TestContainerKeepsAccessoryIDs(t *testing.T) {
+	acc1 := New(info, TypeOther)
+	acc1.SetID(2)
+	acc2 := New(info, TypeOther)
+	acc3 := New(info, TypeOther)
+	acc4 := New(info, TypeOther)
+	acc4.SetID(2)
+
+	c := NewContainer()
+	c.AddAccessory(acc1)
+	c.AddAccessory(acc2)
+	c.AddAccessory(acc3)
+	c.AddAccessory(acc4)
+
+	if is, want := acc1.GetID(), int64(2); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := acc2.GetID(), int64(1); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := acc3.GetID(), int64(3); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := acc4.GetID(), int64(4); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
 func TestAccessoryCount(t *testing.T) {
 	accessory := New(info, TypeOther)
 	c := NewContainer()
@@ -73,3 +104,42 @@ func TestAccessoryType(t *testing.T) {
 		t.Fatalf("is=%v want=%v", is, want)
 	}
 }
+
+func TestContainerQueries(t *testing.T) {
+	sw := NewSwitch(Info{Name: "Switch"})
+	bulb := NewLightbulb(Info{Name: "Bulb"})
+
+	c := NewContainer()
+	c.AddAccessory(sw.Accessory)
+	c.AddAccessory(bulb.Accessory)
+
+	if is, want := c.AccessoryByAID(bulb.GetID()), bulb.Accessory; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if a := c.AccessoryByAID(100); a != nil {
+		t.Fatal(a)
+	}
+
+	if is, want := c.CharacteristicByIDs(sw.GetID(), sw.Switch.On.GetID()), sw.Switch.On.Characteristic; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	s := bulb.ServiceByType(service.TypeLightbulb)
+	if is, want := s, bulb.Lightbulb.Service; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := s.CharacteristicByType(characteristic.TypeOn), bulb.Lightbulb.On.Characteristic; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	count := 0
+	c.ForEachCharacteristic(func(a *Accessory, s *service.Service, c *characteristic.Characteristic) {
+		count++
+	})
+
+	if is, want := count, len(sw.Info.Characteristics)+len(sw.Switch.Characteristics)+len(bulb.Info.Characteristics)+len(bulb.Lightbulb.Characteristics); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
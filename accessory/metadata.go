@@ -0,0 +1,87 @@
+package accessory
+
+// SetMetadata sets the value of the application metadata with key, e.g. the id of the
+// device in a backend or a room hint. The metadata is not sent to controllers, but stored
+// by the transport and restored on the next start.
+func (a *Accessory) SetMetadata(key, value string) {
+	a.metadataMutex.Lock()
+	if a.metadata == nil {
+		a.metadata = map[string]string{}
+	}
+	a.metadata[key] = value
+	a.metadataMutex.Unlock()
+
+	a.metadataChanged()
+}
+
+// DeleteMetadata removes the metadata with key.
+func (a *Accessory) DeleteMetadata(key string) {
+	a.metadataMutex.Lock()
+	_, ok := a.metadata[key]
+	delete(a.metadata, key)
+	a.metadataMutex.Unlock()
+
+	if ok == true {
+		a.metadataChanged()
+	}
+}
+
+// Metadata returns the value of the metadata with key.
+// The second return value is false, when there is no metadata with key.
+func (a *Accessory) Metadata(key string) (string, bool) {
+	a.metadataMutex.Lock()
+	defer a.metadataMutex.Unlock()
+
+	v, ok := a.metadata[key]
+	return v, ok
+}
+
+// AllMetadata returns a copy of the metadata.
+func (a *Accessory) AllMetadata() map[string]string {
+	a.metadataMutex.Lock()
+	defer a.metadataMutex.Unlock()
+
+	m := map[string]string{}
+	for k, v := range a.metadata {
+		m[k] = v
+	}
+
+	return m
+}
+
+// RestoreMetadata sets the metadata from m, which was restored from persistent storage.
+// Existing metadata takes precedence over the values of m with the same key.
+// The functions of OnMetadataChange are not called.
+func (a *Accessory) RestoreMetadata(m map[string]string) {
+	a.metadataMutex.Lock()
+	defer a.metadataMutex.Unlock()
+
+	if a.metadata == nil {
+		a.metadata = map[string]string{}
+	}
+	for k, v := range m {
+		if _, ok := a.metadata[k]; ok == false {
+			a.metadata[k] = v
+		}
+	}
+}
+
+// OnMetadataChange calls fn with the metadata of the accessory, when the metadata changed.
+func (a *Accessory) OnMetadataChange(fn func(metadata map[string]string)) {
+	a.metadataMutex.Lock()
+	defer a.metadataMutex.Unlock()
+
+	a.metadataFuncs = append(a.metadataFuncs, fn)
+}
+
+func (a *Accessory) metadataChanged() {
+	m := a.AllMetadata()
+
+	a.metadataMutex.Lock()
+	funcs := a.metadataFuncs
+	a.metadataMutex.Unlock()
+
+	for _, fn := range funcs {
+		fn(m)
+	}
+}
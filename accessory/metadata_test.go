@@ -0,0 +1,37 @@
+package accessory
+
+import (
+	"testing"
+)
+
+func TestMetadata(t *testing.T) {
+	acc := New(Info{Name: "Lamp"}, TypeLightbulb)
+	acc.SetMetadata("room", "Kitchen")
+
+	var changed map[string]string
+	acc.OnMetadataChange(func(m map[string]string) {
+		changed = m
+	})
+
+	acc.RestoreMetadata(map[string]string{"room": "Bedroom", "backend-id": "abc"})
+	if changed != nil {
+		t.Fatal(changed)
+	}
+
+	if v, _ := acc.Metadata("room"); v != "Kitchen" {
+		t.Fatal(v)
+	}
+
+	if v, _ := acc.Metadata("backend-id"); v != "abc" {
+		t.Fatal(v)
+	}
+
+	acc.DeleteMetadata("room")
+	if is, want := len(changed), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if _, ok := acc.Metadata("room"); ok == true {
+		t.Fatal("metadata not deleted")
+	}
+}
@@ -0,0 +1,33 @@
+package accessory
+
+import (
+	"github.com/brutella/hc/service"
+)
+
+// MultiSensor detects motion and measures the temperature, humidity and ambient light level.
+type MultiSensor struct {
+	*Accessory
+
+	MotionSensor   *service.MotionSensor
+	TempSensor     *service.TemperatureSensor
+	HumiditySensor *service.HumiditySensor
+	LightSensor    *service.LightSensor
+}
+
+// NewMultiSensor returns a multi sensor whose motion sensor is the primary service.
+func NewMultiSensor(info Info) *MultiSensor {
+	acc := MultiSensor{}
+	acc.Accessory = New(info, TypeSensor)
+	acc.MotionSensor = service.NewMotionSensor()
+	acc.MotionSensor.SetPrimary(true)
+	acc.TempSensor = service.NewTemperatureSensor()
+	acc.HumiditySensor = service.NewHumiditySensor()
+	acc.LightSensor = service.NewLightSensor()
+
+	acc.AddService(acc.MotionSensor.Service)
+	acc.AddService(acc.TempSensor.Service)
+	acc.AddService(acc.HumiditySensor.Service)
+	acc.AddService(acc.LightSensor.Service)
+
+	return &acc
+}
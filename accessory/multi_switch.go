@@ -0,0 +1,41 @@
+package accessory
+
+import (
+	"fmt"
+
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
+)
+
+// MultiSwitch is a multi-gang switch, whose switches are numbered in the Home app.
+type MultiSwitch struct {
+	*Accessory
+
+	Switches []*service.Switch
+	Label    *service.ServiceLabel
+}
+
+// NewMultiSwitch returns a switch with n switches named "Switch 1", "Switch 2", ...
+// The first switch is the primary service.
+func NewMultiSwitch(info Info, n int) *MultiSwitch {
+	acc := MultiSwitch{}
+	acc.Accessory = New(info, TypeSwitch)
+
+	var services []*service.Service
+	for i := 0; i < n; i++ {
+		sw := service.NewSwitch()
+		sw.SetPrimary(i == 0)
+
+		name := characteristic.NewName()
+		name.SetValue(fmt.Sprintf("Switch %d", i+1))
+		sw.AddCharacteristic(name.Characteristic)
+
+		acc.Switches = append(acc.Switches, sw)
+		acc.AddService(sw.Service)
+		services = append(services, sw.Service)
+	}
+
+	acc.Label = LabelServices(acc.Accessory, characteristic.ServiceLabelNamespaceArabicNumerals, services...)
+
+	return &acc
+}
@@ -0,0 +1,130 @@
+package accessory
+
+import (
+	"fmt"
+
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
+)
+
+// Name returns the name of the accessory.
+func (a *Accessory) Name() string {
+	return a.Info.Name.GetValue()
+}
+
+// ServiceName returns the name of s, or an empty string if s has no Name characteristic.
+func ServiceName(s *service.Service) string {
+	if c := s.CharacteristicByType(characteristic.TypeName); c != nil {
+		if name, ok := c.Value.(string); ok == true {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// uniqueNames appends an index to the names of a, which are already used within the container.
+// The name of the accessory must be unique within the container and the names of its
+// services must be unique within the accessory, e.g. "Lamp", "Lamp 2", "Lamp 3".
+// The localized names get the same index.
+func (m *Container) uniqueNames(a *Accessory) {
+	used := map[string]bool{}
+	for _, other := range m.Accessories {
+		used[other.Name()] = true
+	}
+	uniqueName(a.Info.Name.Characteristic, used)
+
+	used = map[string]bool{}
+	for _, s := range a.Services {
+		if s == a.Info.Service {
+			continue
+		}
+
+		if c := s.CharacteristicByType(characteristic.TypeName); c != nil {
+			uniqueName(c, used)
+		}
+	}
+}
+
+// uniqueName appends the first index to the name of c, which makes it not used,
+// and adds the name to the used names.
+func uniqueName(c *characteristic.Characteristic, used map[string]bool) {
+	name, ok := c.Value.(string)
+	if ok == false {
+		return
+	}
+
+	if used[name] == true {
+		i := 2
+		for used[fmt.Sprintf("%s %d", name, i)] == true {
+			i++
+		}
+
+		for locale, v := range c.LocalizedValues() {
+			if s, ok := v.(string); ok == true {
+				c.SetLocalizedValue(locale, fmt.Sprintf("%s %d", s, i))
+			}
+		}
+
+		name = fmt.Sprintf("%s %d", name, i)
+		c.UpdateValue(name)
+	}
+
+	used[name] = true
+}
+
+// RenameAccessory sets the name of the accessory with the accessory id aid.
+// An error is returned when the name is used by another accessory of the container.
+func (m *Container) RenameAccessory(aid int64, name string) error {
+	a := m.AccessoryByAID(aid)
+	if a == nil {
+		return fmt.Errorf("Unknown accessory %d", aid)
+	}
+
+	if len(name) == 0 {
+		return fmt.Errorf("Invalid empty name of accessory %d", aid)
+	}
+
+	for _, other := range m.Accessories {
+		if other != a && other.Name() == name {
+			return fmt.Errorf("Name %s is used by accessory %d", name, other.GetID())
+		}
+	}
+
+	a.Info.Name.SetValue(name)
+
+	return nil
+}
+
+// RenameService sets the name of the service with the instance id iid of the accessory
+// with the accessory id aid. An error is returned when the service has no Name characteristic
+// or when the name is used by another service of the accessory.
+func (m *Container) RenameService(aid, iid int64, name string) error {
+	a := m.AccessoryByAID(aid)
+	if a == nil {
+		return fmt.Errorf("Unknown accessory %d", aid)
+	}
+
+	if len(name) == 0 {
+		return fmt.Errorf("Invalid empty name of service %d.%d", aid, iid)
+	}
+
+	var c *characteristic.Characteristic
+	for _, s := range a.Services {
+		if s.ID == iid {
+			if c = s.CharacteristicByType(characteristic.TypeName); c == nil {
+				return fmt.Errorf("Service %d.%d has no name", aid, iid)
+			}
+		} else if s != a.Info.Service && ServiceName(s) == name {
+			return fmt.Errorf("Name %s is used by service %d.%d", name, aid, s.ID)
+		}
+	}
+
+	if c == nil {
+		return fmt.Errorf("Unknown service %d.%d", aid, iid)
+	}
+
+	c.UpdateValue(name)
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package accessory
+
+import (
+	"testing"
+
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
+)
+
+func TestUniqueAccessoryNames(t *testing.T) {
+	c := NewContainer()
+	acc1 := New(Info{Name: "Lamp"}, TypeLightbulb)
+	acc2 := New(Info{Name: "Lamp"}, TypeLightbulb)
+	acc2.SetLocalizedName("de", "Lampe")
+	acc3 := New(Info{Name: "Lamp"}, TypeLightbulb)
+	c.AddAccessory(acc1)
+	c.AddAccessory(acc2)
+	c.AddAccessory(acc3)
+
+	if is, want := acc1.Name(), "Lamp"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := acc2.Name(), "Lamp 2"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := acc2.Info.Name.LocalizedValue([]string{"de"}), "Lampe 2"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := acc3.Name(), "Lamp 3"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestUniqueServiceNames(t *testing.T) {
+	acc := New(Info{Name: "Switch"}, TypeSwitch)
+	for i := 0; i < 2; i++ {
+		sw := service.NewSwitch()
+		name := characteristic.NewName()
+		name.SetValue("Switch")
+		sw.AddCharacteristic(name.Characteristic)
+		acc.AddService(sw.Service)
+	}
+
+	c := NewContainer()
+	c.AddAccessory(acc)
+
+	if is, want := ServiceName(acc.Services[1]), "Switch"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := ServiceName(acc.Services[2]), "Switch 2"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if err := c.RenameService(acc.GetID(), acc.Services[2].ID, "Switch"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if err := c.RenameService(acc.GetID(), acc.Services[2].ID, "Light"); err != nil {
+		t.Fatal(err)
+	}
+	if is, want := ServiceName(acc.Services[2]), "Light"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestRenameAccessory(t *testing.T) {
+	c := NewContainer()
+	acc1 := New(Info{Name: "Lamp"}, TypeLightbulb)
+	acc2 := New(Info{Name: "Light"}, TypeLightbulb)
+	c.AddAccessory(acc1)
+	c.AddAccessory(acc2)
+
+	if err := c.RenameAccessory(acc2.GetID(), "Lamp"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if err := c.RenameAccessory(acc2.GetID(), "Desk Lamp"); err != nil {
+		t.Fatal(err)
+	}
+	if is, want := acc2.Name(), "Desk Lamp"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
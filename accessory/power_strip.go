@@ -0,0 +1,42 @@
+package accessory
+
+import (
+	"fmt"
+
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
+)
+
+// PowerStrip has multiple outlets, which are switched independently.
+type PowerStrip struct {
+	*Accessory
+
+	Outlets []*service.Outlet
+	Label   *service.ServiceLabel
+}
+
+// NewPowerStrip returns a power strip with n outlets named "Outlet 1", "Outlet 2", ...
+// The first outlet is the primary service. The outlets are numbered in the Home app.
+func NewPowerStrip(info Info, n int) *PowerStrip {
+	acc := PowerStrip{}
+	acc.Accessory = New(info, TypeOutlet)
+
+	var services []*service.Service
+	for i := 0; i < n; i++ {
+		outlet := service.NewOutlet()
+		outlet.OutletInUse.SetValue(true)
+		outlet.SetPrimary(i == 0)
+
+		name := characteristic.NewName()
+		name.SetValue(fmt.Sprintf("Outlet %d", i+1))
+		outlet.AddCharacteristic(name.Characteristic)
+
+		acc.Outlets = append(acc.Outlets, outlet)
+		acc.AddService(outlet.Service)
+		services = append(services, outlet.Service)
+	}
+
+	acc.Label = LabelServices(acc.Accessory, characteristic.ServiceLabelNamespaceArabicNumerals, services...)
+
+	return &acc
+}
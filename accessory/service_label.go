@@ -0,0 +1,23 @@
+package accessory
+
+import (
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
+)
+
+// LabelServices adds a service label service with the namespace (e.g. characteristic.ServiceLabelNamespaceArabicNumerals)
+// to the accessory a, and numbers services starting at 1 with a service label index.
+// The Home app then shows the buttons or outlets of multi-gang devices in the right order.
+func LabelServices(a *Accessory, namespace int, services ...*service.Service) *service.ServiceLabel {
+	label := service.NewServiceLabel()
+	label.ServiceLabelNamespace.SetValue(namespace)
+	a.AddService(label.Service)
+
+	for i, s := range services {
+		index := characteristic.NewServiceLabelIndex()
+		index.SetValue(i + 1)
+		a.AddCharacteristic(s, index.Characteristic)
+	}
+
+	return label
+}
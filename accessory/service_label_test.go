@@ -0,0 +1,39 @@
+package accessory
+
+import (
+	"testing"
+
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
+)
+
+func TestMultiSwitchLabels(t *testing.T) {
+	acc := NewMultiSwitch(Info{Name: "Wall Switch", SerialNumber: "001", Manufacturer: "hc", Model: "3-Gang"}, 3)
+
+	if err := acc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if acc.ServiceByType(service.TypeServiceLabel) != acc.Label.Service {
+		t.Fatal("missing service label")
+	}
+
+	if is, want := acc.Label.ServiceLabelNamespace.GetValue(), characteristic.ServiceLabelNamespaceArabicNumerals; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	for i, sw := range acc.Switches {
+		c := sw.CharacteristicByType(characteristic.TypeServiceLabelIndex)
+		if c == nil {
+			t.Fatal("missing service label index")
+		}
+
+		if c.GetID() == 0 || acc.CharacteristicByIID(c.GetID()) != c {
+			t.Fatalf("invalid id %d", c.GetID())
+		}
+
+		if is, want := c.Value, i+1; is != want {
+			t.Fatalf("is=%v want=%v", is, want)
+		}
+	}
+}
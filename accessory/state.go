@@ -0,0 +1,169 @@
+package accessory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// MarshalState returns the json encoded accessory database, as it is returned
+// to controllers, with the accessory ids, instance ids, types, values and
+// permissions of all services and characteristics.
+// The result is indented to be included in bug reports.
+func (m *Container) MarshalState() ([]byte, error) {
+	return json.MarshalIndent(m, "", "  ")
+}
+
+// StateChange is a difference between two accessory states.
+type StateChange struct {
+	AID   int64
+	IID   int64
+	Field string
+
+	// Old and New are the json encoded values of the field.
+	// Old is empty when the field was added and New is empty when the field was removed.
+	Old string
+	New string
+}
+
+func (c StateChange) String() string {
+	switch {
+	case len(c.Old) == 0:
+		return fmt.Sprintf("+ %d.%d %s: %s", c.AID, c.IID, c.Field, c.New)
+	case len(c.New) == 0:
+		return fmt.Sprintf("- %d.%d %s: %s", c.AID, c.IID, c.Field, c.Old)
+	default:
+		return fmt.Sprintf("~ %d.%d %s: %s -> %s", c.AID, c.IID, c.Field, c.Old, c.New)
+	}
+}
+
+// DiffState returns the differences between the accessory states old and new,
+// which are returned by MarshalState.
+// Services and characteristics are matched by their accessory and instance id.
+func DiffState(old, new []byte) ([]StateChange, error) {
+	o, err := flattenState(old)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := flattenState(new)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []StateChange
+	for k, v := range o {
+		if nv, ok := n[k]; ok == false {
+			changes = append(changes, StateChange{AID: k.aid, IID: k.iid, Field: k.field, Old: v})
+		} else if nv != v {
+			changes = append(changes, StateChange{AID: k.aid, IID: k.iid, Field: k.field, Old: v, New: nv})
+		}
+	}
+
+	for k, v := range n {
+		if _, ok := o[k]; ok == false {
+			changes = append(changes, StateChange{AID: k.aid, IID: k.iid, Field: k.field, New: v})
+		}
+	}
+
+	sort.Stable(byID(changes))
+
+	return changes, nil
+}
+
+type stateKey struct {
+	aid   int64
+	iid   int64
+	field string
+}
+
+type stateObject map[string]json.RawMessage
+
+// flattenState returns the json encoded fields of all services and characteristics in b.
+func flattenState(b []byte) (map[stateKey]string, error) {
+	var c struct {
+		Accessories []struct {
+			AID      int64         `json:"aid"`
+			Services []stateObject `json:"services"`
+		} `json:"accessories"`
+	}
+
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+
+	fields := map[stateKey]string{}
+	for _, a := range c.Accessories {
+		for _, s := range a.Services {
+			if err := s.add(fields, a.AID, "characteristics"); err != nil {
+				return nil, err
+			}
+
+			var chars []stateObject
+			if raw, ok := s["characteristics"]; ok == true {
+				if err := json.Unmarshal(raw, &chars); err != nil {
+					return nil, err
+				}
+			}
+
+			for _, ch := range chars {
+				if err := ch.add(fields, a.AID); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return fields, nil
+}
+
+// add adds the fields of the service or characteristic o to fields, except the skipped ones.
+func (o stateObject) add(fields map[stateKey]string, aid int64, skip ...string) error {
+	var iid int64
+	if raw, ok := o["iid"]; ok == true {
+		if err := json.Unmarshal(raw, &iid); err != nil {
+			return err
+		}
+	}
+
+	for name, raw := range o {
+		if name == "iid" || contains(skip, name) == true {
+			continue
+		}
+
+		var b bytes.Buffer
+		if err := json.Compact(&b, raw); err != nil {
+			return err
+		}
+		fields[stateKey{aid, iid, name}] = b.String()
+	}
+
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+type byID []StateChange
+
+func (b byID) Len() int      { return len(b) }
+func (b byID) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byID) Less(i, j int) bool {
+	if b[i].AID != b[j].AID {
+		return b[i].AID < b[j].AID
+	}
+
+	if b[i].IID != b[j].IID {
+		return b[i].IID < b[j].IID
+	}
+
+	return b[i].Field < b[j].Field
+}
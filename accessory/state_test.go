@@ -0,0 +1,68 @@
+package accessory
+
+import (
+	"testing"
+)
+
+func TestDiffState(t *testing.T) {
+	c := NewContainer()
+	sw := NewSwitch(info)
+	c.AddAccessory(sw.Accessory)
+
+	old, err := c.MarshalState()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sw.Switch.On.SetValue(true)
+
+	new, err := c.MarshalState()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := DiffState(old, new)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := len(changes), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	change := changes[0]
+	if is, want := change.IID, sw.Switch.On.ID; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := change.String(), "~ 1.9 value: false -> true"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestDiffStateAddedAccessory(t *testing.T) {
+	c := NewContainer()
+	c.AddAccessory(NewSwitch(info).Accessory)
+
+	old, _ := c.MarshalState()
+	c.AddAccessory(NewSwitch(info).Accessory)
+	new, _ := c.MarshalState()
+
+	changes, err := DiffState(old, new)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(changes) == 0 {
+		t.Fatal("expected changes")
+	}
+
+	for _, change := range changes {
+		if is, want := change.AID, int64(2); is != want {
+			t.Fatalf("is=%v want=%v", is, want)
+		}
+		if len(change.Old) != 0 {
+			t.Fatal(change)
+		}
+	}
+}
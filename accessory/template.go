@@ -0,0 +1,71 @@
+package accessory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/brutella/hc/service"
+)
+
+// TemplateOptions configure an accessory which is created from a template.
+type TemplateOptions struct {
+	// Outlets is the number of outlets of a power strip. When 0, 4 outlets are used.
+	Outlets int
+
+	// Battery is true when a battery service is added, e.g. to a battery powered sensor.
+	Battery bool
+}
+
+// A Template returns a ready-made accessory.
+type Template func(info Info, opts TemplateOptions) *Accessory
+
+// Templates are the ready-made accessories by name, which are instantiated with NewFromTemplate.
+// Applications can add their own templates.
+var Templates = map[string]Template{
+	"weather-station": func(info Info, opts TemplateOptions) *Accessory {
+		return NewWeatherStation(info).Accessory
+	},
+	"power-strip": func(info Info, opts TemplateOptions) *Accessory {
+		n := opts.Outlets
+		if n <= 0 {
+			n = defaultOutlets
+		}
+		return NewPowerStrip(info, n).Accessory
+	},
+	"multi-sensor": func(info Info, opts TemplateOptions) *Accessory {
+		return NewMultiSensor(info).Accessory
+	},
+	"blinds": func(info Info, opts TemplateOptions) *Accessory {
+		return NewBlinds(info).Accessory
+	},
+}
+
+// defaultOutlets is the number of outlets of a power strip
+const defaultOutlets = 4
+
+// NewFromTemplate returns an accessory from the template with the name, e.g. "power-strip".
+// An error is returned when there is no template with the name.
+func NewFromTemplate(name string, info Info, opts TemplateOptions) (*Accessory, error) {
+	t, ok := Templates[name]
+	if ok == false {
+		return nil, fmt.Errorf("Unknown template %s", name)
+	}
+
+	a := t(info, opts)
+	if opts.Battery == true {
+		a.AddService(service.NewBatteryService().Service)
+	}
+
+	return a, nil
+}
+
+// TemplateNames returns the sorted names of the templates.
+func TemplateNames() []string {
+	var names []string
+	for name := range Templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
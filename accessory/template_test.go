@@ -0,0 +1,44 @@
+package accessory
+
+import (
+	"testing"
+
+	"github.com/brutella/hc/service"
+)
+
+func TestTemplates(t *testing.T) {
+	for _, name := range TemplateNames() {
+		a, err := NewFromTemplate(name, Info{Name: name, SerialNumber: "001", Manufacturer: "hc", Model: name}, TemplateOptions{Battery: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := a.Validate(); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestPowerStripTemplate(t *testing.T) {
+	a, err := NewFromTemplate("power-strip", Info{Name: "Power Strip"}, TemplateOptions{Outlets: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := 0
+	for _, s := range a.GetServices() {
+		if s.Type == service.TypeOutlet {
+			n++
+		}
+	}
+
+	if is, want := n, 6; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestUnknownTemplate(t *testing.T) {
+	if _, err := NewFromTemplate("toaster", Info{Name: "Toaster"}, TemplateOptions{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
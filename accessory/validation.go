@@ -0,0 +1,54 @@
+package accessory
+
+import (
+	"github.com/brutella/hc/service"
+
+	"fmt"
+	"strings"
+)
+
+// ValidationError contains the problems of an invalid accessory.
+type ValidationError struct {
+	// Name is the name of the accessory
+	Name string
+
+	Errors []error
+}
+
+func (e *ValidationError) Error() string {
+	var msgs []string
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+
+	return fmt.Sprintf("Invalid accessory %s: %s", e.Name, strings.Join(msgs, "; "))
+}
+
+// Validate returns a *ValidationError when the accessory has no complete accessory information
+// service, or a service misses characteristics which are required by its type.
+// Clients refuse to add such accessories.
+func (a *Accessory) Validate() error {
+	var errs []error
+	var name string
+	if a.Info != nil {
+		name = a.Info.Name.GetValue()
+	}
+
+	if a.Info == nil || a.ServiceByType(service.TypeAccessoryInformation) != a.Info.Service {
+		errs = append(errs, fmt.Errorf("missing accessory information service"))
+	} else if len(name) == 0 {
+		errs = append(errs, fmt.Errorf("empty name"))
+	}
+
+	for _, s := range a.Services {
+		if err := service.ValidateRequired(s); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Name: name, Errors: errs}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package accessory
+
+import (
+	"testing"
+
+	"github.com/brutella/hc/service"
+)
+
+func TestValidateAccessories(t *testing.T) {
+	as := []*Accessory{
+		NewSwitch(info).Accessory,
+		NewOutlet(info).Accessory,
+		NewLightbulb(info).Accessory,
+		NewTemperatureSensor(info, 20, 0, 40, 0.1).Accessory,
+		NewThermostat(info, 20, 0, 40, 0.1).Accessory,
+		NewValve(info, 0).Accessory,
+	}
+
+	for _, a := range as {
+		if err := a.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAddInvalidAccessory(t *testing.T) {
+	a := New(Info{Name: "Lamp"}, TypeOther)
+	a.AddService(service.New(service.TypeLightbulb))
+	a.Services = a.Services[1:]
+
+	c := NewContainer()
+	err := c.AddAccessory(a)
+
+	verr, ok := err.(*ValidationError)
+	if ok == false {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if is, want := len(verr.Errors), 2; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	want := "Invalid accessory Lamp: missing accessory information service; Lightbulb service: missing required characteristics On"
+	if is := err.Error(); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
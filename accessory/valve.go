@@ -0,0 +1,152 @@
+package accessory
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
+	"github.com/brutella/hc/util"
+)
+
+// countdownInterval is the interval in which the remaining duration of a valve is decremented
+const countdownInterval = time.Second
+
+type Valve struct {
+	*Accessory
+
+	Valve             *service.Valve
+	SetDuration       *characteristic.SetDuration
+	RemainingDuration *characteristic.RemainingDuration
+
+	mutex sync.Mutex
+	clock util.Clock
+	timer util.Timer
+
+	// countdown identifies the running countdown
+	countdown int
+
+	// remaining is the remaining duration of the running countdown
+	remaining int
+}
+
+// NewValve returns a valve of type typ (e.g. characteristic.ValveTypeIrrigation).
+//
+// When the valve is activated, it is in use for the duration of SetDuration.
+// The RemainingDuration is counted down every second until the valve is
+// deactivated automatically. A SetDuration of 0 keeps the valve active until
+// it is deactivated.
+func NewValve(info Info, typ int) *Valve {
+	acc := Valve{clock: util.SystemClock}
+	acc.Accessory = New(info, accessoryTypeForValveType(typ))
+	acc.Valve = service.NewValve()
+	acc.Valve.ValveType.SetValue(typ)
+
+	acc.SetDuration = characteristic.NewSetDuration()
+	acc.Valve.AddCharacteristic(acc.SetDuration.Characteristic)
+
+	acc.RemainingDuration = characteristic.NewRemainingDuration()
+	acc.Valve.AddCharacteristic(acc.RemainingDuration.Characteristic)
+
+	acc.Valve.Active.OnValueUpdate(func(c *characteristic.Characteristic, new, old interface{}) {
+		acc.activeChanged(new)
+	})
+	acc.Valve.Active.OnValueUpdateFromConn(func(conn net.Conn, c *characteristic.Characteristic, new, old interface{}) {
+		acc.activeChanged(new)
+	})
+
+	acc.AddService(acc.Valve.Service)
+
+	return &acc
+}
+
+func (v *Valve) activeChanged(value interface{}) {
+	if value == characteristic.ActiveActive {
+		v.Valve.InUse.SetValue(characteristic.InUseInUse)
+		if d := v.SetDuration.GetValue(); d > 0 {
+			v.startCountdown(d)
+		}
+	} else {
+		v.stopCountdown()
+		v.RemainingDuration.SetValue(0)
+		v.Valve.InUse.SetValue(characteristic.InUseNotInUse)
+	}
+}
+
+// SetClock sets the clock of the countdown, e.g. a fake clock in tests.
+func (v *Valve) SetClock(c util.Clock) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	v.clock = c
+}
+
+// startCountdown decrements the remaining duration starting at d
+// and deactivates the valve when the duration is over.
+func (v *Valve) startCountdown(d int) {
+	v.stopCountdown()
+
+	v.mutex.Lock()
+	v.remaining = d
+	v.scheduleTick(v.countdown)
+	v.mutex.Unlock()
+
+	v.RemainingDuration.SetValue(d)
+}
+
+// scheduleTick decrements the remaining duration of the countdown after the countdown interval.
+// The caller must hold the mutex.
+func (v *Valve) scheduleTick(countdown int) {
+	v.timer = v.clock.AfterFunc(countdownInterval, func() {
+		v.tick(countdown)
+	})
+}
+
+func (v *Valve) tick(countdown int) {
+	v.mutex.Lock()
+	if countdown != v.countdown {
+		// The countdown was stopped
+		v.mutex.Unlock()
+		return
+	}
+
+	v.remaining--
+	remaining := v.remaining
+	if remaining > 0 {
+		v.scheduleTick(countdown)
+	}
+	v.mutex.Unlock()
+
+	if remaining > 0 {
+		v.RemainingDuration.SetValue(remaining)
+		return
+	}
+
+	// Stops the countdown
+	v.Valve.Active.SetValue(characteristic.ActiveInactive)
+}
+
+func (v *Valve) stopCountdown() {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	v.countdown++
+	if v.timer != nil {
+		v.timer.Stop()
+		v.timer = nil
+	}
+}
+
+func accessoryTypeForValveType(typ int) AccessoryType {
+	switch typ {
+	case characteristic.ValveTypeIrrigation:
+		return TypeSprinkler
+	case characteristic.ValveTypeShowerHead:
+		return TypeShowerSystem
+	case characteristic.ValveTypeWaterFaucet:
+		return TypeFaucet
+	}
+
+	return TypeOther
+}
@@ -0,0 +1,21 @@
+package accessory
+
+import (
+	"testing"
+
+	"github.com/brutella/hc/characteristic"
+)
+
+func TestValveWithoutDuration(t *testing.T) {
+	v := NewValve(Info{Name: "Faucet"}, characteristic.ValveTypeWaterFaucet)
+	v.Valve.Active.SetValue(characteristic.ActiveActive)
+
+	if is, want := v.RemainingDuration.GetValue(), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	v.Valve.Active.SetValue(characteristic.ActiveInactive)
+	if is, want := v.Valve.InUse.GetValue(), characteristic.InUseNotInUse; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,30 @@
+package accessory
+
+import (
+	"github.com/brutella/hc/service"
+)
+
+// WeatherStation measures the temperature, humidity and ambient light level.
+type WeatherStation struct {
+	*Accessory
+
+	TempSensor     *service.TemperatureSensor
+	HumiditySensor *service.HumiditySensor
+	LightSensor    *service.LightSensor
+}
+
+// NewWeatherStation returns a weather station whose temperature sensor is the primary service.
+func NewWeatherStation(info Info) *WeatherStation {
+	acc := WeatherStation{}
+	acc.Accessory = New(info, TypeSensor)
+	acc.TempSensor = service.NewTemperatureSensor()
+	acc.TempSensor.SetPrimary(true)
+	acc.HumiditySensor = service.NewHumiditySensor()
+	acc.LightSensor = service.NewLightSensor()
+
+	acc.AddService(acc.TempSensor.Service)
+	acc.AddService(acc.HumiditySensor.Service)
+	acc.AddService(acc.LightSensor.Service)
+
+	return &acc
+}
@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/util"
+
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type memorySink struct {
+	records []Record
+}
+
+func (s *memorySink) Append(r Record) error {
+	s.records = append(s.records, r)
+	return nil
+}
+
+func TestFileSinkAppends(t *testing.T) {
+	path := filepath.Join(os.TempDir(), util.RandomHexString(), "audit.log")
+	defer os.RemoveAll(filepath.Dir(path))
+
+	for i := 0; i < 2; i++ {
+		sink, err := NewFileSink(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := sink.Append(Record{Operation: OperationPairingAdded, Username: "A"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, r)
+	}
+
+	if is, want := len(records), 2; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := records[1].Username, "A"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestLogRecord(t *testing.T) {
+	sink := &memorySink{}
+	l := NewLog(sink)
+	l.Record(Record{Operation: OperationFactoryReset})
+
+	if is, want := len(sink.records), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if sink.records[0].Time.IsZero() == true {
+		t.Fatal("expected record time")
+	}
+}
+
+func TestSensitiveTypes(t *testing.T) {
+	l := NewLog(&memorySink{})
+
+	if is, want := l.IsSensitive(characteristic.NewLockTargetState().Characteristic), true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	on := characteristic.NewOn()
+	if is, want := l.IsSensitive(on.Characteristic), false; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	l.AddSensitiveType(characteristic.TypeOn)
+	if is, want := l.IsSensitive(on.Characteristic), true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestNilLog(t *testing.T) {
+	var l *Log
+	l.Record(Record{Operation: OperationFactoryReset})
+
+	if is, want := l.IsSensitive(characteristic.NewLockTargetState().Characteristic), false; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
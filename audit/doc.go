@@ -0,0 +1,7 @@
+// Package audit implements an append-only log of security-relevant operations.
+//
+// The log records pairing additions and removals, failed pair setup attempts,
+// factory resets and writes to sensitive characteristics (e.g. the target state of a lock).
+// Records are written to a Sink, which can be a file (see NewFileSink) or
+// any other persistent storage.
+package audit
@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/log"
+
+	"sync"
+	"time"
+)
+
+// Log records security-relevant operations to a sink.
+//
+// The methods of a nil log do nothing, which means that auditing is disabled.
+type Log struct {
+	sink      Sink
+	sensitive map[string]bool
+	mutex     sync.Mutex
+}
+
+// NewLog returns a log which writes records to sink.
+// Writes to the target state of locks are recorded by default.
+func NewLog(sink Sink) *Log {
+	l := Log{
+		sink: sink,
+		sensitive: map[string]bool{
+			characteristic.TypeLockTargetState: true,
+		},
+	}
+
+	return &l
+}
+
+// AddSensitiveType designates characteristics of type typ as sensitive.
+// Writes to sensitive characteristics are recorded.
+func (l *Log) AddSensitiveType(typ string) {
+	if l == nil {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.sensitive[typ] = true
+}
+
+// IsSensitive returns true when writes to c are recorded.
+func (l *Log) IsSensitive(c *characteristic.Characteristic) bool {
+	if l == nil {
+		return false
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.sensitive[c.Type]
+}
+
+// Record appends r to the sink. The record time is set to the current time if it's zero.
+// Errors of the sink are logged.
+func (l *Log) Record(r Record) {
+	if l == nil {
+		return
+	}
+
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+
+	if err := l.sink.Append(r); err != nil {
+		log.Println("[ERRO] Could not write audit record:", err)
+	}
+}
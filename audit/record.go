@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"time"
+)
+
+// Operation is the type of a security-relevant operation.
+type Operation string
+
+const (
+	// OperationPairingAdded is recorded when a controller was paired.
+	OperationPairingAdded Operation = "pairing-added"
+
+	// OperationPairingRemoved is recorded when the pairing of a controller was removed.
+	OperationPairingRemoved Operation = "pairing-removed"
+
+	// OperationPairSetupFailed is recorded when a pair setup attempt failed, e.g. because of a wrong setup code.
+	OperationPairSetupFailed Operation = "pair-setup-failed"
+
+	// OperationFactoryReset is recorded when the pairings of all controllers were removed
+	// to reset the accessory, e.g. with hap.Transport.ResetPairings.
+	OperationFactoryReset Operation = "factory-reset"
+
+	// OperationCharacteristicWrite is recorded when a controller wrote the value of a sensitive characteristic.
+	OperationCharacteristicWrite Operation = "characteristic-write"
+)
+
+// Record is an entry of the audit log.
+type Record struct {
+	Time      time.Time `json:"time"`
+	Operation Operation `json:"operation"`
+
+	// Username is the pairing identifier of the controller
+	Username string `json:"username,omitempty"`
+
+	// Addr is the network address of the peer
+	Addr string `json:"addr,omitempty"`
+
+	// AccessoryID and CharacteristicID identify the written characteristic
+	AccessoryID      int64 `json:"aid,omitempty"`
+	CharacteristicID int64 `json:"iid,omitempty"`
+
+	// Value is the written characteristic value
+	Value interface{} `json:"value,omitempty"`
+
+	// Message describes the operation, e.g. why pair setup failed
+	Message string `json:"message,omitempty"`
+}
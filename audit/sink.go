@@ -0,0 +1,51 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Sink stores audit records. Records must only be appended and never changed.
+type Sink interface {
+	// Append adds the record to the end of the sink
+	Append(r Record) error
+}
+
+type fileSink struct {
+	file  *os.File
+	mutex sync.Mutex
+}
+
+// NewFileSink returns a sink which appends records as json lines to the file at path.
+// The file is created if necessary.
+func NewFileSink(path string) (Sink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileSink{file: file}, nil
+}
+
+func (s *fileSink) Append(r Record) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, err := s.file.Write(append(b, '\n')); err != nil {
+		return err
+	}
+
+	// Records must not get lost when the accessory loses power
+	return s.file.Sync()
+}
@@ -0,0 +1,9 @@
+// Package camera implements camera streaming and HomeKit Secure Video recordings.
+//
+// Live streams are negotiated by a StreamManager via the Camera RTP Stream Management service.
+// The video and audio RTP streams (including talk-back audio from the controller) are
+// encrypted with SRTP. The media pipeline is implemented by a StreamHandler.
+//
+// Recordings are uploaded as fragmented MP4 over a HomeKit Data Stream (see package hds).
+// The media is provided by a RecordingSource, which is implemented by the camera.
+package camera
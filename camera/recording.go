@@ -0,0 +1,229 @@
+package camera
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/hds"
+	"github.com/brutella/hc/service"
+	"github.com/brutella/log"
+)
+
+// DataSendTypeRecording is the type of data streams which upload recordings
+const DataSendTypeRecording = "ipcamera.recording"
+
+const (
+	dataTypeInitialization = "mediaInitialization"
+	dataTypeFragment       = "mediaFragment"
+
+	// maxChunkSize is the maximum size of data in one data event
+	maxChunkSize = 0x40000
+)
+
+// ErrNoRecording is returned by a RecordingSource when there is no recording available.
+var ErrNoRecording = errors.New("camera: no recording available")
+
+// A RecordingSource provides recordings encoded as fragmented MP4.
+type RecordingSource interface {
+	// OpenRecording returns the recording of the current event encoded with
+	// the configuration c. The recording should start PrebufferLength before the event.
+	OpenRecording(c SelectedRecordingConfiguration) (Recording, error)
+}
+
+// A Recording is a fragmented MP4 recording.
+type Recording interface {
+	// Initialization returns the initialization segment (ftyp and moov boxes).
+	Initialization() ([]byte, error)
+
+	// NextFragment returns the next media fragment (moof and mdat boxes).
+	// It returns io.EOF after the last fragment.
+	NextFragment() ([]byte, error)
+
+	// Close is called when the upload is finished or was cancelled.
+	Close() error
+}
+
+// RecordingManager uploads recordings to controllers via HomeKit Data Stream.
+//
+// The services of the manager must be added to the camera accessory together with
+// a Data Stream Transport Management service, which is set up by a hds.Server.
+// The manager must be registered at the server for DataSendTypeRecording.
+//
+//	server.HandleDataSend(camera.DataSendTypeRecording, manager)
+type RecordingManager struct {
+	Management           *service.CameraRecordingManagement
+	OperatingMode        *service.CameraOperatingMode
+	RecordingAudioActive *characteristic.RecordingAudioActive
+
+	source RecordingSource
+
+	mutex    sync.Mutex
+	selected *SelectedRecordingConfiguration
+}
+
+// NewRecordingManager returns a manager which uploads recordings of src.
+// The supported formats are described by conf.
+func NewRecordingManager(src RecordingSource, conf RecordingConfiguration) *RecordingManager {
+	m := RecordingManager{
+		Management:           service.NewCameraRecordingManagement(),
+		OperatingMode:        service.NewCameraOperatingMode(),
+		RecordingAudioActive: characteristic.NewRecordingAudioActive(),
+		source:               src,
+	}
+
+	m.Management.AddCharacteristic(m.RecordingAudioActive.Characteristic)
+	m.OperatingMode.HomeKitCameraActive.SetValue(characteristic.HomeKitCameraActiveOn)
+	m.OperatingMode.EventSnapshotsActive.SetValue(characteristic.EventSnapshotsActiveEnable)
+
+	m.Management.SupportedCameraRecordingConfiguration.SetValue(conf.cameraRecordingConfiguration())
+	m.Management.SupportedVideoRecordingConfiguration.SetValue(conf.videoRecordingConfiguration())
+	m.Management.SupportedAudioRecordingConfiguration.SetValue(conf.audioRecordingConfiguration())
+
+	m.Management.SelectedCameraRecordingConfiguration.OnValueUpdateFromConn(func(conn net.Conn, c *characteristic.Characteristic, newValue, oldValue interface{}) {
+		str, _ := newValue.(string)
+		b, err := base64.StdEncoding.DecodeString(str)
+		if err != nil {
+			log.Println("[ERRO]", err)
+			return
+		}
+
+		selected, err := parseSelectedRecordingConfiguration(b)
+		if err != nil {
+			log.Println("[ERRO] Invalid recording configuration", err)
+			return
+		}
+
+		m.mutex.Lock()
+		m.selected = selected
+		m.mutex.Unlock()
+	})
+
+	return &m
+}
+
+// SelectedConfiguration returns the recording configuration selected by the controller, or nil.
+func (m *RecordingManager) SelectedConfiguration() *SelectedRecordingConfiguration {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.selected
+}
+
+// HandleOpen handles a request of the controller to upload a recording.
+func (m *RecordingManager) HandleOpen(st *hds.DataStream, body map[string]interface{}) int64 {
+	if m.isRecordingEnabled() == false {
+		return hds.CloseReasonNotAllowed
+	}
+
+	selected := m.SelectedConfiguration()
+	if selected == nil {
+		return hds.CloseReasonInvalidConfiguration
+	}
+
+	// Controllers expect data after the open response
+	st.Session().AfterResponse(func() {
+		go m.upload(st, *selected)
+	})
+
+	return hds.CloseReasonNormal
+}
+
+func (m *RecordingManager) isRecordingEnabled() bool {
+	if m.Management.Active.GetValue() != characteristic.ActiveActive {
+		return false
+	}
+
+	return m.OperatingMode.HomeKitCameraActive.GetValue() == characteristic.HomeKitCameraActiveOn
+}
+
+// upload sends the initialization segment and all fragments of a recording.
+func (m *RecordingManager) upload(st *hds.DataStream, c SelectedRecordingConfiguration) {
+	rec, err := m.source.OpenRecording(c)
+	if err != nil {
+		log.Println("[ERRO] Could not open recording", err)
+		st.Close(hds.CloseReasonUnexpectedFailure)
+		return
+	}
+	defer rec.Close()
+
+	b, err := rec.Initialization()
+	if err != nil {
+		log.Println("[ERRO] Could not read initialization segment", err)
+		st.Close(hds.CloseReasonUnexpectedFailure)
+		return
+	}
+
+	seq := int64(1)
+	if err := m.sendData(st, b, dataTypeInitialization, seq, false); err != nil {
+		return
+	}
+
+	fragment, err := rec.NextFragment()
+	for err == nil {
+		// Read ahead to know if the current fragment is the last one
+		next, nextErr := rec.NextFragment()
+		if nextErr != nil && nextErr != io.EOF {
+			log.Println("[ERRO] Could not read fragment", nextErr)
+			st.Close(hds.CloseReasonUnexpectedFailure)
+			return
+		}
+
+		seq++
+		if err := m.sendData(st, fragment, dataTypeFragment, seq, nextErr == io.EOF); err != nil {
+			return
+		}
+
+		fragment, err = next, nextErr
+	}
+
+	if err != io.EOF {
+		log.Println("[ERRO] Could not read fragment", err)
+		st.Close(hds.CloseReasonUnexpectedFailure)
+	} else if seq == 1 {
+		// Recordings without fragments are closed explicitly
+		st.Close(hds.CloseReasonNormal)
+	}
+}
+
+// sendData sends b in chunks of data events.
+func (m *RecordingManager) sendData(st *hds.DataStream, b []byte, dataType string, seq int64, endOfStream bool) error {
+	chunk := int64(1)
+	for offset := 0; offset == 0 || offset < len(b); chunk++ {
+		end := offset + maxChunkSize
+		if end > len(b) {
+			end = len(b)
+		}
+		last := end == len(b)
+
+		metadata := map[string]interface{}{
+			"dataType":                dataType,
+			"dataSequenceNumber":      seq,
+			"dataChunkSequenceNumber": chunk,
+			"isLastDataChunk":         last,
+		}
+		if chunk == 1 {
+			metadata["dataTotalSize"] = int64(len(b))
+		}
+
+		packet := map[string]interface{}{
+			"data":     b[offset:end],
+			"metadata": metadata,
+		}
+
+		if err := st.SendData([]interface{}{packet}, endOfStream == true && last == true); err != nil {
+			log.Println("[ERRO] Could not send recording data", err)
+			return err
+		}
+
+		if last == true {
+			break
+		}
+		offset = end
+	}
+
+	return nil
+}
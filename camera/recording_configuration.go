@@ -0,0 +1,293 @@
+package camera
+
+import (
+	"time"
+
+	"github.com/brutella/hc/util"
+)
+
+// Event triggers of recordings
+const (
+	EventTriggerMotion   uint64 = 0x01
+	EventTriggerDoorbell uint64 = 0x02
+)
+
+// Container types of recordings
+const (
+	ContainerTypeFragmentedMP4 byte = 0
+)
+
+// Video codec types
+const (
+	VideoCodecTypeH264 byte = 0
+)
+
+// H.264 profiles
+const (
+	H264ProfileConstrainedBaseline byte = 0
+	H264ProfileMain                byte = 1
+	H264ProfileHigh                byte = 2
+)
+
+// H.264 levels
+const (
+	H264Level3_1 byte = 0
+	H264Level3_2 byte = 1
+	H264Level4   byte = 2
+)
+
+// Audio codec types of recordings
+const (
+	AudioRecordingCodecTypeAACLC  byte = 0
+	AudioRecordingCodecTypeAACELD byte = 1
+)
+
+// Audio bitrate modes
+const (
+	AudioBitrateVariable byte = 0
+	AudioBitrateConstant byte = 1
+)
+
+// Audio sample rates of recordings
+const (
+	AudioRecordingSampleRate8Khz    byte = 0
+	AudioRecordingSampleRate16Khz   byte = 1
+	AudioRecordingSampleRate24Khz   byte = 2
+	AudioRecordingSampleRate32Khz   byte = 3
+	AudioRecordingSampleRate44_1Khz byte = 4
+	AudioRecordingSampleRate48Khz   byte = 5
+)
+
+// TLV8 tags of the recording configurations
+const (
+	tagRecordingPrebufferLength  = 0x01
+	tagRecordingEventTriggers    = 0x02
+	tagRecordingMediaContainer   = 0x03
+	tagMediaContainerType        = 0x01
+	tagMediaContainerParameters  = 0x02
+	tagMediaContainerFragmentLen = 0x01
+
+	tagCodecConfiguration = 0x01
+	tagCodecType          = 0x01
+	tagCodecParameters    = 0x02
+	tagVideoAttributes    = 0x03
+
+	tagVideoProfile        = 0x01
+	tagVideoLevel          = 0x02
+	tagVideoBitrate        = 0x03
+	tagVideoIFrameInterval = 0x04
+
+	tagVideoWidth     = 0x01
+	tagVideoHeight    = 0x02
+	tagVideoFramerate = 0x03
+
+	tagAudioChannels    = 0x01
+	tagAudioBitrateMode = 0x02
+	tagAudioSampleRate  = 0x03
+	tagAudioMaxBitrate  = 0x04
+
+	tagSelectedGeneral = 0x01
+	tagSelectedVideo   = 0x02
+	tagSelectedAudio   = 0x03
+)
+
+// VideoAttributes describes a video resolution and frame rate.
+type VideoAttributes struct {
+	Width     uint16
+	Height    uint16
+	Framerate uint8
+}
+
+func (a VideoAttributes) tlv8() []byte {
+	b := tlv8(tagVideoWidth, uint16Bytes(a.Width))
+	b = append(b, tlv8(tagVideoHeight, uint16Bytes(a.Height))...)
+	return append(b, tlv8(tagVideoFramerate, []byte{a.Framerate})...)
+}
+
+// RecordingConfiguration describes the supported recording formats of a camera.
+type RecordingConfiguration struct {
+	// PrebufferLength is the duration of the video before an event, which is part of the recording.
+	PrebufferLength time.Duration
+
+	// EventTriggers is a bitmask of event triggers, e.g. EventTriggerMotion
+	EventTriggers uint64
+
+	// FragmentLength is the duration of one media fragment
+	FragmentLength time.Duration
+
+	Video VideoRecordingConfiguration
+	Audio AudioRecordingConfiguration
+}
+
+// VideoRecordingConfiguration describes the supported H.264 video formats.
+type VideoRecordingConfiguration struct {
+	Profiles   []byte
+	Levels     []byte
+	Attributes []VideoAttributes
+}
+
+// AudioRecordingConfiguration describes the supported audio formats.
+type AudioRecordingConfiguration struct {
+	CodecType   byte
+	Channels    byte
+	BitrateMode byte
+	SampleRates []byte
+}
+
+// cameraRecordingConfiguration returns the value of the Supported Camera Recording Configuration characteristic.
+func (c RecordingConfiguration) cameraRecordingConfiguration() []byte {
+	params := tlv8(tagMediaContainerFragmentLen, uint32Bytes(milliseconds(c.FragmentLength)))
+	container := tlv8(tagMediaContainerType, []byte{ContainerTypeFragmentedMP4})
+	container = append(container, tlv8(tagMediaContainerParameters, params)...)
+
+	b := tlv8(tagRecordingPrebufferLength, uint32Bytes(milliseconds(c.PrebufferLength)))
+	b = append(b, tlv8(tagRecordingEventTriggers, uint64Bytes(c.EventTriggers))...)
+	return append(b, tlv8(tagRecordingMediaContainer, container)...)
+}
+
+// videoRecordingConfiguration returns the value of the Supported Video Recording Configuration characteristic.
+func (c RecordingConfiguration) videoRecordingConfiguration() []byte {
+	var profiles, levels, attributes [][]byte
+	for _, p := range c.Video.Profiles {
+		profiles = append(profiles, []byte{p})
+	}
+	for _, l := range c.Video.Levels {
+		levels = append(levels, []byte{l})
+	}
+	for _, a := range c.Video.Attributes {
+		attributes = append(attributes, a.tlv8())
+	}
+
+	params := tlv8List(tagVideoProfile, profiles)
+	params = append(params, tlv8List(tagVideoLevel, levels)...)
+
+	codec := tlv8(tagCodecType, []byte{VideoCodecTypeH264})
+	codec = append(codec, tlv8(tagCodecParameters, params)...)
+	codec = append(codec, tlv8List(tagVideoAttributes, attributes)...)
+
+	return tlv8(tagCodecConfiguration, codec)
+}
+
+// audioRecordingConfiguration returns the value of the Supported Audio Recording Configuration characteristic.
+func (c RecordingConfiguration) audioRecordingConfiguration() []byte {
+	var rates [][]byte
+	for _, r := range c.Audio.SampleRates {
+		rates = append(rates, []byte{r})
+	}
+
+	params := tlv8(tagAudioChannels, []byte{c.Audio.Channels})
+	params = append(params, tlv8(tagAudioBitrateMode, []byte{c.Audio.BitrateMode})...)
+	params = append(params, tlv8List(tagAudioSampleRate, rates)...)
+
+	codec := tlv8(tagCodecType, []byte{c.Audio.CodecType})
+	codec = append(codec, tlv8(tagCodecParameters, params)...)
+
+	return tlv8(tagCodecConfiguration, codec)
+}
+
+// SelectedRecordingConfiguration is the recording configuration which was selected by a controller.
+type SelectedRecordingConfiguration struct {
+	PrebufferLength time.Duration
+	EventTriggers   uint64
+	FragmentLength  time.Duration
+
+	Video SelectedVideoRecordingConfiguration
+	Audio SelectedAudioRecordingConfiguration
+}
+
+// SelectedVideoRecordingConfiguration is the selected H.264 video format.
+type SelectedVideoRecordingConfiguration struct {
+	Profile        byte
+	Level          byte
+	Bitrate        uint32 // kbit/s
+	IFrameInterval uint32 // ms
+	Attributes     VideoAttributes
+}
+
+// SelectedAudioRecordingConfiguration is the selected audio format.
+type SelectedAudioRecordingConfiguration struct {
+	CodecType   byte
+	Channels    byte
+	BitrateMode byte
+	SampleRate  byte
+	MaxBitrate  uint32 // kbit/s
+}
+
+// parseSelectedRecordingConfiguration returns the configuration of a
+// Selected Camera Recording Configuration characteristic value.
+func parseSelectedRecordingConfiguration(b []byte) (*SelectedRecordingConfiguration, error) {
+	var conf SelectedRecordingConfiguration
+
+	c, err := tlv8Container(b)
+	if err != nil {
+		return nil, err
+	}
+
+	general, err := tlv8Container(c.GetBytes(tagSelectedGeneral))
+	if err != nil {
+		return nil, err
+	}
+	conf.PrebufferLength = time.Duration(uintFromBytes(general.GetBytes(tagRecordingPrebufferLength))) * time.Millisecond
+	conf.EventTriggers = uintFromBytes(general.GetBytes(tagRecordingEventTriggers))
+
+	container, err := tlv8Container(general.GetBytes(tagRecordingMediaContainer))
+	if err != nil {
+		return nil, err
+	}
+	containerParams, err := tlv8Container(container.GetBytes(tagMediaContainerParameters))
+	if err != nil {
+		return nil, err
+	}
+	conf.FragmentLength = time.Duration(uintFromBytes(containerParams.GetBytes(tagMediaContainerFragmentLen))) * time.Millisecond
+
+	video, err := codecConfiguration(c.GetBytes(tagSelectedVideo))
+	if err != nil {
+		return nil, err
+	}
+	videoParams, err := tlv8Container(video.GetBytes(tagCodecParameters))
+	if err != nil {
+		return nil, err
+	}
+	conf.Video.Profile = videoParams.GetByte(tagVideoProfile)
+	conf.Video.Level = videoParams.GetByte(tagVideoLevel)
+	conf.Video.Bitrate = uint32(uintFromBytes(videoParams.GetBytes(tagVideoBitrate)))
+	conf.Video.IFrameInterval = uint32(uintFromBytes(videoParams.GetBytes(tagVideoIFrameInterval)))
+
+	attributes, err := tlv8Container(video.GetBytes(tagVideoAttributes))
+	if err != nil {
+		return nil, err
+	}
+	conf.Video.Attributes.Width = uint16(uintFromBytes(attributes.GetBytes(tagVideoWidth)))
+	conf.Video.Attributes.Height = uint16(uintFromBytes(attributes.GetBytes(tagVideoHeight)))
+	conf.Video.Attributes.Framerate = attributes.GetByte(tagVideoFramerate)
+
+	audio, err := codecConfiguration(c.GetBytes(tagSelectedAudio))
+	if err != nil {
+		return nil, err
+	}
+	conf.Audio.CodecType = audio.GetByte(tagCodecType)
+	audioParams, err := tlv8Container(audio.GetBytes(tagCodecParameters))
+	if err != nil {
+		return nil, err
+	}
+	conf.Audio.Channels = audioParams.GetByte(tagAudioChannels)
+	conf.Audio.BitrateMode = audioParams.GetByte(tagAudioBitrateMode)
+	conf.Audio.SampleRate = audioParams.GetByte(tagAudioSampleRate)
+	conf.Audio.MaxBitrate = uint32(uintFromBytes(audioParams.GetBytes(tagAudioMaxBitrate)))
+
+	return &conf, nil
+}
+
+// codecConfiguration returns the codec configuration container of a selected video or audio configuration.
+func codecConfiguration(b []byte) (util.Container, error) {
+	c, err := tlv8Container(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return tlv8Container(c.GetBytes(tagCodecConfiguration))
+}
+
+func milliseconds(d time.Duration) uint32 {
+	return uint32(d / time.Millisecond)
+}
@@ -0,0 +1,93 @@
+package camera
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestSupportedVideoRecordingConfiguration(t *testing.T) {
+	conf := RecordingConfiguration{
+		Video: VideoRecordingConfiguration{
+			Profiles: []byte{H264ProfileMain},
+			Levels:   []byte{H264Level3_1},
+			Attributes: []VideoAttributes{
+				{1920, 1080, 30},
+				{1280, 720, 30},
+			},
+		},
+	}
+
+	b := conf.videoRecordingConfiguration()
+	c, err := codecConfiguration(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := c.GetByte(tagCodecType), VideoCodecTypeH264; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// Attributes are separated by an empty delimiter
+	attrs := []byte{
+		0x03, 0x0B, 0x01, 0x02, 0x80, 0x07, 0x02, 0x02, 0x38, 0x04, 0x03, 0x01, 0x1E,
+		0x00, 0x00,
+		0x03, 0x0B, 0x01, 0x02, 0x00, 0x05, 0x02, 0x02, 0xD0, 0x02, 0x03, 0x01, 0x1E,
+	}
+	if bytes.HasSuffix(b, attrs) == false {
+		t.Fatalf("%X does not end with %X", b, attrs)
+	}
+}
+
+func TestParseSelectedRecordingConfiguration(t *testing.T) {
+	general := tlv8(tagRecordingPrebufferLength, uint32Bytes(4000))
+	general = append(general, tlv8(tagRecordingEventTriggers, uint64Bytes(EventTriggerMotion))...)
+	container := tlv8(tagMediaContainerType, []byte{ContainerTypeFragmentedMP4})
+	container = append(container, tlv8(tagMediaContainerParameters, tlv8(tagMediaContainerFragmentLen, uint32Bytes(4000)))...)
+	general = append(general, tlv8(tagRecordingMediaContainer, container)...)
+
+	videoParams := tlv8(tagVideoProfile, []byte{H264ProfileHigh})
+	videoParams = append(videoParams, tlv8(tagVideoLevel, []byte{H264Level4})...)
+	videoParams = append(videoParams, tlv8(tagVideoBitrate, uint32Bytes(2000))...)
+	video := tlv8(tagCodecType, []byte{VideoCodecTypeH264})
+	video = append(video, tlv8(tagCodecParameters, videoParams)...)
+	video = append(video, tlv8(tagVideoAttributes, VideoAttributes{1920, 1080, 24}.tlv8())...)
+
+	audioParams := tlv8(tagAudioChannels, []byte{1})
+	audioParams = append(audioParams, tlv8(tagAudioSampleRate, []byte{AudioRecordingSampleRate32Khz})...)
+	audio := tlv8(tagCodecType, []byte{AudioRecordingCodecTypeAACLC})
+	audio = append(audio, tlv8(tagCodecParameters, audioParams)...)
+
+	b := tlv8(tagSelectedGeneral, general)
+	b = append(b, tlv8(tagSelectedVideo, tlv8(tagCodecConfiguration, video))...)
+	b = append(b, tlv8(tagSelectedAudio, tlv8(tagCodecConfiguration, audio))...)
+
+	conf, err := parseSelectedRecordingConfiguration(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := conf.PrebufferLength, 4*time.Second; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := conf.FragmentLength, 4*time.Second; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := conf.Video.Profile, H264ProfileHigh; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := conf.Video.Bitrate, uint32(2000); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := conf.Video.Attributes, (VideoAttributes{1920, 1080, 24}); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := conf.Audio.SampleRate, AudioRecordingSampleRate32Khz; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
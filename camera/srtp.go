@@ -0,0 +1,84 @@
+package camera
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// SRTP crypto suites
+const (
+	CryptoSuiteAES128 byte = 0 // AES_CM_128_HMAC_SHA1_80
+	CryptoSuiteAES256 byte = 1 // AES_256_CM_HMAC_SHA1_80
+	CryptoSuiteNone   byte = 2
+)
+
+// TLV8 tags of SRTP parameters
+const (
+	tagSRTPCryptoSuite = 0x01
+	tagSRTPMasterKey   = 0x02
+	tagSRTPMasterSalt  = 0x03
+)
+
+const srtpMasterSaltLength = 14
+
+// SRTPParameters is the key material of an SRTP stream.
+type SRTPParameters struct {
+	CryptoSuite byte
+	MasterKey   []byte
+	MasterSalt  []byte
+}
+
+// newSRTPParameters returns random key material for the crypto suite.
+func newSRTPParameters(suite byte) (SRTPParameters, error) {
+	p := SRTPParameters{CryptoSuite: suite}
+
+	var keyLength int
+	switch suite {
+	case CryptoSuiteAES128:
+		keyLength = 16
+	case CryptoSuiteAES256:
+		keyLength = 32
+	case CryptoSuiteNone:
+		return p, nil
+	default:
+		return p, fmt.Errorf("camera: unsupported crypto suite %d", suite)
+	}
+
+	p.MasterKey = make([]byte, keyLength)
+	if _, err := rand.Read(p.MasterKey); err != nil {
+		return p, err
+	}
+
+	p.MasterSalt = make([]byte, srtpMasterSaltLength)
+	_, err := rand.Read(p.MasterSalt)
+
+	return p, err
+}
+
+// parseSRTPParameters returns the parameters of a TLV8 value.
+func parseSRTPParameters(b []byte) (SRTPParameters, error) {
+	c, err := tlv8Container(b)
+	if err != nil {
+		return SRTPParameters{}, err
+	}
+
+	p := SRTPParameters{
+		CryptoSuite: c.GetByte(tagSRTPCryptoSuite),
+		MasterKey:   c.GetBytes(tagSRTPMasterKey),
+		MasterSalt:  c.GetBytes(tagSRTPMasterSalt),
+	}
+
+	return p, nil
+}
+
+func (p SRTPParameters) tlv8() []byte {
+	b := tlv8(tagSRTPCryptoSuite, []byte{p.CryptoSuite})
+	if len(p.MasterKey) > 0 {
+		b = append(b, tlv8(tagSRTPMasterKey, p.MasterKey)...)
+	}
+	if len(p.MasterSalt) > 0 {
+		b = append(b, tlv8(tagSRTPMasterSalt, p.MasterSalt)...)
+	}
+
+	return b
+}
@@ -0,0 +1,353 @@
+package camera
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
+	"github.com/brutella/hc/util"
+	"github.com/brutella/log"
+)
+
+// TLV8 tags of the Setup Endpoints characteristic
+const (
+	tagEndpointsSessionID  = 0x01
+	tagEndpointsStatus     = 0x02
+	tagEndpointsAddress    = 0x03
+	tagEndpointsVideoSRTP  = 0x04
+	tagEndpointsAudioSRTP  = 0x05
+	tagEndpointsVideoSSRC  = 0x06
+	tagEndpointsAudioSSRC  = 0x07
+	tagAddressIPVersion    = 0x01
+	tagAddressIP           = 0x02
+	tagAddressVideoRTPPort = 0x03
+	tagAddressAudioRTPPort = 0x04
+	tagStreamingStatus     = 0x01
+)
+
+const (
+	endpointsStatusSuccess byte = 0
+	endpointsStatusBusy    byte = 1
+	endpointsStatusError   byte = 2
+
+	ipVersion4 byte = 0
+	ipVersion6 byte = 1
+)
+
+// Streaming status values
+const (
+	StreamingStatusAvailable   byte = 0
+	StreamingStatusInUse       byte = 1
+	StreamingStatusUnavailable byte = 2
+)
+
+// Session control commands
+const (
+	sessionCommandEnd         byte = 0
+	sessionCommandStart       byte = 1
+	sessionCommandSuspend     byte = 2
+	sessionCommandResume      byte = 3
+	sessionCommandReconfigure byte = 4
+)
+
+// Endpoint is the address and RTP ports of a stream participant.
+type Endpoint struct {
+	IP        string
+	VideoPort uint16
+	AudioPort uint16
+}
+
+// Stream is a media stream between the camera and a controller.
+type Stream struct {
+	SessionID []byte
+
+	Controller Endpoint
+	Accessory  Endpoint
+
+	// VideoSRTP and AudioSRTP are the key material of the media sent to the controller.
+	// They are provided by the controller.
+	VideoSRTP SRTPParameters
+	AudioSRTP SRTPParameters
+
+	// IncomingVideoSRTP and IncomingAudioSRTP are the key material of the media sent
+	// by the controller, e.g. the talk-back audio of a doorbell.
+	// They are generated by the accessory.
+	IncomingVideoSRTP SRTPParameters
+	IncomingAudioSRTP SRTPParameters
+
+	// VideoSSRC and AudioSSRC are the synchronization sources of the media sent to the controller.
+	VideoSSRC uint32
+	AudioSSRC uint32
+
+	// Video and Audio are the parameters selected by the controller when the stream is started.
+	Video VideoStreamParameters
+	Audio AudioStreamParameters
+}
+
+// A StreamHandler manages the media pipeline of streams.
+type StreamHandler interface {
+	// PrepareStream is called when a controller sets up the endpoints of a stream.
+	// The handler must set the local RTP ports Accessory.VideoPort and Accessory.AudioPort.
+	PrepareStream(s *Stream) error
+
+	// StartStream is called when the controller starts the stream with the selected parameters.
+	StartStream(s *Stream) error
+
+	// ReconfigureStream is called when the controller changes the video parameters of a running stream.
+	ReconfigureStream(s *Stream) error
+
+	// StopStream is called when the controller ends the stream.
+	StopStream(s *Stream)
+}
+
+// StreamManager negotiates streams via the Camera RTP Stream Management service.
+// The service supports one stream at a time.
+type StreamManager struct {
+	Management *service.CameraRTPStreamManagement
+
+	handler StreamHandler
+	suites  []byte
+
+	mutex     sync.Mutex
+	stream    *Stream
+	streaming bool
+}
+
+// NewStreamManager returns a manager which negotiates streams with the supported
+// formats conf, and starts the media pipeline with h.
+func NewStreamManager(h StreamHandler, conf StreamConfiguration) *StreamManager {
+	m := StreamManager{
+		Management: service.NewCameraRTPStreamManagement(),
+		handler:    h,
+		suites:     conf.CryptoSuites,
+	}
+
+	m.Management.SupportedVideoStreamConfiguration.SetValue(conf.videoStreamConfiguration())
+	m.Management.SupportedAudioStreamConfiguration.SetValue(conf.audioStreamConfiguration())
+	m.Management.SupportedRTPConfiguration.SetValue(conf.rtpConfiguration())
+	m.setStreamingStatus(StreamingStatusAvailable)
+
+	m.Management.SetupEndpoints.OnValueUpdateFromConn(func(conn net.Conn, c *characteristic.Characteristic, newValue, oldValue interface{}) {
+		str, _ := newValue.(string)
+		b, err := base64.StdEncoding.DecodeString(str)
+		if err != nil {
+			log.Println("[ERRO]", err)
+			return
+		}
+
+		// The controller reads the response
+		c.Value = base64.StdEncoding.EncodeToString(m.setupEndpoints(b, conn))
+	})
+
+	m.Management.SelectedRTPStreamConfiguration.OnValueUpdateFromConn(func(conn net.Conn, c *characteristic.Characteristic, newValue, oldValue interface{}) {
+		str, _ := newValue.(string)
+		b, err := base64.StdEncoding.DecodeString(str)
+		if err == nil {
+			err = m.selectStreamConfiguration(b)
+		}
+
+		if err != nil {
+			log.Println("[ERRO]", err)
+		}
+	})
+
+	return &m
+}
+
+// Stream returns the current stream, or nil.
+func (m *StreamManager) Stream() *Stream {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.stream
+}
+
+func (m *StreamManager) setStreamingStatus(status byte) {
+	b := tlv8(tagStreamingStatus, []byte{status})
+	m.Management.StreamingStatus.SetValue(b)
+}
+
+// setupEndpoints handles a write request of the Setup Endpoints characteristic
+// and returns the response value.
+func (m *StreamManager) setupEndpoints(b []byte, conn net.Conn) []byte {
+	in, err := tlv8Container(b)
+	if err != nil {
+		log.Println("[ERRO]", err)
+		return nil
+	}
+
+	sessionID := in.GetBytes(tagEndpointsSessionID)
+
+	m.mutex.Lock()
+	streaming := m.streaming
+	m.mutex.Unlock()
+
+	if streaming == true {
+		return endpointsResponse(sessionID, endpointsStatusBusy)
+	}
+
+	s, err := m.newStream(in, conn)
+	if err == nil {
+		err = m.handler.PrepareStream(s)
+	}
+
+	if err != nil {
+		log.Println("[ERRO] Could not prepare stream", err)
+		return endpointsResponse(sessionID, endpointsStatusError)
+	}
+
+	m.mutex.Lock()
+	m.stream = s
+	m.mutex.Unlock()
+
+	ipVersion := ipVersion4
+	if ip := net.ParseIP(s.Accessory.IP); ip != nil && ip.To4() == nil {
+		ipVersion = ipVersion6
+	}
+
+	address := tlv8(tagAddressIPVersion, []byte{ipVersion})
+	address = append(address, tlv8(tagAddressIP, []byte(s.Accessory.IP))...)
+	address = append(address, tlv8(tagAddressVideoRTPPort, uint16Bytes(s.Accessory.VideoPort))...)
+	address = append(address, tlv8(tagAddressAudioRTPPort, uint16Bytes(s.Accessory.AudioPort))...)
+
+	out := endpointsResponse(sessionID, endpointsStatusSuccess)
+	out = append(out, tlv8(tagEndpointsAddress, address)...)
+	out = append(out, tlv8(tagEndpointsVideoSRTP, s.IncomingVideoSRTP.tlv8())...)
+	out = append(out, tlv8(tagEndpointsAudioSRTP, s.IncomingAudioSRTP.tlv8())...)
+	out = append(out, tlv8(tagEndpointsVideoSSRC, uint32Bytes(s.VideoSSRC))...)
+	out = append(out, tlv8(tagEndpointsAudioSSRC, uint32Bytes(s.AudioSSRC))...)
+
+	return out
+}
+
+// newStream returns a stream for a Setup Endpoints request.
+func (m *StreamManager) newStream(in util.Container, conn net.Conn) (*Stream, error) {
+	s := Stream{
+		SessionID: in.GetBytes(tagEndpointsSessionID),
+	}
+
+	address, err := tlv8Container(in.GetBytes(tagEndpointsAddress))
+	if err != nil {
+		return nil, err
+	}
+	s.Controller.IP = address.GetString(tagAddressIP)
+	s.Controller.VideoPort = uint16(uintFromBytes(address.GetBytes(tagAddressVideoRTPPort)))
+	s.Controller.AudioPort = uint16(uintFromBytes(address.GetBytes(tagAddressAudioRTPPort)))
+
+	if addr, ok := conn.LocalAddr().(*net.TCPAddr); ok == true {
+		s.Accessory.IP = addr.IP.String()
+	}
+
+	if s.VideoSRTP, err = parseSRTPParameters(in.GetBytes(tagEndpointsVideoSRTP)); err != nil {
+		return nil, err
+	}
+
+	if s.AudioSRTP, err = parseSRTPParameters(in.GetBytes(tagEndpointsAudioSRTP)); err != nil {
+		return nil, err
+	}
+
+	if m.isSupportedSuite(s.VideoSRTP.CryptoSuite) == false || m.isSupportedSuite(s.AudioSRTP.CryptoSuite) == false {
+		return nil, errors.New("camera: unsupported crypto suite")
+	}
+
+	if s.IncomingVideoSRTP, err = newSRTPParameters(s.VideoSRTP.CryptoSuite); err != nil {
+		return nil, err
+	}
+
+	if s.IncomingAudioSRTP, err = newSRTPParameters(s.AudioSRTP.CryptoSuite); err != nil {
+		return nil, err
+	}
+
+	if s.VideoSSRC, err = randomSSRC(); err != nil {
+		return nil, err
+	}
+
+	s.AudioSSRC, err = randomSSRC()
+
+	return &s, err
+}
+
+func (m *StreamManager) isSupportedSuite(suite byte) bool {
+	for _, s := range m.suites {
+		if s == suite {
+			return true
+		}
+	}
+
+	return false
+}
+
+// selectStreamConfiguration handles a write request of the Selected RTP Stream Configuration characteristic.
+func (m *StreamManager) selectStreamConfiguration(b []byte) error {
+	in, err := tlv8Container(b)
+	if err != nil {
+		return err
+	}
+
+	control, err := tlv8Container(in.GetBytes(tagSelectedSessionControl))
+	if err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	s := m.stream
+	m.mutex.Unlock()
+
+	if s == nil || bytes.Equal(s.SessionID, control.GetBytes(tagSessionControlID)) == false {
+		return errors.New("camera: unknown stream session")
+	}
+
+	switch cmd := control.GetByte(tagSessionControlCommand); cmd {
+	case sessionCommandStart, sessionCommandReconfigure:
+		if s.Video, err = parseVideoStreamParameters(in.GetBytes(tagSelectedVideoStream)); err != nil {
+			return err
+		}
+
+		if cmd == sessionCommandReconfigure {
+			return m.handler.ReconfigureStream(s)
+		}
+
+		if s.Audio, err = parseAudioStreamParameters(in.GetBytes(tagSelectedAudioStream)); err != nil {
+			return err
+		}
+
+		if err := m.handler.StartStream(s); err != nil {
+			return err
+		}
+
+		m.mutex.Lock()
+		m.streaming = true
+		m.mutex.Unlock()
+		m.setStreamingStatus(StreamingStatusInUse)
+	case sessionCommandEnd:
+		m.handler.StopStream(s)
+
+		m.mutex.Lock()
+		m.stream = nil
+		m.streaming = false
+		m.mutex.Unlock()
+		m.setStreamingStatus(StreamingStatusAvailable)
+	default:
+		log.Printf("[WARN] Unsupported stream session command %d\n", cmd)
+	}
+
+	return nil
+}
+
+func endpointsResponse(sessionID []byte, status byte) []byte {
+	b := tlv8(tagEndpointsSessionID, sessionID)
+	return append(b, tlv8(tagEndpointsStatus, []byte{status})...)
+}
+
+func randomSSRC() (uint32, error) {
+	b := make([]byte, 4)
+	_, err := rand.Read(b)
+
+	return binary.LittleEndian.Uint32(b), err
+}
@@ -0,0 +1,255 @@
+package camera
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// H.264 packetization modes
+const (
+	PacketizationModeNonInterleaved byte = 0
+)
+
+// Audio codec types of streams
+const (
+	AudioCodecTypePCMU   byte = 0
+	AudioCodecTypePCMA   byte = 1
+	AudioCodecTypeAACELD byte = 2
+	AudioCodecTypeOpus   byte = 3
+	AudioCodecTypeMSBC   byte = 4
+	AudioCodecTypeAMR    byte = 5
+	AudioCodecTypeAMRWB  byte = 6
+)
+
+// Audio sample rates of streams
+const (
+	AudioSampleRate8Khz  byte = 0
+	AudioSampleRate16Khz byte = 1
+	AudioSampleRate24Khz byte = 2
+)
+
+// TLV8 tags of the stream configurations
+const (
+	tagVideoPacketizationMode = 0x03
+
+	tagAudioRTPTime          = 0x04
+	tagAudioComfortNoise     = 0x02
+	tagSupportedCryptoSuites = 0x02
+
+	tagSelectedSessionControl = 0x01
+	tagSelectedVideoStream    = 0x02
+	tagSelectedAudioStream    = 0x03
+
+	tagSessionControlID      = 0x01
+	tagSessionControlCommand = 0x02
+
+	tagVideoRTPParameters         = 0x04
+	tagAudioRTPParameters         = 0x03
+	tagAudioComfortNoiseEnabled   = 0x04
+	tagRTPPayloadType             = 0x01
+	tagRTPSSRC                    = 0x02
+	tagRTPMaxBitrate              = 0x03
+	tagRTPMinRTCPInterval         = 0x04
+	tagRTPMaxMTU                  = 0x05
+	tagRTPComfortNoisePayloadType = 0x06
+)
+
+// StreamConfiguration describes the supported streaming formats of a camera.
+type StreamConfiguration struct {
+	Video VideoStreamConfiguration
+
+	// Audio contains one configuration for every supported codec and sample rate
+	Audio        []AudioStreamConfiguration
+	ComfortNoise bool
+
+	CryptoSuites []byte
+}
+
+// VideoStreamConfiguration describes the supported H.264 video formats.
+type VideoStreamConfiguration struct {
+	Profiles           []byte
+	Levels             []byte
+	PacketizationModes []byte
+	Attributes         []VideoAttributes
+}
+
+// AudioStreamConfiguration describes a supported audio format.
+type AudioStreamConfiguration struct {
+	CodecType   byte
+	Channels    byte
+	BitrateMode byte
+	SampleRate  byte
+}
+
+// videoStreamConfiguration returns the value of the Supported Video Stream Configuration characteristic.
+func (c StreamConfiguration) videoStreamConfiguration() []byte {
+	var profiles, levels, modes, attributes [][]byte
+	for _, p := range c.Video.Profiles {
+		profiles = append(profiles, []byte{p})
+	}
+	for _, l := range c.Video.Levels {
+		levels = append(levels, []byte{l})
+	}
+	for _, m := range c.Video.PacketizationModes {
+		modes = append(modes, []byte{m})
+	}
+	for _, a := range c.Video.Attributes {
+		attributes = append(attributes, a.tlv8())
+	}
+
+	params := tlv8List(tagVideoProfile, profiles)
+	params = append(params, tlv8List(tagVideoLevel, levels)...)
+	params = append(params, tlv8List(tagVideoPacketizationMode, modes)...)
+
+	codec := tlv8(tagCodecType, []byte{VideoCodecTypeH264})
+	codec = append(codec, tlv8(tagCodecParameters, params)...)
+	codec = append(codec, tlv8List(tagVideoAttributes, attributes)...)
+
+	return tlv8(tagCodecConfiguration, codec)
+}
+
+// audioStreamConfiguration returns the value of the Supported Audio Stream Configuration characteristic.
+func (c StreamConfiguration) audioStreamConfiguration() []byte {
+	var codecs [][]byte
+	for _, a := range c.Audio {
+		params := tlv8(tagAudioChannels, []byte{a.Channels})
+		params = append(params, tlv8(tagAudioBitrateMode, []byte{a.BitrateMode})...)
+		params = append(params, tlv8(tagAudioSampleRate, []byte{a.SampleRate})...)
+
+		codec := tlv8(tagCodecType, []byte{a.CodecType})
+		codec = append(codec, tlv8(tagCodecParameters, params)...)
+		codecs = append(codecs, codec)
+	}
+
+	var comfortNoise byte
+	if c.ComfortNoise == true {
+		comfortNoise = 1
+	}
+
+	b := tlv8List(tagCodecConfiguration, codecs)
+	return append(b, tlv8(tagAudioComfortNoise, []byte{comfortNoise})...)
+}
+
+// rtpConfiguration returns the value of the Supported RTP Configuration characteristic.
+func (c StreamConfiguration) rtpConfiguration() []byte {
+	var suites [][]byte
+	for _, s := range c.CryptoSuites {
+		suites = append(suites, []byte{s})
+	}
+
+	return tlv8List(tagSupportedCryptoSuites, suites)
+}
+
+// RTPParameters are the selected RTP parameters of a media stream.
+type RTPParameters struct {
+	PayloadType     byte
+	SSRC            uint32
+	MaxBitrate      uint16  // kbit/s
+	MinRTCPInterval float32 // s
+	MaxMTU          uint16  // video only
+}
+
+// VideoStreamParameters are the selected parameters of a video stream.
+type VideoStreamParameters struct {
+	CodecType         byte
+	Profile           byte
+	Level             byte
+	PacketizationMode byte
+	Attributes        VideoAttributes
+	RTP               RTPParameters
+}
+
+// AudioStreamParameters are the selected parameters of an audio stream.
+type AudioStreamParameters struct {
+	CodecType   byte
+	Channels    byte
+	BitrateMode byte
+	SampleRate  byte
+
+	// RTPTime is the duration of audio in one RTP packet in ms
+	RTPTime byte
+	RTP     RTPParameters
+
+	ComfortNoise            bool
+	ComfortNoisePayloadType byte
+}
+
+// parseRTPParameters returns the RTP parameters of a TLV8 value.
+func parseRTPParameters(b []byte) (RTPParameters, error) {
+	var p RTPParameters
+	c, err := tlv8Container(b)
+	if err != nil {
+		return p, err
+	}
+
+	p.PayloadType = c.GetByte(tagRTPPayloadType)
+	p.SSRC = uint32(uintFromBytes(c.GetBytes(tagRTPSSRC)))
+	p.MaxBitrate = uint16(uintFromBytes(c.GetBytes(tagRTPMaxBitrate)))
+	if b := c.GetBytes(tagRTPMinRTCPInterval); len(b) == 4 {
+		p.MinRTCPInterval = math.Float32frombits(binary.LittleEndian.Uint32(b))
+	}
+	p.MaxMTU = uint16(uintFromBytes(c.GetBytes(tagRTPMaxMTU)))
+
+	return p, nil
+}
+
+// parseVideoStreamParameters returns the parameters of a selected video stream.
+func parseVideoStreamParameters(b []byte) (VideoStreamParameters, error) {
+	var p VideoStreamParameters
+	c, err := tlv8Container(b)
+	if err != nil {
+		return p, err
+	}
+
+	p.CodecType = c.GetByte(tagCodecType)
+	params, err := tlv8Container(c.GetBytes(tagCodecParameters))
+	if err != nil {
+		return p, err
+	}
+	p.Profile = params.GetByte(tagVideoProfile)
+	p.Level = params.GetByte(tagVideoLevel)
+	p.PacketizationMode = params.GetByte(tagVideoPacketizationMode)
+
+	attributes, err := tlv8Container(c.GetBytes(tagVideoAttributes))
+	if err != nil {
+		return p, err
+	}
+	p.Attributes.Width = uint16(uintFromBytes(attributes.GetBytes(tagVideoWidth)))
+	p.Attributes.Height = uint16(uintFromBytes(attributes.GetBytes(tagVideoHeight)))
+	p.Attributes.Framerate = attributes.GetByte(tagVideoFramerate)
+
+	p.RTP, err = parseRTPParameters(c.GetBytes(tagVideoRTPParameters))
+
+	return p, err
+}
+
+// parseAudioStreamParameters returns the parameters of a selected audio stream.
+func parseAudioStreamParameters(b []byte) (AudioStreamParameters, error) {
+	var p AudioStreamParameters
+	c, err := tlv8Container(b)
+	if err != nil {
+		return p, err
+	}
+
+	p.CodecType = c.GetByte(tagCodecType)
+	params, err := tlv8Container(c.GetBytes(tagCodecParameters))
+	if err != nil {
+		return p, err
+	}
+	p.Channels = params.GetByte(tagAudioChannels)
+	p.BitrateMode = params.GetByte(tagAudioBitrateMode)
+	p.SampleRate = params.GetByte(tagAudioSampleRate)
+	p.RTPTime = params.GetByte(tagAudioRTPTime)
+
+	rtp := c.GetBytes(tagAudioRTPParameters)
+	if p.RTP, err = parseRTPParameters(rtp); err != nil {
+		return p, err
+	}
+
+	if rtpParams, err := tlv8Container(rtp); err == nil {
+		p.ComfortNoisePayloadType = rtpParams.GetByte(tagRTPComfortNoisePayloadType)
+	}
+	p.ComfortNoise = c.GetByte(tagAudioComfortNoiseEnabled) == 1
+
+	return p, nil
+}
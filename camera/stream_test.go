@@ -0,0 +1,140 @@
+package camera
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/brutella/hc/characteristic"
+)
+
+type testStreamHandler struct {
+	started *Stream
+	stopped *Stream
+}
+
+func (h *testStreamHandler) PrepareStream(s *Stream) error {
+	s.Accessory.VideoPort = 5000
+	s.Accessory.AudioPort = 5002
+	return nil
+}
+
+func (h *testStreamHandler) StartStream(s *Stream) error {
+	h.started = s
+	return nil
+}
+
+func (h *testStreamHandler) ReconfigureStream(s *Stream) error {
+	return nil
+}
+
+func (h *testStreamHandler) StopStream(s *Stream) {
+	h.stopped = s
+}
+
+func setupEndpointsRequest(sessionID []byte) []byte {
+	srtp := SRTPParameters{
+		CryptoSuite: CryptoSuiteAES128,
+		MasterKey:   bytes.Repeat([]byte{0x01}, 16),
+		MasterSalt:  bytes.Repeat([]byte{0x02}, 14),
+	}
+
+	address := tlv8(tagAddressIPVersion, []byte{ipVersion4})
+	address = append(address, tlv8(tagAddressIP, []byte("192.168.0.10"))...)
+	address = append(address, tlv8(tagAddressVideoRTPPort, uint16Bytes(51000))...)
+	address = append(address, tlv8(tagAddressAudioRTPPort, uint16Bytes(51002))...)
+
+	b := tlv8(tagEndpointsSessionID, sessionID)
+	b = append(b, tlv8(tagEndpointsAddress, address)...)
+	b = append(b, tlv8(tagEndpointsVideoSRTP, srtp.tlv8())...)
+	return append(b, tlv8(tagEndpointsAudioSRTP, srtp.tlv8())...)
+}
+
+func sessionControl(sessionID []byte, cmd byte) []byte {
+	control := tlv8(tagSessionControlID, sessionID)
+	control = append(control, tlv8(tagSessionControlCommand, []byte{cmd})...)
+
+	return tlv8(tagSelectedSessionControl, control)
+}
+
+func TestStream(t *testing.T) {
+	h := &testStreamHandler{}
+	m := NewStreamManager(h, StreamConfiguration{CryptoSuites: []byte{CryptoSuiteAES128}})
+	sessionID := bytes.Repeat([]byte{0xAB}, 16)
+
+	res, err := tlv8Container(m.setupEndpoints(setupEndpointsRequest(sessionID), characteristic.TestConn))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := res.GetByte(tagEndpointsStatus), endpointsStatusSuccess; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	audio, err := parseSRTPParameters(res.GetBytes(tagEndpointsAudioSRTP))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := len(audio.MasterKey), 16; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	s := m.Stream()
+	if is, want := s.Controller.AudioPort, uint16(51002); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if bytes.Equal(s.IncomingAudioSRTP.MasterKey, audio.MasterKey) == false {
+		t.Fatal("incoming audio key does not match response")
+	}
+
+	audioParams := tlv8(tagAudioSampleRate, []byte{AudioSampleRate16Khz})
+	audioParams = append(audioParams, tlv8(tagAudioRTPTime, []byte{20})...)
+	selectedAudio := tlv8(tagCodecType, []byte{AudioCodecTypeOpus})
+	selectedAudio = append(selectedAudio, tlv8(tagCodecParameters, audioParams)...)
+	selectedAudio = append(selectedAudio, tlv8(tagAudioRTPParameters, tlv8(tagRTPPayloadType, []byte{110}))...)
+
+	b := sessionControl(sessionID, sessionCommandStart)
+	b = append(b, tlv8(tagSelectedAudioStream, selectedAudio)...)
+	if err := m.selectStreamConfiguration(b); err != nil {
+		t.Fatal(err)
+	}
+
+	if h.started == nil {
+		t.Fatal("stream not started")
+	}
+
+	if is, want := h.started.Audio.CodecType, AudioCodecTypeOpus; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := h.started.Audio.RTPTime, byte(20); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := h.started.Audio.RTP.PayloadType, byte(110); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// A second controller cannot set up a stream
+	res, err = tlv8Container(m.setupEndpoints(setupEndpointsRequest([]byte{0x01}), characteristic.TestConn))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := res.GetByte(tagEndpointsStatus), endpointsStatusBusy; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if err := m.selectStreamConfiguration(sessionControl(sessionID, sessionCommandEnd)); err != nil {
+		t.Fatal(err)
+	}
+
+	if h.stopped == nil {
+		t.Fatal("stream not stopped")
+	}
+
+	if m.Stream() != nil {
+		t.Fatal("stream not removed")
+	}
+}
@@ -0,0 +1,62 @@
+package camera
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/brutella/hc/util"
+)
+
+// tlv8 returns the bytes of a container with a single value.
+func tlv8(tag byte, value []byte) []byte {
+	c := util.NewTLV8Container()
+	c.SetBytes(tag, value)
+
+	return c.BytesBuffer().Bytes()
+}
+
+// tlv8List returns the values with the same tag separated by empty delimiters.
+func tlv8List(tag byte, values [][]byte) []byte {
+	var b bytes.Buffer
+	for i, v := range values {
+		if i > 0 {
+			b.Write([]byte{0x00, 0x00})
+		}
+		b.Write(tlv8(tag, v))
+	}
+
+	return b.Bytes()
+}
+
+// tlv8Container returns the container of b.
+func tlv8Container(b []byte) (util.Container, error) {
+	return util.NewTLV8ContainerFromReader(bytes.NewBuffer(b))
+}
+
+func uint16Bytes(v uint16) []byte {
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, v)
+	return b
+}
+
+func uint32Bytes(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, v)
+	return b
+}
+
+func uint64Bytes(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, v)
+	return b
+}
+
+// uintFromBytes returns the little endian integer of b, which has up to 8 bytes.
+func uintFromBytes(b []byte) uint64 {
+	var v uint64
+	for i := len(b) - 1; i >= 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+
+	return v
+}
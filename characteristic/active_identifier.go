@@ -0,0 +1,18 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeActiveIdentifier = "E7"
+
+type ActiveIdentifier struct {
+	*Int
+}
+
+func NewActiveIdentifier() *ActiveIdentifier {
+	char := NewInt(TypeActiveIdentifier)
+	char.Format = FormatUInt32
+	char.Perms = []string{PermRead, PermWrite, PermEvents}
+
+	char.SetValue(0)
+
+	return &ActiveIdentifier{char}
+}
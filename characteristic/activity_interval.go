@@ -0,0 +1,20 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeActivityInterval = "23B"
+
+type ActivityInterval struct {
+	*Int
+}
+
+func NewActivityInterval() *ActivityInterval {
+	char := NewInt(TypeActivityInterval)
+	char.Format = FormatUInt32
+	char.Perms = []string{PermRead, PermEvents}
+	char.SetMinValue(0)
+
+	char.SetStepValue(1)
+	char.SetValue(0)
+
+	return &ActivityInterval{char}
+}
@@ -0,0 +1,20 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeButtonEvent = "126"
+
+type ButtonEvent struct {
+	*Bytes
+}
+
+func NewButtonEvent() *ButtonEvent {
+	char := NewBytes(TypeButtonEvent)
+	char.Format = FormatTLV8
+	char.Perms = []string{PermRead, PermEvents}
+
+	char.SetValue([]byte{})
+
+	char.AlwaysNotify = true
+
+	return &ButtonEvent{char}
+}
@@ -1,11 +1,11 @@
 package characteristic
 
 import (
-	"bytes"
 	"encoding/base64"
-	"github.com/brutella/hc/util"
 )
 
+// Bytes is a characteristic with format tlv8 or data.
+// The value is encoded as base64 string.
 type Bytes struct {
 	*Characteristic
 }
@@ -16,44 +16,24 @@ func NewBytes(typ string) *Bytes {
 	return &Bytes{s}
 }
 
+// SetValue sets the raw bytes of the characteristic, e.g. TLV8 encoded data.
 func (bs *Bytes) SetValue(b []byte) {
-	bs.UpdateValue(tlv8Base64FromBytes(b))
+	bs.UpdateValue(base64.StdEncoding.EncodeToString(b))
 }
 
+// SetMaxDataLen sets the max length of data values in bytes.
+// Longer values from clients are ignored.
+func (bs *Bytes) SetMaxDataLen(max int) {
+	bs.MaxDataLen = max
+}
+
+// GetValue returns the raw bytes of the characteristic.
 func (bs *Bytes) GetValue() []byte {
 	if str, ok := bs.Value.(string); ok == true {
-		b, _ := bytesFromTLV8Base64(str)
-		return b
+		if b, err := base64.StdEncoding.DecodeString(str); err == nil {
+			return b
+		}
 	}
 
 	return []byte{}
 }
-
-func tlv8FromBytes(b []byte) []byte {
-	c := util.NewTLV8Container()
-	c.SetBytes(0x00, b)
-	return c.BytesBuffer().Bytes()
-}
-
-func bytesFromTLV8(b []byte) ([]byte, error) {
-	buf := bytes.NewBuffer(b)
-	c, err := util.NewTLV8ContainerFromReader(buf)
-	if err != nil {
-		return nil, err
-	}
-
-	return c.GetBytes(0x00), nil
-}
-
-func tlv8Base64FromBytes(b []byte) string {
-	return base64.StdEncoding.EncodeToString(tlv8FromBytes(b))
-}
-
-func bytesFromTLV8Base64(b64 string) ([]byte, error) {
-	b, err := base64.StdEncoding.DecodeString(b64)
-	if err != nil {
-		return nil, err
-	}
-
-	return bytesFromTLV8(b)
-}
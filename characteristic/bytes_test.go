@@ -2,17 +2,17 @@ package characteristic
 
 import (
 	"encoding/base64"
+	"net"
 	"reflect"
 	"testing"
 )
 
 func TestBytesEncoding(t *testing.T) {
-	val := []byte{0xFA, 0xAA}
+	val := []byte{0x01, 0x02, 0xFA, 0xAA}
 	b := NewBytes(TypeLogs)
 	b.SetValue(val)
 
-	tlv8 := []byte{0x00, 0x02, 0xFA, 0xAA}
-	expect := base64.StdEncoding.EncodeToString(tlv8)
+	expect := base64.StdEncoding.EncodeToString(val)
 
 	if x := b.Value; reflect.DeepEqual(x, expect) == false {
 		t.Fatal(x)
@@ -22,3 +22,53 @@ func TestBytesEncoding(t *testing.T) {
 		t.Fatal(x)
 	}
 }
+
+func TestBytesRemoteUpdate(t *testing.T) {
+	b := NewBytes(TypeLogs)
+	b.Format = FormatTLV8
+	b.Perms = PermsAll()
+
+	val := []byte{0x01, 0x01, 0x00}
+	b.UpdateValueFromConnection(base64.StdEncoding.EncodeToString(val), &net.TCPConn{})
+
+	if x := b.GetValue(); reflect.DeepEqual(x, val) == false {
+		t.Fatal(x)
+	}
+
+	// Invalid base64 values are rejected
+	err := b.UpdateValueFromConnection("not base64!", &net.TCPConn{})
+	if se, ok := err.(*StatusError); ok == false || se.Status != statusInvalidValueInRequest {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if x := b.GetValue(); reflect.DeepEqual(x, val) == false {
+		t.Fatal(x)
+	}
+}
+
+func TestBytesUpdateWithBytes(t *testing.T) {
+	b := NewBytes(TypeLogs)
+	b.Format = FormatData
+	b.UpdateValue([]byte{0xAB})
+
+	if is, want := b.Value, base64.StdEncoding.EncodeToString([]byte{0xAB}); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestBytesMaxDataLen(t *testing.T) {
+	b := NewBytes(TypeLogs)
+	b.Format = FormatData
+	b.Perms = PermsAll()
+	b.SetMaxDataLen(2)
+
+	b.UpdateValueFromConnection(base64.StdEncoding.EncodeToString([]byte{0x01, 0x02, 0x03}), &net.TCPConn{})
+	if is, want := len(b.GetValue()), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	b.UpdateValueFromConnection(base64.StdEncoding.EncodeToString([]byte{0x01, 0x02}), &net.TCPConn{})
+	if is, want := len(b.GetValue()), 2; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,18 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeCameraOperatingModeIndicator = "21D"
+
+type CameraOperatingModeIndicator struct {
+	*Bool
+}
+
+func NewCameraOperatingModeIndicator() *CameraOperatingModeIndicator {
+	char := NewBool(TypeCameraOperatingModeIndicator)
+	char.Format = FormatBool
+	char.Perms = []string{PermRead, PermWrite, PermEvents}
+
+	char.SetValue(false)
+
+	return &CameraOperatingModeIndicator{char}
+}
@@ -1,14 +1,36 @@
 package characteristic
 
 import (
+	"encoding/base64"
 	"fmt"
+	"github.com/brutella/log"
 	"github.com/gosexy/to"
+	"math"
 	"net"
 	"reflect"
 )
 
 type ConnChangeFunc func(conn net.Conn, c *Characteristic, newValue, oldValue interface{})
 type ChangeFunc func(c *Characteristic, newValue, oldValue interface{})
+type BeforeUpdateFunc func(newValue interface{}) error
+type ReadFunc func() (interface{}, error)
+
+// CommitFunc calls set, which sets a new value, and returns true.
+// It returns false without calling set when the value must be dropped,
+// e.g. because the deadline of a request expired while a callback was running.
+type CommitFunc func(set func()) bool
+
+// ChangeContext describes where a value change originated.
+type ChangeContext struct {
+	// Source is SourceLocal, SourceRemote or SourceRestore.
+	Source string
+
+	// Conn is the connection of the client which wrote the value, or nil.
+	Conn net.Conn
+}
+
+// SourceChangeFunc is called with the context of a value change.
+type SourceChangeFunc func(ctx ChangeContext, c *Characteristic, newValue, oldValue interface{})
 
 // Characteristic is a HomeKit characteristic.
 type Characteristic struct {
@@ -21,16 +43,31 @@ type Characteristic struct {
 	Format string      `json:"format"`
 	Unit   string      `json:"unit,omitempty"`
 
-	MaxLen    int         `json:"maxLen,omitempty"`
-	MaxValue  interface{} `json:"maxValue,omitempty"`
-	MinValue  interface{} `json:"minValue,omitempty"`
-	StepValue interface{} `json:"minStep,omitempty"`
+	MaxLen     int         `json:"maxLen,omitempty"`     // max length of strings
+	MaxDataLen int         `json:"maxDataLen,omitempty"` // max length of data in bytes
+	MaxValue   interface{} `json:"maxValue,omitempty"`
+	MinValue   interface{} `json:"minValue,omitempty"`
+	StepValue  interface{} `json:"minStep,omitempty"`
 
 	// unused
 	Events bool `json:"-"`
 
+	// AlwaysNotify is true when change functions must be called even when the value
+	// didn't change, e.g. for characteristics which represent button presses.
+	AlwaysNotify bool `json:"-"`
+
 	connValueUpdateFuncs []ConnChangeFunc
 	valueChangeFuncs     []ChangeFunc
+	sourceChangeFuncs    []SourceChangeFunc
+	beforeUpdateFuncs    []BeforeUpdateFunc
+	rangeFuncs           []RangeFunc
+	readFunc             ReadFunc
+
+	// history records the changes of the value, or is nil
+	history *History
+
+	// localizedValues are the values by locale
+	localizedValues map[string]interface{}
 }
 
 // writeOnlyPerms returns true when permissions only include write permission
@@ -66,11 +103,29 @@ func NewCharacteristic(typ string) *Characteristic {
 }
 
 func (c *Characteristic) UpdateValue(value interface{}) {
-	c.updateValue(value, nil)
+	c.updateValue(value, nil, SourceLocal, nil)
+}
+
+// UpdateValueFromConnection sets the value written by a client over conn.
+// A *StatusError is returned when the value is invalid
+// or when a function registered with OnBeforeRemoteUpdate rejected the value.
+func (c *Characteristic) UpdateValueFromConnection(value interface{}, conn net.Conn) error {
+	return c.updateValue(value, conn, SourceRemote, nil)
 }
 
-func (c *Characteristic) UpdateValueFromConnection(value interface{}, conn net.Conn) {
-	c.updateValue(value, conn)
+// CommitValueFromConnection sets the value written by a client over conn like
+// UpdateValueFromConnection, but sets the value through commit after the functions
+// registered with OnBeforeRemoteUpdate accepted it. When commit drops the value,
+// the value is not set and no change functions are called.
+func (c *Characteristic) CommitValueFromConnection(value interface{}, conn net.Conn, commit CommitFunc) error {
+	return c.updateValue(value, conn, SourceRemote, commit)
+}
+
+// RestoreValue sets a value which was restored from persistent storage, e.g. after a restart.
+// The functions of OnValueUpdate are called like for local changes, and the functions
+// of OnValueChange are called with SourceRestore.
+func (c *Characteristic) RestoreValue(value interface{}) {
+	c.updateValue(value, nil, SourceRestore, nil)
 }
 
 func (c *Characteristic) SetEventsEnabled(enable bool) {
@@ -89,6 +144,66 @@ func (c *Characteristic) OnValueUpdateFromConn(fn ConnChangeFunc) {
 	c.connValueUpdateFuncs = append(c.connValueUpdateFuncs, fn)
 }
 
+// OnValueChange calls fn for every value change with the source of the change,
+// e.g. to not write values back to the hardware which reported them.
+func (c *Characteristic) OnValueChange(fn SourceChangeFunc) {
+	c.sourceChangeFuncs = append(c.sourceChangeFuncs, fn)
+}
+
+// OnBeforeRemoteUpdate calls fn before a value written by a client is set.
+// When fn returns an error, the value is not set and no change functions are called.
+// Return a *StatusError to respond with a specific HAP status code.
+func (c *Characteristic) OnBeforeRemoteUpdate(fn BeforeUpdateFunc) {
+	c.beforeUpdateFuncs = append(c.beforeUpdateFuncs, fn)
+}
+
+// OnValueRead calls fn when a client reads the value, e.g. to read the current value from hardware.
+// The returned value is set as the value of the characteristic.
+// Return a *StatusError to respond with a specific HAP status code.
+func (c *Characteristic) OnValueRead(fn ReadFunc) {
+	c.readFunc = fn
+}
+
+// ReadValue returns the value which is read by the function set with OnValueRead,
+// or the current value when no function is set.
+func (c *Characteristic) ReadValue() (interface{}, error) {
+	return c.CommitReadValue(nil)
+}
+
+// CommitReadValue returns the value like ReadValue, but reads and sets the value
+// through commit. When commit drops the value, nil is returned.
+func (c *Characteristic) CommitReadValue(commit CommitFunc) (interface{}, error) {
+	if commit == nil {
+		commit = setNow
+	}
+
+	var value interface{}
+	if c.readFunc == nil {
+		commit(func() {
+			value = c.Value
+		})
+		return value, nil
+	}
+
+	v, err := c.readFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	commit(func() {
+		c.UpdateValue(v)
+		value = c.Value
+	})
+
+	return value, nil
+}
+
+// setNow is a CommitFunc which never drops values.
+func setNow(set func()) bool {
+	set()
+	return true
+}
+
 // Equal returns true when receiver has the values as the argument.
 func (c *Characteristic) Equal(other interface{}) bool {
 	if characteristic, ok := other.(*Characteristic); ok == true {
@@ -97,7 +212,7 @@ func (c *Characteristic) Equal(other interface{}) bool {
 		value := fmt.Sprintf("%+v", c.Value)
 		otherValue := fmt.Sprintf("%+v", characteristic.Value)
 
-		return value == otherValue && c.ID == characteristic.ID && c.Type == characteristic.Type && len(c.Perms) == len(characteristic.Perms) && c.Description == characteristic.Description && c.Format == characteristic.Format && c.Unit == characteristic.Unit && c.MaxLen == characteristic.MaxLen && c.MaxValue == characteristic.MaxValue && c.MinValue == characteristic.MinValue && c.StepValue == characteristic.StepValue && c.Events == characteristic.Events
+		return value == otherValue && c.ID == characteristic.ID && c.Type == characteristic.Type && len(c.Perms) == len(characteristic.Perms) && c.Description == characteristic.Description && c.Format == characteristic.Format && c.Unit == characteristic.Unit && c.MaxLen == characteristic.MaxLen && c.MaxDataLen == characteristic.MaxDataLen && c.MaxValue == characteristic.MaxValue && c.MinValue == characteristic.MinValue && c.StepValue == characteristic.StepValue && c.Events == characteristic.Events
 	}
 
 	return false
@@ -112,6 +227,11 @@ func (c *Characteristic) GetID() int64 {
 	return c.ID
 }
 
+// IsWritable returns true when the permissions allow clients to write the value.
+func (c *Characteristic) IsWritable() bool {
+	return c.hasWritePerms()
+}
+
 // Private
 
 func (c *Characteristic) isWriteOnly() bool {
@@ -127,7 +247,18 @@ func (c *Characteristic) hasWritePerms() bool {
 // E.g. Type of characteristic value int, calling updateValue("10.5") sets the value to int(10)
 //
 // When permissions are write only, this methods does not set the Value field.
-func (c *Characteristic) updateValue(value interface{}, conn net.Conn) {
+// The value is set through commit, which may drop the value (see CommitFunc).
+func (c *Characteristic) updateValue(value interface{}, conn net.Conn, source string, commit CommitFunc) error {
+	// Values of tlv8 and data characteristics are base64 encoded strings
+	if c.Format == FormatTLV8 || c.Format == FormatData {
+		if b, ok := value.([]byte); ok == true {
+			value = base64.StdEncoding.EncodeToString(b)
+		} else if str, ok := value.(string); ok == false || isBase64(str) == false {
+			log.Printf("[WARN] Invalid %s value %v\n", c.Format, value)
+			return NewStatusError(statusInvalidValueInRequest, fmt.Sprintf("Invalid %s value", c.Format))
+		}
+	}
+
 	if c.Value != nil {
 		if converted, err := to.Convert(value, reflect.TypeOf(c.Value).Kind()); err == nil {
 			value = converted
@@ -139,24 +270,63 @@ func (c *Characteristic) updateValue(value interface{}, conn net.Conn) {
 	case FormatFloat:
 		value = c.boundFloat64Value(value.(float64))
 	case FormatUInt8, FormatUInt16, FormatUInt32, FormatUInt64, FormatInt32, FormatInt64:
-		value = c.boundIntValue(value.(int))
+		switch v := value.(type) {
+		case int:
+			value = c.boundIntValue(v)
+		case uint64:
+			value = c.boundUInt64Value(v)
+		}
+	}
+
+	// Value must be a multiple of the step value and valid for the unit
+	value = c.roundToStep(value)
+	if c.isValidForUnit(value) == false {
+		log.Printf("[WARN] Value %v of characteristic %d is invalid for unit %s\n", value, c.ID, c.Unit)
+		return NewStatusError(statusInvalidValueInRequest, fmt.Sprintf("Invalid value %v for unit %s", value, c.Unit))
 	}
 
-	// Ignore when new value is same
-	if c.Value == value {
-		return
+	// Ignore when new value is same, so that no events are sent for unchanged values.
+	// Event-only characteristics (e.g. button presses) are updated anyway.
+	if c.AlwaysNotify == false && reflect.DeepEqual(c.Value, value) == true {
+		return nil
 	}
 
 	// Ignore new values from remote when permissions don't allow write
 	if c.hasWritePerms() == false && conn != nil {
-		return
+		return nil
 	}
 
-	old := c.Value
-	if c.isWriteOnly() == false {
-		c.Value = value
-	} else {
-		c.Value = nil
+	// Reject new values from remote which exceed the max length
+	if conn != nil && c.exceedsMaxLen(value) == true {
+		log.Printf("[WARN] Value of characteristic %d exceeds max length\n", c.ID)
+		return NewStatusError(statusInvalidValueInRequest, "Value exceeds max length")
+	}
+
+	// Functions can reject new values from remote before they are set
+	if conn != nil {
+		for _, fn := range c.beforeUpdateFuncs {
+			if err := fn(value); err != nil {
+				return err
+			}
+		}
+	}
+
+	if commit == nil {
+		commit = setNow
+	}
+
+	var old interface{}
+	set := func() {
+		old = c.Value
+		if c.isWriteOnly() == false {
+			c.Value = value
+		} else {
+			c.Value = nil
+		}
+		c.recordChange(conn, source, old, value)
+	}
+	if commit(set) == false {
+		return nil
 	}
 
 	if conn != nil {
@@ -164,6 +334,34 @@ func (c *Characteristic) updateValue(value interface{}, conn net.Conn) {
 	} else {
 		c.onValueUpdate(c.valueChangeFuncs, value, old)
 	}
+
+	ctx := ChangeContext{Source: source, Conn: conn}
+	for _, fn := range c.sourceChangeFuncs {
+		fn(ctx, c, value, old)
+	}
+
+	return nil
+}
+
+// exceedsMaxLen returns true when value is longer than the max length
+// of strings or data. The max length of strings is 64 when not specified.
+func (c *Characteristic) exceedsMaxLen(value interface{}) bool {
+	switch c.Format {
+	case FormatString:
+		max := c.MaxLen
+		if max == 0 {
+			max = DefaultMaxLen
+		}
+		if str, ok := value.(string); ok == true {
+			return len(str) > max
+		}
+	case FormatData:
+		if str, ok := value.(string); ok == true && c.MaxDataLen > 0 {
+			return len(decodeBase64(str)) > c.MaxDataLen
+		}
+	}
+
+	return false
 }
 
 func (c *Characteristic) onValueUpdate(funcs []ChangeFunc, newValue, oldValue interface{}) {
@@ -199,5 +397,99 @@ func (c *Characteristic) boundIntValue(value int) interface{} {
 		value = min
 	}
 
+	// Value must also be within the range of the format
+	if min, max, ok := formatBounds(c.Format); ok == true {
+		if int64(value) > max {
+			value = int(max)
+		} else if int64(value) < min {
+			value = int(min)
+		}
+	}
+
+	return value
+}
+
+func (c *Characteristic) boundUInt64Value(value uint64) interface{} {
+	min, minOK := c.MinValue.(uint64)
+	max, maxOK := c.MaxValue.(uint64)
+	if maxOK == true && value > max {
+		value = max
+	} else if minOK == true && value < min {
+		value = min
+	}
+
+	return value
+}
+
+// roundToStep rounds numeric values to the next multiple of the step value.
+func (c *Characteristic) roundToStep(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float64:
+		step, ok := c.StepValue.(float64)
+		if ok == false {
+			return v
+		}
+		min, _ := c.MinValue.(float64)
+		r := roundFloatToStep(v, min, step)
+		if max, ok := c.MaxValue.(float64); ok == true && r > max {
+			r = roundFloatToStep(r-step, min, step)
+		}
+		return r
+	case int:
+		step, ok := c.StepValue.(int)
+		if ok == false {
+			return v
+		}
+		min, _ := c.MinValue.(int)
+		r := roundIntToStep(v, min, step)
+		if max, ok := c.MaxValue.(int); ok == true && r > max {
+			r -= step
+		}
+		return r
+	}
+
 	return value
 }
+
+// isValidForUnit returns false when value is a number which is invalid for the unit.
+func (c *Characteristic) isValidForUnit(value interface{}) bool {
+	switch v := value.(type) {
+	case float64:
+		return validForUnit(c.Unit, v)
+	case int:
+		return validForUnit(c.Unit, float64(v))
+	case uint64:
+		return validForUnit(c.Unit, float64(v))
+	}
+
+	return true
+}
+
+// formatBounds returns the range of int values of an integer format.
+// There is no range for int64 because it covers every int value.
+func formatBounds(format string) (int64, int64, bool) {
+	switch format {
+	case FormatUInt8:
+		return 0, math.MaxUint8, true
+	case FormatUInt16:
+		return 0, math.MaxUint16, true
+	case FormatUInt32:
+		return 0, math.MaxUint32, true
+	case FormatInt32:
+		return math.MinInt32, math.MaxInt32, true
+	case FormatUInt64:
+		return 0, math.MaxInt64, true
+	}
+
+	return 0, 0, false
+}
+
+func isBase64(str string) bool {
+	_, err := base64.StdEncoding.DecodeString(str)
+	return err == nil
+}
+
+func decodeBase64(str string) []byte {
+	b, _ := base64.StdEncoding.DecodeString(str)
+	return b
+}
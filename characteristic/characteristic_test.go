@@ -77,6 +77,44 @@ func TestCharacteristicRemoteDelegate(t *testing.T) {
 	}
 }
 
+func TestCharacteristicBeforeRemoteUpdate(t *testing.T) {
+	c := NewCharacteristic(TypeOn)
+	c.Perms = PermsAll()
+	c.Value = 5
+
+	c.OnBeforeRemoteUpdate(func(new interface{}) error {
+		if new == 10 {
+			return NewStatusError(-70403, "busy")
+		}
+		return nil
+	})
+
+	var called bool
+	c.OnValueUpdateFromConn(func(conn net.Conn, c *Characteristic, new, old interface{}) {
+		called = true
+	})
+
+	if err := c.UpdateValueFromConnection(10, TestConn); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if is, want := c.Value, 5; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := called, false; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if err := c.UpdateValueFromConnection(20, TestConn); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := c.Value, 20; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
 func TestNoValueChange(t *testing.T) {
 	c := NewCharacteristic(TypeOn)
 	c.Value = 5
@@ -101,6 +139,22 @@ func TestNoValueChange(t *testing.T) {
 	}
 }
 
+func TestAlwaysNotify(t *testing.T) {
+	c := NewProgrammableSwitchEvent()
+
+	count := 0
+	c.OnValueUpdate(func(c *Characteristic, new, old interface{}) {
+		count++
+	})
+
+	c.SetValue(0)
+	c.SetValue(0)
+
+	if is, want := count, 2; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
 func TestReadOnlyValue(t *testing.T) {
 	c := NewCharacteristic(TypeOn)
 	c.Perms = PermsRead()
@@ -144,3 +198,54 @@ func TestEqual(t *testing.T) {
 		t.Fatal("characteristics not the same")
 	}
 }
+
+func TestReadValue(t *testing.T) {
+	c := NewOn()
+	c.OnValueRead(func() (interface{}, error) {
+		return true, nil
+	})
+
+	v, err := c.ReadValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := v, true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestValueChangeSource(t *testing.T) {
+	c := NewCharacteristic(TypeOn)
+	c.Perms = PermsAll()
+	c.Value = 5
+
+	var sources []string
+	var conns []net.Conn
+	c.OnValueChange(func(ctx ChangeContext, c *Characteristic, new, old interface{}) {
+		sources = append(sources, ctx.Source)
+		conns = append(conns, ctx.Conn)
+	})
+
+	c.UpdateValueFromConnection(10, TestConn)
+	c.UpdateValue(20)
+	c.RestoreValue(30)
+
+	if is, want := len(sources), 3; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	for i, want := range []string{SourceRemote, SourceLocal, SourceRestore} {
+		if is := sources[i]; is != want {
+			t.Fatalf("is=%v want=%v", is, want)
+		}
+	}
+
+	if is, want := conns[0], TestConn; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := c.Value, 30; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -4,6 +4,8 @@ const (
 	PermRead   = "pr" // can be read
 	PermWrite  = "pw" // can be written
 	PermEvents = "ev" // sends events
+
+	PermWriteResponse = "wr" // returns the value in the write response
 )
 
 // PermsAll returns read, write and event permissions
@@ -31,11 +33,16 @@ func PermsWriteOnly() []string {
 	return []string{PermWrite}
 }
 
+// DefaultMaxLen is the max length of string values when MaxLen is not specified
+const DefaultMaxLen = 64
+
 // HAP characteristic units
 const (
 	UnitPercentage = "percentage"
 	UnitArcDegrees = "arcdegrees"
 	UnitCelsius    = "celsius"
+	UnitLux        = "lux"
+	UnitSeconds    = "seconds"
 )
 
 // HAP characterisitic formats
@@ -50,4 +57,5 @@ const (
 	FormatUInt64 = "uint64"
 	FormatInt64  = "int64"
 	FormatTLV8   = "tlv8"
+	FormatData   = "data"
 )
@@ -0,0 +1,84 @@
+package characteristic
+
+// Toggle inverts the value and returns the new value.
+func (c *Bool) Toggle() bool {
+	value := c.GetValue() == false
+	c.SetValue(value)
+
+	return value
+}
+
+// OnRise calls fn when the value changed from false to true, locally or by a client.
+func (c *Bool) OnRise(fn func()) {
+	onEdge(c.Characteristic, true, fn)
+}
+
+// OnFall calls fn when the value changed from true to false, locally or by a client.
+func (c *Bool) OnFall(fn func()) {
+	onEdge(c.Characteristic, false, fn)
+}
+
+// BoolAdapter accesses an integer characteristic with two states like a bool characteristic,
+// e.g. Contact Sensor State, whose value is true for ContactSensorStateContactNotDetected.
+type BoolAdapter struct {
+	*Int
+
+	trueValue  int
+	falseValue int
+}
+
+// NewBoolAdapter returns an adapter of c, whose value is true for trueValue and false for falseValue.
+func NewBoolAdapter(c *Int, trueValue, falseValue int) *BoolAdapter {
+	return &BoolAdapter{Int: c, trueValue: trueValue, falseValue: falseValue}
+}
+
+// SetValue sets the value of the characteristic to trueValue or falseValue.
+func (a *BoolAdapter) SetValue(value bool) {
+	if value == true {
+		a.Int.SetValue(a.trueValue)
+	} else {
+		a.Int.SetValue(a.falseValue)
+	}
+}
+
+// GetValue returns true when the value of the characteristic is trueValue.
+func (a *BoolAdapter) GetValue() bool {
+	return a.Int.GetValue() == a.trueValue
+}
+
+// Toggle inverts the value and returns the new value.
+func (a *BoolAdapter) Toggle() bool {
+	value := a.GetValue() == false
+	a.SetValue(value)
+
+	return value
+}
+
+// OnRise calls fn when the value changed to trueValue.
+func (a *BoolAdapter) OnRise(fn func()) {
+	a.OnValueChange(func(ctx ChangeContext, c *Characteristic, new, old interface{}) {
+		if new == a.trueValue && old != a.trueValue {
+			fn()
+		}
+	})
+}
+
+// OnFall calls fn when the value changed from trueValue to falseValue.
+func (a *BoolAdapter) OnFall(fn func()) {
+	a.OnValueChange(func(ctx ChangeContext, c *Characteristic, new, old interface{}) {
+		if new == a.falseValue && old == a.trueValue {
+			fn()
+		}
+	})
+}
+
+// onEdge calls fn when the bool value of c changed to value.
+func onEdge(c *Characteristic, value bool, fn func()) {
+	c.OnValueChange(func(ctx ChangeContext, c *Characteristic, new, old interface{}) {
+		if n, ok := new.(bool); ok == true && n == value {
+			if o, ok := old.(bool); ok == false || o != value {
+				fn()
+			}
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package characteristic
+
+import (
+	"testing"
+)
+
+func TestBoolEdges(t *testing.T) {
+	on := NewOn()
+
+	rises, falls := 0, 0
+	on.OnRise(func() { rises++ })
+	on.OnFall(func() { falls++ })
+
+	if is, want := on.Toggle(), true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// Unchanged values are not an edge
+	on.SetValue(true)
+	on.UpdateValueFromConnection(false, TestConn)
+
+	if is, want := rises, 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := falls, 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestBoolAdapter(t *testing.T) {
+	state := NewContactSensorState()
+	open := NewBoolAdapter(state.Int, ContactSensorStateContactNotDetected, ContactSensorStateContactDetected)
+
+	rises := 0
+	open.OnRise(func() { rises++ })
+
+	if is, want := open.Toggle(), true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := state.GetValue(), ContactSensorStateContactNotDetected; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := rises, 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
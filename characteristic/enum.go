@@ -0,0 +1,114 @@
+package characteristic
+
+import (
+	"fmt"
+	"sort"
+)
+
+// statusInvalidValueInRequest is the HAP status code of invalid values (see netio.StatusInvalidValueInRequest)
+const statusInvalidValueInRequest = -70410
+
+// State is a valid value of an enum characteristic, e.g. CurrentDoorStateOpen.
+// It is an alias of int, so that the generated constants can be used as states.
+type State = int
+
+// Enum is an integer characteristic with named valid values, e.g. Current Door State.
+// The names are defined by the characteristic type or set with NewEnumWithNames.
+type Enum struct {
+	*Int
+
+	names map[int]string
+}
+
+// ValidValues returns the names of the valid values of the characteristic type typ,
+// e.g. TypeCurrentDoorState, or nil if the type has no defined valid values.
+func ValidValues(typ string) map[int]string {
+	values, ok := validValues[typ]
+	if ok == false {
+		return nil
+	}
+
+	result := map[int]string{}
+	for v, name := range values {
+		result[v] = name
+	}
+
+	return result
+}
+
+// NewEnum returns an enum of c, e.g. svc.TargetDoorState.Int, with the valid values
+// of the characteristic type. Values from clients which are not valid are rejected.
+func NewEnum(c *Int) *Enum {
+	return NewEnumWithNames(c, ValidValues(c.Type))
+}
+
+// NewEnumWithNames returns an enum of c, whose valid values are the keys of names.
+// Use this for vendor characteristics or to only allow a subset of the valid values,
+// e.g. if a lock doesn't support the jammed state.
+func NewEnumWithNames(c *Int, names map[int]string) *Enum {
+	e := &Enum{Int: c, names: names}
+
+	c.OnBeforeRemoteUpdate(func(value interface{}) error {
+		if v, ok := value.(int); ok == false || e.IsValid(v) == false {
+			return NewStatusError(statusInvalidValueInRequest, fmt.Sprintf("Invalid state %v", value))
+		}
+
+		return nil
+	})
+
+	return e
+}
+
+// State returns the current state.
+func (e *Enum) State() State {
+	return e.GetValue()
+}
+
+// SetState sets the state. An error is returned when the state is not valid.
+func (e *Enum) SetState(s State) error {
+	if e.IsValid(s) == false {
+		return fmt.Errorf("Invalid state %d of characteristic %s", s, e.Type)
+	}
+
+	e.SetValue(s)
+
+	return nil
+}
+
+// IsValid returns true when s is a valid state.
+func (e *Enum) IsValid(s State) bool {
+	_, ok := e.names[s]
+	return ok
+}
+
+// States returns the valid states in ascending order.
+func (e *Enum) States() []State {
+	var states []State
+	for v := range e.names {
+		states = append(states, v)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i] < states[j]
+	})
+
+	return states
+}
+
+// Name returns the name of s, e.g. "Open", or an empty string if s is not valid.
+func (e *Enum) Name(s State) string {
+	return e.names[s]
+}
+
+// String returns the name of the current state.
+func (e *Enum) String() string {
+	return e.Name(e.State())
+}
+
+// OnState calls fn with the new state, when the state was changed locally or by a client.
+func (e *Enum) OnState(fn func(State)) {
+	e.OnValueChange(func(ctx ChangeContext, c *Characteristic, new, old interface{}) {
+		if v, ok := new.(int); ok == true {
+			fn(v)
+		}
+	})
+}
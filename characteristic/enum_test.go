@@ -0,0 +1,46 @@
+package characteristic
+
+import (
+	"testing"
+)
+
+func TestEnum(t *testing.T) {
+	e := NewEnum(NewTargetDoorState().Int)
+
+	var states []State
+	e.OnState(func(s State) {
+		states = append(states, s)
+	})
+
+	if err := e.SetState(TargetDoorStateClosed); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := e.SetState(5); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if err := e.UpdateValueFromConnection(7, TestConn); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if err := e.UpdateValueFromConnection(TargetDoorStateOpen, TestConn); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := len(states), 2; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := e.State(), TargetDoorStateOpen; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := e.String(), "Open"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := len(e.States()), 2; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,9 @@
+package eve
+
+// Units of Eve characteristics
+const (
+	UnitWatt         = "W"
+	UnitKilowattHour = "kWh"
+	UnitVolt         = "V"
+	UnitAmpere       = "A"
+)
@@ -0,0 +1,25 @@
+// THIS FILE IS AUTO-GENERATED
+package eve
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+const TypeCurrentConsumption = "E863F10D-079E-48FF-8F27-9C2605A29F52"
+
+type CurrentConsumption struct {
+	*characteristic.Float
+}
+
+func NewCurrentConsumption() *CurrentConsumption {
+	char := characteristic.NewFloat(TypeCurrentConsumption)
+	char.Format = characteristic.FormatFloat
+	char.Perms = []string{characteristic.PermRead, characteristic.PermEvents}
+	char.SetMinValue(0)
+	char.SetMaxValue(100000)
+	char.SetStepValue(0.1)
+	char.SetValue(0)
+	char.Unit = UnitWatt
+
+	return &CurrentConsumption{char}
+}
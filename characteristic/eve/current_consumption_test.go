@@ -0,0 +1,23 @@
+package eve
+
+import (
+	"testing"
+)
+
+func TestCurrentConsumption(t *testing.T) {
+	c := NewCurrentConsumption()
+
+	if is, want := c.Type, "E863F10D-079E-48FF-8F27-9C2605A29F52"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	c.SetValue(12.5)
+
+	if is, want := c.GetValue(), 12.5; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := c.Unit, UnitWatt; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
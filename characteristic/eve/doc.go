@@ -0,0 +1,6 @@
+// Package eve implements the custom characteristics used by Elgato Eve accessories.
+//
+// The characteristics are not defined by Apple and are not shown in the Home app,
+// but are displayed by the Eve app. They are commonly used by smart plugs to
+// provide energy metering values.
+package eve
@@ -0,0 +1,25 @@
+// THIS FILE IS AUTO-GENERATED
+package eve
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+const TypeElectricCurrent = "E863F126-079E-48FF-8F27-9C2605A29F52"
+
+type ElectricCurrent struct {
+	*characteristic.Float
+}
+
+func NewElectricCurrent() *ElectricCurrent {
+	char := characteristic.NewFloat(TypeElectricCurrent)
+	char.Format = characteristic.FormatFloat
+	char.Perms = []string{characteristic.PermRead, characteristic.PermEvents}
+	char.SetMinValue(0)
+	char.SetMaxValue(100)
+	char.SetStepValue(0.01)
+	char.SetValue(0)
+	char.Unit = UnitAmpere
+
+	return &ElectricCurrent{char}
+}
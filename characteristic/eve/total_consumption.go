@@ -0,0 +1,25 @@
+// THIS FILE IS AUTO-GENERATED
+package eve
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+const TypeTotalConsumption = "E863F10C-079E-48FF-8F27-9C2605A29F52"
+
+type TotalConsumption struct {
+	*characteristic.Float
+}
+
+func NewTotalConsumption() *TotalConsumption {
+	char := characteristic.NewFloat(TypeTotalConsumption)
+	char.Format = characteristic.FormatFloat
+	char.Perms = []string{characteristic.PermRead, characteristic.PermEvents}
+	char.SetMinValue(0)
+	char.SetMaxValue(1000000)
+	char.SetStepValue(0.01)
+	char.SetValue(0)
+	char.Unit = UnitKilowattHour
+
+	return &TotalConsumption{char}
+}
@@ -0,0 +1,25 @@
+// THIS FILE IS AUTO-GENERATED
+package eve
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+const TypeVoltage = "E863F10A-079E-48FF-8F27-9C2605A29F52"
+
+type Voltage struct {
+	*characteristic.Float
+}
+
+func NewVoltage() *Voltage {
+	char := characteristic.NewFloat(TypeVoltage)
+	char.Format = characteristic.FormatFloat
+	char.Perms = []string{characteristic.PermRead, characteristic.PermEvents}
+	char.SetMinValue(0)
+	char.SetMaxValue(1000)
+	char.SetStepValue(0.1)
+	char.SetValue(0)
+	char.Unit = UnitVolt
+
+	return &Voltage{char}
+}
@@ -0,0 +1,23 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const (
+	EventSnapshotsActiveDisable int = 0
+	EventSnapshotsActiveEnable  int = 1
+)
+
+const TypeEventSnapshotsActive = "223"
+
+type EventSnapshotsActive struct {
+	*Int
+}
+
+func NewEventSnapshotsActive() *EventSnapshotsActive {
+	char := NewInt(TypeEventSnapshotsActive)
+	char.Format = FormatUInt8
+	char.Perms = []string{PermRead, PermWrite, PermEvents}
+
+	char.SetValue(0)
+
+	return &EventSnapshotsActive{char}
+}
@@ -0,0 +1,18 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeFirmwareUpdateReadiness = "234"
+
+type FirmwareUpdateReadiness struct {
+	*Bytes
+}
+
+func NewFirmwareUpdateReadiness() *FirmwareUpdateReadiness {
+	char := NewBytes(TypeFirmwareUpdateReadiness)
+	char.Format = FormatTLV8
+	char.Perms = []string{PermRead, PermEvents}
+
+	char.SetValue([]byte{})
+
+	return &FirmwareUpdateReadiness{char}
+}
@@ -0,0 +1,18 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeFirmwareUpdateStatus = "235"
+
+type FirmwareUpdateStatus struct {
+	*Bytes
+}
+
+func NewFirmwareUpdateStatus() *FirmwareUpdateStatus {
+	char := NewBytes(TypeFirmwareUpdateStatus)
+	char.Format = FormatTLV8
+	char.Perms = []string{PermRead, PermEvents}
+
+	char.SetValue([]byte{})
+
+	return &FirmwareUpdateStatus{char}
+}
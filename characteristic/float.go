@@ -19,15 +19,21 @@ func (c *Float) SetValue(value float64) {
 }
 
 func (c *Float) SetMinValue(value float64) {
-	c.MinValue = value
+	c.SetValueRange(value, c.MaxValue, c.StepValue)
 }
 
 func (c *Float) SetMaxValue(value float64) {
-	c.MaxValue = value
+	c.SetValueRange(c.MinValue, value, c.StepValue)
 }
 
 func (c *Float) SetStepValue(value float64) {
-	c.StepValue = value
+	c.SetValueRange(c.MinValue, c.MaxValue, value)
+}
+
+// SetRange sets the min, max and step value at once, which can be changed at any time.
+// See Characteristic.SetValueRange.
+func (c *Float) SetRange(min, max, step float64) {
+	c.SetValueRange(min, max, step)
 }
 
 // GetValue returns the value as float
@@ -0,0 +1,18 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeHardwareFinish = "26C"
+
+type HardwareFinish struct {
+	*Bytes
+}
+
+func NewHardwareFinish() *HardwareFinish {
+	char := NewBytes(TypeHardwareFinish)
+	char.Format = FormatTLV8
+	char.Perms = []string{PermRead}
+
+	char.SetValue([]byte{})
+
+	return &HardwareFinish{char}
+}
@@ -0,0 +1,18 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeHeartBeat = "24A"
+
+type HeartBeat struct {
+	*Int
+}
+
+func NewHeartBeat() *HeartBeat {
+	char := NewInt(TypeHeartBeat)
+	char.Format = FormatUInt32
+	char.Perms = []string{PermRead, PermEvents}
+
+	char.SetValue(0)
+
+	return &HeartBeat{char}
+}
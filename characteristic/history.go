@@ -0,0 +1,120 @@
+package characteristic
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// Sources of a change
+const (
+	// SourceLocal is the source of changes by the accessory, e.g. with SetValue.
+	SourceLocal = "local"
+
+	// SourceRemote is the source of changes written by a client.
+	SourceRemote = "remote"
+
+	// SourceRestore is the source of values restored from persistent storage (see RestoreValue).
+	SourceRestore = "restore"
+)
+
+// ControllerConn is a connection which knows the name of the controller which verified it.
+type ControllerConn interface {
+	net.Conn
+
+	// Controller returns the name of the verified controller, or an empty string.
+	Controller() string
+}
+
+// Change is a change of a characteristic value.
+type Change struct {
+	Time     time.Time
+	Old, New interface{}
+
+	// Source is SourceLocal, SourceRemote or SourceRestore.
+	Source string
+
+	// Controller is the name of the controller which wrote the value, if known.
+	Controller string
+}
+
+// History records the latest changes of a characteristic in a ring buffer.
+type History struct {
+	mutex   sync.Mutex
+	changes []Change
+	next    int
+	full    bool
+}
+
+// NewHistory returns a history which records up to size changes.
+func NewHistory(size int) *History {
+	if size < 1 {
+		size = 1
+	}
+
+	return &History{changes: make([]Change, size)}
+}
+
+// Changes returns the recorded changes, the oldest change first.
+func (h *History) Changes() []Change {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	var changes []Change
+	if h.full == true {
+		changes = append(changes, h.changes[h.next:]...)
+	}
+
+	return append(changes, h.changes[:h.next]...)
+}
+
+// Since returns the changes which happened after t, the oldest change first.
+func (h *History) Since(t time.Time) []Change {
+	var changes []Change
+	for _, c := range h.Changes() {
+		if c.Time.After(t) == true {
+			changes = append(changes, c)
+		}
+	}
+
+	return changes
+}
+
+// record adds ch to the history and overwrites the oldest change if the history is full.
+func (h *History) record(ch Change) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.changes[h.next] = ch
+	h.next++
+	if h.next == len(h.changes) {
+		h.next = 0
+		h.full = true
+	}
+}
+
+// EnableHistory records the latest size changes of the value in a history.
+func (c *Characteristic) EnableHistory(size int) {
+	c.history = NewHistory(size)
+}
+
+// History returns the history of the value, or nil if the history is not enabled.
+func (c *Characteristic) History() *History {
+	return c.history
+}
+
+// recordChange adds a change of the value from old to new by conn or source to the history.
+func (c *Characteristic) recordChange(conn net.Conn, source string, old, new interface{}) {
+	if c.history == nil {
+		return
+	}
+
+	ch := Change{Time: time.Now(), Old: old, New: new, Source: source}
+	if conn != nil {
+		if cc, ok := conn.(ControllerConn); ok == true {
+			ch.Controller = cc.Controller()
+		}
+	}
+
+	c.history.record(ch)
+}
@@ -0,0 +1,58 @@
+package characteristic
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+type controllerConn struct {
+	net.Conn
+}
+
+func (c controllerConn) Controller() string {
+	return "Alice"
+}
+
+func TestHistory(t *testing.T) {
+	c := NewOn()
+	c.EnableHistory(2)
+
+	c.SetValue(true)
+	c.UpdateValueFromConnection(false, controllerConn{TestConn})
+	c.UpdateValueFromConnection(true, TestConn)
+
+	changes := c.History().Changes()
+	if is, want := len(changes), 2; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := changes[0].Source, SourceRemote; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := changes[0].Controller, "Alice"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := changes[0].New, false; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := changes[1].Controller, ""; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := len(c.History().Since(time.Now())), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestHistoryDisabled(t *testing.T) {
+	c := NewOn()
+	c.SetValue(true)
+
+	if c.History() != nil {
+		t.Fatal("expected no history")
+	}
+}
@@ -0,0 +1,23 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const (
+	HomeKitCameraActiveOff int = 0
+	HomeKitCameraActiveOn  int = 1
+)
+
+const TypeHomeKitCameraActive = "21B"
+
+type HomeKitCameraActive struct {
+	*Int
+}
+
+func NewHomeKitCameraActive() *HomeKitCameraActive {
+	char := NewInt(TypeHomeKitCameraActive)
+	char.Format = FormatUInt8
+	char.Perms = []string{PermRead, PermWrite, PermEvents}
+
+	char.SetValue(0)
+
+	return &HomeKitCameraActive{char}
+}
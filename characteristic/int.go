@@ -19,15 +19,21 @@ func (c *Int) SetValue(value int) {
 }
 
 func (c *Int) SetMinValue(value int) {
-	c.MinValue = value
+	c.SetValueRange(value, c.MaxValue, c.StepValue)
 }
 
 func (c *Int) SetMaxValue(value int) {
-	c.MaxValue = value
+	c.SetValueRange(c.MinValue, value, c.StepValue)
 }
 
 func (c *Int) SetStepValue(value int) {
-	c.StepValue = value
+	c.SetValueRange(c.MinValue, c.MaxValue, value)
+}
+
+// SetRange sets the min, max and step value at once, which can be changed at any time.
+// See Characteristic.SetValueRange.
+func (c *Int) SetRange(min, max, step int) {
+	c.SetValueRange(min, max, step)
 }
 
 // GetValue returns the value as int
@@ -47,6 +53,37 @@ func (c *Int) GetStepValue() int {
 	return c.StepValue.(int)
 }
 
+// UInt8 returns the value of a characteristic with the uint8 format.
+func (c *Int) UInt8() uint8 {
+	return uint8(c.GetValue())
+}
+
+// SetUInt8 sets the value of a characteristic with the uint8 format.
+func (c *Int) SetUInt8(value uint8) {
+	c.SetValue(int(value))
+}
+
+// UInt16 returns the value of a characteristic with the uint16 format.
+func (c *Int) UInt16() uint16 {
+	return uint16(c.GetValue())
+}
+
+// SetUInt16 sets the value of a characteristic with the uint16 format.
+func (c *Int) SetUInt16(value uint16) {
+	c.SetValue(int(value))
+}
+
+// UInt32 returns the value of a characteristic with the uint32 format.
+func (c *Int) UInt32() uint32 {
+	return uint32(c.GetValue())
+}
+
+// SetUInt32 sets the value of a characteristic with the uint32 format.
+// On platforms with 32-bit int, values above math.MaxInt32 can't be represented.
+func (c *Int) SetUInt32(value uint32) {
+	c.SetValue(int(value))
+}
+
 // OnValueRemoteUpdate calls fn when the value was updated by a client.
 func (c *Int) OnValueRemoteUpdate(fn func(int)) {
 	c.OnValueUpdateFromConn(func(conn net.Conn, c *Characteristic, new, old interface{}) {
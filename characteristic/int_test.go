@@ -1,6 +1,10 @@
 package characteristic
 
 import (
+	"encoding/json"
+	"math"
+	"net"
+	"strings"
 	"testing"
 )
 
@@ -22,3 +26,67 @@ func TestNumberIntOutOfBounds(t *testing.T) {
 		t.Fatalf("is=%v want=%v", is, want)
 	}
 }
+
+func TestNumberIntFormatBounds(t *testing.T) {
+	number := NewInt(TypeBrightness)
+	number.Format = FormatUInt8
+	number.Value = 0
+
+	number.SetValue(300)
+	if is, want := number.GetValue(), 255; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	number.SetValue(-1)
+	if is, want := number.GetValue(), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestNumberIntTypedAccessors(t *testing.T) {
+	number := NewInt(TypeBrightness)
+	number.Format = FormatUInt16
+	number.Value = 0
+
+	number.SetUInt16(math.MaxUint16)
+	if is, want := number.UInt16(), uint16(math.MaxUint16); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	number.Format = FormatUInt8
+	number.SetUInt8(200)
+	if is, want := number.UInt8(), uint8(200); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	number.Format = FormatUInt32
+	number.SetUInt32(70000)
+	if is, want := number.UInt32(), uint32(70000); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestUInt64(t *testing.T) {
+	number := NewUInt64(TypeBrightness)
+	number.Format = FormatUInt64
+	number.Perms = PermsAll()
+	number.SetValue(0)
+
+	number.SetValue(math.MaxUint64)
+	if is, want := number.GetValue(), uint64(math.MaxUint64); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	number.UpdateValueFromConnection(float64(1<<40), &net.TCPConn{})
+	if is, want := number.GetValue(), uint64(1<<40); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	b, err := json.Marshal(number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x := string(b); strings.Contains(x, `"value":1099511627776`) == false {
+		t.Fatalf("unexpected json %s", x)
+	}
+}
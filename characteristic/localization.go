@@ -0,0 +1,50 @@
+package characteristic
+
+import (
+	"strings"
+)
+
+// SetLocalizedValue sets the value of the characteristic for a locale, e.g. "de" or "de-AT".
+// Controllers which prefer the locale get the localized value instead of the value.
+func (c *Characteristic) SetLocalizedValue(locale string, value interface{}) {
+	if c.localizedValues == nil {
+		c.localizedValues = map[string]interface{}{}
+	}
+
+	c.localizedValues[strings.ToLower(locale)] = value
+}
+
+// IsLocalized returns true when the characteristic has localized values.
+func (c *Characteristic) IsLocalized() bool {
+	return len(c.localizedValues) > 0
+}
+
+// LocalizedValue returns the localized value for the first of the preferred locales.
+// A locale also matches the value of its language, e.g. "de-AT" matches "de".
+// When there is no localized value, the value of the characteristic is returned.
+func (c *Characteristic) LocalizedValue(locales []string) interface{} {
+	for _, locale := range locales {
+		locale = strings.ToLower(locale)
+		if v, ok := c.localizedValues[locale]; ok == true {
+			return v
+		}
+
+		if i := strings.IndexAny(locale, "-_"); i > 0 {
+			if v, ok := c.localizedValues[locale[:i]]; ok == true {
+				return v
+			}
+		}
+	}
+
+	return c.Value
+}
+
+// LocalizedValues returns a copy of the localized values by locale.
+func (c *Characteristic) LocalizedValues() map[string]interface{} {
+	values := map[string]interface{}{}
+	for locale, v := range c.localizedValues {
+		values[locale] = v
+	}
+
+	return values
+}
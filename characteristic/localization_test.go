@@ -0,0 +1,25 @@
+package characteristic
+
+import (
+	"testing"
+)
+
+func TestLocalizedValue(t *testing.T) {
+	name := NewName()
+	name.SetValue("Lamp")
+	name.SetLocalizedValue("de", "Lampe")
+	name.SetLocalizedValue("fr-CA", "Lampe de table")
+
+	if is, want := name.LocalizedValue([]string{"de-AT", "en"}), "Lampe"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := name.LocalizedValue([]string{"fr-ca"}), "Lampe de table"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := name.LocalizedValue([]string{"en-US"}), "Lamp"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := name.LocalizedValue(nil), "Lamp"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,18 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeManuallyDisabled = "227"
+
+type ManuallyDisabled struct {
+	*Bool
+}
+
+func NewManuallyDisabled() *ManuallyDisabled {
+	char := NewBool(TypeManuallyDisabled)
+	char.Format = FormatBool
+	char.Perms = []string{PermRead, PermEvents}
+
+	char.SetValue(false)
+
+	return &ManuallyDisabled{char}
+}
@@ -0,0 +1,18 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeMute = "11A"
+
+type Mute struct {
+	*Bool
+}
+
+func NewMute() *Mute {
+	char := NewBool(TypeMute)
+	char.Format = FormatBool
+	char.Perms = []string{PermRead, PermWrite, PermEvents}
+
+	char.SetValue(false)
+
+	return &Mute{char}
+}
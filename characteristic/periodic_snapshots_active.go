@@ -0,0 +1,23 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const (
+	PeriodicSnapshotsActiveDisable int = 0
+	PeriodicSnapshotsActiveEnable  int = 1
+)
+
+const TypePeriodicSnapshotsActive = "225"
+
+type PeriodicSnapshotsActive struct {
+	*Int
+}
+
+func NewPeriodicSnapshotsActive() *PeriodicSnapshotsActive {
+	char := NewInt(TypePeriodicSnapshotsActive)
+	char.Format = FormatUInt8
+	char.Perms = []string{PermRead, PermWrite, PermEvents}
+
+	char.SetValue(0)
+
+	return &PeriodicSnapshotsActive{char}
+}
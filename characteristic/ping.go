@@ -0,0 +1,18 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypePing = "23C"
+
+type Ping struct {
+	*Bytes
+}
+
+func NewPing() *Ping {
+	char := NewBytes(TypePing)
+	char.Format = FormatData
+	char.Perms = []string{PermRead}
+
+	char.SetValue([]byte{})
+
+	return &Ping{char}
+}
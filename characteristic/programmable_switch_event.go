@@ -16,5 +16,7 @@ func NewProgrammableSwitchEvent() *ProgrammableSwitchEvent {
 	char.SetStepValue(1)
 	char.SetValue(0)
 
+	char.AlwaysNotify = true
+
 	return &ProgrammableSwitchEvent{char}
 }
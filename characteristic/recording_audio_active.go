@@ -0,0 +1,23 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const (
+	RecordingAudioActiveDisable int = 0
+	RecordingAudioActiveEnable  int = 1
+)
+
+const TypeRecordingAudioActive = "226"
+
+type RecordingAudioActive struct {
+	*Int
+}
+
+func NewRecordingAudioActive() *RecordingAudioActive {
+	char := NewInt(TypeRecordingAudioActive)
+	char.Format = FormatUInt8
+	char.Perms = []string{PermRead, PermWrite, PermEvents}
+
+	char.SetValue(0)
+
+	return &RecordingAudioActive{char}
+}
@@ -0,0 +1,18 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeSelectedAudioStreamConfiguration = "128"
+
+type SelectedAudioStreamConfiguration struct {
+	*Bytes
+}
+
+func NewSelectedAudioStreamConfiguration() *SelectedAudioStreamConfiguration {
+	char := NewBytes(TypeSelectedAudioStreamConfiguration)
+	char.Format = FormatTLV8
+	char.Perms = []string{PermRead, PermWrite}
+
+	char.SetValue([]byte{})
+
+	return &SelectedAudioStreamConfiguration{char}
+}
@@ -0,0 +1,18 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeSetupDataStreamTransport = "131"
+
+type SetupDataStreamTransport struct {
+	*Bytes
+}
+
+func NewSetupDataStreamTransport() *SetupDataStreamTransport {
+	char := NewBytes(TypeSetupDataStreamTransport)
+	char.Format = FormatTLV8
+	char.Perms = []string{PermRead, PermWrite, PermWriteResponse}
+
+	char.SetValue([]byte{})
+
+	return &SetupDataStreamTransport{char}
+}
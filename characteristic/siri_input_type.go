@@ -0,0 +1,22 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const (
+	SiriInputTypePushButtonTriggeredAppleTV int = 0
+)
+
+const TypeSiriInputType = "132"
+
+type SiriInputType struct {
+	*Int
+}
+
+func NewSiriInputType() *SiriInputType {
+	char := NewInt(TypeSiriInputType)
+	char.Format = FormatUInt8
+	char.Perms = []string{PermRead}
+
+	char.SetValue(0)
+
+	return &SiriInputType{char}
+}
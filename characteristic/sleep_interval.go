@@ -0,0 +1,20 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeSleepInterval = "23A"
+
+type SleepInterval struct {
+	*Int
+}
+
+func NewSleepInterval() *SleepInterval {
+	char := NewInt(TypeSleepInterval)
+	char.Format = FormatUInt32
+	char.Perms = []string{PermRead, PermEvents}
+	char.SetMinValue(0)
+
+	char.SetStepValue(1)
+	char.SetValue(0)
+
+	return &SleepInterval{char}
+}
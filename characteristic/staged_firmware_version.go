@@ -0,0 +1,18 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeStagedFirmwareVersion = "249"
+
+type StagedFirmwareVersion struct {
+	*String
+}
+
+func NewStagedFirmwareVersion() *StagedFirmwareVersion {
+	char := NewString(TypeStagedFirmwareVersion)
+	char.Format = FormatString
+	char.Perms = []string{PermRead, PermEvents}
+
+	char.SetValue("")
+
+	return &StagedFirmwareVersion{char}
+}
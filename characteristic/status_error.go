@@ -0,0 +1,21 @@
+package characteristic
+
+import (
+	"fmt"
+)
+
+// StatusError rejects a value written by a client with a HAP status code.
+// The status codes are defined in the netio package, e.g. netio.StatusResourceBusy.
+type StatusError struct {
+	Status  int
+	Message string
+}
+
+// NewStatusError returns an error with the HAP status code status.
+func NewStatusError(status int, message string) *StatusError {
+	return &StatusError{Status: status, Message: message}
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s (status %d)", e.Message, e.Status)
+}
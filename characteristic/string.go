@@ -17,6 +17,12 @@ func (c *String) SetValue(str string) {
 	c.UpdateValue(str)
 }
 
+// SetMaxLen sets the max length of the value. Longer values from clients are ignored.
+// When not set, the max length is DefaultMaxLen.
+func (c *String) SetMaxLen(max int) {
+	c.MaxLen = max
+}
+
 // GetValue returns the value as string
 func (c *String) GetValue() string {
 	return c.Value.(string)
@@ -1,6 +1,8 @@
 package characteristic
 
 import (
+	"net"
+	"strings"
 	"testing"
 )
 
@@ -18,3 +20,37 @@ func TestString(t *testing.T) {
 		t.Fatalf("is=%v want=%v", is, want)
 	}
 }
+
+func TestStringMaxLen(t *testing.T) {
+	str := NewString(TypeName)
+	str.Format = FormatString
+	str.Perms = PermsAll()
+	str.SetValue("")
+	str.SetMaxLen(4)
+
+	str.UpdateValueFromConnection("abcd", &net.TCPConn{})
+	if is, want := str.GetValue(), "abcd"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	err := str.UpdateValueFromConnection("abcde", &net.TCPConn{})
+	if se, ok := err.(*StatusError); ok == false || se.Status != statusInvalidValueInRequest {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if is, want := str.GetValue(), "abcd"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestStringDefaultMaxLen(t *testing.T) {
+	str := NewString(TypeName)
+	str.Format = FormatString
+	str.Perms = PermsAll()
+	str.SetValue("")
+
+	str.UpdateValueFromConnection(strings.Repeat("a", DefaultMaxLen+1), &net.TCPConn{})
+	if is, want := str.GetValue(), ""; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
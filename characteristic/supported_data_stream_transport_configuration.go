@@ -0,0 +1,18 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeSupportedDataStreamTransportConfiguration = "130"
+
+type SupportedDataStreamTransportConfiguration struct {
+	*Bytes
+}
+
+func NewSupportedDataStreamTransportConfiguration() *SupportedDataStreamTransportConfiguration {
+	char := NewBytes(TypeSupportedDataStreamTransportConfiguration)
+	char.Format = FormatTLV8
+	char.Perms = []string{PermRead}
+
+	char.SetValue([]byte{})
+
+	return &SupportedDataStreamTransportConfiguration{char}
+}
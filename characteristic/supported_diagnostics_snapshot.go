@@ -0,0 +1,18 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeSupportedDiagnosticsSnapshot = "238"
+
+type SupportedDiagnosticsSnapshot struct {
+	*Bytes
+}
+
+func NewSupportedDiagnosticsSnapshot() *SupportedDiagnosticsSnapshot {
+	char := NewBytes(TypeSupportedDiagnosticsSnapshot)
+	char.Format = FormatTLV8
+	char.Perms = []string{PermRead}
+
+	char.SetValue([]byte{})
+
+	return &SupportedDiagnosticsSnapshot{char}
+}
@@ -0,0 +1,18 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeTargetControlList = "124"
+
+type TargetControlList struct {
+	*Bytes
+}
+
+func NewTargetControlList() *TargetControlList {
+	char := NewBytes(TypeTargetControlList)
+	char.Format = FormatTLV8
+	char.Perms = []string{PermRead, PermWrite, PermWriteResponse}
+
+	char.SetValue([]byte{})
+
+	return &TargetControlList{char}
+}
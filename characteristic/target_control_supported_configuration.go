@@ -0,0 +1,18 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeTargetControlSupportedConfiguration = "123"
+
+type TargetControlSupportedConfiguration struct {
+	*Bytes
+}
+
+func NewTargetControlSupportedConfiguration() *TargetControlSupportedConfiguration {
+	char := NewBytes(TypeTargetControlSupportedConfiguration)
+	char.Format = FormatTLV8
+	char.Perms = []string{PermRead}
+
+	char.SetValue([]byte{})
+
+	return &TargetControlSupportedConfiguration{char}
+}
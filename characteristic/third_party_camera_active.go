@@ -0,0 +1,23 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const (
+	ThirdPartyCameraActiveOff int = 0
+	ThirdPartyCameraActiveOn  int = 1
+)
+
+const TypeThirdPartyCameraActive = "21C"
+
+type ThirdPartyCameraActive struct {
+	*Int
+}
+
+func NewThirdPartyCameraActive() *ThirdPartyCameraActive {
+	char := NewInt(TypeThirdPartyCameraActive)
+	char.Format = FormatUInt8
+	char.Perms = []string{PermRead, PermEvents}
+
+	char.SetValue(0)
+
+	return &ThirdPartyCameraActive{char}
+}
@@ -0,0 +1,16 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeThreadControlPoint = "704"
+
+type ThreadControlPoint struct {
+	*Bytes
+}
+
+func NewThreadControlPoint() *ThreadControlPoint {
+	char := NewBytes(TypeThreadControlPoint)
+	char.Format = FormatTLV8
+	char.Perms = []string{PermWrite}
+
+	return &ThreadControlPoint{char}
+}
@@ -0,0 +1,20 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeThreadNodeCapabilities = "702"
+
+type ThreadNodeCapabilities struct {
+	*Int
+}
+
+func NewThreadNodeCapabilities() *ThreadNodeCapabilities {
+	char := NewInt(TypeThreadNodeCapabilities)
+	char.Format = FormatUInt16
+	char.Perms = []string{PermRead}
+	char.SetMinValue(0)
+	char.SetMaxValue(31)
+	char.SetStepValue(1)
+	char.SetValue(0)
+
+	return &ThreadNodeCapabilities{char}
+}
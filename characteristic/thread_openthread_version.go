@@ -0,0 +1,18 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeThreadOpenThreadVersion = "706"
+
+type ThreadOpenThreadVersion struct {
+	*String
+}
+
+func NewThreadOpenThreadVersion() *ThreadOpenThreadVersion {
+	char := NewString(TypeThreadOpenThreadVersion)
+	char.Format = FormatString
+	char.Perms = []string{PermRead}
+
+	char.SetValue("")
+
+	return &ThreadOpenThreadVersion{char}
+}
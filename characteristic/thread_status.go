@@ -0,0 +1,20 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeThreadStatus = "703"
+
+type ThreadStatus struct {
+	*Int
+}
+
+func NewThreadStatus() *ThreadStatus {
+	char := NewInt(TypeThreadStatus)
+	char.Format = FormatUInt16
+	char.Perms = []string{PermRead, PermEvents}
+	char.SetMinValue(0)
+	char.SetMaxValue(6)
+	char.SetStepValue(1)
+	char.SetValue(0)
+
+	return &ThreadStatus{char}
+}
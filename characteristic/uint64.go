@@ -0,0 +1,64 @@
+package characteristic
+
+import (
+	"net"
+)
+
+// UInt64 is a characteristic with the uint64 format.
+// Unlike Int, the value is stored as uint64 so that large values
+// (e.g. total consumption counters) don't overflow.
+type UInt64 struct {
+	*Characteristic
+}
+
+func NewUInt64(typ string) *UInt64 {
+	number := NewCharacteristic(typ)
+	return &UInt64{number}
+}
+
+// SetValue sets a value
+func (c *UInt64) SetValue(value uint64) {
+	c.UpdateValue(value)
+}
+
+func (c *UInt64) SetMinValue(value uint64) {
+	c.SetValueRange(value, c.MaxValue, c.StepValue)
+}
+
+func (c *UInt64) SetMaxValue(value uint64) {
+	c.SetValueRange(c.MinValue, value, c.StepValue)
+}
+
+func (c *UInt64) SetStepValue(value uint64) {
+	c.SetValueRange(c.MinValue, c.MaxValue, value)
+}
+
+// SetRange sets the min, max and step value at once, which can be changed at any time.
+// See Characteristic.SetValueRange.
+func (c *UInt64) SetRange(min, max, step uint64) {
+	c.SetValueRange(min, max, step)
+}
+
+// GetValue returns the value as uint64
+func (c *UInt64) GetValue() uint64 {
+	return c.Value.(uint64)
+}
+
+func (c *UInt64) GetMinValue() uint64 {
+	return c.MinValue.(uint64)
+}
+
+func (c *UInt64) GetMaxValue() uint64 {
+	return c.MaxValue.(uint64)
+}
+
+func (c *UInt64) GetStepValue() uint64 {
+	return c.StepValue.(uint64)
+}
+
+// OnValueRemoteUpdate calls fn when the value was updated by a client.
+func (c *UInt64) OnValueRemoteUpdate(fn func(uint64)) {
+	c.OnValueUpdateFromConn(func(conn net.Conn, c *Characteristic, new, old interface{}) {
+		fn(new.(uint64))
+	})
+}
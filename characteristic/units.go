@@ -0,0 +1,96 @@
+package characteristic
+
+import (
+	"math"
+)
+
+// AbsoluteZero is the lowest temperature in celsius
+const AbsoluteZero = -273.15
+
+// FahrenheitToCelsius returns the temperature f in celsius.
+func FahrenheitToCelsius(f float64) float64 {
+	return (f - 32) * 5 / 9
+}
+
+// CelsiusToFahrenheit returns the temperature c in fahrenheit.
+func CelsiusToFahrenheit(c float64) float64 {
+	return c*9/5 + 32
+}
+
+// MiredToKelvin returns the color temperature m in kelvin.
+// The color temperature of HomeKit is specified in mired (micro reciprocal degree).
+func MiredToKelvin(m int) int {
+	if m <= 0 {
+		return 0
+	}
+
+	return int(math.Floor(1000000/float64(m) + 0.5))
+}
+
+// KelvinToMired returns the color temperature k in mired.
+func KelvinToMired(k int) int {
+	if k <= 0 {
+		return 0
+	}
+
+	return int(math.Floor(1000000/float64(k) + 0.5))
+}
+
+// validForUnit returns false when value is not a valid value of the unit,
+// e.g. a percentage of 120 or a negative duration in seconds.
+func validForUnit(unit string, value float64) bool {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return false
+	}
+
+	switch unit {
+	case UnitPercentage:
+		return value >= 0 && value <= 100
+	case UnitArcDegrees:
+		return value >= -360 && value <= 360
+	case UnitCelsius:
+		return value >= AbsoluteZero
+	case UnitLux, UnitSeconds:
+		return value >= 0
+	}
+
+	return true
+}
+
+// roundFloatToStep returns value rounded to the next multiple of step starting at min.
+func roundFloatToStep(value, min, step float64) float64 {
+	if step <= 0 {
+		return value
+	}
+
+	value = min + math.Floor((value-min)/step+0.5)*step
+
+	// Remove the floating point error of the multiplication, e.g. 20.200000000000003
+	p := math.Pow10(decimals(step))
+	return math.Floor(value*p+0.5) / p
+}
+
+// roundIntToStep returns value rounded to the next multiple of step starting at min.
+func roundIntToStep(value, min, step int) int {
+	if step <= 1 {
+		return value
+	}
+
+	n := (value - min + step/2) / step
+	if value < min {
+		n = -((min - value + step/2) / step)
+	}
+
+	return min + n*step
+}
+
+// decimals returns the number of decimal places of f.
+func decimals(f float64) int {
+	d := 0
+	for f != math.Trunc(f) && d < 10 {
+		f *= 10
+		d++
+	}
+
+	return d
+}
@@ -0,0 +1,102 @@
+package characteristic
+
+import (
+	"testing"
+)
+
+func TestTemperatureConversion(t *testing.T) {
+	if is, want := FahrenheitToCelsius(212), 100.0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := CelsiusToFahrenheit(-40), -40.0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestColorTemperatureConversion(t *testing.T) {
+	if is, want := MiredToKelvin(140), 7143; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := KelvinToMired(2000), 500; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := MiredToKelvin(0), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestFloatRoundToStep(t *testing.T) {
+	float := NewFloat(TypeCurrentTemperature)
+	float.Format = FormatFloat
+	float.Unit = UnitCelsius
+	float.Value = 0.0
+	float.SetMinValue(0.0)
+	float.SetMaxValue(100.0)
+	float.SetStepValue(0.1)
+
+	float.SetValue(21.37)
+	if is, want := float.GetValue(), 21.4; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestIntRoundToStep(t *testing.T) {
+	number := NewInt(TypeBrightness)
+	number.Format = FormatInt32
+	number.Value = 0
+	number.SetMinValue(0)
+	number.SetMaxValue(10)
+	number.SetStepValue(4)
+
+	number.SetValue(5)
+	if is, want := number.GetValue(), 4; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	number.SetValue(10)
+	if is, want := number.GetValue(), 8; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestValueInvalidForUnit(t *testing.T) {
+	number := NewInt(TypeBrightness)
+	number.Format = FormatInt32
+	number.Unit = UnitPercentage
+	number.Value = 50
+
+	number.SetValue(120)
+	if is, want := number.GetValue(), 50; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	float := NewFloat(TypeCurrentTemperature)
+	float.Format = FormatFloat
+	float.Unit = UnitCelsius
+	float.Value = 20.0
+
+	float.SetValue(-300)
+	if is, want := float.GetValue(), 20.0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestRemoteValueInvalidForUnit(t *testing.T) {
+	number := NewInt(TypeBrightness)
+	number.Format = FormatInt32
+	number.Unit = UnitPercentage
+	number.Perms = PermsAll()
+	number.Value = 50
+
+	err := number.UpdateValueFromConnection(120, TestConn)
+	if se, ok := err.(*StatusError); ok == false || se.Status != statusInvalidValueInRequest {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if is, want := number.GetValue(), 50; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,180 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+// validValues are the names of the valid values of integer characteristics by type
+var validValues = map[string]map[int]string{
+	TypeActive: map[int]string{
+		ActiveInactive: "Inactive",
+		ActiveActive:   "Active",
+	},
+	TypeAirParticulateSize: map[int]string{
+		AirParticulateSize2_5Μm: "2.5 μm",
+		AirParticulateSize10Μm:  "10 μm",
+	},
+	TypeAirQuality: map[int]string{
+		AirQualityUnknown:   "Unknown",
+		AirQualityExcellent: "Excellent",
+		AirQualityGood:      "Good",
+		AirQualityFair:      "Fair",
+		AirQualityInferior:  "Inferior",
+		AirQualityPoor:      "Poor",
+	},
+	TypeCarbonDioxideDetected: map[int]string{
+		CarbonDioxideDetectedCO2LevelsNormal:   "CO2 Levels Normal",
+		CarbonDioxideDetectedCO2LevelsAbnormal: "CO2 Levels Abnormal",
+	},
+	TypeCarbonMonoxideDetected: map[int]string{
+		CarbonMonoxideDetectedCOLevelsNormal:   "CO Levels Normal",
+		CarbonMonoxideDetectedCOLevelsAbnormal: "CO Levels Abnormal",
+	},
+	TypeChargingState: map[int]string{
+		ChargingStateNotCharging: "Not Charging",
+		ChargingStateCharging:    "Charging",
+	},
+	TypeContactSensorState: map[int]string{
+		ContactSensorStateContactDetected:    "Contact Detected",
+		ContactSensorStateContactNotDetected: "Contact Not Detected",
+	},
+	TypeCurrentDoorState: map[int]string{
+		CurrentDoorStateOpen:    "Open",
+		CurrentDoorStateClosed:  "Closed",
+		CurrentDoorStateOpening: "Opening",
+		CurrentDoorStateClosing: "Closing",
+		CurrentDoorStateStopped: "Stopped",
+	},
+	TypeCurrentHeatingCoolingState: map[int]string{
+		CurrentHeatingCoolingStateOff:  "Off",
+		CurrentHeatingCoolingStateHeat: "Heat",
+		CurrentHeatingCoolingStateCool: "Cool",
+	},
+	TypeDiscoverBridgedAccessories: map[int]string{
+		DiscoverBridgedAccessoriesStartDiscovery: "Start Discovery",
+		DiscoverBridgedAccessoriesStopDiscovery:  "Stop Discovery",
+	},
+	TypeEventSnapshotsActive: map[int]string{
+		EventSnapshotsActiveDisable: "Disable",
+		EventSnapshotsActiveEnable:  "Enable",
+	},
+	TypeHomeKitCameraActive: map[int]string{
+		HomeKitCameraActiveOff: "Off",
+		HomeKitCameraActiveOn:  "On",
+	},
+	TypeInUse: map[int]string{
+		InUseNotInUse: "Not In Use",
+		InUseInUse:    "In Use",
+	},
+	TypeIsConfigured: map[int]string{
+		IsConfiguredNotConfigured: "Not Configured",
+		IsConfiguredConfigured:    "Configured",
+	},
+	TypeLeakDetected: map[int]string{
+		LeakDetectedLeakNotDetected: "Leak Not Detected",
+		LeakDetectedLeakDetected:    "Leak Detected",
+	},
+	TypeLockCurrentState: map[int]string{
+		LockCurrentStateUnsecured: "Unsecured",
+		LockCurrentStateSecured:   "Secured",
+		LockCurrentStateJammed:    "Jammed",
+		LockCurrentStateUnknown:   "Unknown",
+	},
+	TypeLockLastKnownAction: map[int]string{
+		LockLastKnownActionSecuredPhysicallyInterior:   "Secured physically, interior",
+		LockLastKnownActionUnsecuredPhysicallyInterior: "Unsecured physically, interior",
+		LockLastKnownActionSecuredPhysicallyExterior:   "Secured physically, exterior",
+		LockLastKnownActionUnsecuredPhysicallyExterior: "Unsecured physically, exterior",
+		LockLastKnownActionSecuredByKeypad:             "Secured by keypad",
+		LockLastKnownActionUnsecuredByKeypad:           "Unsecured by keypad",
+		LockLastKnownActionSecuredRemotely:             "Secured remotely",
+		LockLastKnownActionUnsecuredRemotely:           "Unsecured remotely",
+		LockLastKnownActionSecuredByAutoSecureTimeout:  "Secured by Auto Secure timeout",
+	},
+	TypeLockTargetState: map[int]string{
+		LockTargetStateUnsecured: "Unsecured",
+		LockTargetStateSecured:   "Secured",
+	},
+	TypeOccupancyDetected: map[int]string{
+		OccupancyDetectedOccupancyNotDetected: "Occupancy Not Detected",
+		OccupancyDetectedOccupancyDetected:    "Occupancy Detected",
+	},
+	TypePeriodicSnapshotsActive: map[int]string{
+		PeriodicSnapshotsActiveDisable: "Disable",
+		PeriodicSnapshotsActiveEnable:  "Enable",
+	},
+	TypePositionState: map[int]string{
+		PositionStateDecreasing: "Decreasing",
+		PositionStateIncreasing: "Increasing",
+		PositionStateStopped:    "Stopped",
+	},
+	TypeRecordingAudioActive: map[int]string{
+		RecordingAudioActiveDisable: "Disable",
+		RecordingAudioActiveEnable:  "Enable",
+	},
+	TypeRotationDirection: map[int]string{
+		RotationDirectionClockwise:        "Clockwise",
+		RotationDirectionCounterclockwise: "Counter-clockwise",
+	},
+	TypeSecuritySystemCurrentState: map[int]string{
+		SecuritySystemCurrentStateStayArm:        "Stay Arm",
+		SecuritySystemCurrentStateAwayArm:        "Away Arm",
+		SecuritySystemCurrentStateNightArm:       "Night Arm",
+		SecuritySystemCurrentStateDisarmed:       "Disarmed",
+		SecuritySystemCurrentStateAlarmTriggered: "Alarm Triggered",
+	},
+	TypeSecuritySystemTargetState: map[int]string{
+		SecuritySystemTargetStateStayArm:  "Stay Arm",
+		SecuritySystemTargetStateAwayArm:  "Away Arm",
+		SecuritySystemTargetStateNightArm: "Night Arm",
+		SecuritySystemTargetStateDisarm:   "Disarm",
+	},
+	TypeServiceLabelNamespace: map[int]string{
+		ServiceLabelNamespaceDots:           "Dots",
+		ServiceLabelNamespaceArabicNumerals: "Arabic Numerals",
+	},
+	TypeSiriInputType: map[int]string{
+		SiriInputTypePushButtonTriggeredAppleTV: "Push Button Triggered Apple TV",
+	},
+	TypeSmokeDetected: map[int]string{
+		SmokeDetectedSmokeNotDetected: "Smoke Not Detected",
+		SmokeDetectedSmokeDetected:    "Smoke Detected",
+	},
+	TypeStatusFault: map[int]string{
+		StatusFaultNoFault:      "No Fault",
+		StatusFaultGeneralFault: "General Fault",
+	},
+	TypeStatusJammed: map[int]string{
+		StatusJammedNotJammed: "Not Jammed",
+		StatusJammedJammed:    "Jammed",
+	},
+	TypeStatusLowBattery: map[int]string{
+		StatusLowBatteryBatteryLevelNormal: "Battery Level Normal",
+		StatusLowBatteryBatteryLevelLow:    "Battery Level Low",
+	},
+	TypeStatusTampered: map[int]string{
+		StatusTamperedNotTampered: "Not Tampered",
+		StatusTamperedTampered:    "Tampered",
+	},
+	TypeTargetDoorState: map[int]string{
+		TargetDoorStateOpen:   "Open",
+		TargetDoorStateClosed: "Closed",
+	},
+	TypeTargetHeatingCoolingState: map[int]string{
+		TargetHeatingCoolingStateOff:  "Off",
+		TargetHeatingCoolingStateHeat: "Heat",
+		TargetHeatingCoolingStateCool: "Cool",
+		TargetHeatingCoolingStateAuto: "Auto",
+	},
+	TypeTemperatureDisplayUnits: map[int]string{
+		TemperatureDisplayUnitsCelsius:    "Celsius",
+		TemperatureDisplayUnitsFahrenheit: "Fahrenheit",
+	},
+	TypeThirdPartyCameraActive: map[int]string{
+		ThirdPartyCameraActiveOff: "Off",
+		ThirdPartyCameraActiveOn:  "On",
+	},
+	TypeValveType: map[int]string{
+		ValveTypeGenericValve: "Generic Valve",
+		ValveTypeIrrigation:   "Irrigation",
+		ValveTypeShowerHead:   "Shower Head",
+		ValveTypeWaterFaucet:  "Water Faucet",
+	},
+}
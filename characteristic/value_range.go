@@ -0,0 +1,30 @@
+package characteristic
+
+// RangeFunc is called when the min, max or step value of a characteristic changed.
+type RangeFunc func(c *Characteristic)
+
+// OnValueRangeUpdate calls fn when the min, max or step value changed, e.g. with SetValueRange.
+func (c *Characteristic) OnValueRangeUpdate(fn RangeFunc) {
+	c.rangeFuncs = append(c.rangeFuncs, fn)
+}
+
+// SetValueRange sets the min, max and step value, e.g. after a dimmer reported its real
+// brightness range. The value is bounded to the new range and rounded to the new step value.
+// The functions of OnValueRangeUpdate are called once, when one of the values changed.
+func (c *Characteristic) SetValueRange(min, max, step interface{}) {
+	if c.MinValue == min && c.MaxValue == max && c.StepValue == step {
+		return
+	}
+
+	c.MinValue = min
+	c.MaxValue = max
+	c.StepValue = step
+
+	if c.Value != nil {
+		c.UpdateValue(c.Value)
+	}
+
+	for _, fn := range c.rangeFuncs {
+		fn(c)
+	}
+}
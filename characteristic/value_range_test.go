@@ -0,0 +1,36 @@
+package characteristic
+
+import (
+	"testing"
+)
+
+func TestSetValueRange(t *testing.T) {
+	b := NewBrightness()
+	b.SetValue(80)
+
+	updates := 0
+	b.OnValueRangeUpdate(func(c *Characteristic) {
+		updates++
+	})
+
+	b.SetRange(10, 50, 5)
+
+	if is, want := updates, 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := b.GetMaxValue(), 50; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// The value is bounded to the new range
+	if is, want := b.GetValue(), 50; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// Unchanged values are ignored
+	b.SetMinValue(10)
+	if is, want := updates, 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,20 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeWiFiCapabilities = "22C"
+
+type WiFiCapabilities struct {
+	*Int
+}
+
+func NewWiFiCapabilities() *WiFiCapabilities {
+	char := NewInt(TypeWiFiCapabilities)
+	char.Format = FormatUInt32
+	char.Perms = []string{PermRead}
+	char.SetMinValue(0)
+	char.SetMaxValue(15)
+	char.SetStepValue(1)
+	char.SetValue(0)
+
+	return &WiFiCapabilities{char}
+}
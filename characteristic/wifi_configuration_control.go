@@ -0,0 +1,18 @@
+// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+const TypeWiFiConfigurationControl = "22D"
+
+type WiFiConfigurationControl struct {
+	*Bytes
+}
+
+func NewWiFiConfigurationControl() *WiFiConfigurationControl {
+	char := NewBytes(TypeWiFiConfigurationControl)
+	char.Format = FormatTLV8
+	char.Perms = []string{PermRead, PermWrite, PermEvents}
+
+	char.SetValue([]byte{})
+
+	return &WiFiConfigurationControl{char}
+}
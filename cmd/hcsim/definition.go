@@ -0,0 +1,222 @@
+package main
+
+import (
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
+
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+)
+
+// Definition describes the simulated accessories and the transport.
+//
+//	{
+//	  "name": "Simulator",
+//	  "pin": "00102003",
+//	  "accessories": [{
+//	    "name": "Lamp",
+//	    "category": 5,
+//	    "services": [{
+//	      "type": "43",
+//	      "characteristics": [{
+//	        "type": "25",
+//	        "format": "bool",
+//	        "perms": ["pr", "pw", "ev"],
+//	        "value": false,
+//	        "simulation": {"mode": "random", "interval": "10s"}
+//	      }]
+//	    }]
+//	  }]
+//	}
+type Definition struct {
+	Name        string         `json:"name"`
+	Pin         string         `json:"pin"`
+	Port        string         `json:"port"`
+	StoragePath string         `json:"storagePath"`
+	Accessories []AccessoryDef `json:"accessories"`
+}
+
+// AccessoryDef describes an accessory.
+type AccessoryDef struct {
+	Name         string       `json:"name"`
+	Manufacturer string       `json:"manufacturer"`
+	Model        string       `json:"model"`
+	SerialNumber string       `json:"serialNumber"`
+	Category     int          `json:"category"`
+	Services     []ServiceDef `json:"services"`
+
+	// Unreachable accessories fail reads with a communication failure status
+	Unreachable bool `json:"unreachable"`
+}
+
+// ServiceDef describes a service.
+type ServiceDef struct {
+	Type            string              `json:"type"`
+	Primary         bool                `json:"primary"`
+	Characteristics []CharacteristicDef `json:"characteristics"`
+}
+
+// CharacteristicDef describes a characteristic and how its value is simulated.
+type CharacteristicDef struct {
+	Type     string      `json:"type"`
+	Format   string      `json:"format"`
+	Perms    []string    `json:"perms"`
+	Unit     string      `json:"unit"`
+	MinValue interface{} `json:"minValue"`
+	MaxValue interface{} `json:"maxValue"`
+	Step     interface{} `json:"minStep"`
+	Value    interface{} `json:"value"`
+
+	// Latency delays writes by clients
+	Latency Duration `json:"latency"`
+
+	// Status is the HAP status code with which writes are rejected, e.g. -70403 (resource busy).
+	// ErrorRate is the probability of a rejection between 0 and 1. When 0, every write is rejected.
+	Status    int     `json:"status"`
+	ErrorRate float64 `json:"errorRate"`
+
+	Simulation *SimulationDef `json:"simulation"`
+}
+
+// Simulation modes
+const (
+	// ModeRandom sets random values within the min and max value
+	ModeRandom = "random"
+
+	// ModeScript sets the values of the script one after another
+	ModeScript = "script"
+)
+
+// SimulationDef describes how the value of a characteristic changes over time.
+type SimulationDef struct {
+	Mode     string        `json:"mode"`
+	Interval Duration      `json:"interval"`
+	Values   []interface{} `json:"values"`
+}
+
+// Duration is a time.Duration which is encoded as string in json, e.g. "500ms".
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	d.Duration = v
+	return nil
+}
+
+// ReadDefinition reads the definition from a json file.
+func ReadDefinition(path string) (*Definition, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var d Definition
+	if err := json.Unmarshal(b, &d); err != nil {
+		return nil, err
+	}
+
+	if len(d.Accessories) == 0 {
+		return nil, fmt.Errorf("%s defines no accessories", path)
+	}
+
+	return &d, nil
+}
+
+// NewAccessory returns the accessory of the definition.
+func NewAccessory(def AccessoryDef) (*accessory.Accessory, error) {
+	info := accessory.Info{
+		Name:         def.Name,
+		Manufacturer: def.Manufacturer,
+		Model:        def.Model,
+		SerialNumber: def.SerialNumber,
+	}
+
+	a := accessory.New(info, accessory.AccessoryType(def.Category))
+	for _, sdef := range def.Services {
+		if len(sdef.Type) == 0 {
+			return nil, fmt.Errorf("Service of %s has no type", def.Name)
+		}
+
+		s := service.New(sdef.Type)
+		s.Primary = sdef.Primary
+		for _, cdef := range sdef.Characteristics {
+			c, err := NewCharacteristic(cdef)
+			if err != nil {
+				return nil, err
+			}
+			s.AddCharacteristic(c)
+		}
+		a.AddService(s)
+	}
+
+	if def.Unreachable == true {
+		a.SetReachable(false)
+		a.FailReadsWhenUnreachable = true
+	}
+
+	return a, nil
+}
+
+// NewCharacteristic returns the characteristic of the definition.
+func NewCharacteristic(def CharacteristicDef) (*characteristic.Characteristic, error) {
+	if len(def.Type) == 0 || len(def.Format) == 0 {
+		return nil, fmt.Errorf("Characteristic %v has no type or format", def)
+	}
+
+	c := characteristic.NewCharacteristic(def.Type)
+	c.Format = def.Format
+	c.Perms = def.Perms
+	if len(c.Perms) == 0 {
+		c.Perms = characteristic.PermsAll()
+	}
+	c.Unit = def.Unit
+	c.MinValue = def.MinValue
+	c.MaxValue = def.MaxValue
+	c.StepValue = def.Step
+	c.Value = valueForFormat(def.Format, def.Value)
+
+	return c, nil
+}
+
+// valueForFormat returns v as the go type of the characteristic format.
+// Numbers are decoded as float64 from json, but integer formats require int values.
+func valueForFormat(format string, v interface{}) interface{} {
+	switch format {
+	case characteristic.FormatBool:
+		if b, ok := v.(bool); ok == true {
+			return b
+		}
+		return false
+	case characteristic.FormatFloat:
+		if f, ok := v.(float64); ok == true {
+			return f
+		}
+		return float64(0)
+	case characteristic.FormatUInt8, characteristic.FormatUInt16, characteristic.FormatUInt32, characteristic.FormatUInt64, characteristic.FormatInt32, characteristic.FormatInt64:
+		if f, ok := v.(float64); ok == true {
+			return int(f)
+		}
+		return 0
+	case characteristic.FormatString:
+		if s, ok := v.(string); ok == true {
+			return s
+		}
+		return ""
+	}
+
+	return v
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"github.com/brutella/hc/characteristic"
+
+	"testing"
+)
+
+func TestReadExampleDefinition(t *testing.T) {
+	def, err := ReadDefinition("example.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := len(def.Accessories), 3; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	for _, adef := range def.Accessories {
+		if _, err := NewAccessory(adef); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sim := def.Accessories[2].Services[0].Characteristics[0].Simulation
+	if is, want := sim.Interval.Seconds(), float64(60); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestNewCharacteristic(t *testing.T) {
+	def := CharacteristicDef{Type: "8", Format: characteristic.FormatInt32, MinValue: float64(10), MaxValue: float64(20), Value: float64(15)}
+	c, err := NewCharacteristic(def)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := c.Value, 15; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	for i := 0; i < 100; i++ {
+		if v := RandomValue(c).(int); v < 10 || v > 20 {
+			t.Fatal(v)
+		}
+	}
+
+	if _, err := NewCharacteristic(CharacteristicDef{Type: "8"}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestInjectFaults(t *testing.T) {
+	c, _ := NewCharacteristic(CharacteristicDef{Type: "25", Format: characteristic.FormatBool})
+	InjectFaults(c, CharacteristicDef{Status: -70403})
+
+	err := c.UpdateValueFromConnection(true, characteristic.TestConn)
+	se, ok := err.(*characteristic.StatusError)
+	if ok == false {
+		t.Fatal(err)
+	}
+
+	if is, want := se.Status, -70403; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
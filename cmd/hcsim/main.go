@@ -0,0 +1,83 @@
+// Runs simulated accessories, which are defined in a json file (see Definition).
+//
+// The simulated characteristics change their values randomly or as scripted,
+// and writes of clients can be delayed and rejected with a HAP status code.
+// This is useful to develop and demo HomeKit automations without hardware.
+//
+//	hcsim -definition accessories.json
+package main
+
+import (
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/hap"
+	"github.com/brutella/log"
+
+	"flag"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+func main() {
+	var path = flag.String("definition", "hcsim.json", "Path to the accessory definition")
+	flag.Parse()
+
+	def, err := ReadDefinition(*path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rand.Seed(time.Now().UnixNano())
+
+	var as []*accessory.Accessory
+	for _, adef := range def.Accessories {
+		a, err := NewAccessory(adef)
+		if err != nil {
+			log.Fatal(err)
+		}
+		as = append(as, a)
+	}
+
+	if len(as) == 0 {
+		log.Fatal(fmt.Errorf("%s defines no accessories", *path))
+	}
+
+	// Multiple accessories are bridged by a bridge accessory
+	if len(as) > 1 {
+		name := def.Name
+		if len(name) == 0 {
+			name = "Simulator"
+		}
+		bridge := accessory.New(accessory.Info{Name: name}, accessory.TypeBridge)
+		as = append([]*accessory.Accessory{bridge}, as...)
+	}
+
+	config := hap.Config{Pin: def.Pin, Port: def.Port, StoragePath: def.StoragePath}
+	t, err := hap.NewIPTransport(config, as[0], as[1:]...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Characteristics of the bridge are not simulated
+	if len(as) > len(def.Accessories) {
+		as = as[1:]
+	}
+
+	for i, adef := range def.Accessories {
+		// The first service is the accessory information service
+		services := as[i].GetServices()[1:]
+		for j, sdef := range adef.Services {
+			for k, cdef := range sdef.Characteristics {
+				c := services[j].Characteristics[k]
+				InjectFaults(c, cdef)
+				Simulate(t, c, cdef.Simulation)
+			}
+		}
+	}
+
+	hap.OnTermination(func() {
+		t.Stop()
+	})
+
+	t.Start()
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/hap"
+	"github.com/brutella/log"
+	"github.com/gosexy/to"
+
+	"math/rand"
+	"time"
+)
+
+// defaultMaxValue is the max value of random numbers when the characteristic has no max value
+const defaultMaxValue = 100
+
+// InjectFaults delays and rejects writes of clients as defined.
+func InjectFaults(c *characteristic.Characteristic, def CharacteristicDef) {
+	if def.Latency.Duration <= 0 && def.Status == 0 {
+		return
+	}
+
+	c.OnBeforeRemoteUpdate(func(value interface{}) error {
+		time.Sleep(def.Latency.Duration)
+
+		if def.Status != 0 && (def.ErrorRate <= 0 || rand.Float64() < def.ErrorRate) {
+			log.Printf("[INFO] Reject value %v of characteristic %s with status %d\n", value, c.Type, def.Status)
+			return characteristic.NewStatusError(def.Status, "simulated error")
+		}
+
+		return nil
+	})
+}
+
+// Simulate schedules the value changes of the characteristic on the transport.
+func Simulate(t hap.Transport, c *characteristic.Characteristic, def *SimulationDef) {
+	if def == nil {
+		return
+	}
+
+	interval := def.Interval.Duration
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	var next func() interface{}
+	switch def.Mode {
+	case ModeRandom:
+		next = func() interface{} {
+			return RandomValue(c)
+		}
+	case ModeScript:
+		if len(def.Values) == 0 {
+			log.Printf("[WARN] Script of characteristic %s has no values\n", c.Type)
+			return
+		}
+
+		i := 0
+		next = func() interface{} {
+			v := def.Values[i%len(def.Values)]
+			i++
+			return valueForFormat(c.Format, v)
+		}
+	default:
+		log.Printf("[WARN] Unknown simulation mode %s\n", def.Mode)
+		return
+	}
+
+	t.Schedule(hap.Every(interval), func() {
+		v := next()
+		log.Printf("[VERB] Set value %v of characteristic %s\n", v, c.Type)
+		c.UpdateValue(v)
+	})
+}
+
+// RandomValue returns a random value within the min and max value of the characteristic.
+func RandomValue(c *characteristic.Characteristic) interface{} {
+	min, max := float64(0), float64(defaultMaxValue)
+	if c.MinValue != nil {
+		min = to.Float64(c.MinValue)
+	}
+	if c.MaxValue != nil {
+		max = to.Float64(c.MaxValue)
+	}
+
+	switch c.Format {
+	case characteristic.FormatBool:
+		return rand.Intn(2) == 1
+	case characteristic.FormatFloat:
+		return min + rand.Float64()*(max-min)
+	case characteristic.FormatUInt8, characteristic.FormatUInt16, characteristic.FormatUInt32, characteristic.FormatUInt64, characteristic.FormatInt32, characteristic.FormatInt64:
+		return int(min) + rand.Intn(int(max-min)+1)
+	}
+
+	return c.Value
+}
@@ -1,5 +1,6 @@
 // Imports HomeKit metadata from a file and creates files for every characteristic and service.
-// It finishes by running `go fmt` in the characterist and service packages.
+// Vendor characteristics (e.g. Eve) are imported from separate metadata files into their own packages.
+// It finishes by running `go fmt` in the characterist, service and vendor packages.
 //
 // The metadata file is created by running the following command on OS X
 //
@@ -22,28 +23,15 @@ var SvcPkgPath = filepath.Join(LibPath, "service")
 var CharPkgPath = filepath.Join(LibPath, "characteristic")
 var MetadataPath = filepath.Join(GenPath, "metadata.json")
 
+// VendorMetadataPaths maps vendor package names to their metadata file.
+var VendorMetadataPaths = map[string]string{
+	"eve": filepath.Join(GenPath, "eve.json"),
+}
+
 func main() {
 
 	log.Println("Import data from", MetadataPath)
-
-	// Open metadata file
-	f, err := os.Open(MetadataPath)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// Read content
-	b, err := ioutil.ReadAll(f)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// Import json
-	metadata := gen.Metadata{}
-	err = json.Unmarshal(b, &metadata)
-	if err != nil {
-		log.Fatal(err)
-	}
+	metadata := readMetadata(MetadataPath)
 
 	// Create characteristic files
 	for _, char := range metadata.Characteristics {
@@ -57,7 +45,7 @@ func main() {
 				log.Fatal(err)
 			} else {
 				if _, err := f.Write(b); err != nil {
-					log.Fatal()
+					log.Fatal(err)
 				}
 			}
 		}
@@ -75,7 +63,52 @@ func main() {
 				log.Fatal(err)
 			} else {
 				if _, err := f.Write(b); err != nil {
-					log.Fatal()
+					log.Fatal(err)
+				}
+			}
+		}
+	}
+
+	// Create service spec file
+	if b, err := gen.ServiceSpecGoCode(metadata.Services, metadata.Characteristics); err != nil {
+		log.Println(err)
+	} else {
+		filePath := filepath.Join(SvcPkgPath, gen.ServiceSpecFileName)
+		log.Println("Creating file", filePath)
+		if err := ioutil.WriteFile(filePath, b, 0666); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Create file with the names of valid values
+	if b, err := gen.ValidValuesGoCode(metadata.Characteristics); err != nil {
+		log.Println(err)
+	} else {
+		filePath := filepath.Join(CharPkgPath, gen.ValidValuesFileName)
+		log.Println("Creating file", filePath)
+		if err := ioutil.WriteFile(filePath, b, 0666); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Create vendor characteristic files
+	for pkg, path := range VendorMetadataPaths {
+		log.Println("Import vendor data from", path)
+		vendor := readMetadata(path)
+		pkgPath := filepath.Join(CharPkgPath, pkg)
+		for _, char := range vendor.Characteristics {
+			log.Printf("Processing %s Characteristic", char.Name)
+			if b, err := gen.VendorCharacteristicGoCode(char, pkg); err != nil {
+				log.Println(err)
+			} else {
+				filePath := filepath.Join(pkgPath, gen.FileName(char))
+				log.Println("Creating file", filePath)
+				if f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666); err != nil {
+					log.Fatal(err)
+				} else {
+					if _, err := f.Write(b); err != nil {
+						log.Fatal(err)
+					}
 				}
 			}
 		}
@@ -94,4 +127,33 @@ func main() {
 	if err := svcCmd.Run(); err != nil {
 		log.Fatal(err)
 	}
+
+	for pkg := range VendorMetadataPaths {
+		vendorCmd := exec.Command("go", "fmt")
+		vendorCmd.Dir = filepath.Join(CharPkgPath, pkg)
+		if err := vendorCmd.Run(); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// readMetadata returns the metadata stored in the json file at path.
+func readMetadata(path string) gen.Metadata {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	metadata := gen.Metadata{}
+	if err := json.Unmarshal(b, &metadata); err != nil {
+		log.Fatal(err)
+	}
+
+	return metadata
 }
@@ -0,0 +1,148 @@
+package coap
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/brutella/hc/netio"
+)
+
+var errReadNotSupported = errors.New("coap: read not supported")
+
+// conn is the connection to a peer. It implements net.Conn so that the peer
+// has a session in the HAP context like a TCP connection.
+//
+// Requests are read by the server. Writes to the connection send events.
+type conn struct {
+	server  *Server
+	addr    *net.UDPAddr
+	context netio.HAPContext
+
+	mutex      sync.Mutex
+	lastID     uint16
+	lastResult []byte
+
+	// lastSeen is the time of the last request of the peer, which is guarded by the mutex of the server
+	lastSeen time.Time
+}
+
+func newConn(s *Server, addr *net.UDPAddr) *conn {
+	c := conn{
+		server:  s,
+		addr:    addr,
+		context: s.context,
+	}
+	c.resetSession()
+
+	return &c
+}
+
+// resetSession creates a new session for the peer.
+func (c *conn) resetSession() {
+	c.context.SetSessionForConnection(netio.NewSession(c), c)
+}
+
+// decrypt decrypts b when the session is encrypted.
+func (c *conn) decrypt(b []byte) ([]byte, error) {
+	session := c.context.GetSessionForConnection(c)
+	if session == nil {
+		return nil, errors.New("coap: no session")
+	}
+
+	d := session.Decrypter()
+	if d == nil {
+		return b, nil
+	}
+
+	r, err := d.Decrypt(bytes.NewBuffer(b))
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadAll(r)
+}
+
+// encrypt encrypts b when the session is encrypted.
+func (c *conn) encrypt(b []byte) ([]byte, error) {
+	session := c.context.GetSessionForConnection(c)
+	if session == nil {
+		return nil, errors.New("coap: no session")
+	}
+
+	e := session.Encrypter()
+	if e == nil {
+		return b, nil
+	}
+
+	r, err := e.Encrypt(bytes.NewBuffer(b))
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadAll(r)
+}
+
+// WriteEvent sends the json body of an event as non-confirmable message.
+func (c *conn) WriteEvent(body []byte) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	payload, err := c.encrypt(body)
+	if err != nil {
+		return err
+	}
+
+	m := Message{
+		Type:      TypeNonConfirmable,
+		Code:      CodeContent,
+		MessageID: c.server.nextMessageID(),
+		Payload:   payload,
+	}
+	m.SetPath(eventPath)
+
+	return c.server.send(&m, c.addr)
+}
+
+// Write sends b as event.
+func (c *conn) Write(b []byte) (int, error) {
+	return len(b), c.WriteEvent(b)
+}
+
+func (c *conn) Read(b []byte) (int, error) {
+	return 0, errReadNotSupported
+}
+
+// Close removes the session of the peer.
+func (c *conn) Close() error {
+	c.context.DeleteSessionForConnection(c)
+	return nil
+}
+
+func (c *conn) LocalAddr() net.Addr {
+	return c.server.conn.LocalAddr()
+}
+
+func (c *conn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+func (c *conn) SetDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *conn) SetReadDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *conn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+// Context returns the context of the connection.
+func (c *conn) Context() netio.HAPContext {
+	return c.context
+}
@@ -0,0 +1,10 @@
+// Package coap implements an experimental transport of the HomeKit Accessory Protocol over CoAP (RFC 7252),
+// e.g. for accessories in a Thread network which are reachable via an OpenThread border router.
+//
+// The transport reuses the pairing and the endpoints of the TCP server. Every CoAP request is
+// translated into a HTTP request with the same path and handled by the same router. After pair verify,
+// the payloads of requests and responses are encrypted with the session keys. Requests which can't be
+// decrypted are dropped. The sessions of peers which don't send requests for 30 minutes are removed.
+//
+// Block-wise transfers are not supported. Responses must therefore fit into a single UDP datagram.
+package coap
@@ -0,0 +1,241 @@
+package coap
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+	"strings"
+)
+
+// Type is the type of a message.
+type Type uint8
+
+// Message types
+const (
+	TypeConfirmable     Type = 0
+	TypeNonConfirmable  Type = 1
+	TypeAcknowledgement Type = 2
+	TypeReset           Type = 3
+)
+
+// Code is the method of a request or the status of a response.
+// The upper 3 bits are the class, the lower 5 bits are the detail.
+type Code uint8
+
+// Request methods
+const (
+	CodeEmpty  Code = 0x00
+	CodeGET    Code = 0x01
+	CodePOST   Code = 0x02
+	CodePUT    Code = 0x03
+	CodeDELETE Code = 0x04
+)
+
+// Response codes
+const (
+	CodeCreated             Code = 0x41 // 2.01
+	CodeDeleted             Code = 0x42 // 2.02
+	CodeChanged             Code = 0x44 // 2.04
+	CodeContent             Code = 0x45 // 2.05
+	CodeBadRequest          Code = 0x80 // 4.00
+	CodeUnauthorized        Code = 0x81 // 4.01
+	CodeNotFound            Code = 0x84 // 4.04
+	CodeMethodNotAllowed    Code = 0x85 // 4.05
+	CodeInternalServerError Code = 0xA0 // 5.00
+)
+
+// IsRequest returns true when c is a request method.
+func (c Code) IsRequest() bool {
+	return c != CodeEmpty && c>>5 == 0
+}
+
+// Option numbers
+const (
+	OptionObserve       uint16 = 6
+	OptionURIPath       uint16 = 11
+	OptionContentFormat uint16 = 12
+	OptionURIQuery      uint16 = 15
+)
+
+const (
+	version       = 1
+	payloadMarker = 0xFF
+)
+
+var errInvalidMessage = errors.New("coap: invalid message")
+
+// Option is an option of a message.
+type Option struct {
+	Number uint16
+	Value  []byte
+}
+
+type byNumber []Option
+
+func (o byNumber) Len() int           { return len(o) }
+func (o byNumber) Swap(i, j int)      { o[i], o[j] = o[j], o[i] }
+func (o byNumber) Less(i, j int) bool { return o[i].Number < o[j].Number }
+
+// Message is a CoAP message as defined in RFC 7252.
+type Message struct {
+	Type      Type
+	Code      Code
+	MessageID uint16
+	Token     []byte
+	Options   []Option
+	Payload   []byte
+}
+
+// Path returns the URI path of the message, e.g. "/characteristics".
+func (m *Message) Path() string {
+	var segments []string
+	for _, o := range m.Options {
+		if o.Number == OptionURIPath {
+			segments = append(segments, string(o.Value))
+		}
+	}
+
+	return "/" + strings.Join(segments, "/")
+}
+
+// SetPath sets the URI path of the message.
+func (m *Message) SetPath(path string) {
+	for _, s := range strings.Split(strings.Trim(path, "/"), "/") {
+		if len(s) > 0 {
+			m.Options = append(m.Options, Option{Number: OptionURIPath, Value: []byte(s)})
+		}
+	}
+}
+
+// Query returns the URI query of the message, e.g. "id=1.10".
+func (m *Message) Query() string {
+	var params []string
+	for _, o := range m.Options {
+		if o.Number == OptionURIQuery {
+			params = append(params, string(o.Value))
+		}
+	}
+
+	return strings.Join(params, "&")
+}
+
+// Bytes returns the encoded message.
+func (m *Message) Bytes() ([]byte, error) {
+	if len(m.Token) > 8 {
+		return nil, errors.New("coap: token too long")
+	}
+
+	b := []byte{version<<6 | byte(m.Type)<<4 | byte(len(m.Token)), byte(m.Code), 0, 0}
+	binary.BigEndian.PutUint16(b[2:], m.MessageID)
+	b = append(b, m.Token...)
+
+	options := make([]Option, len(m.Options))
+	copy(options, m.Options)
+	sort.Stable(byNumber(options))
+
+	var last uint16
+	for _, o := range options {
+		delta, deltaExt := optionNibble(int(o.Number - last))
+		length, lengthExt := optionNibble(len(o.Value))
+
+		b = append(b, delta<<4|length)
+		b = append(b, deltaExt...)
+		b = append(b, lengthExt...)
+		b = append(b, o.Value...)
+		last = o.Number
+	}
+
+	if len(m.Payload) > 0 {
+		b = append(b, payloadMarker)
+		b = append(b, m.Payload...)
+	}
+
+	return b, nil
+}
+
+// ParseMessage returns the message encoded in b.
+func ParseMessage(b []byte) (*Message, error) {
+	if len(b) < 4 || b[0]>>6 != version {
+		return nil, errInvalidMessage
+	}
+
+	m := Message{
+		Type:      Type(b[0] >> 4 & 0x3),
+		Code:      Code(b[1]),
+		MessageID: binary.BigEndian.Uint16(b[2:4]),
+	}
+
+	tkl := int(b[0] & 0xF)
+	if tkl > 8 || len(b) < 4+tkl {
+		return nil, errInvalidMessage
+	}
+	m.Token = append([]byte{}, b[4:4+tkl]...)
+	b = b[4+tkl:]
+
+	var number int
+	for len(b) > 0 {
+		if b[0] == payloadMarker {
+			if len(b) == 1 {
+				return nil, errInvalidMessage
+			}
+			m.Payload = append([]byte{}, b[1:]...)
+			break
+		}
+
+		delta, length := int(b[0]>>4), int(b[0]&0xF)
+		b = b[1:]
+
+		var err error
+		if delta, b, err = optionValue(delta, b); err != nil {
+			return nil, err
+		}
+		if length, b, err = optionValue(length, b); err != nil {
+			return nil, err
+		}
+
+		if len(b) < length {
+			return nil, errInvalidMessage
+		}
+
+		number += delta
+		m.Options = append(m.Options, Option{Number: uint16(number), Value: append([]byte{}, b[:length]...)})
+		b = b[length:]
+	}
+
+	return &m, nil
+}
+
+// optionNibble returns the 4-bit value and the extended bytes of an option delta or length.
+func optionNibble(v int) (byte, []byte) {
+	switch {
+	case v < 13:
+		return byte(v), nil
+	case v < 269:
+		return 13, []byte{byte(v - 13)}
+	default:
+		ext := make([]byte, 2)
+		binary.BigEndian.PutUint16(ext, uint16(v-269))
+		return 14, ext
+	}
+}
+
+// optionValue returns the option delta or length of the 4-bit value v,
+// which is followed by extended bytes in b.
+func optionValue(v int, b []byte) (int, []byte, error) {
+	switch v {
+	case 13:
+		if len(b) < 1 {
+			return 0, b, errInvalidMessage
+		}
+		return int(b[0]) + 13, b[1:], nil
+	case 14:
+		if len(b) < 2 {
+			return 0, b, errInvalidMessage
+		}
+		return int(binary.BigEndian.Uint16(b)) + 269, b[2:], nil
+	case 15:
+		return 0, b, errInvalidMessage
+	}
+
+	return v, b, nil
+}
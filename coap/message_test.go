@@ -0,0 +1,60 @@
+package coap
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMessageRoundTrip(t *testing.T) {
+	m := Message{
+		Type:      TypeConfirmable,
+		Code:      CodePUT,
+		MessageID: 0x1234,
+		Token:     []byte{0x01, 0x02},
+		Options: []Option{
+			{Number: OptionURIQuery, Value: []byte("id=1.10")},
+			{Number: OptionURIQuery, Value: []byte("ev=1")},
+		},
+		Payload: []byte("{}"),
+	}
+	m.SetPath("/characteristics")
+
+	// Long options use extended lengths
+	m.Options = append(m.Options, Option{Number: 300, Value: bytes.Repeat([]byte{0xAB}, 20)})
+
+	b, err := m.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseMessage(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := parsed.Path(), "/characteristics"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := parsed.Query(), "id=1.10&ev=1"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := parsed.MessageID, m.MessageID; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := parsed.Options[len(parsed.Options)-1].Number, uint16(300); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := string(parsed.Payload), "{}"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestInvalidMessage(t *testing.T) {
+	if _, err := ParseMessage([]byte{0x40, 0x01}); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,312 @@
+package coap
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/brutella/hc/netio"
+	"github.com/brutella/log"
+)
+
+// eventPath is the path of event messages
+const eventPath = "/characteristics"
+
+// maxMessageSize is the maximum size of a UDP datagram
+const maxMessageSize = 0xFFFF
+
+// idleTimeout is the duration after which the session of a peer, which didn't send a request, is removed.
+// The peer has to verify the pairing again afterwards.
+const idleTimeout = 30 * time.Minute
+
+// Server serves HAP requests over CoAP.
+//
+// Every CoAP request is translated into a HTTP request and handled by the
+// same handler as requests of the TCP server (see server.NewRouter). Peers
+// are identified by their address and have a session in the context.
+// The connection of the peer is carried by the request context.
+// The session is used to pair and to en-/decrypt the payload of messages.
+type Server struct {
+	conn    *net.UDPConn
+	port    int
+	handler http.Handler
+	context netio.HAPContext
+
+	mutex  sync.Mutex
+	conns  map[string]*conn
+	nextID uint16
+
+	// idleTimeout is the duration after which idle peers are removed
+	idleTimeout time.Duration
+}
+
+// NewServer returns a server which listens on port and handles requests with h.
+// If port is empty, a free port is used.
+func NewServer(port string, h http.Handler, context netio.HAPContext) (*Server, error) {
+	addr, err := net.ResolveUDPAddr("udp", port)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewServerWithConn(c, h, context), nil
+}
+
+// NewServerWithConn returns a server which receives requests on c and handles them with h.
+func NewServerWithConn(c *net.UDPConn, h http.Handler, context netio.HAPContext) *Server {
+	s := Server{
+		conn:    c,
+		port:    c.LocalAddr().(*net.UDPAddr).Port,
+		handler: h,
+		context: context,
+		conns:   map[string]*conn{},
+
+		idleTimeout: idleTimeout,
+	}
+
+	return &s
+}
+
+// Port returns the port on which the server listens to.
+func (s *Server) Port() int {
+	return s.port
+}
+
+// ListenAndServe handles messages until Stop is called.
+func (s *Server) ListenAndServe() error {
+	b := make([]byte, maxMessageSize)
+	for {
+		n, addr, err := s.conn.ReadFromUDP(b)
+		if err != nil {
+			return err
+		}
+
+		m, err := ParseMessage(b[:n])
+		if err != nil {
+			log.Println("[VERB]", addr, err)
+			continue
+		}
+
+		if m.Code.IsRequest() == false {
+			// Acknowledgements of events are ignored
+			continue
+		}
+
+		if c := s.connForAddr(addr); c != nil {
+			s.handle(m, c)
+		}
+	}
+}
+
+// Stop stops the server and removes the sessions of all peers.
+func (s *Server) Stop() {
+	s.mutex.Lock()
+	for key, c := range s.conns {
+		c.Close()
+		delete(s.conns, key)
+	}
+	s.mutex.Unlock()
+
+	s.conn.Close()
+}
+
+// connForAddr returns the connection of the peer with address addr
+// and removes the connections of idle peers.
+func (s *Server) connForAddr(addr *net.UDPAddr) *conn {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	s.removeIdleConns(now)
+
+	key := addr.String()
+	c, ok := s.conns[key]
+	if ok == false {
+		c = newConn(s, addr)
+		s.conns[key] = c
+	}
+	c.lastSeen = now
+
+	return c
+}
+
+// removeIdleConns removes the connections and sessions of peers which didn't send a request
+// since the idle timeout. The caller must hold the mutex.
+func (s *Server) removeIdleConns(now time.Time) {
+	for key, c := range s.conns {
+		if now.Sub(c.lastSeen) > s.idleTimeout {
+			log.Println("[VERB] Removing idle peer", key)
+			c.Close()
+			delete(s.conns, key)
+		}
+	}
+}
+
+func (s *Server) nextMessageID() uint16 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.nextID++
+	return s.nextID
+}
+
+func (s *Server) send(m *Message, addr *net.UDPAddr) error {
+	b, err := m.Bytes()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.conn.WriteToUDP(b, addr)
+	return err
+}
+
+// handle handles the request m of the peer c.
+func (s *Server) handle(m *Message, c *conn) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Retransmitted requests are answered with the previous response
+	// because the payload was already decrypted.
+	if m.Type == TypeConfirmable && m.MessageID == c.lastID && c.lastResult != nil {
+		s.conn.WriteToUDP(c.lastResult, c.addr)
+		return
+	}
+
+	res := s.response(m, c)
+	if res == nil {
+		return
+	}
+
+	b, err := res.Bytes()
+	if err != nil {
+		log.Println("[ERRO]", err)
+		return
+	}
+
+	if m.Type == TypeConfirmable {
+		c.lastID = m.MessageID
+		c.lastResult = b
+	}
+
+	if _, err := s.conn.WriteToUDP(b, c.addr); err != nil {
+		log.Println("[ERRO]", err)
+	}
+}
+
+// response returns the response message of the request m,
+// or nil when the request is dropped.
+func (s *Server) response(m *Message, c *conn) *Message {
+	res := Message{
+		Type:      TypeAcknowledgement,
+		MessageID: m.MessageID,
+		Token:     m.Token,
+	}
+
+	if m.Type != TypeConfirmable {
+		res.Type = TypeNonConfirmable
+		res.MessageID = s.nextMessageID()
+	}
+
+	path := m.Path()
+	body, err := c.decrypt(m.Payload)
+	if err != nil {
+		// Undecryptable packets, e.g. with a spoofed sender address, don't change the session
+		log.Println("[WARN] Dropping undecryptable request from", c.addr, err)
+		return nil
+	}
+
+	method, ok := methods[m.Code]
+	if ok == false {
+		res.Code = CodeMethodNotAllowed
+		return &res
+	}
+
+	url := path
+	if query := m.Query(); len(query) > 0 {
+		url += "?" + query
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewBuffer(body))
+	if err != nil {
+		res.Code = CodeBadRequest
+		return &res
+	}
+	req.RemoteAddr = c.addr.String()
+	req = req.WithContext(netio.WithConnection(req.Context(), c))
+
+	log.Printf("[VERB] %v CoAP %s %s", c.addr, method, url)
+
+	w := newResponseWriter()
+	s.handler.ServeHTTP(w, req)
+
+	if res.Payload, err = c.encrypt(w.body.Bytes()); err != nil {
+		log.Println("[ERRO] Encryption failed:", err)
+		res.Code = CodeInternalServerError
+		res.Payload = nil
+		return &res
+	}
+	res.Code = codeForStatus(w.status)
+
+	return &res
+}
+
+var methods = map[Code]string{
+	CodeGET:    netio.MethodGET,
+	CodePOST:   netio.MethodPOST,
+	CodePUT:    netio.MethodPUT,
+	CodeDELETE: netio.MethodDEL,
+}
+
+// codeForStatus returns the response code of a HTTP status code.
+func codeForStatus(status int) Code {
+	switch status {
+	case http.StatusOK, http.StatusMultiStatus:
+		return CodeContent
+	case http.StatusNoContent:
+		return CodeChanged
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	}
+
+	if status >= 200 && status < 300 {
+		return CodeContent
+	}
+
+	return CodeInternalServerError
+}
+
+// responseWriter records the response of a http.Handler.
+type responseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newResponseWriter() *responseWriter {
+	return &responseWriter{
+		header: http.Header{},
+		status: http.StatusOK,
+	}
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	w.status = status
+}
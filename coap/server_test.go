@@ -0,0 +1,101 @@
+package coap
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brutella/hc/netio"
+)
+
+func TestServer(t *testing.T) {
+	context := netio.NewContextForSecuredDevice(nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/identify", func(w http.ResponseWriter, r *http.Request) {
+		if context.GetSessionForRequest(r) == nil {
+			t.Error("missing session")
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		w.Write(b)
+	})
+
+	s, err := NewServer("127.0.0.1:0", mux, context)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.ListenAndServe()
+	defer s.Stop()
+
+	conn, err := net.Dial("udp", s.conn.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	req := Message{
+		Type:      TypeConfirmable,
+		Code:      CodePOST,
+		MessageID: 7,
+		Token:     []byte{0xAA},
+		Payload:   []byte("hello"),
+	}
+	req.SetPath("/identify")
+
+	b, _ := req.Bytes()
+	if _, err := conn.Write(b); err != nil {
+		t.Fatal(err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := ParseMessage(buf[:n])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := res.Type, TypeAcknowledgement; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := res.Code, CodeContent; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := res.MessageID, req.MessageID; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := string(res.Payload), "hello"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestRemoveIdlePeers(t *testing.T) {
+	context := netio.NewContextForSecuredDevice(nil)
+	s, err := NewServer("127.0.0.1:0", http.NewServeMux(), context)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	idle := s.connForAddr(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1000})
+	idle.lastSeen = time.Now().Add(-2 * idleTimeout)
+
+	s.connForAddr(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1001})
+
+	if is, want := len(s.conns), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if context.GetSessionForConnection(idle) != nil {
+		t.Fatal("expected no session")
+	}
+}
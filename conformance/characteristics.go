@@ -0,0 +1,117 @@
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/netio"
+	"github.com/brutella/hc/netio/controller"
+	"github.com/brutella/hc/netio/data"
+	"github.com/gosexy/to"
+)
+
+func newSwitch() (*accessory.Switch, *controller.CharacteristicController) {
+	a := accessory.NewSwitch(accessory.Info{Name: "Switch"})
+	c := accessory.NewContainer()
+	c.AddAccessory(a.Accessory)
+
+	return a, controller.NewCharacteristicController(c)
+}
+
+// write writes the characteristics chs and returns the response.
+func write(ctr *controller.CharacteristicController, chs ...data.Characteristic) ([]data.Characteristic, error) {
+	b, err := json.Marshal(data.Characteristics{Characteristics: chs})
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := ctr.HandleUpdateCharacteristics(bytes.NewReader(b), characteristic.TestConn)
+	if err != nil || r == nil {
+		return nil, err
+	}
+
+	var res data.Characteristics
+	if err := json.NewDecoder(r).Decode(&res); err != nil {
+		return nil, err
+	}
+
+	return res.Characteristics, nil
+}
+
+// expectStatus writes ch and returns an error if the response doesn't include status.
+func expectStatus(ctr *controller.CharacteristicController, ch data.Characteristic, status int) error {
+	res, err := write(ctr, ch)
+	if err != nil {
+		return err
+	}
+
+	if len(res) != 1 {
+		return fmt.Errorf("Unexpected response %v", res)
+	}
+
+	if is := to.Int64(res[0].Status); is != int64(status) {
+		return fmt.Errorf("Unexpected status %d instead of %d", is, status)
+	}
+
+	return nil
+}
+
+func testWriteReadOnlyCharacteristic() error {
+	a, ctr := newSwitch()
+	model := a.Info.Model
+
+	ch := data.Characteristic{AccessoryID: a.ID, CharacteristicID: model.ID, Value: "Other"}
+	if err := expectStatus(ctr, ch, netio.StatusReadOnlyCharacteristic); err != nil {
+		return err
+	}
+
+	if model.GetValue() == "Other" {
+		return fmt.Errorf("Read-only value was written")
+	}
+
+	return nil
+}
+
+func testWriteUnknownCharacteristic() error {
+	a, ctr := newSwitch()
+
+	ch := data.Characteristic{AccessoryID: a.ID, CharacteristicID: 999, Value: true}
+	return expectStatus(ctr, ch, netio.StatusResourceDoesNotExist)
+}
+
+func testWriteRejectedValue() error {
+	a, ctr := newSwitch()
+	a.Switch.On.OnBeforeRemoteUpdate(func(v interface{}) error {
+		return characteristic.NewStatusError(netio.StatusResourceBusy, "busy")
+	})
+
+	ch := data.Characteristic{AccessoryID: a.ID, CharacteristicID: a.Switch.On.ID, Value: true}
+	if err := expectStatus(ctr, ch, netio.StatusResourceBusy); err != nil {
+		return err
+	}
+
+	if a.Switch.On.GetValue() == true {
+		return fmt.Errorf("Rejected value was written")
+	}
+
+	return nil
+}
+
+func testWriteWithResponse() error {
+	a, ctr := newSwitch()
+
+	ch := data.Characteristic{AccessoryID: a.ID, CharacteristicID: a.Switch.On.ID, Value: true, Response: true}
+	res, err := write(ctr, ch)
+	if err != nil {
+		return err
+	}
+
+	if len(res) != 1 || res[0].Value != true {
+		return fmt.Errorf("Unexpected response %v", res)
+	}
+
+	return nil
+}
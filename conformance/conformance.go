@@ -0,0 +1,53 @@
+package conformance
+
+import (
+	"fmt"
+)
+
+// Case is a conformance test case.
+type Case struct {
+	Name string
+
+	// Run returns an error when the implementation does not conform.
+	Run func() error
+}
+
+// Cases are the conformance test cases of this package.
+var Cases = []Case{
+	{"pair-setup", testPairSetup},
+	{"pair-setup with wrong setup code", testPairSetupWrongCode},
+	{"pair-setup with malformed tlv8", testPairSetupMalformedTLV},
+	{"pair-setup with unexpected step", testPairSetupUnexpectedStep},
+	{"pair-setup with unknown step", testPairSetupUnknownStep},
+	{"pair-setup with unknown method", testPairSetupUnknownMethod},
+	{"pair-verify", testPairVerify},
+	{"pair-verify with unknown controller", testPairVerifyUnknownController},
+	{"pair-verify with unexpected step", testPairVerifyUnexpectedStep},
+	{"write read-only characteristic", testWriteReadOnlyCharacteristic},
+	{"write unknown characteristic", testWriteUnknownCharacteristic},
+	{"write rejected value", testWriteRejectedValue},
+	{"write with response", testWriteWithResponse},
+	{"srp test vectors", testSRPVectors},
+	{"curve25519 test vectors", testCurve25519Vectors},
+}
+
+// Run runs cases and returns the report.
+// A case which panics fails.
+func Run(cases []Case) Report {
+	var r Report
+	for _, c := range cases {
+		r.Results = append(r.Results, Result{Name: c.Name, Err: run(c)})
+	}
+
+	return r
+}
+
+func run(c Case) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("panic: %v", v)
+		}
+	}()
+
+	return c.Run()
+}
@@ -0,0 +1,35 @@
+package conformance
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	for _, res := range Run(Cases).Results {
+		if res.Passed() == false {
+			t.Error(res)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	cases := []Case{
+		{"pass", func() error { return nil }},
+		{"fail", func() error { return errors.New("wrong") }},
+		{"panic", func() error { panic("boom") }},
+	}
+
+	r := Run(cases)
+	if is, want := len(r.Failed()), 2; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	var b bytes.Buffer
+	r.WriteTo(&b)
+	if is, want := strings.Split(b.String(), "\n")[3], "1 passed, 2 failed"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,13 @@
+// Package conformance checks the implementation against the HomeKit Accessory Protocol specification.
+//
+// The cases run pair setup and pair verify with valid and invalid requests (wrong setup code,
+// malformed TLV8 data, unexpected pairing steps), write characteristics with invalid values
+// and verify the crypto primitives against the test vectors of the specification.
+//
+// Run returns a report of the passed and failed cases.
+//
+//	report := conformance.Run(conformance.Cases)
+//	report.WriteTo(os.Stdout)
+//
+// Forks of this package can append their own cases to Cases.
+package conformance
@@ -0,0 +1,322 @@
+package conformance
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/brutella/hc/db"
+	"github.com/brutella/hc/haptest"
+	"github.com/brutella/hc/netio"
+	"github.com/brutella/hc/netio/pair"
+	"github.com/brutella/hc/util"
+)
+
+// testPin is the setup code of the accessory
+const testPin = "001-02-003"
+
+// fixture is a paired accessory and controller
+type fixture struct {
+	accessory netio.SecuredDevice
+	database  db.Database
+
+	controller         netio.Device
+	controllerDatabase db.Database
+}
+
+func newFixture() (*fixture, error) {
+	database := haptest.NewDatabase()
+	accessory, err := netio.NewSecuredDevice("Accessory", testPin, database)
+	if err != nil {
+		return nil, err
+	}
+
+	controllerDatabase := haptest.NewDatabase()
+	controller, err := netio.NewDevice("Controller", controllerDatabase)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fixture{accessory, database, controller, controllerDatabase}, nil
+}
+
+// pairSetup runs pair setup with the setup code pin.
+func (f *fixture) pairSetup(pin string) error {
+	setup, err := pair.NewSetupServerController(f.accessory, f.database)
+	if err != nil {
+		return err
+	}
+
+	client := pair.NewSetupClientController(pin, f.controller, f.controllerDatabase)
+	if err := exchange(client.InitialPairingRequest(), setup, client, setup, client, setup, client); err != nil {
+		return err
+	}
+
+	if _, err := f.database.EntityWithName(f.controller.Name()); err != nil {
+		return errors.New("Controller is not paired")
+	}
+
+	return nil
+}
+
+// exchange sends req to the first handler and the response to the next handler
+// until a handler returns no response.
+func exchange(req io.Reader, handlers ...netio.ContainerHandler) error {
+	var err error
+	for _, h := range handlers {
+		if req == nil {
+			return nil
+		}
+
+		if req, err = pair.HandleReaderForHandler(req, h); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// request returns a tlv8 encoded request with the values by tag.
+func request(values map[uint8]byte) io.Reader {
+	c := util.NewTLV8Container()
+	for tag, value := range values {
+		c.SetByte(tag, value)
+	}
+
+	return c.BytesBuffer()
+}
+
+// errCode returns the error code of the tlv8 encoded response r.
+func errCode(r io.Reader) (byte, error) {
+	if r == nil {
+		return 0, errors.New("No response")
+	}
+
+	c, err := util.NewTLV8ContainerFromReader(r)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.GetByte(pair.TagErrCode), nil
+}
+
+func testPairSetup() error {
+	f, err := newFixture()
+	if err != nil {
+		return err
+	}
+
+	if err := f.pairSetup(testPin); err != nil {
+		return err
+	}
+
+	// The controller must know the public key of the accessory
+	if _, err := f.controllerDatabase.EntityWithName(f.accessory.Name()); err != nil {
+		return errors.New("Accessory is not paired")
+	}
+
+	return nil
+}
+
+// The accessory must respond to M3 with kTLVError_Authentication when the setup code is wrong.
+func testPairSetupWrongCode() error {
+	f, err := newFixture()
+	if err != nil {
+		return err
+	}
+
+	setup, err := pair.NewSetupServerController(f.accessory, f.database)
+	if err != nil {
+		return err
+	}
+
+	client := pair.NewSetupClientController("001-02-004", f.controller, f.controllerDatabase)
+	m2, err := pair.HandleReaderForHandler(client.InitialPairingRequest(), setup)
+	if err != nil {
+		return err
+	}
+
+	m3, err := pair.HandleReaderForHandler(m2, client)
+	if err != nil {
+		return err
+	}
+
+	m4, err := pair.HandleReaderForHandler(m3, setup)
+	if err != nil {
+		return err
+	}
+
+	code, err := errCode(m4)
+	if err != nil {
+		return err
+	}
+
+	if code != pair.ErrCodeAuthenticationFailed.Byte() {
+		return fmt.Errorf("Unexpected error code %d", code)
+	}
+
+	if _, err := f.database.EntityWithName(f.controller.Name()); err == nil {
+		return errors.New("Controller is paired")
+	}
+
+	return nil
+}
+
+// The accessory must reject truncated tlv8 data.
+func testPairSetupMalformedTLV() error {
+	f, err := newFixture()
+	if err != nil {
+		return err
+	}
+
+	setup, err := pair.NewSetupServerController(f.accessory, f.database)
+	if err != nil {
+		return err
+	}
+
+	// The sequence value is announced with 5 bytes but only 1 byte follows
+	b := []byte{pair.TagSequence, 0x05, pair.PairStepStartRequest.Byte()}
+	if _, err := pair.HandleReaderForHandler(bytes.NewReader(b), setup); err == nil {
+		return errors.New("Malformed request accepted")
+	}
+
+	return nil
+}
+
+// The accessory must reject M3 before M1 and must allow pair setup afterwards.
+func testPairSetupUnexpectedStep() error {
+	f, err := newFixture()
+	if err != nil {
+		return err
+	}
+
+	setup, err := pair.NewSetupServerController(f.accessory, f.database)
+	if err != nil {
+		return err
+	}
+
+	req := request(map[uint8]byte{pair.TagSequence: pair.PairStepVerifyRequest.Byte()})
+	if _, err := pair.HandleReaderForHandler(req, setup); err == nil {
+		return errors.New("M3 accepted before M1")
+	}
+
+	return f.pairSetup(testPin)
+}
+
+func testPairSetupUnknownStep() error {
+	f, err := newFixture()
+	if err != nil {
+		return err
+	}
+
+	setup, err := pair.NewSetupServerController(f.accessory, f.database)
+	if err != nil {
+		return err
+	}
+
+	req := request(map[uint8]byte{pair.TagSequence: 0x09})
+	if _, err := pair.HandleReaderForHandler(req, setup); err == nil {
+		return errors.New("Unknown step accepted")
+	}
+
+	return nil
+}
+
+func testPairSetupUnknownMethod() error {
+	f, err := newFixture()
+	if err != nil {
+		return err
+	}
+
+	setup, err := pair.NewSetupServerController(f.accessory, f.database)
+	if err != nil {
+		return err
+	}
+
+	req := request(map[uint8]byte{
+		pair.TagPairingMethod: 0x05,
+		pair.TagSequence:      pair.PairStepStartRequest.Byte(),
+	})
+	if _, err := pair.HandleReaderForHandler(req, setup); err == nil {
+		return errors.New("Unknown method accepted")
+	}
+
+	return nil
+}
+
+func testPairVerify() error {
+	f, err := newFixture()
+	if err != nil {
+		return err
+	}
+
+	if err := f.pairSetup(testPin); err != nil {
+		return err
+	}
+
+	verify := pair.NewVerifyServerController(f.database, netio.NewContextForSecuredDevice(f.accessory))
+	client := pair.NewVerifyClientController(f.controller, f.controllerDatabase)
+	if err := exchange(client.InitialKeyVerifyRequest(), verify, client, verify, client); err != nil {
+		return err
+	}
+
+	if verify.SharedKey() == [32]byte{} {
+		return errors.New("No shared key")
+	}
+
+	return nil
+}
+
+// The accessory must not verify a controller which is not paired.
+func testPairVerifyUnknownController() error {
+	f, err := newFixture()
+	if err != nil {
+		return err
+	}
+
+	// Only the controller knows the accessory
+	entity := db.NewEntity(f.accessory.Name(), f.accessory.PublicKey(), nil)
+	if err := f.controllerDatabase.SaveEntity(entity); err != nil {
+		return err
+	}
+
+	verify := pair.NewVerifyServerController(f.database, netio.NewContextForSecuredDevice(f.accessory))
+	client := pair.NewVerifyClientController(f.controller, f.controllerDatabase)
+	m2, err := pair.HandleReaderForHandler(client.InitialKeyVerifyRequest(), verify)
+	if err != nil {
+		return err
+	}
+
+	m3, err := pair.HandleReaderForHandler(m2, client)
+	if err != nil {
+		return err
+	}
+
+	m4, err := pair.HandleReaderForHandler(m3, verify)
+	if err != nil {
+		// The request is rejected
+		return nil
+	}
+
+	if code, err := errCode(m4); err != nil || code == pair.ErrCodeNo.Byte() {
+		return errors.New("Unknown controller verified")
+	}
+
+	return nil
+}
+
+func testPairVerifyUnexpectedStep() error {
+	f, err := newFixture()
+	if err != nil {
+		return err
+	}
+
+	verify := pair.NewVerifyServerController(f.database, netio.NewContextForSecuredDevice(f.accessory))
+	req := request(map[uint8]byte{pair.TagSequence: pair.VerifyStepFinishRequest.Byte()})
+	if _, err := pair.HandleReaderForHandler(req, verify); err == nil {
+		return errors.New("M3 accepted before M1")
+	}
+
+	return nil
+}
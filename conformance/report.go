@@ -0,0 +1,61 @@
+package conformance
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// Result is the result of a case.
+type Result struct {
+	Name string
+
+	// Err is the reason why the case failed, or nil if the case passed.
+	Err error
+}
+
+// Passed returns true when the case passed.
+func (r Result) Passed() bool {
+	return r.Err == nil
+}
+
+func (r Result) String() string {
+	if r.Passed() == true {
+		return fmt.Sprintf("PASS %s", r.Name)
+	}
+
+	return fmt.Sprintf("FAIL %s: %v", r.Name, r.Err)
+}
+
+// Report contains the results of a run.
+type Report struct {
+	Results []Result
+}
+
+// Passed returns true when all cases passed.
+func (r Report) Passed() bool {
+	return len(r.Failed()) == 0
+}
+
+// Failed returns the results of the failed cases.
+func (r Report) Failed() []Result {
+	var failed []Result
+	for _, res := range r.Results {
+		if res.Passed() == false {
+			failed = append(failed, res)
+		}
+	}
+
+	return failed
+}
+
+// WriteTo writes a line for every result and a summary to w.
+func (r Report) WriteTo(w io.Writer) (int64, error) {
+	var b bytes.Buffer
+	for _, res := range r.Results {
+		fmt.Fprintln(&b, res)
+	}
+	fmt.Fprintf(&b, "%d passed, %d failed\n", len(r.Results)-len(r.Failed()), len(r.Failed()))
+
+	return b.WriteTo(w)
+}
@@ -0,0 +1,70 @@
+package conformance
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+
+	"github.com/brutella/hc/crypto/curve25519"
+	"github.com/brutella/hc/crypto/srp"
+)
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+// testSRPVectors verifies the verifier of the SRP test vectors of the specification.
+func testSRPVectors() error {
+	salt := mustDecodeHex("BEB25379D1A8581EB5A727673A2441EE")
+	verifier := mustDecodeHex(
+		"9B5E061701EA7AEB39CF6E3519655A853CF94C75CAF2555EF1FAF759BB79CB47" +
+			"7014E04A88D68FFC05323891D4C205B8DE81C2F203D8FAD1B24D2C109737F1BE" +
+			"BBD71F912447C4A03C26B9FAD8EDB3E780778E302529ED1EE138CCFC36D4BA31" +
+			"3CC48B14EA8C22A0186B222E655F2DF5603FD75DF76B3B08FF8950069ADD03A7" +
+			"54EE4AE88587CCE1BFDE36794DBAE4592B7B904F442B041CB17AEBAD1E3AEBE3" +
+			"CBE99DE65F4BB1FA00B0E7AF06863DB53B02254EC66E781E3B62A8212C86BEB0" +
+			"D50B5BA6D0B478D8C4E9BBCEC21765326FBD14058D2BBDE2C33045F03873E539" +
+			"48D78B794F0790E48C36AED6E880F557427B2FC06DB5E1E2E1D7E661AC482D18" +
+			"E528D7295EF7437295FF1A72D402771713F16876DD050AE5B7AD53CCB90855C9" +
+			"3956648358ADFD966422F52498732D68D1D7FBEF10D78034AB8DCB6F0FCF885C" +
+			"C2B2EA2C3E6AC86609EA058A9DA8CC63531DC915414DF568B09482DDAC1954DE" +
+			"C7EB714F6FF7D44CD5B86F6BD115810930637C01D0F6013BC9740FA2C633BA89",
+	)
+
+	s := srp.New(srp.Group3072, sha512.New)
+	if bytes.Equal(s.Verifier([]byte("alice"), []byte("password123"), salt), verifier) == false {
+		return errors.New("Wrong SRP verifier")
+	}
+
+	return nil
+}
+
+// testCurve25519Vectors verifies the Diffie-Hellman test vectors of RFC 7748.
+func testCurve25519Vectors() error {
+	var alice, bob, bobPublic [32]byte
+	copy(alice[:], mustDecodeHex("77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a"))
+	copy(bob[:], mustDecodeHex("5dab087e624a8a4b79e17f8b83800ee66f3bb1292618b6fd1c2f8b27ff88e0eb"))
+
+	alicePublic := curve25519.PublicKey(alice)
+	if bytes.Equal(alicePublic[:], mustDecodeHex("8520f0098930a754748b7ddcb43ef75a0dbf3a0d26381af4eba4a98eaa9b4e6a")) == false {
+		return errors.New("Wrong public key")
+	}
+
+	copy(bobPublic[:], mustDecodeHex("de9edb7d7b7dc1b4d35b61c2ece435373f8343c85b78674dadfc7e146f882b4f"))
+	if is := curve25519.PublicKey(bob); is != bobPublic {
+		return errors.New("Wrong public key")
+	}
+
+	secret := curve25519.SharedSecret(alice, bobPublic)
+	if bytes.Equal(secret[:], mustDecodeHex("4a5d9d5ba4ce2de1728e3bf480350f25e07e21c947d19e3376f09b3c1e161742")) == false {
+		return errors.New("Wrong shared secret")
+	}
+
+	return nil
+}
@@ -0,0 +1,252 @@
+// Control is the API to read and write the characteristic values of accessories
+// from other processes, e.g. hardware daemons written in C or Rust.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v4.25.3
+// source: control.proto
+
+package controlpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Control_ListCharacteristics_FullMethodName = "/hc.control.v1.Control/ListCharacteristics"
+	Control_GetValues_FullMethodName           = "/hc.control.v1.Control/GetValues"
+	Control_SetValues_FullMethodName           = "/hc.control.v1.Control/SetValues"
+	Control_WatchValues_FullMethodName         = "/hc.control.v1.Control/WatchValues"
+)
+
+// ControlClient is the client API for Control service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ControlClient interface {
+	// ListCharacteristics returns the characteristics of all accessories.
+	ListCharacteristics(ctx context.Context, in *ListCharacteristicsRequest, opts ...grpc.CallOption) (*ListCharacteristicsResponse, error)
+	// GetValues returns the values of characteristics.
+	GetValues(ctx context.Context, in *GetValuesRequest, opts ...grpc.CallOption) (*GetValuesResponse, error)
+	// SetValues sets the values of characteristics like the accessory does, e.g. after
+	// a sensor reading. Clients receive events for the changes.
+	SetValues(ctx context.Context, in *SetValuesRequest, opts ...grpc.CallOption) (*SetValuesResponse, error)
+	// WatchValues streams the changes of all characteristic values, including writes of clients.
+	WatchValues(ctx context.Context, in *WatchValuesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ValueChange], error)
+}
+
+type controlClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewControlClient(cc grpc.ClientConnInterface) ControlClient {
+	return &controlClient{cc}
+}
+
+func (c *controlClient) ListCharacteristics(ctx context.Context, in *ListCharacteristicsRequest, opts ...grpc.CallOption) (*ListCharacteristicsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCharacteristicsResponse)
+	err := c.cc.Invoke(ctx, Control_ListCharacteristics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) GetValues(ctx context.Context, in *GetValuesRequest, opts ...grpc.CallOption) (*GetValuesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetValuesResponse)
+	err := c.cc.Invoke(ctx, Control_GetValues_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) SetValues(ctx context.Context, in *SetValuesRequest, opts ...grpc.CallOption) (*SetValuesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetValuesResponse)
+	err := c.cc.Invoke(ctx, Control_SetValues_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) WatchValues(ctx context.Context, in *WatchValuesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ValueChange], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Control_ServiceDesc.Streams[0], Control_WatchValues_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchValuesRequest, ValueChange]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Control_WatchValuesClient = grpc.ServerStreamingClient[ValueChange]
+
+// ControlServer is the server API for Control service.
+// All implementations must embed UnimplementedControlServer
+// for forward compatibility.
+type ControlServer interface {
+	// ListCharacteristics returns the characteristics of all accessories.
+	ListCharacteristics(context.Context, *ListCharacteristicsRequest) (*ListCharacteristicsResponse, error)
+	// GetValues returns the values of characteristics.
+	GetValues(context.Context, *GetValuesRequest) (*GetValuesResponse, error)
+	// SetValues sets the values of characteristics like the accessory does, e.g. after
+	// a sensor reading. Clients receive events for the changes.
+	SetValues(context.Context, *SetValuesRequest) (*SetValuesResponse, error)
+	// WatchValues streams the changes of all characteristic values, including writes of clients.
+	WatchValues(*WatchValuesRequest, grpc.ServerStreamingServer[ValueChange]) error
+	mustEmbedUnimplementedControlServer()
+}
+
+// UnimplementedControlServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedControlServer struct{}
+
+func (UnimplementedControlServer) ListCharacteristics(context.Context, *ListCharacteristicsRequest) (*ListCharacteristicsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCharacteristics not implemented")
+}
+func (UnimplementedControlServer) GetValues(context.Context, *GetValuesRequest) (*GetValuesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetValues not implemented")
+}
+func (UnimplementedControlServer) SetValues(context.Context, *SetValuesRequest) (*SetValuesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetValues not implemented")
+}
+func (UnimplementedControlServer) WatchValues(*WatchValuesRequest, grpc.ServerStreamingServer[ValueChange]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchValues not implemented")
+}
+func (UnimplementedControlServer) mustEmbedUnimplementedControlServer() {}
+func (UnimplementedControlServer) testEmbeddedByValue()                 {}
+
+// UnsafeControlServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ControlServer will
+// result in compilation errors.
+type UnsafeControlServer interface {
+	mustEmbedUnimplementedControlServer()
+}
+
+func RegisterControlServer(s grpc.ServiceRegistrar, srv ControlServer) {
+	// If the following call pancis, it indicates UnimplementedControlServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Control_ServiceDesc, srv)
+}
+
+func _Control_ListCharacteristics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCharacteristicsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).ListCharacteristics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_ListCharacteristics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).ListCharacteristics(ctx, req.(*ListCharacteristicsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_GetValues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetValuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).GetValues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_GetValues_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).GetValues(ctx, req.(*GetValuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_SetValues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetValuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).SetValues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Control_SetValues_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).SetValues(ctx, req.(*SetValuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_WatchValues_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchValuesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServer).WatchValues(m, &grpc.GenericServerStream[WatchValuesRequest, ValueChange]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Control_WatchValuesServer = grpc.ServerStreamingServer[ValueChange]
+
+// Control_ServiceDesc is the grpc.ServiceDesc for Control service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Control_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "hc.control.v1.Control",
+	HandlerType: (*ControlServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListCharacteristics",
+			Handler:    _Control_ListCharacteristics_Handler,
+		},
+		{
+			MethodName: "GetValues",
+			Handler:    _Control_GetValues_Handler,
+		},
+		{
+			MethodName: "SetValues",
+			Handler:    _Control_SetValues_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchValues",
+			Handler:       _Control_WatchValues_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "control.proto",
+}
@@ -0,0 +1,5 @@
+// Package controlpb contains the protocol buffer messages and the gRPC service of the control API,
+// which are generated from control.proto.
+package controlpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative control.proto
@@ -0,0 +1,4 @@
+// Package control provides a gRPC service to read and write the characteristic values
+// of accessories, so that other processes (e.g. hardware daemons written in C or Rust)
+// can drive the accessories. The service is defined in controlpb/control.proto.
+package control
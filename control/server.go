@@ -0,0 +1,169 @@
+package control
+
+import (
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/control/controlpb"
+	"github.com/brutella/hc/service"
+	"github.com/brutella/log"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"context"
+	"net"
+	"sync"
+)
+
+// watchQueueSize is the number of changes which are queued for a watcher.
+// Changes are dropped when a watcher doesn't receive them fast enough.
+const watchQueueSize = 64
+
+// Server implements the control service for accessories.
+type Server struct {
+	controlpb.UnimplementedControlServer
+
+	container *accessory.Container
+
+	mutex    sync.Mutex
+	watchers map[chan *controlpb.ValueChange]bool
+}
+
+// NewServer returns a server for the accessories, which must have accessory ids,
+// e.g. after they were added to a transport.
+func NewServer(as ...*accessory.Accessory) *Server {
+	s := &Server{
+		container: accessory.NewContainer(),
+		watchers:  map[chan *controlpb.ValueChange]bool{},
+	}
+
+	for _, a := range as {
+		s.container.AddAccessory(a)
+	}
+
+	s.container.ForEachCharacteristic(func(a *accessory.Accessory, _ *service.Service, c *characteristic.Characteristic) {
+		aid := a.GetID()
+		c.OnValueUpdate(func(c *characteristic.Characteristic, new, old interface{}) {
+			s.broadcast(aid, c, new, old, false)
+		})
+		c.OnValueUpdateFromConn(func(conn net.Conn, c *characteristic.Characteristic, new, old interface{}) {
+			s.broadcast(aid, c, new, old, true)
+		})
+	})
+
+	return s
+}
+
+// Register registers the service at g.
+func (s *Server) Register(g *grpc.Server) {
+	controlpb.RegisterControlServer(g, s)
+}
+
+// ListCharacteristics returns the characteristics of all accessories.
+func (s *Server) ListCharacteristics(ctx context.Context, req *controlpb.ListCharacteristicsRequest) (*controlpb.ListCharacteristicsResponse, error) {
+	res := &controlpb.ListCharacteristicsResponse{}
+	s.container.ForEachCharacteristic(func(a *accessory.Accessory, _ *service.Service, c *characteristic.Characteristic) {
+		res.Characteristics = append(res.Characteristics, characteristicMessage(a.GetID(), c))
+	})
+
+	return res, nil
+}
+
+// GetValues returns the values of the requested characteristics.
+func (s *Server) GetValues(ctx context.Context, req *controlpb.GetValuesRequest) (*controlpb.GetValuesResponse, error) {
+	res := &controlpb.GetValuesResponse{}
+	for _, id := range req.Ids {
+		c := s.container.CharacteristicByIDs(id.Aid, id.Iid)
+		if c == nil {
+			return nil, status.Errorf(codes.NotFound, "characteristic %d.%d not found", id.Aid, id.Iid)
+		}
+		res.Characteristics = append(res.Characteristics, characteristicMessage(id.Aid, c))
+	}
+
+	return res, nil
+}
+
+// SetValues sets the values of the characteristics. No value is set when a characteristic
+// doesn't exist or a value is missing.
+func (s *Server) SetValues(ctx context.Context, req *controlpb.SetValuesRequest) (*controlpb.SetValuesResponse, error) {
+	var chars []*characteristic.Characteristic
+	for _, m := range req.Characteristics {
+		if m.Id == nil {
+			return nil, status.Error(codes.InvalidArgument, "missing id")
+		}
+
+		c := s.container.CharacteristicByIDs(m.Id.Aid, m.Id.Iid)
+		if c == nil {
+			return nil, status.Errorf(codes.NotFound, "characteristic %d.%d not found", m.Id.Aid, m.Id.Iid)
+		}
+
+		if valueOf(m.Value) == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "missing value of characteristic %d.%d", m.Id.Aid, m.Id.Iid)
+		}
+		chars = append(chars, c)
+	}
+
+	for i, c := range chars {
+		c.UpdateValue(valueOf(req.Characteristics[i].Value))
+	}
+
+	return &controlpb.SetValuesResponse{}, nil
+}
+
+// WatchValues sends the changes of all characteristic values until the stream is closed.
+func (s *Server) WatchValues(req *controlpb.WatchValuesRequest, stream controlpb.Control_WatchValuesServer) error {
+	ch := make(chan *controlpb.ValueChange, watchQueueSize)
+	s.mutex.Lock()
+	s.watchers[ch] = true
+	s.mutex.Unlock()
+
+	defer func() {
+		s.mutex.Lock()
+		delete(s.watchers, ch)
+		s.mutex.Unlock()
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case change := <-ch:
+			if err := stream.Send(change); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// broadcast sends the change of c to all watchers.
+func (s *Server) broadcast(aid int64, c *characteristic.Characteristic, new, old interface{}, remote bool) {
+	change := &controlpb.ValueChange{
+		Id:       &controlpb.CharacteristicID{Aid: aid, Iid: c.GetID()},
+		OldValue: valueMessage(old),
+		NewValue: valueMessage(new),
+		Remote:   remote,
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for ch := range s.watchers {
+		select {
+		case ch <- change:
+		default:
+			log.Printf("[WARN] Dropped change of characteristic %d.%d for slow watcher\n", aid, c.GetID())
+		}
+	}
+}
+
+// characteristicMessage returns the message of the characteristic c of the accessory with id aid.
+func characteristicMessage(aid int64, c *characteristic.Characteristic) *controlpb.Characteristic {
+	return &controlpb.Characteristic{
+		Id:          &controlpb.CharacteristicID{Aid: aid, Iid: c.GetID()},
+		Type:        c.Type,
+		Format:      c.Format,
+		Permissions: c.Perms,
+		Value:       valueMessage(c.Value),
+	}
+}
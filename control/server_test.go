@@ -0,0 +1,126 @@
+package control
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/control/controlpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T, s *Server) controlpb.ControlClient {
+	l := bufconn.Listen(1024 * 1024)
+	g := grpc.NewServer()
+	s.Register(g)
+	go g.Serve(l)
+	t.Cleanup(g.Stop)
+
+	dial := func(ctx context.Context, addr string) (net.Conn, error) {
+		return l.DialContext(ctx)
+	}
+	conn, err := grpc.NewClient("passthrough:///bufnet", grpc.WithContextDialer(dial), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return controlpb.NewControlClient(conn)
+}
+
+func TestSetAndGetValues(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "Switch"})
+	client := newTestClient(t, NewServer(a.Accessory))
+	id := &controlpb.CharacteristicID{Aid: a.ID, Iid: a.Switch.On.ID}
+
+	_, err := client.SetValues(context.Background(), &controlpb.SetValuesRequest{
+		Characteristics: []*controlpb.Characteristic{{Id: id, Value: valueMessage(true)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := a.Switch.On.GetValue(), true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	res, err := client.GetValues(context.Background(), &controlpb.GetValuesRequest{Ids: []*controlpb.CharacteristicID{id}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := res.Characteristics[0].Value.GetBoolValue(), true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	_, err = client.GetValues(context.Background(), &controlpb.GetValuesRequest{Ids: []*controlpb.CharacteristicID{{Aid: 9, Iid: 9}}})
+	if is, want := status.Code(err), codes.NotFound; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestWatchValues(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "Switch"})
+	s := NewServer(a.Accessory)
+	client := newTestClient(t, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.WatchValues(ctx, &controlpb.WatchValuesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait until the watcher is registered
+	for {
+		s.mutex.Lock()
+		n := len(s.watchers)
+		s.mutex.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	a.Switch.On.UpdateValueFromConnection(true, characteristic.TestConn)
+
+	change, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := change.Id.Iid, a.Switch.On.ID; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := change.NewValue.GetBoolValue(), true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := change.Remote, true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestListCharacteristics(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "Switch"})
+	client := newTestClient(t, NewServer(a.Accessory))
+
+	res, err := client.ListCharacteristics(context.Background(), &controlpb.ListCharacteristicsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Characteristics) == 0 {
+		t.Fatal("expected characteristics")
+	}
+}
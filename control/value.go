@@ -0,0 +1,51 @@
+package control
+
+import (
+	"github.com/brutella/hc/control/controlpb"
+)
+
+// valueMessage returns the message of a characteristic value, or nil if v is nil.
+func valueMessage(v interface{}) *controlpb.Value {
+	switch v := v.(type) {
+	case bool:
+		return &controlpb.Value{Kind: &controlpb.Value_BoolValue{BoolValue: v}}
+	case int:
+		return &controlpb.Value{Kind: &controlpb.Value_IntValue{IntValue: int64(v)}}
+	case int32:
+		return &controlpb.Value{Kind: &controlpb.Value_IntValue{IntValue: int64(v)}}
+	case int64:
+		return &controlpb.Value{Kind: &controlpb.Value_IntValue{IntValue: v}}
+	case uint8:
+		return &controlpb.Value{Kind: &controlpb.Value_IntValue{IntValue: int64(v)}}
+	case uint16:
+		return &controlpb.Value{Kind: &controlpb.Value_IntValue{IntValue: int64(v)}}
+	case uint32:
+		return &controlpb.Value{Kind: &controlpb.Value_IntValue{IntValue: int64(v)}}
+	case uint64:
+		return &controlpb.Value{Kind: &controlpb.Value_IntValue{IntValue: int64(v)}}
+	case float32:
+		return &controlpb.Value{Kind: &controlpb.Value_FloatValue{FloatValue: float64(v)}}
+	case float64:
+		return &controlpb.Value{Kind: &controlpb.Value_FloatValue{FloatValue: v}}
+	case string:
+		return &controlpb.Value{Kind: &controlpb.Value_StringValue{StringValue: v}}
+	}
+
+	return nil
+}
+
+// valueOf returns the characteristic value of a message, or nil if the message has no value.
+func valueOf(m *controlpb.Value) interface{} {
+	switch k := m.GetKind().(type) {
+	case *controlpb.Value_BoolValue:
+		return k.BoolValue
+	case *controlpb.Value_IntValue:
+		return int(k.IntValue)
+	case *controlpb.Value_FloatValue:
+		return k.FloatValue
+	case *controlpb.Value_StringValue:
+		return k.StringValue
+	}
+
+	return nil
+}
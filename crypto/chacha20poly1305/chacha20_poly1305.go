@@ -1,99 +1,81 @@
 package chacha20poly1305
 
 import (
-	"encoding/binary"
-	"encoding/hex"
+	"crypto/cipher"
 	"errors"
-	"github.com/codahale/chacha20"
-	"golang.org/x/crypto/poly1305"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
 )
 
 // DecryptAndVerify returns the chacha20 decrypted messages.
 // An error is returned when the poly1305 message authenticator (seal) could not be verified.
 // Nonce should be 8 byte.
 func DecryptAndVerify(key, nonce, message []byte, mac [16]byte, add []byte) ([]byte, error) {
-
-	chacha20, err := chacha20.New(key, nonce)
+	aead, err := newAEAD(key)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	// poly1305 key is chacha20 over 32 zeros
-	var poly1305Key [32]byte
-	var chacha20KeyOut = make([]byte, 64)
-	var zeros = make([]byte, 64)
-	chacha20.XORKeyStream(chacha20KeyOut, zeros)
-	copy(poly1305Key[:], chacha20KeyOut)
-
-	var chacha20Out = make([]byte, len(message))
-	var poly1305Out [16]byte
-
-	// poly1305 byte order
-	// - add bytes up to mod 16 (if available)
-	// - message up to mod 16
-	// - number of add bytes up to mod 8
-	// - number of message bytes up to mod 8
-	var poly1305In []byte
-	if len(add) > 0 {
-		poly1305In = AddBytes(poly1305In, add, 16)
+	n, err := paddedNonce(nonce)
+	if err != nil {
+		return nil, err
 	}
 
-	poly1305In = AddBytes(poly1305In, message, 16)
-	addLength := make([]byte, 8)
-	msgLength := make([]byte, 8)
-	binary.LittleEndian.PutUint64(addLength, uint64(len(add)))
-	binary.LittleEndian.PutUint64(msgLength, uint64(len(message)))
+	sealed := make([]byte, 0, len(message)+len(mac))
+	sealed = append(sealed, message...)
+	sealed = append(sealed, mac[:]...)
 
-	poly1305In = AddBytes(poly1305In, addLength, 8)
-	poly1305In = AddBytes(poly1305In, msgLength, 8)
-
-	poly1305.Sum(&poly1305Out, poly1305In, &poly1305Key)
-
-	if poly1305.Verify(&mac, poly1305In, &poly1305Key) == false {
-		return nil, errors.New("MAC not equal: " + hex.EncodeToString(poly1305Out[:]) + " != " + hex.EncodeToString(mac[:]))
+	out, err := aead.Open(nil, n, sealed, add)
+	if err != nil {
+		return nil, errors.New("MAC not equal")
 	}
 
-	chacha20.XORKeyStream(chacha20Out, message)
-	return chacha20Out, nil
+	return out, nil
 }
 
 // EncryptAndSeal returns the chacha20 encrypted message and poly1305 message authentictor (also refered as seals)
 // Nonce should be 8 byte
 func EncryptAndSeal(key, nonce, message []byte, add []byte) ([]byte /*encrypted*/, [16]byte /*mac*/, error) {
+	var mac [16]byte
 
-	chacha20, err := chacha20.New(key, nonce)
+	aead, err := newAEAD(key)
 	if err != nil {
-		panic(err)
+		return nil, mac, err
 	}
 
-	// poly1305 key is chacha20 over 32 zeros
-	var poly1305Key [32]byte
-	var chacha20KeyOut = make([]byte, 64)
-	var zeros = make([]byte, 64)
-	chacha20.XORKeyStream(chacha20KeyOut, zeros)
-	copy(poly1305Key[:], chacha20KeyOut)
+	n, err := paddedNonce(nonce)
+	if err != nil {
+		return nil, mac, err
+	}
+
+	sealed := aead.Seal(nil, n, message, add)
+	encrypted := sealed[:len(message)]
+	copy(mac[:], sealed[len(message):])
 
-	var chacha20Out = make([]byte, len(message))
-	var poly1305Out [16]byte
-	chacha20.XORKeyStream(chacha20Out, message)
+	return encrypted, mac, nil
+}
 
-	var poly1305In []byte
-	if len(add) > 0 {
-		poly1305In = AddBytes(poly1305In, add, 16)
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != chacha20poly1305.KeySize {
+		return nil, fmt.Errorf("Invalid size of key (%v)", len(key))
 	}
 
-	poly1305In = AddBytes(poly1305In, chacha20Out, 16)
-	addLength := make([]byte, 8)
-	msgLength := make([]byte, 8)
-	binary.LittleEndian.PutUint64(addLength, uint64(len(add)))
-	binary.LittleEndian.PutUint64(msgLength, uint64(len(message)))
+	return chacha20poly1305.New(key)
+}
 
-	poly1305In = AddBytes(poly1305In, addLength, 8)
-	poly1305In = AddBytes(poly1305In, msgLength, 8)
+// paddedNonce returns the 12 byte nonce of the IETF variant for an 8 byte nonce.
+// The nonce is prefixed with zeros, which results in the same key stream as
+// the original chacha20 variant for messages shorter than 256 GB.
+func paddedNonce(nonce []byte) ([]byte, error) {
+	if len(nonce) != 8 {
+		return nil, fmt.Errorf("Invalid size of nonce (%v)", len(nonce))
+	}
 
-	poly1305.Sum(&poly1305Out, poly1305In, &poly1305Key)
+	n := make([]byte, chacha20poly1305.NonceSize)
+	copy(n[chacha20poly1305.NonceSize-len(nonce):], nonce)
 
-	return chacha20Out, poly1305Out, nil
+	return n, nil
 }
 
 // AddBytes appends *add* to *b*
@@ -3,7 +3,6 @@ package chacha20poly1305
 import (
 	"encoding/binary"
 	"encoding/hex"
-	"github.com/codahale/chacha20"
 	"reflect"
 	"testing"
 )
@@ -27,27 +26,42 @@ func TestAddBytesMod8FromUint64(t *testing.T) {
 	}
 }
 
-// Test how and if de-/encoding works with chacha20 - surprise it works
-func TestChacha20(t *testing.T) {
+func TestEncryptAndDecrypt(t *testing.T) {
 	K, _ := hex.DecodeString("6a3bfd77d9efac53f8ef51712796bf7a37541f425a5dc5397c8a2c3c040d9301")
 	message, _ := hex.DecodeString("8e685bd3237866e7a424b0f33df1a087a397a78e147042d2d17b159044d2ad1162dea13df2a119b61c90d62fc76335f49954557f2b07c463dca1664ca042599fca66068b16bc3e7e1896536ca2")
+	add := []byte{0x01, 0x02}
 
-	c, err := chacha20.New(K, []byte("PS-Msg05"))
+	encrypted, mac, err := EncryptAndSeal(K, []byte("PS-Msg05"), message, add)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	var out = make([]byte, len(message))
-	c.XORKeyStream(out, message)
+	decrypted, err := DecryptAndVerify(K, []byte("PS-Msg05"), encrypted, mac, add)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reflect.DeepEqual(decrypted, message) == false {
+		t.Fatal(decrypted)
+	}
+}
 
-	c2, err := chacha20.New(K, []byte("PS-Msg05"))
+func TestDecryptWithInvalidMAC(t *testing.T) {
+	K, _ := hex.DecodeString("6a3bfd77d9efac53f8ef51712796bf7a37541f425a5dc5397c8a2c3c040d9301")
+	encrypted, mac, err := EncryptAndSeal(K, []byte("PS-Msg05"), []byte("hello"), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	var out2 = make([]byte, len(message))
-	c2.XORKeyStream(out2, out)
-	if reflect.DeepEqual(out2, message) == false {
-		t.Fatal(out2)
+	mac[0] ^= 0xFF
+	if _, err := DecryptAndVerify(K, []byte("PS-Msg05"), encrypted, mac, nil); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestInvalidNonceSize(t *testing.T) {
+	K, _ := hex.DecodeString("6a3bfd77d9efac53f8ef51712796bf7a37541f425a5dc5397c8a2c3c040d9301")
+	if _, _, err := EncryptAndSeal(K, []byte("PS-Msg"), []byte("hello"), nil); err == nil {
+		t.Fatal("expected error")
 	}
 }
@@ -2,8 +2,7 @@ package crypto
 
 import (
 	"bytes"
-	"github.com/agl/ed25519"
-
+	"crypto/ed25519"
 	"fmt"
 )
 
@@ -13,25 +12,23 @@ func ValidateED25519Signature(key, data, signature []byte) bool {
 		return false
 	}
 
-	var k [ed25519.PublicKeySize]byte
-	var s [ed25519.SignatureSize]byte
-	copy(k[:], key)
-	copy(s[:], signature)
-
-	return ed25519.Verify(&k, data, &s)
+	return ed25519.Verify(ed25519.PublicKey(key), data, signature)
 }
 
 // ED25519Signature returns the ED25519 signature of data using the key.
+// The key is either a 64 byte private key or a 32 byte seed.
 func ED25519Signature(key, data []byte) ([]byte, error) {
-	if len(key) != ed25519.PrivateKeySize {
+	var k ed25519.PrivateKey
+	switch len(key) {
+	case ed25519.PrivateKeySize:
+		k = ed25519.PrivateKey(key)
+	case ed25519.SeedSize:
+		k = ed25519.NewKeyFromSeed(key)
+	default:
 		return nil, fmt.Errorf("Invalid size of key (%v)", len(key))
 	}
 
-	var k [ed25519.PrivateKeySize]byte
-	copy(k[:], key)
-	signature := ed25519.Sign(&k, data)
-
-	return signature[:], nil
+	return ed25519.Sign(k, data), nil
 }
 
 // ED25519GenerateKey return a public and private ED25519 key pair from a string.
@@ -44,5 +41,21 @@ func ED25519GenerateKey(str string) ([]byte /* public */, []byte /* private */,
 
 	public, private, err := ed25519.GenerateKey(bytes.NewReader(b.Bytes()))
 
-	return public[:], private[:], err
+	return public, private, err
+}
+
+// ED25519MigrateKey returns the 64 byte private key and the public key for a private key.
+// Private keys which are stored as 32 byte seed are expanded to the 64 byte format.
+// The returned bool is true when the key was migrated.
+func ED25519MigrateKey(private []byte) ([]byte /* public */, []byte /* private */, bool, error) {
+	switch len(private) {
+	case ed25519.PrivateKeySize:
+		k := ed25519.PrivateKey(private)
+		return k.Public().(ed25519.PublicKey), private, false, nil
+	case ed25519.SeedSize:
+		k := ed25519.NewKeyFromSeed(private)
+		return k.Public().(ed25519.PublicKey), k, true, nil
+	}
+
+	return nil, nil, false, fmt.Errorf("Invalid size of key (%v)", len(private))
 }
@@ -0,0 +1,45 @@
+package crypto
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Implementation describes which implementations of the cryptographic primitives are used.
+// The implementations are selected at init based on the architecture and the features of the CPU.
+// Build with the `purego` tag to force the pure Go implementations, e.g. to rule out
+// an assembly implementation when debugging.
+type Implementation struct {
+	// Arch is the architecture (GOARCH) of the binary
+	Arch string
+
+	// Features are the detected CPU features which are relevant for the implementations, e.g. "avx2" or "asimd"
+	Features []string
+
+	// ChaCha20Poly1305 is the implementation of the frame encryption ("asm" or "generic")
+	ChaCha20Poly1305 string
+
+	// Curve25519 is the implementation of the key exchange during pair verify ("asm" or "generic")
+	Curve25519 string
+}
+
+// String returns a short description of the implementation.
+func (i Implementation) String() string {
+	return fmt.Sprintf("chacha20poly1305=%s curve25519=%s arch=%s features=%v", i.ChaCha20Poly1305, i.Curve25519, i.Arch, i.Features)
+}
+
+// Selected is the implementation which is used on this machine.
+var Selected = selectImplementation()
+
+const (
+	implementationAsm     = "asm"
+	implementationGeneric = "generic"
+)
+
+func genericImplementation() Implementation {
+	return Implementation{
+		Arch:             runtime.GOARCH,
+		ChaCha20Poly1305: implementationGeneric,
+		Curve25519:       implementationGeneric,
+	}
+}
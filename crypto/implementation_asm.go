@@ -0,0 +1,41 @@
+//go:build (amd64 || arm64) && gc && !purego
+// +build amd64 arm64
+// +build gc
+// +build !purego
+
+package crypto
+
+import (
+	"runtime"
+
+	"golang.org/x/sys/cpu"
+)
+
+// selectImplementation returns the assembly implementations of golang.org/x/crypto
+// and crypto/ecdh when the CPU supports the required instructions.
+func selectImplementation() Implementation {
+	i := genericImplementation()
+
+	switch runtime.GOARCH {
+	case "amd64":
+		if cpu.X86.HasAVX2 {
+			i.Features = append(i.Features, "avx2")
+		}
+		if cpu.X86.HasSSSE3 {
+			i.Features = append(i.Features, "ssse3")
+			i.ChaCha20Poly1305 = implementationAsm
+		}
+		if cpu.X86.HasBMI2 {
+			i.Features = append(i.Features, "bmi2")
+		}
+		i.Curve25519 = implementationAsm
+	case "arm64":
+		if cpu.ARM64.HasASIMD {
+			i.Features = append(i.Features, "asimd")
+			i.ChaCha20Poly1305 = implementationAsm
+		}
+		i.Curve25519 = implementationAsm
+	}
+
+	return i
+}
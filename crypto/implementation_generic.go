@@ -0,0 +1,22 @@
+//go:build (!amd64 && !arm64) || !gc || purego
+// +build !amd64,!arm64 !gc purego
+
+package crypto
+
+import (
+	"runtime"
+
+	"golang.org/x/sys/cpu"
+)
+
+// selectImplementation returns the pure Go implementations.
+// There are no assembly implementations for 32-bit ARM (e.g. ARMv6 of a Raspberry Pi Zero),
+// even if the CPU supports NEON.
+func selectImplementation() Implementation {
+	i := genericImplementation()
+	if runtime.GOARCH == "arm" && cpu.ARM.HasNEON {
+		i.Features = append(i.Features, "neon")
+	}
+
+	return i
+}
@@ -0,0 +1,18 @@
+package crypto
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestSelectedImplementation(t *testing.T) {
+	if is, want := Selected.Arch, runtime.GOARCH; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	for _, impl := range []string{Selected.ChaCha20Poly1305, Selected.Curve25519} {
+		if impl != implementationAsm && impl != implementationGeneric {
+			t.Fatalf("invalid implementation %s", impl)
+		}
+	}
+}
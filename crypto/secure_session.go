@@ -44,7 +44,7 @@ func NewSecureSessionFromSharedKey(sharedKey [32]byte) (Cryptographer, error) {
 }
 
 // NewSecureClientSessionFromSharedKey returns a session from a shared secret key to simulate a HomeKit client.
-// This is currently only used for testing.
+// This is used for testing and by clients of custom transports (see netio.Serve).
 func NewSecureClientSessionFromSharedKey(sharedKey [32]byte) (Cryptographer, error) {
 	salt := []byte("Control-Salt")
 	out := []byte("Control-Write-Encryption-Key")
@@ -0,0 +1,37 @@
+//go:build go1.18
+// +build go1.18
+
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+// FuzzDecrypt checks that malformed frames are rejected without panic.
+func FuzzDecrypt(f *testing.F) {
+	var key [32]byte
+	client, err := NewSecureClientSessionFromSharedKey(key)
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	encrypted, err := client.Encrypt(bytes.NewBufferString("GET /accessories HTTP/1.1\r\n\r\n"))
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	b.ReadFrom(encrypted)
+	f.Add(b.Bytes())
+	f.Add([]byte{0xFF, 0xFF})
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		server, err := NewSecureSessionFromSharedKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		server.Decrypt(bytes.NewReader(b))
+	})
+}
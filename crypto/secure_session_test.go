@@ -131,3 +131,39 @@ func TestCryptoMaxPacketLength(t *testing.T) {
 		t.Fatal("invalid decryption")
 	}
 }
+
+func benchmarkSession(b *testing.B, size int, decrypt bool) {
+	var key [32]byte
+	server, err := NewSecureSessionFromSharedKey(key)
+	if err != nil {
+		b.Fatal(err)
+	}
+	client, err := NewSecureClientSessionFromSharedKey(key)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	data := make([]byte, size)
+	b.SetBytes(int64(size))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		encrypted, err := server.Encrypt(bytes.NewBuffer(data))
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		if decrypt == true {
+			if _, err := client.Decrypt(encrypted); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkEncrypt64(b *testing.B)    { benchmarkSession(b, 64, false) }
+func BenchmarkEncrypt1K(b *testing.B)    { benchmarkSession(b, 1024, false) }
+func BenchmarkEncrypt16K(b *testing.B)   { benchmarkSession(b, 16*1024, false) }
+func BenchmarkRoundTrip64(b *testing.B)  { benchmarkSession(b, 64, true) }
+func BenchmarkRoundTrip1K(b *testing.B)  { benchmarkSession(b, 1024, true) }
+func BenchmarkRoundTrip16K(b *testing.B) { benchmarkSession(b, 16*1024, true) }
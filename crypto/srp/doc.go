@@ -0,0 +1,4 @@
+// Package srp implements the Secure Remote Password protocol (SRP-6a) which is used for pair setup.
+//
+// The modular exponentiations are constant-time (see SRP).
+package srp
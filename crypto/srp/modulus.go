@@ -0,0 +1,200 @@
+package srp
+
+import (
+	"crypto/subtle"
+	"math/big"
+	"math/bits"
+)
+
+// modulus implements constant-time arithmetic modulo an odd N.
+//
+// Numbers are little-endian 64-bit limbs with the length of N and are kept in the
+// Montgomery representation x*R mod N with R = 2^(64*limbs). The operations don't
+// branch on and don't index memory with the values of numbers, so their timing
+// only depends on the size of N and the length of exponents.
+type modulus struct {
+	N     *big.Int
+	n     []uint64
+	n0inv uint64   // -N^-1 mod 2^64
+	rr    []uint64 // R^2 mod N
+	one   []uint64 // R mod N, which is 1 in the Montgomery representation
+}
+
+func newModulus(N *big.Int) *modulus {
+	limbs := (N.BitLen() + 63) / 64
+	m := &modulus{N: N, n: limbsFromBytes(N.Bytes(), limbs)}
+
+	// Newton's method doubles the number of correct bits of N^-1 mod 2^64 in every step
+	inv := uint64(1)
+	for i := 0; i < 6; i++ {
+		inv *= 2 - m.n[0]*inv
+	}
+	m.n0inv = -inv
+
+	R := new(big.Int).Lsh(big.NewInt(1), uint(64*limbs))
+	m.one = limbsFromBytes(new(big.Int).Mod(R, N).Bytes(), limbs)
+	m.rr = limbsFromBytes(new(big.Int).Mod(new(big.Int).Mul(R, R), N).Bytes(), limbs)
+
+	return m
+}
+
+// limbsFromBytes returns the limbs of the big-endian b, which must fit into limbs.
+func limbsFromBytes(b []byte, limbs int) []uint64 {
+	z := make([]uint64, limbs)
+	for i := 0; i < len(b); i++ {
+		z[i/8] |= uint64(b[len(b)-1-i]) << (8 * uint(i%8))
+	}
+
+	return z
+}
+
+// nat returns the big-endian b in the Montgomery representation.
+func (m *modulus) nat(b []byte) []uint64 {
+	if len(b) > 8*len(m.n) {
+		// Only public keys can be larger than N
+		b = new(big.Int).Mod(new(big.Int).SetBytes(b), m.N).Bytes()
+	}
+
+	return m.mul(limbsFromBytes(b, len(m.n)), m.rr)
+}
+
+// bytes returns x as big-endian number without leading zeros.
+func (m *modulus) bytes(x []uint64) []byte {
+	one := make([]uint64, len(m.n))
+	one[0] = 1
+	z := m.mul(x, one)
+
+	b := make([]byte, 8*len(z))
+	for i := range b {
+		b[len(b)-1-i] = byte(z[i/8] >> (8 * uint(i%8)))
+	}
+
+	return new(big.Int).SetBytes(b).Bytes()
+}
+
+// mul returns x*y*R^-1 mod N for x*y < R*N.
+func (m *modulus) mul(x, y []uint64) []uint64 {
+	n := len(m.n)
+	t := make([]uint64, n+2)
+	for i := 0; i < n; i++ {
+		// t += x*y[i]
+		var c, cc uint64
+		for j := 0; j < n; j++ {
+			hi, lo := bits.Mul64(x[j], y[i])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j], c = lo, hi
+		}
+		t[n], cc = bits.Add64(t[n], c, 0)
+		t[n+1] = cc
+
+		// t = (t + q*N) / 2^64 with q chosen so that the lowest limb becomes 0
+		q := t[0] * m.n0inv
+		hi, lo := bits.Mul64(m.n[0], q)
+		_, cc = bits.Add64(lo, t[0], 0)
+		c = hi + cc
+		for j := 1; j < n; j++ {
+			hi, lo = bits.Mul64(m.n[j], q)
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j-1], c = lo, hi
+		}
+		t[n-1], cc = bits.Add64(t[n], c, 0)
+		t[n] = t[n+1] + cc
+	}
+
+	z := make([]uint64, n)
+	copy(z, t[:n])
+	m.reduce(z, t[n])
+
+	return z
+}
+
+// reduce subtracts N from carry:z when carry:z is not smaller than N.
+// carry:z must be smaller than 2N.
+func (m *modulus) reduce(z []uint64, carry uint64) {
+	d := make([]uint64, len(z))
+	var b uint64
+	for i := range z {
+		d[i], b = bits.Sub64(z[i], m.n[i], b)
+	}
+
+	// Use d when there is a carry or no borrow
+	mask := -(carry | (b ^ 1))
+	for i := range z {
+		z[i] ^= mask & (z[i] ^ d[i])
+	}
+}
+
+// add returns x+y mod N.
+func (m *modulus) add(x, y []uint64) []uint64 {
+	z := make([]uint64, len(x))
+	var c uint64
+	for i := range z {
+		z[i], c = bits.Add64(x[i], y[i], c)
+	}
+	m.reduce(z, c)
+
+	return z
+}
+
+// sub returns x-y mod N.
+func (m *modulus) sub(x, y []uint64) []uint64 {
+	z := make([]uint64, len(x))
+	var b uint64
+	for i := range z {
+		z[i], b = bits.Sub64(x[i], y[i], b)
+	}
+
+	// Add N when there is a borrow
+	mask := -b
+	var c uint64
+	for i := range z {
+		z[i], c = bits.Add64(z[i], m.n[i]&mask, c)
+	}
+
+	return z
+}
+
+// exp returns x^e mod N for the big-endian exponent e.
+//
+// All bytes of e are processed, including leading zeros.
+// The exponent is processed in windows of 4 bits and the table
+// entries are selected without accessing memory depending on e.
+func (m *modulus) exp(x []uint64, e []byte) []uint64 {
+	var table [16][]uint64
+	table[0] = m.one
+	table[1] = x
+	for i := 2; i < len(table); i++ {
+		table[i] = m.mul(table[i-1], x)
+	}
+
+	z := m.one
+	for _, b := range e {
+		for _, w := range []byte{b >> 4, b & 0x0F} {
+			for i := 0; i < 4; i++ {
+				z = m.mul(z, z)
+			}
+			z = m.mul(z, m.lookup(&table, w))
+		}
+	}
+
+	return z
+}
+
+// lookup returns table[w] by reading all entries of the table.
+func (m *modulus) lookup(table *[16][]uint64, w byte) []uint64 {
+	z := make([]uint64, len(m.n))
+	for i, x := range table {
+		mask := -uint64(subtle.ConstantTimeByteEq(byte(i), w))
+		for j := range z {
+			z[j] |= x[j] & mask
+		}
+	}
+
+	return z
+}
@@ -0,0 +1,312 @@
+package srp
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"math/big"
+)
+
+// SaltLength is the number of bytes of a random salt.
+const SaltLength = 16
+
+// privateKeyLength is the number of bytes of the random private values a and b.
+const privateKeyLength = 32
+
+// SRP implements the SRP-6a protocol as used by HAP.
+//
+//	x  = H(s | H(I | ":" | P))
+//	v  = g^x
+//	k  = H(N | PAD(g))
+//	u  = H(PAD(A) | PAD(B))
+//	K  = H(S)
+//	M1 = H(H(N) xor H(g) | H(I) | s | A | B | K)
+//	M2 = H(A | M1 | K)
+//
+// Proofs are compared in constant time, the private values have a fixed length
+// and public values are padded to the size of N before they are hashed.
+//
+// The modular arithmetic on the private values and the verifier is constant-time.
+// The exponentiations use Montgomery multiplication with fixed-size numbers and
+// process all bytes of an exponent, which have a fixed length.
+type SRP struct {
+	group *Group
+	hash  func() hash.Hash
+	mod   *modulus
+}
+
+// New returns a SRP for a group and a hash function, e.g. New(Group3072, sha512.New).
+func New(group *Group, h func() hash.Hash) *SRP {
+	return &SRP{group, h, newModulus(group.N)}
+}
+
+// Verifier returns the verifier v for a username, password and salt.
+//
+// The verifier can be computed once (e.g. when the accessory is manufactured)
+// and stored instead of the password.
+func (s *SRP) Verifier(username, password, salt []byte) []byte {
+	x := s.x(username, password, salt)
+	v := s.mod.exp(s.g(), x)
+
+	return s.mod.bytes(v)
+}
+
+// NewVerifier returns a random salt and the verifier for a username and password.
+func (s *SRP) NewVerifier(username, password []byte) ([]byte /*salt*/, []byte /*verifier*/, error) {
+	salt := make([]byte, SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, err
+	}
+
+	return salt, s.Verifier(username, password, salt), nil
+}
+
+func (s *SRP) x(username, password, salt []byte) []byte {
+	inner := s.digest(username, []byte(":"), password)
+	return s.digest(salt, inner)
+}
+
+// g returns the generator in the Montgomery representation.
+func (s *SRP) g() []uint64 {
+	return s.mod.nat(s.group.G.Bytes())
+}
+
+// k returns the multiplier in the Montgomery representation.
+func (s *SRP) k() []uint64 {
+	return s.mod.nat(s.digest(s.group.N.Bytes(), s.pad(s.group.G.Bytes())))
+}
+
+func (s *SRP) u(A, B []byte) ([]byte, error) {
+	u := s.digest(s.pad(A), s.pad(B))
+	if new(big.Int).SetBytes(u).Sign() == 0 {
+		return nil, errors.New("Invalid scrambling parameter")
+	}
+
+	return u, nil
+}
+
+// clientProof returns M1.
+func (s *SRP) clientProof(username, salt, A, B, K []byte) []byte {
+	hn := s.digest(s.group.N.Bytes())
+	hg := s.digest(s.group.G.Bytes())
+	for i := range hn {
+		hn[i] ^= hg[i]
+	}
+
+	return s.digest(hn, s.digest(username), salt, A, B, K)
+}
+
+// serverProof returns M2.
+func (s *SRP) serverProof(A, M1, K []byte) []byte {
+	return s.digest(A, M1, K)
+}
+
+func (s *SRP) digest(parts ...[]byte) []byte {
+	h := s.hash()
+	for _, p := range parts {
+		h.Write(p)
+	}
+
+	return h.Sum(nil)
+}
+
+// pad returns b prefixed with zeros to the size of N.
+func (s *SRP) pad(b []byte) []byte {
+	n := s.group.size()
+	if len(b) >= n {
+		return b
+	}
+
+	p := make([]byte, n)
+	copy(p[n-len(b):], b)
+
+	return p
+}
+
+// isValidPublicKey returns false when key mod N is 0.
+func (s *SRP) isValidPublicKey(key *big.Int) bool {
+	return new(big.Int).Mod(key, s.group.N).Sign() != 0
+}
+
+func randomPrivateKey() ([]byte, error) {
+	b := make([]byte, privateKeyLength)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// ServerSession is the server side of a SRP key exchange.
+type ServerSession struct {
+	srp      *SRP
+	username []byte
+	salt     []byte
+	v        []uint64
+	b        []byte
+	pubB     []byte
+
+	pubA []byte
+	key  []byte
+	m1   []byte
+}
+
+// NewServerSession returns a server session with a random private value
+// for the username, salt and verifier.
+func (s *SRP) NewServerSession(username, salt, verifier []byte) (*ServerSession, error) {
+	b, err := randomPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.newServerSession(username, salt, verifier, b), nil
+}
+
+func (s *SRP) newServerSession(username, salt, verifier, b []byte) *ServerSession {
+	m := s.mod
+	v := m.nat(verifier)
+
+	// B = k*v + g^b
+	B := m.add(m.mul(s.k(), v), m.exp(s.g(), b))
+
+	return &ServerSession{
+		srp:      s,
+		username: username,
+		salt:     salt,
+		v:        v,
+		b:        b,
+		pubB:     m.bytes(B),
+	}
+}
+
+// PublicKey returns B.
+func (ss *ServerSession) PublicKey() []byte {
+	return ss.pubB
+}
+
+// ComputeKey returns the session key K for the client public key A.
+func (ss *ServerSession) ComputeKey(A []byte) ([]byte, error) {
+	s := ss.srp
+	m := s.mod
+
+	if s.isValidPublicKey(new(big.Int).SetBytes(A)) == false {
+		return nil, errors.New("Invalid client public key")
+	}
+
+	u, err := s.u(A, ss.pubB)
+	if err != nil {
+		return nil, err
+	}
+
+	// S = (A * v^u) ^ b
+	S := m.exp(m.mul(m.nat(A), m.exp(ss.v, u)), ss.b)
+
+	ss.pubA = A
+	ss.key = s.digest(m.bytes(S))
+	ss.m1 = s.clientProof(ss.username, ss.salt, ss.pubA, ss.pubB, ss.key)
+
+	return ss.key, nil
+}
+
+// VerifyClientProof returns true when M1 of the client is valid.
+// The session key must be computed before.
+func (ss *ServerSession) VerifyClientProof(M1 []byte) bool {
+	if ss.key == nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(ss.m1, M1) == 1
+}
+
+// Proof returns the server proof M2 for a valid client proof M1.
+func (ss *ServerSession) Proof(M1 []byte) ([]byte, error) {
+	if ss.VerifyClientProof(M1) == false {
+		return nil, errors.New("Invalid client proof")
+	}
+
+	return ss.srp.serverProof(ss.pubA, M1, ss.key), nil
+}
+
+// ClientSession is the client side of a SRP key exchange.
+type ClientSession struct {
+	srp      *SRP
+	username []byte
+	password []byte
+	a        []byte
+	pubA     []byte
+
+	key []byte
+	m1  []byte
+	m2  []byte
+}
+
+// NewClientSession returns a client session with a random private value
+// for the username and password.
+func (s *SRP) NewClientSession(username, password []byte) (*ClientSession, error) {
+	a, err := randomPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.newClientSession(username, password, a), nil
+}
+
+func (s *SRP) newClientSession(username, password, a []byte) *ClientSession {
+	A := s.mod.exp(s.g(), a)
+
+	return &ClientSession{
+		srp:      s,
+		username: username,
+		password: password,
+		a:        a,
+		pubA:     s.mod.bytes(A),
+	}
+}
+
+// PublicKey returns A.
+func (cs *ClientSession) PublicKey() []byte {
+	return cs.pubA
+}
+
+// ComputeKey returns the session key K for the salt and the server public key B.
+func (cs *ClientSession) ComputeKey(salt, B []byte) ([]byte, error) {
+	s := cs.srp
+	m := s.mod
+
+	if s.isValidPublicKey(new(big.Int).SetBytes(B)) == false {
+		return nil, errors.New("Invalid server public key")
+	}
+
+	u, err := s.u(cs.pubA, B)
+	if err != nil {
+		return nil, err
+	}
+
+	x := s.x(cs.username, cs.password, salt)
+
+	// S = (B - k*g^x) ^ (a + u*x) = (B - k*g^x)^a * ((B - k*g^x)^u)^x
+	base := m.sub(m.nat(B), m.mul(s.k(), m.exp(s.g(), x)))
+	S := m.mul(m.exp(base, cs.a), m.exp(m.exp(base, u), x))
+
+	cs.key = s.digest(m.bytes(S))
+	cs.m1 = s.clientProof(cs.username, salt, cs.pubA, B, cs.key)
+	cs.m2 = s.serverProof(cs.pubA, cs.m1, cs.key)
+
+	return cs.key, nil
+}
+
+// Proof returns the client proof M1.
+// The session key must be computed before.
+func (cs *ClientSession) Proof() []byte {
+	return cs.m1
+}
+
+// VerifyServerProof returns true when M2 of the server is valid.
+func (cs *ClientSession) VerifyServerProof(M2 []byte) bool {
+	if cs.key == nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(cs.m2, M2) == 1
+}
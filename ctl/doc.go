@@ -0,0 +1,26 @@
+// Package ctl provides a local control socket, which is a Unix domain socket speaking
+// newline-delimited JSON. It is a lightweight way to control a bridge on embedded systems
+// without opening another TCP port.
+//
+// A request contains an id, a method and parameters. The response contains the same id
+// and either a result or an error.
+//
+//	> {"id": 1, "method": "set", "params": {"aid": 2, "iid": 9, "value": true}}
+//	< {"id": 1, "result": null}
+//	> {"id": 2, "method": "get", "params": {"aid": 2, "iid": 9}}
+//	< {"id": 2, "result": true}
+//
+// The methods are
+//
+//	state              returns the accessories and their values
+//	get {aid, iid}     returns the value of a characteristic
+//	set {aid, iid, value}
+//	                   sets the value of a characteristic
+//	subscribe          sends an event for every value change to the connection
+//	pairings           returns the names of the paired controllers
+//	reset              removes all pairings, so that the accessory can be paired again
+//
+// Events have no id.
+//
+//	< {"event": "change", "aid": 2, "iid": 9, "value": false, "remote": true}
+package ctl
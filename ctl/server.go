@@ -0,0 +1,293 @@
+package ctl
+
+import (
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/db"
+	"github.com/brutella/hc/hap"
+	"github.com/brutella/hc/service"
+	"github.com/brutella/log"
+
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// writeTimeout is the deadline of writes to a connection
+const writeTimeout = 5 * time.Second
+
+// Request is a request of a client.
+type Request struct {
+	ID     interface{}     `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is the response to a request.
+type Response struct {
+	ID     interface{} `json:"id"`
+	Result interface{} `json:"result"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// Event is the change of a characteristic value, which is sent to subscribed connections.
+type Event struct {
+	Event  string      `json:"event"`
+	AID    int64       `json:"aid"`
+	IID    int64       `json:"iid"`
+	Value  interface{} `json:"value"`
+	Remote bool        `json:"remote"`
+}
+
+// valueParams are the parameters of the get and set methods.
+type valueParams struct {
+	AID   int64       `json:"aid"`
+	IID   int64       `json:"iid"`
+	Value interface{} `json:"value"`
+}
+
+// Server serves the control socket of a transport.
+type Server struct {
+	// Database is used to list pairings. When nil, pairings are not available.
+	Database db.Database
+
+	transport hap.Transport
+	container *accessory.Container
+
+	mutex    sync.Mutex
+	listener net.Listener
+	conns    map[*conn]bool
+}
+
+// NewServer returns a server for the transport t and its accessories.
+func NewServer(t hap.Transport, as ...*accessory.Accessory) *Server {
+	s := &Server{
+		transport: t,
+		container: accessory.NewContainer(),
+		conns:     map[*conn]bool{},
+	}
+
+	for _, a := range as {
+		s.container.AddAccessory(a)
+	}
+
+	s.container.ForEachCharacteristic(func(a *accessory.Accessory, _ *service.Service, c *characteristic.Characteristic) {
+		aid := a.GetID()
+		c.OnValueUpdate(func(c *characteristic.Characteristic, new, old interface{}) {
+			s.broadcast(Event{Event: "change", AID: aid, IID: c.GetID(), Value: new})
+		})
+		c.OnValueUpdateFromConn(func(_ net.Conn, c *characteristic.Characteristic, new, old interface{}) {
+			s.broadcast(Event{Event: "change", AID: aid, IID: c.GetID(), Value: new, Remote: true})
+		})
+	})
+
+	return s
+}
+
+// ListenAndServe listens on the Unix domain socket at path, which is only accessible
+// by the current user, and serves connections until the server is closed.
+func (s *Server) ListenAndServe(path string) error {
+	// Remove the socket of a previous process
+	os.Remove(path)
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Chmod(path, 0600); err != nil {
+		l.Close()
+		return err
+	}
+
+	return s.Serve(l)
+}
+
+// Serve serves connections of l until the server is closed.
+func (s *Server) Serve(l net.Listener) error {
+	s.mutex.Lock()
+	s.listener = l
+	s.mutex.Unlock()
+
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			return err
+		}
+
+		go s.serve(&conn{Conn: c})
+	}
+}
+
+// Close closes the listener and all connections.
+func (s *Server) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for c := range s.conns {
+		c.Close()
+	}
+
+	if s.listener == nil {
+		return nil
+	}
+
+	return s.listener.Close()
+}
+
+// serve handles the requests of c until c is closed.
+func (s *Server) serve(c *conn) {
+	s.mutex.Lock()
+	s.conns[c] = true
+	s.mutex.Unlock()
+
+	defer func() {
+		s.mutex.Lock()
+		delete(s.conns, c)
+		s.mutex.Unlock()
+		c.Close()
+	}()
+
+	dec := json.NewDecoder(c)
+	for {
+		var req Request
+		if err := dec.Decode(&req); err != nil {
+			if _, ok := err.(*json.SyntaxError); ok == true {
+				c.send(Response{Error: err.Error()})
+			}
+			return
+		}
+
+		res := Response{ID: req.ID}
+		if result, err := s.handle(c, req); err != nil {
+			res.Error = err.Error()
+		} else {
+			res.Result = result
+		}
+
+		if err := c.send(res); err != nil {
+			log.Println("[WARN]", err)
+			return
+		}
+	}
+}
+
+// handle handles the request req of c and returns the result.
+func (s *Server) handle(c *conn, req Request) (interface{}, error) {
+	switch req.Method {
+	case "state":
+		b, err := s.container.MarshalState()
+		return json.RawMessage(b), err
+	case "get":
+		ch, _, err := s.characteristic(req.Params)
+		if err != nil {
+			return nil, err
+		}
+		return ch.Value, nil
+	case "set":
+		ch, p, err := s.characteristic(req.Params)
+		if err != nil {
+			return nil, err
+		}
+		if p.Value == nil {
+			return nil, fmt.Errorf("missing value")
+		}
+		ch.UpdateValue(p.Value)
+		return nil, nil
+	case "subscribe":
+		c.setSubscribed(true)
+		return nil, nil
+	case "pairings":
+		controllers, err := s.controllers()
+		if err != nil {
+			return nil, err
+		}
+		names := []string{}
+		for _, e := range controllers {
+			names = append(names, e.Name)
+		}
+		return names, nil
+	case "reset":
+		return nil, s.transport.ResetPairings()
+	}
+
+	return nil, fmt.Errorf("unknown method %s", req.Method)
+}
+
+// characteristic returns the characteristic which is identified by the parameters.
+func (s *Server) characteristic(params json.RawMessage) (*characteristic.Characteristic, valueParams, error) {
+	var p valueParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, p, err
+	}
+
+	c := s.container.CharacteristicByIDs(p.AID, p.IID)
+	if c == nil {
+		return nil, p, fmt.Errorf("characteristic %d.%d not found", p.AID, p.IID)
+	}
+
+	return c, p, nil
+}
+
+// controllers returns the entities of the paired controllers.
+func (s *Server) controllers() ([]db.Entity, error) {
+	if s.Database == nil {
+		return nil, fmt.Errorf("pairings not available")
+	}
+
+	return db.Controllers(s.Database)
+}
+
+// broadcast sends ev to all subscribed connections.
+func (s *Server) broadcast(ev Event) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for c := range s.conns {
+		if c.isSubscribed() == true {
+			if err := c.send(ev); err != nil {
+				log.Println("[WARN]", err)
+			}
+		}
+	}
+}
+
+// conn is a connection to the control socket.
+type conn struct {
+	net.Conn
+
+	mutex      sync.Mutex
+	subscribed bool
+}
+
+// send writes v as JSON line.
+func (c *conn) send(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// A client which doesn't read must not block other connections
+	c.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err = c.Write(append(b, '\n'))
+	return err
+}
+
+func (c *conn) setSubscribed(subscribed bool) {
+	c.mutex.Lock()
+	c.subscribed = subscribed
+	c.mutex.Unlock()
+}
+
+func (c *conn) isSubscribed() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.subscribed
+}
@@ -0,0 +1,186 @@
+package ctl
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/db"
+	"github.com/brutella/hc/hap"
+	"github.com/brutella/hc/netio/controller"
+)
+
+type testTransport struct {
+	database db.Database
+}
+
+func (t *testTransport) Start()                                      {}
+func (t *testTransport) Stop()                                       {}
+func (t *testTransport) Restart(hap.Config) error                    { return nil }
+func (t *testTransport) Started() <-chan struct{}                    { return nil }
+func (t *testTransport) Schedule(s hap.Schedule, fn func()) *hap.Job { return nil }
+func (t *testTransport) Reverify()                                   {}
+func (t *testTransport) EnablePairing()                              {}
+func (t *testTransport) AddSetupCode(hap.SetupCode) error            { return nil }
+func (t *testTransport) RevokeSetupCode(string) error                { return nil }
+func (t *testTransport) SetupCodes() []hap.SetupCode                 { return nil }
+func (t *testTransport) SetChildOnline(aid int64, online bool) error { return nil }
+func (t *testTransport) RenameAccessory(int64, string) error         { return nil }
+func (t *testTransport) RenameService(int64, int64, string) error    { return nil }
+func (t *testTransport) RemovePairing(string) error                  { return nil }
+func (t *testTransport) Announce()                                   {}
+func (t *testTransport) Status() hap.Status                          { return hap.Status{} }
+func (t *testTransport) Endpoint() hap.Endpoint                      { return hap.Endpoint{} }
+func (t *testTransport) Apply(map[controller.CharacteristicID]interface{}) error {
+	return nil
+}
+
+func (t *testTransport) ResetPairings() error {
+	controllers, err := db.Controllers(t.database)
+	for _, e := range controllers {
+		t.database.DeleteEntity(e)
+	}
+	return err
+}
+
+type testClient struct {
+	t       *testing.T
+	conn    net.Conn
+	scanner *bufio.Scanner
+}
+
+func dial(t *testing.T, s *Server) *testClient {
+	dir, err := ioutil.TempDir("", "ctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "ctl.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Serve(l)
+	t.Cleanup(func() { s.Close() })
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	return &testClient{t, conn, bufio.NewScanner(conn)}
+}
+
+// call sends a request and returns the response.
+func (c *testClient) call(method string, params interface{}) Response {
+	b, err := json.Marshal(params)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	req := Request{ID: 1, Method: method, Params: b}
+	if err := json.NewEncoder(c.conn).Encode(req); err != nil {
+		c.t.Fatal(err)
+	}
+
+	var res Response
+	c.read(&res)
+
+	return res
+}
+
+func (c *testClient) read(v interface{}) {
+	if c.scanner.Scan() == false {
+		c.t.Fatal(c.scanner.Err())
+	}
+
+	if err := json.Unmarshal(c.scanner.Bytes(), v); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func TestGetAndSet(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "Switch"})
+	c := dial(t, NewServer(&testTransport{}, a.Accessory))
+
+	params := valueParams{AID: a.ID, IID: a.Switch.On.ID, Value: true}
+	if res := c.call("set", params); len(res.Error) > 0 {
+		t.Fatal(res.Error)
+	}
+
+	if is, want := a.Switch.On.GetValue(), true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	res := c.call("get", params)
+	if is, want := res.Result, true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if res := c.call("get", valueParams{AID: a.ID, IID: 99}); len(res.Error) == 0 {
+		t.Fatal("expected error")
+	}
+
+	if res := c.call("reboot", nil); len(res.Error) == 0 {
+		t.Fatal("expected error")
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "Switch"})
+	c := dial(t, NewServer(&testTransport{}, a.Accessory))
+
+	if res := c.call("subscribe", nil); len(res.Error) > 0 {
+		t.Fatal(res.Error)
+	}
+
+	a.Switch.On.UpdateValueFromConnection(true, characteristic.TestConn)
+
+	var ev Event
+	c.read(&ev)
+
+	if is, want := ev.IID, a.Switch.On.ID; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := ev.Remote, true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestReset(t *testing.T) {
+	database, err := db.NewTempDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	database.SaveEntity(db.NewEntity("Bridge", []byte{0x01}, []byte{0x02}))
+	database.SaveEntity(db.NewEntity("Alice", []byte{0x03}, nil))
+
+	transport := &testTransport{database: database}
+	s := NewServer(transport)
+	s.Database = database
+	c := dial(t, s)
+
+	res := c.call("pairings", nil)
+	if is, want := len(res.Result.([]interface{})), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if res := c.call("reset", nil); len(res.Error) > 0 {
+		t.Fatal(res.Error)
+	}
+
+	res = c.call("pairings", nil)
+	if is, want := len(res.Result.([]interface{})), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"errors"
+)
+
+// BroadcastKeyLifetime is the number of increments of the global state number
+// after which a broadcast key expires and must be generated again.
+const BroadcastKeyLifetime = 32767
+
+// BroadcastKey is the key which encrypts broadcast notifications, which are sent
+// when characteristic values change while no controller is connected (HAP over Bluetooth LE).
+//
+// The key is generated from the pair verify session of a controller and expires
+// when the global state number (GSN) advanced by BroadcastKeyLifetime since then,
+// or when the pairing of the controller is removed.
+type BroadcastKey struct {
+	Key []byte
+
+	// GSN is the global state number at the time the key was generated
+	GSN uint16
+
+	// Controller is the name of the controller which generated the key
+	Controller string
+}
+
+// IsExpired returns true when the key expired at the global state number gsn.
+func (k BroadcastKey) IsExpired(gsn uint16) bool {
+	return gsnDistance(k.GSN, gsn) >= BroadcastKeyLifetime
+}
+
+// gsnDistance returns the number of increments from the global state number a to b.
+// The global state number wraps around from 65535 to 1.
+func gsnDistance(a, b uint16) int {
+	if b >= a {
+		return int(b) - int(a)
+	}
+
+	return 65535 - int(a) + int(b)
+}
+
+// CurrentBroadcastKey returns the broadcast key stored in database at the global state number gsn.
+// An expired key is deleted and an error is returned, so that a new key has to be generated.
+func CurrentBroadcastKey(database Database, gsn uint16) (BroadcastKey, error) {
+	k, err := database.BroadcastKey()
+	if err != nil {
+		return k, err
+	}
+
+	if k.IsExpired(gsn) {
+		database.DeleteBroadcastKey()
+		return BroadcastKey{}, errors.New("Broadcast key expired")
+	}
+
+	return k, nil
+}
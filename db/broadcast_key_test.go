@@ -0,0 +1,93 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBroadcastKey(t *testing.T) {
+	db, _ := NewTempDatabase()
+
+	if _, err := db.BroadcastKey(); err == nil {
+		t.Fatal("expected error")
+	}
+
+	k := BroadcastKey{Key: []byte{0x01, 0x02}, GSN: 10, Controller: "Controller"}
+	if err := db.SaveBroadcastKey(k); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := db.BroadcastKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := saved, k; reflect.DeepEqual(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	db.DeleteBroadcastKey()
+
+	if _, err := db.BroadcastKey(); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestBroadcastKeyExpiration(t *testing.T) {
+	var tests = []struct {
+		keyGSN  uint16
+		gsn     uint16
+		expired bool
+	}{
+		{1, 1, false},
+		{1, 32767, false},
+		{1, 32768, true},
+		{65000, 65535, false},
+		{65000, 1, false},
+		{65000, 32231, false},
+		{65000, 32232, true},
+	}
+
+	for _, test := range tests {
+		k := BroadcastKey{GSN: test.keyGSN}
+		if is, want := k.IsExpired(test.gsn), test.expired; is != want {
+			t.Fatalf("%d->%d is=%v want=%v", test.keyGSN, test.gsn, is, want)
+		}
+	}
+}
+
+func TestCurrentBroadcastKey(t *testing.T) {
+	db, _ := NewTempDatabase()
+	db.SaveBroadcastKey(BroadcastKey{Key: []byte{0x01}, GSN: 1})
+
+	if _, err := CurrentBroadcastKey(db, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := CurrentBroadcastKey(db, 40000); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if _, err := db.BroadcastKey(); err == nil {
+		t.Fatal("expected expired key to be deleted")
+	}
+}
+
+func TestDeleteEntityDeletesBroadcastKey(t *testing.T) {
+	db, _ := NewTempDatabase()
+	a := NewEntity("A", []byte{0x01}, nil)
+	b := NewEntity("B", []byte{0x02}, nil)
+	db.SaveEntity(a)
+	db.SaveEntity(b)
+	db.SaveBroadcastKey(BroadcastKey{Key: []byte{0x01}, GSN: 1, Controller: "A"})
+
+	db.DeleteEntity(b)
+	if _, err := db.BroadcastKey(); err != nil {
+		t.Fatal(err)
+	}
+
+	db.DeleteEntity(a)
+	if _, err := db.BroadcastKey(); err == nil {
+		t.Fatal("expected error")
+	}
+}
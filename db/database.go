@@ -3,7 +3,9 @@ package db
 import (
 	"encoding/hex"
 	"encoding/json"
+	"github.com/brutella/hc/crypto"
 	"github.com/brutella/hc/util"
+	"github.com/brutella/log"
 )
 
 // Database stores entities
@@ -19,8 +21,26 @@ type Database interface {
 
 	// Entities returns all entities
 	Entities() ([]Entity, error)
+
+	// BroadcastKey returns the stored broadcast key
+	BroadcastKey() (BroadcastKey, error)
+
+	// SaveBroadcastKey stores the broadcast key and replaces the previous key
+	SaveBroadcastKey(k BroadcastKey) error
+
+	// DeleteBroadcastKey deletes the broadcast key
+	DeleteBroadcastKey()
+
+	// AccessoryMetadata returns the stored metadata of the accessory with id aid
+	AccessoryMetadata(aid int64) (map[string]string, error)
+
+	// SaveAccessoryMetadata stores the metadata of the accessory with id aid and replaces the previous metadata
+	SaveAccessoryMetadata(aid int64, metadata map[string]string) error
 }
 
+// broadcastKeyKey is the storage key of the broadcast key
+const broadcastKeyKey = "broadcast.key"
+
 type database struct {
 	storage util.Storage
 }
@@ -38,6 +58,10 @@ func NewDatabase(path string) (Database, error) {
 		return nil, err
 	}
 
+	if err := Migrate(storage); err != nil {
+		return nil, err
+	}
+
 	return NewDatabaseWithStorage(storage), nil
 }
 
@@ -68,6 +92,11 @@ func (db *database) SaveEntity(e Entity) error {
 
 func (db *database) DeleteEntity(e Entity) {
 	db.storage.Delete(toEntityKey(e.Name))
+
+	// The broadcast key expires when the pairing of its controller is removed
+	if k, err := db.BroadcastKey(); err == nil && k.Controller == e.Name {
+		db.DeleteBroadcastKey()
+	}
 }
 
 func (db *database) Entities() (es []Entity, err error) {
@@ -86,16 +115,64 @@ func (db *database) Entities() (es []Entity, err error) {
 	return
 }
 
+func (db *database) BroadcastKey() (k BroadcastKey, err error) {
+	var b []byte
+
+	if b, err = db.storage.Get(broadcastKeyKey); err == nil {
+		err = json.Unmarshal(b, &k)
+	}
+
+	return
+}
+
+func (db *database) SaveBroadcastKey(k BroadcastKey) error {
+	b, err := json.Marshal(k)
+	if err != nil {
+		return err
+	}
+
+	return db.storage.Set(broadcastKeyKey, b)
+}
+
+func (db *database) DeleteBroadcastKey() {
+	db.storage.Delete(broadcastKeyKey)
+}
+
 func (db *database) entityForKey(key string) (e Entity, err error) {
 	var b []byte
 
-	if b, err = db.storage.Get(key); err == nil {
-		err = json.Unmarshal(b, &e)
+	if b, err = db.storage.Get(key); err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(b, &e); err != nil {
+		return
+	}
+
+	if len(e.PrivateKey) > 0 {
+		err = db.migrateEntity(&e)
 	}
 
 	return
 }
 
+// migrateEntity converts the keys of e to the current key format.
+// Private keys which were stored as 32 byte seed are expanded to 64 bytes
+// and saved, so that already paired controllers keep working.
+func (db *database) migrateEntity(e *Entity) error {
+	public, private, migrated, err := crypto.ED25519MigrateKey(e.PrivateKey)
+	if err != nil || migrated == false {
+		// Keys with an unknown format are returned as they are
+		return nil
+	}
+
+	log.Printf("[INFO] Migrating keys of %s\n", e.Name)
+	e.PublicKey = public
+	e.PrivateKey = private
+
+	return db.SaveEntity(*e)
+}
+
 func toEntityKey(s string) string {
 	return hex.EncodeToString([]byte(s)) + ".entity"
 }
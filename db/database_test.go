@@ -1,6 +1,7 @@
 package db
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 )
@@ -81,3 +82,83 @@ func TestGetEntities(t *testing.T) {
 		t.Fatal(x)
 	}
 }
+
+func TestMigrateEntityWithSeed(t *testing.T) {
+	db, _ := NewTempDatabase()
+	e, err := NewRandomEntityWithName("Entity")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seed := e.PrivateKey[:32]
+	db.SaveEntity(NewEntity("Entity", nil, seed))
+
+	migrated, err := db.EntityWithName("Entity")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := migrated.PrivateKey, e.PrivateKey; reflect.DeepEqual(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := migrated.PublicKey, e.PublicKey; reflect.DeepEqual(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// The migrated keys are saved
+	b, _ := db.(*database).storage.Get(toEntityKey("Entity"))
+	var saved Entity
+	json.Unmarshal(b, &saved)
+	if is, want := len(saved.PrivateKey), 64; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestControllers(t *testing.T) {
+	db, _ := NewTempDatabase()
+	db.SaveEntity(NewEntity("Accessory", []byte{0x01}, []byte{0x02}))
+	db.SaveEntity(NewEntity("Controller", []byte{0x03}, nil))
+
+	controllers, err := Controllers(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := len(controllers), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := controllers[0].Name, "Controller"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestAccessoryMetadata(t *testing.T) {
+	db, _ := NewTempDatabase()
+
+	if m, err := db.AccessoryMetadata(2); err != nil || len(m) != 0 {
+		t.Fatal(m, err)
+	}
+
+	if err := db.SaveAccessoryMetadata(2, map[string]string{"backend-id": "abc"}); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := db.AccessoryMetadata(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := m["backend-id"], "abc"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if err := db.SaveAccessoryMetadata(2, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if m, err := db.AccessoryMetadata(2); err != nil || len(m) != 0 {
+		t.Fatal(m, err)
+	}
+}
@@ -29,6 +29,24 @@ func NewEntity(name string, publicKey, privateKey []byte) Entity {
 	return Entity{Name: name, PublicKey: publicKey, PrivateKey: privateKey}
 }
 
+// Controllers returns the entities of the paired controllers in d.
+// Unlike the entity of the accessory, controller entities have no private key.
+func Controllers(d Database) ([]Entity, error) {
+	es, err := d.Entities()
+	if err != nil {
+		return nil, err
+	}
+
+	var controllers []Entity
+	for _, e := range es {
+		if len(e.PrivateKey) == 0 {
+			controllers = append(controllers, e)
+		}
+	}
+
+	return controllers, nil
+}
+
 // generateKeyPairs generates random public and private key pairs
 func generateKeyPairs() ([]byte, []byte, error) {
 	str := util.RandomHexString()
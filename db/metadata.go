@@ -0,0 +1,38 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// AccessoryMetadata returns the metadata of the accessory with id aid, which was stored with SaveAccessoryMetadata.
+// An empty map is returned when no metadata is stored.
+func (db *database) AccessoryMetadata(aid int64) (map[string]string, error) {
+	m := map[string]string{}
+	b, err := db.storage.Get(toMetadataKey(aid))
+	if err != nil || len(b) == 0 {
+		return m, nil
+	}
+
+	err = json.Unmarshal(b, &m)
+
+	return m, err
+}
+
+func (db *database) SaveAccessoryMetadata(aid int64, metadata map[string]string) error {
+	if len(metadata) == 0 {
+		db.storage.Delete(toMetadataKey(aid))
+		return nil
+	}
+
+	b, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+
+	return db.storage.Set(toMetadataKey(aid), b)
+}
+
+func toMetadataKey(aid int64) string {
+	return fmt.Sprintf("%d.metadata", aid)
+}
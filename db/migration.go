@@ -0,0 +1,88 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/brutella/hc/util"
+	"github.com/brutella/log"
+)
+
+// SchemaVersion is the version of the current storage schema.
+const SchemaVersion = 1
+
+// schemaVersionKey is the storage key of the schema version
+const schemaVersionKey = "schema.version"
+
+// ErrNewerSchema is returned when the storage was written by a newer version.
+// Using such a storage could break the stored pairings.
+var ErrNewerSchema = errors.New("Storage was written with a newer schema version")
+
+// migration upgrades the storage from the previous schema version to version.
+type migration struct {
+	version int
+	migrate func(db *database) error
+}
+
+// migrations lists all migrations in ascending order of their version.
+// A migration must be added here when the encoding of stored data changes.
+var migrations = []migration{
+	{version: 1, migrate: migrateEntityKeys},
+}
+
+// Migrate upgrades the data in storage to the current schema version.
+// Storages without a schema version are treated as version 0.
+func Migrate(storage util.Storage) error {
+	db := &database{storage: storage}
+
+	version, err := db.schemaVersion()
+	if err != nil {
+		return err
+	}
+
+	if version > SchemaVersion {
+		return ErrNewerSchema
+	}
+
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+
+		log.Printf("[INFO] Migrating storage to schema version %d\n", m.version)
+		if err := m.migrate(db); err != nil {
+			return fmt.Errorf("Migration to schema version %d failed: %v", m.version, err)
+		}
+
+		// The version is stored after every migration so that an interrupted
+		// migration continues where it stopped
+		if err := db.saveSchemaVersion(m.version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *database) schemaVersion() (int, error) {
+	b, err := db.storage.Get(schemaVersionKey)
+	if err != nil {
+		// No version is stored by installations before schema versioning
+		return 0, nil
+	}
+
+	return strconv.Atoi(string(b))
+}
+
+func (db *database) saveSchemaVersion(version int) error {
+	return db.storage.Set(schemaVersionKey, []byte(strconv.Itoa(version)))
+}
+
+// migrateEntityKeys expands the private keys of all entities, which were
+// stored as 32 byte seed, to 64 bytes.
+func migrateEntityKeys(db *database) error {
+	// Entities are migrated when they are loaded
+	_, err := db.Entities()
+	return err
+}
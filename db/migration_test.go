@@ -0,0 +1,46 @@
+package db
+
+import (
+	"github.com/brutella/hc/util"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	storage, err := util.NewTempFileStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Migrate(storage); err != nil {
+		t.Fatal(err)
+	}
+
+	db := &database{storage: storage}
+	version, err := db.schemaVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := version, SchemaVersion; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// Migrating again has no effect
+	if err := Migrate(storage); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigrateNewerSchema(t *testing.T) {
+	storage, err := util.NewTempFileStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := &database{storage: storage}
+	db.saveSchemaVersion(SchemaVersion + 1)
+
+	if is, want := Migrate(storage), ErrNewerSchema; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
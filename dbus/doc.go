@@ -0,0 +1,17 @@
+// Package dbus exposes the accessories and the pairing controls of a bridge on D-Bus,
+// so that desktop tools and other daemons can introspect and control the bridge.
+//
+// The object Path implements the interface Interface with the methods
+//
+//	State() (s)                           // JSON of the accessories and their values
+//	GetValue(x aid, x iid) (v)
+//	SetValue(x aid, x iid, v value)
+//	Status() (a{sv})                      // advertising, paired, active_connections, uptime, healthy
+//	EnablePairing()
+//	Pairings() (as)                       // names of the paired controllers
+//	RemovePairing(s name)
+//
+// and the signal
+//
+//	ValueChanged(x aid, x iid, v value, b remote)
+package dbus
@@ -0,0 +1,234 @@
+package dbus
+
+import (
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/db"
+	"github.com/brutella/hc/hap"
+	"github.com/brutella/hc/service"
+	"github.com/brutella/log"
+
+	godbus "github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+
+	"fmt"
+	"net"
+)
+
+// Names of the D-Bus service
+const (
+	// Name is the well-known bus name of the service.
+	Name = "com.github.brutella.hc"
+
+	// Interface is the name of the interface.
+	Interface = "com.github.brutella.hc.Bridge"
+
+	// Path is the path of the object.
+	Path = godbus.ObjectPath("/com/github/brutella/hc")
+)
+
+// errorNotFound is the name of the error for unknown characteristics and pairings
+const errorNotFound = Interface + ".NotFound"
+
+// Service provides the accessories and pairing controls of a transport on D-Bus.
+type Service struct {
+	// Database is used to list and remove pairings. When nil, pairings are not available.
+	Database db.Database
+
+	transport hap.Transport
+	container *accessory.Container
+	conn      *godbus.Conn
+}
+
+// NewService returns a service for the transport t and its accessories.
+func NewService(t hap.Transport, as ...*accessory.Accessory) *Service {
+	s := &Service{
+		transport: t,
+		container: accessory.NewContainer(),
+	}
+
+	for _, a := range as {
+		s.container.AddAccessory(a)
+	}
+
+	s.container.ForEachCharacteristic(func(a *accessory.Accessory, _ *service.Service, c *characteristic.Characteristic) {
+		aid := a.GetID()
+		c.OnValueUpdate(func(c *characteristic.Characteristic, new, old interface{}) {
+			s.emit(aid, c.GetID(), new, false)
+		})
+		c.OnValueUpdateFromConn(func(conn net.Conn, c *characteristic.Characteristic, new, old interface{}) {
+			s.emit(aid, c.GetID(), new, true)
+		})
+	})
+
+	return s
+}
+
+// Export exports the service on conn, e.g. the system or session bus, and requests the name Name.
+func (s *Service) Export(conn *godbus.Conn) error {
+	obj := &object{s}
+	if err := conn.Export(obj, Path, Interface); err != nil {
+		return err
+	}
+
+	node := &introspect.Node{
+		Name: string(Path),
+		Interfaces: []introspect.Interface{
+			introspect.IntrospectData,
+			{
+				Name:    Interface,
+				Methods: introspect.Methods(obj),
+				Signals: []introspect.Signal{{
+					Name: "ValueChanged",
+					Args: []introspect.Arg{
+						{Name: "aid", Type: "x"},
+						{Name: "iid", Type: "x"},
+						{Name: "value", Type: "v"},
+						{Name: "remote", Type: "b"},
+					},
+				}},
+			},
+		},
+	}
+	if err := conn.Export(introspect.NewIntrospectable(node), Path, "org.freedesktop.DBus.Introspectable"); err != nil {
+		return err
+	}
+
+	reply, err := conn.RequestName(Name, godbus.NameFlagDoNotQueue)
+	if err != nil {
+		return err
+	}
+
+	if reply != godbus.RequestNameReplyPrimaryOwner {
+		return fmt.Errorf("name %s already taken", Name)
+	}
+
+	s.conn = conn
+	return nil
+}
+
+// emit sends the ValueChanged signal.
+func (s *Service) emit(aid, iid int64, value interface{}, remote bool) {
+	if s.conn == nil {
+		return
+	}
+
+	if err := s.conn.Emit(Path, Interface+".ValueChanged", aid, iid, variant(value), remote); err != nil {
+		log.Println("[WARN]", err)
+	}
+}
+
+// object implements the methods of the D-Bus interface.
+type object struct {
+	s *Service
+}
+
+func (o *object) State() (string, *godbus.Error) {
+	b, err := o.s.container.MarshalState()
+	if err != nil {
+		return "", godbus.MakeFailedError(err)
+	}
+
+	return string(b), nil
+}
+
+func (o *object) GetValue(aid, iid int64) (godbus.Variant, *godbus.Error) {
+	c := o.s.container.CharacteristicByIDs(aid, iid)
+	if c == nil {
+		return godbus.Variant{}, notFound("characteristic %d.%d not found", aid, iid)
+	}
+
+	return variant(c.Value), nil
+}
+
+func (o *object) SetValue(aid, iid int64, v godbus.Variant) *godbus.Error {
+	c := o.s.container.CharacteristicByIDs(aid, iid)
+	if c == nil {
+		return notFound("characteristic %d.%d not found", aid, iid)
+	}
+
+	c.UpdateValue(v.Value())
+	return nil
+}
+
+func (o *object) Status() (map[string]godbus.Variant, *godbus.Error) {
+	st := o.s.transport.Status()
+
+	return map[string]godbus.Variant{
+		"advertising":        godbus.MakeVariant(st.Advertising),
+		"paired":             godbus.MakeVariant(int64(st.Paired)),
+		"active_connections": godbus.MakeVariant(int64(st.ActiveConnections)),
+		"uptime":             godbus.MakeVariant(st.Uptime.Seconds()),
+		"healthy":            godbus.MakeVariant(st.Healthy()),
+	}, nil
+}
+
+func (o *object) EnablePairing() *godbus.Error {
+	o.s.transport.EnablePairing()
+	return nil
+}
+
+func (o *object) Pairings() ([]string, *godbus.Error) {
+	controllers, err := o.s.controllers()
+	if err != nil {
+		return nil, godbus.MakeFailedError(err)
+	}
+
+	names := []string{}
+	for _, e := range controllers {
+		names = append(names, e.Name)
+	}
+
+	return names, nil
+}
+
+// RemovePairing removes the pairing of a controller and closes the sessions of all controllers.
+// The other controllers verify their pairing again.
+func (o *object) RemovePairing(name string) *godbus.Error {
+	controllers, err := o.s.controllers()
+	if err != nil {
+		return godbus.MakeFailedError(err)
+	}
+
+	for _, e := range controllers {
+		if e.Name == name {
+			if err := o.s.transport.RemovePairing(name); err != nil {
+				return godbus.MakeFailedError(err)
+			}
+			return nil
+		}
+	}
+
+	return notFound("pairing %s not found", name)
+}
+
+// controllers returns the entities of the paired controllers.
+func (s *Service) controllers() ([]db.Entity, error) {
+	if s.Database == nil {
+		return nil, fmt.Errorf("pairings not available")
+	}
+
+	return db.Controllers(s.Database)
+}
+
+func notFound(format string, args ...interface{}) *godbus.Error {
+	return godbus.NewError(errorNotFound, []interface{}{fmt.Sprintf(format, args...)})
+}
+
+// variant returns the variant of a characteristic value. Integers are sent as int64.
+func variant(v interface{}) godbus.Variant {
+	switch v := v.(type) {
+	case int:
+		return godbus.MakeVariant(int64(v))
+	case uint8:
+		return godbus.MakeVariant(int64(v))
+	case uint16:
+		return godbus.MakeVariant(int64(v))
+	case uint32:
+		return godbus.MakeVariant(int64(v))
+	case nil:
+		return godbus.MakeVariant("")
+	}
+
+	return godbus.MakeVariant(v)
+}
@@ -0,0 +1,124 @@
+package dbus
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/db"
+	"github.com/brutella/hc/hap"
+	"github.com/brutella/hc/netio/controller"
+	"github.com/brutella/hc/util"
+
+	godbus "github.com/godbus/dbus/v5"
+)
+
+type testTransport struct {
+	pairing  bool
+	database db.Database
+}
+
+func (t *testTransport) Start()                                                  {}
+func (t *testTransport) Stop()                                                   {}
+func (t *testTransport) Restart(hap.Config) error                                { return nil }
+func (t *testTransport) Started() <-chan struct{}                                { return nil }
+func (t *testTransport) Schedule(s hap.Schedule, fn func()) *hap.Job             { return nil }
+func (t *testTransport) Reverify()                                               {}
+func (t *testTransport) EnablePairing()                                          { t.pairing = true }
+func (t *testTransport) AddSetupCode(hap.SetupCode) error                        { return nil }
+func (t *testTransport) RevokeSetupCode(string) error                            { return nil }
+func (t *testTransport) SetupCodes() []hap.SetupCode                             { return nil }
+func (t *testTransport) SetChildOnline(aid int64, online bool) error             { return nil }
+func (t *testTransport) RenameAccessory(int64, string) error                     { return nil }
+func (t *testTransport) RenameService(int64, int64, string) error                { return nil }
+func (t *testTransport) ResetPairings() error                                    { return nil }
+func (t *testTransport) Announce()                                               {}
+func (t *testTransport) Status() hap.Status                                      { return hap.Status{Paired: 1} }
+func (t *testTransport) Endpoint() hap.Endpoint                                  { return hap.Endpoint{} }
+func (t *testTransport) Apply(map[controller.CharacteristicID]interface{}) error { return nil }
+
+func (t *testTransport) RemovePairing(username string) error {
+	t.database.DeleteEntity(db.NewEntity(username, nil, nil))
+	return nil
+}
+
+func TestValues(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "Switch"})
+	o := &object{NewService(&testTransport{}, a.Accessory)}
+
+	if err := o.SetValue(a.ID, a.Switch.On.ID, godbus.MakeVariant(true)); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := o.GetValue(a.ID, a.Switch.On.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := v.Value(), true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if _, err := o.GetValue(a.ID, 99); err == nil || err.Name != errorNotFound {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	state, err := o.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if json.Valid([]byte(state)) == false {
+		t.Fatalf("invalid state %s", state)
+	}
+}
+
+func TestPairings(t *testing.T) {
+	storage, err := util.NewTempFileStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	database := db.NewDatabaseWithStorage(storage)
+	accessory, err := db.NewRandomEntityWithName("Bridge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	database.SaveEntity(accessory)
+	database.SaveEntity(db.NewEntity("Alice", []byte{0x01}, nil))
+
+	transport := &testTransport{database: database}
+	s := NewService(transport)
+	s.Database = database
+	o := &object{s}
+
+	names, dbusErr := o.Pairings()
+	if dbusErr != nil {
+		t.Fatal(dbusErr)
+	}
+
+	if is, want := len(names), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := names[0], "Alice"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if err := o.RemovePairing("Bob"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if err := o.RemovePairing("Alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	if names, _ := o.Pairings(); len(names) != 0 {
+		t.Fatalf("unexpected pairings %v", names)
+	}
+
+	o.EnablePairing()
+	if transport.pairing == false {
+		t.Fatal("expected pairing")
+	}
+}
@@ -0,0 +1,54 @@
+package diagnostics
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestZipFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "diagnostics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "accessory.log")
+	if err := ioutil.WriteFile(path, []byte("started"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ZipFiles(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := len(r.File), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := r.File[0].Name, "accessory.log"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestZipMissingFile(t *testing.T) {
+	if _, err := ZipFiles("/nonexistent/accessory.log"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestSupportedSnapshot(t *testing.T) {
+	m := NewManager(func() ([]byte, error) { return nil, nil })
+
+	if is, want := m.Service.SupportedDiagnosticsSnapshot.GetValue(), []byte{tagSnapshotFormat, 1, snapshotFormatZip, tagSnapshotType, 1, snapshotTypeAccessory}; bytes.Equal(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
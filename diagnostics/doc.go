@@ -0,0 +1,6 @@
+// Package diagnostics implements the Diagnostics service of an accessory.
+//
+// A controller captures a diagnostics snapshot by opening a HomeKit Data Stream
+// of type DataSendTypeSnapshot. The snapshot is a zip archive (e.g. of log files),
+// which is returned by a user-defined SnapshotFunc and sent to the controller.
+package diagnostics
@@ -0,0 +1,112 @@
+package diagnostics
+
+import (
+	"github.com/brutella/hc/hds"
+	"github.com/brutella/hc/service"
+	"github.com/brutella/hc/util"
+	"github.com/brutella/log"
+)
+
+// DataSendTypeSnapshot is the type of data streams which capture a diagnostics snapshot
+const DataSendTypeSnapshot = "diagnostics.snapshot"
+
+const (
+	dataTypeSnapshot = "diagnostics.snapshot"
+
+	// maxChunkSize is the maximum size of data in one data event
+	maxChunkSize = 0x40000
+)
+
+// Snapshot formats and types of the Supported Diagnostics Snapshot characteristic
+const (
+	tagSnapshotFormat = 0x01
+	tagSnapshotType   = 0x02
+
+	snapshotFormatZip     = 0x00
+	snapshotTypeAccessory = 0x01
+)
+
+// SnapshotFunc returns a diagnostics snapshot as zip archive, e.g. created with ZipFiles.
+type SnapshotFunc func() ([]byte, error)
+
+// Manager sends diagnostics snapshots to controllers via HomeKit Data Stream.
+//
+// The service of the manager must be added to the accessory together with a
+// Data Stream Transport Management service, which is set up by a hds.Server.
+// The manager must be registered at the server for DataSendTypeSnapshot.
+//
+//	server.HandleDataSend(diagnostics.DataSendTypeSnapshot, manager)
+type Manager struct {
+	Service *service.Diagnostics
+
+	snapshot SnapshotFunc
+}
+
+// NewManager returns a manager which sends the snapshots returned by fn.
+func NewManager(fn SnapshotFunc) *Manager {
+	m := Manager{
+		Service:  service.NewDiagnostics(),
+		snapshot: fn,
+	}
+
+	tlv := util.NewTLV8Container()
+	tlv.SetByte(tagSnapshotFormat, snapshotFormatZip)
+	tlv.SetByte(tagSnapshotType, snapshotTypeAccessory)
+	m.Service.SupportedDiagnosticsSnapshot.SetValue(tlv.BytesBuffer().Bytes())
+
+	return &m
+}
+
+// HandleOpen handles a request of the controller to capture a snapshot.
+func (m *Manager) HandleOpen(st *hds.DataStream, body map[string]interface{}) int64 {
+	// Controllers expect data after the open response
+	st.Session().AfterResponse(func() {
+		go m.upload(st)
+	})
+
+	return hds.CloseReasonNormal
+}
+
+// upload sends a snapshot in chunks of data events.
+func (m *Manager) upload(st *hds.DataStream) {
+	b, err := m.snapshot()
+	if err != nil {
+		log.Println("[ERRO] Could not capture diagnostics snapshot", err)
+		st.Close(hds.CloseReasonUnexpectedFailure)
+		return
+	}
+
+	chunk := int64(1)
+	for offset := 0; offset == 0 || offset < len(b); chunk++ {
+		end := offset + maxChunkSize
+		if end > len(b) {
+			end = len(b)
+		}
+		last := end == len(b)
+
+		metadata := map[string]interface{}{
+			"dataType":                dataTypeSnapshot,
+			"dataSequenceNumber":      int64(1),
+			"dataChunkSequenceNumber": chunk,
+			"isLastDataChunk":         last,
+		}
+		if chunk == 1 {
+			metadata["dataTotalSize"] = int64(len(b))
+		}
+
+		packet := map[string]interface{}{
+			"data":     b[offset:end],
+			"metadata": metadata,
+		}
+
+		if err := st.SendData([]interface{}{packet}, last); err != nil {
+			log.Println("[ERRO] Could not send diagnostics data", err)
+			return
+		}
+
+		if last == true {
+			break
+		}
+		offset = end
+	}
+}
@@ -0,0 +1,45 @@
+package diagnostics
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ZipFiles returns a zip archive of the files at paths, e.g. log files.
+// The files are stored by their base name.
+func ZipFiles(paths ...string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+
+	for _, path := range paths {
+		if err := addFile(w, path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func addFile(w *zip.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zf, err := w.Create(filepath.Base(path))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(zf, f)
+
+	return err
+}
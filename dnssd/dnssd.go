@@ -0,0 +1,68 @@
+package dnssd
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotSupported is returned when the platform has no Bonjour daemon.
+var ErrNotSupported = errors.New("DNS-SD is not supported on this platform")
+
+// Service is a service which is registered with the Bonjour daemon.
+type Service struct {
+	ref serviceRef
+}
+
+// Register registers the service instance name of type regtype (e.g. "_hap._tcp")
+// on port with txt records. The service is announced for all addresses of the machine.
+func Register(name, regtype string, port int, txt []string) (*Service, error) {
+	b, err := txtRecord(txt)
+	if err != nil {
+		return nil, err
+	}
+
+	ref, err := register(name, regtype, uint16(port), b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{ref: ref}, nil
+}
+
+// SetText updates the txt records of the service.
+func (s *Service) SetText(txt []string) error {
+	b, err := txtRecord(txt)
+	if err != nil {
+		return err
+	}
+
+	return updateTXT(s.ref, b)
+}
+
+// Stop unregisters the service.
+func (s *Service) Stop() {
+	deallocate(s.ref)
+}
+
+// txtRecord returns the txt records encoded as length-prefixed strings.
+func txtRecord(txt []string) ([]byte, error) {
+	var b []byte
+	for _, t := range txt {
+		if len(t) > 255 {
+			return nil, fmt.Errorf("TXT record %s is too long", t)
+		}
+		b = append(b, byte(len(t)))
+		b = append(b, t...)
+	}
+
+	return b, nil
+}
+
+// errorForCode returns an error for a DNSServiceErrorType code.
+func errorForCode(code int32) error {
+	if code == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("DNS-SD error %d", code)
+}
@@ -0,0 +1,56 @@
+//go:build darwin && cgo
+// +build darwin,cgo
+
+package dnssd
+
+/*
+#include <stdlib.h>
+#include <dns_sd.h>
+
+static DNSServiceErrorType registerService(DNSServiceRef *ref, const char *name, const char *regtype, uint16_t port, uint16_t txtLen, const void *txt) {
+	return DNSServiceRegister(ref, 0, 0, name, regtype, NULL, NULL, htons(port), txtLen, txt, NULL, NULL);
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+type serviceRef C.DNSServiceRef
+
+func register(name, regtype string, port uint16, txt []byte) (serviceRef, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	ctype := C.CString(regtype)
+	defer C.free(unsafe.Pointer(ctype))
+
+	var ctxt unsafe.Pointer
+	if len(txt) > 0 {
+		ctxt = C.CBytes(txt)
+		defer C.free(ctxt)
+	}
+
+	var ref C.DNSServiceRef
+	code := C.registerService(&ref, cname, ctype, C.uint16_t(port), C.uint16_t(len(txt)), ctxt)
+	if err := errorForCode(int32(code)); err != nil {
+		return nil, err
+	}
+
+	return serviceRef(ref), nil
+}
+
+func updateTXT(ref serviceRef, txt []byte) error {
+	var ctxt unsafe.Pointer
+	if len(txt) > 0 {
+		ctxt = C.CBytes(txt)
+		defer C.free(ctxt)
+	}
+
+	code := C.DNSServiceUpdateRecord(C.DNSServiceRef(ref), nil, 0, C.uint16_t(len(txt)), ctxt, 0)
+	return errorForCode(int32(code))
+}
+
+func deallocate(ref serviceRef) {
+	C.DNSServiceRefDeallocate(C.DNSServiceRef(ref))
+}
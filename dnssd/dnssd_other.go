@@ -0,0 +1,16 @@
+//go:build (!darwin && !windows) || (darwin && !cgo)
+// +build !darwin,!windows darwin,!cgo
+
+package dnssd
+
+type serviceRef struct{}
+
+func register(name, regtype string, port uint16, txt []byte) (serviceRef, error) {
+	return serviceRef{}, ErrNotSupported
+}
+
+func updateTXT(ref serviceRef, txt []byte) error {
+	return ErrNotSupported
+}
+
+func deallocate(ref serviceRef) {}
@@ -0,0 +1,24 @@
+package dnssd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTXTRecord(t *testing.T) {
+	b, err := txtRecord([]string{"pv=1.0", "c#=1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := b, []byte("\x06pv=1.0\x04c#=1"); bytes.Equal(is, want) == false {
+		t.Fatalf("is=%q want=%q", is, want)
+	}
+}
+
+func TestTXTRecordTooLong(t *testing.T) {
+	if _, err := txtRecord([]string{strings.Repeat("a", 256)}); err == nil {
+		t.Fatal("expected error")
+	}
+}
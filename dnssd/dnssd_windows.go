@@ -0,0 +1,71 @@
+package dnssd
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// dnssd.dll is installed by Bonjour for Windows (or iTunes)
+var (
+	dll               = syscall.NewLazyDLL("dnssd.dll")
+	procRegister      = dll.NewProc("DNSServiceRegister")
+	procUpdateRecord  = dll.NewProc("DNSServiceUpdateRecord")
+	procRefDeallocate = dll.NewProc("DNSServiceRefDeallocate")
+)
+
+type serviceRef uintptr
+
+func register(name, regtype string, port uint16, txt []byte) (serviceRef, error) {
+	if err := dll.Load(); err != nil {
+		return 0, ErrNotSupported
+	}
+
+	cname, err := syscall.BytePtrFromString(name)
+	if err != nil {
+		return 0, err
+	}
+
+	ctype, err := syscall.BytePtrFromString(regtype)
+	if err != nil {
+		return 0, err
+	}
+
+	var ref serviceRef
+	code, _, _ := procRegister.Call(
+		uintptr(unsafe.Pointer(&ref)),
+		0, // flags
+		0, // all interfaces
+		uintptr(unsafe.Pointer(cname)),
+		uintptr(unsafe.Pointer(ctype)),
+		0,                        // default domain
+		0,                        // default host
+		uintptr(port>>8|port<<8), // network byte order
+		uintptr(len(txt)),
+		bytesPtr(txt),
+		0, // no callback
+		0,
+	)
+
+	if err := errorForCode(int32(code)); err != nil {
+		return 0, err
+	}
+
+	return ref, nil
+}
+
+func updateTXT(ref serviceRef, txt []byte) error {
+	code, _, _ := procUpdateRecord.Call(uintptr(ref), 0, 0, uintptr(len(txt)), bytesPtr(txt), 0)
+	return errorForCode(int32(code))
+}
+
+func deallocate(ref serviceRef) {
+	procRefDeallocate.Call(uintptr(ref))
+}
+
+func bytesPtr(b []byte) uintptr {
+	if len(b) == 0 {
+		return 0
+	}
+
+	return uintptr(unsafe.Pointer(&b[0]))
+}
@@ -0,0 +1,7 @@
+// Package dnssd registers DNS-SD services with the Bonjour daemon of the
+// operating system (mDNSResponder on macOS, Bonjour for Windows).
+//
+// Using the system daemon avoids conflicts with a responder in the process,
+// which has to share the multicast port 5353 with the system daemon.
+// On other platforms, or when built without cgo on macOS, Register returns ErrNotSupported.
+package dnssd
@@ -0,0 +1,111 @@
+package event
+
+import (
+	"sync"
+
+	"github.com/brutella/log"
+)
+
+// AsyncEmitter emits events asynchronously from a bounded queue.
+// A slow listener delays the delivery of events but not the caller of Emit.
+//
+// Pairing events (DevicePaired and DeviceUnpaired) are never dropped;
+// they are queued without a limit and delivered in the order they were emitted.
+type AsyncEmitter struct {
+	*eventEmitter
+
+	queue chan interface{}
+	done  chan struct{}
+	once  sync.Once
+
+	// pending are the pairing events which were not delivered yet
+	pending      []interface{}
+	pendingMutex sync.Mutex
+	wake         chan struct{}
+}
+
+// NewAsyncEmitter returns an emitter which queues up to size events.
+// Events are dropped when the queue is full, except pairing events.
+func NewAsyncEmitter(size int) *AsyncEmitter {
+	e := AsyncEmitter{
+		eventEmitter: newEventEmitter(),
+		queue:        make(chan interface{}, size),
+		done:         make(chan struct{}),
+		wake:         make(chan struct{}, 1),
+	}
+
+	go e.dispatch()
+
+	return &e
+}
+
+// Emit adds the event to the queue.
+func (e *AsyncEmitter) Emit(ev interface{}) {
+	select {
+	case <-e.done:
+		return
+	default:
+	}
+
+	if isPairingEvent(ev) == true {
+		e.pendingMutex.Lock()
+		e.pending = append(e.pending, ev)
+		e.pendingMutex.Unlock()
+
+		select {
+		case e.wake <- struct{}{}:
+		default:
+			// The dispatcher is already woken up
+		}
+		return
+	}
+
+	select {
+	case e.queue <- ev:
+	default:
+		log.Printf("[WARN] Dropped event %T because queue is full\n", ev)
+	}
+}
+
+// Stop stops the delivery of events. Queued events are dropped.
+func (e *AsyncEmitter) Stop() {
+	e.once.Do(func() {
+		close(e.done)
+	})
+}
+
+func (e *AsyncEmitter) dispatch() {
+	for {
+		select {
+		case <-e.done:
+			return
+		case <-e.wake:
+			for _, ev := range e.takePending() {
+				e.eventEmitter.Emit(ev)
+			}
+		case ev := <-e.queue:
+			e.eventEmitter.Emit(ev)
+		}
+	}
+}
+
+// takePending removes and returns the pending pairing events.
+func (e *AsyncEmitter) takePending() []interface{} {
+	e.pendingMutex.Lock()
+	defer e.pendingMutex.Unlock()
+
+	evs := e.pending
+	e.pending = nil
+
+	return evs
+}
+
+// isPairingEvent returns true when ev must not be dropped.
+func isPairingEvent(ev interface{}) bool {
+	switch ev.(type) {
+	case DevicePaired, DeviceUnpaired:
+		return true
+	}
+
+	return false
+}
@@ -1,7 +1,50 @@
 package event
 
+import "time"
+
+// Permissions of a paired controller
+const (
+	PermissionUser  byte = 0x00 // regular user
+	PermissionAdmin byte = 0x01 // admin which can add and remove pairings
+)
+
 // DevicePaired is emitted when transport paired with a device (e.g. iOS client successfully paired with the accessory)
-type DevicePaired struct{}
+type DevicePaired struct {
+	// Username is the pairing identifier of the controller
+	Username string
+
+	// Permission is the permission of the controller, e.g. PermissionAdmin
+	Permission byte
+}
 
 // DeviceUnpaired is emitted when pairing with a device is removed (e.g. iOS client removed the accessory from HomeKit)
-type DeviceUnpaired struct{}
+type DeviceUnpaired struct {
+	// Username is the pairing identifier of the controller
+	Username string
+}
+
+// ConsumerEvicted is emitted when a connection was closed because the controller
+// didn't acknowledge the sent data for too long (e.g. a misbehaving hub)
+type ConsumerEvicted struct {
+	// Addr is the remote address of the connection
+	Addr string
+
+	// Controller is the name of the controller which verified the connection, or empty
+	Controller string
+
+	// Unacknowledged is the number of bytes which were not acknowledged
+	Unacknowledged int
+}
+
+// AcceptFailed is emitted when accepting a connection failed with a temporary error
+// and is retried after a backoff, e.g. when the process ran out of file descriptors
+type AcceptFailed struct {
+	// Err is the error of the accept
+	Err error
+
+	// Backoff is the delay before accept is retried
+	Backoff time.Duration
+
+	// OutOfFileDescriptors is true when the file descriptor limit is reached (EMFILE or ENFILE)
+	OutOfFileDescriptors bool
+}
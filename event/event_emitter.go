@@ -1,32 +1,107 @@
 package event
 
+import (
+	"reflect"
+	"sync"
+
+	"github.com/brutella/log"
+)
+
 // Emitter emits events to listeners
 type Emitter interface {
 
 	// Emit emits the event to all listeners
 	Emit(ev interface{})
 
-	// AddListener adds a listener to the event stream
-	AddListener(l EventListener)
+	// AddListener adds a listener to the event stream.
+	// The listener is removed by calling Remove() on the returned subscription.
+	AddListener(l EventListener) *Subscription
+
+	// Subscribe returns a channel which receives events of the same type as ev, e.g. DevicePaired{}.
+	// Events are dropped when the channel buffer of the argument size is full.
+	Subscribe(ev interface{}, size int) (<-chan interface{}, *Subscription)
+}
+
+// Subscription is a listener of an emitter.
+type Subscription struct {
+	emitter *eventEmitter
+	l       EventListener
+}
+
+// Remove removes the listener from the emitter.
+func (s *Subscription) Remove() {
+	s.emitter.remove(s)
 }
 
 type eventEmitter struct {
-	ls []EventListener
+	mutex sync.Mutex
+	subs  []*Subscription
 }
 
-// NewEmitter returns a new event emitter
+// NewEmitter returns a new event emitter, which delivers events synchronously
 func NewEmitter() Emitter {
+	return newEventEmitter()
+}
+
+func newEventEmitter() *eventEmitter {
 	return &eventEmitter{
-		ls: make([]EventListener, 0),
+		subs: make([]*Subscription, 0),
 	}
 }
 
 func (e *eventEmitter) Emit(ev interface{}) {
-	for _, l := range e.ls {
-		l.Handle(ev)
+	e.mutex.Lock()
+	subs := append([]*Subscription{}, e.subs...)
+	e.mutex.Unlock()
+
+	for _, s := range subs {
+		s.l.Handle(ev)
+	}
+}
+
+func (e *eventEmitter) AddListener(l EventListener) *Subscription {
+	s := &Subscription{emitter: e, l: l}
+
+	e.mutex.Lock()
+	e.subs = append(e.subs, s)
+	e.mutex.Unlock()
+
+	return s
+}
+
+func (e *eventEmitter) Subscribe(ev interface{}, size int) (<-chan interface{}, *Subscription) {
+	ch := make(chan interface{}, size)
+	l := &channelListener{typ: reflect.TypeOf(ev), ch: ch}
+
+	return ch, e.AddListener(l)
+}
+
+func (e *eventEmitter) remove(s *Subscription) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for i, sub := range e.subs {
+		if sub == s {
+			e.subs = append(e.subs[:i], e.subs[i+1:]...)
+			return
+		}
 	}
 }
 
-func (e *eventEmitter) AddListener(l EventListener) {
-	e.ls = append(e.ls, l)
+// channelListener sends events of a specific type to a channel.
+type channelListener struct {
+	typ reflect.Type
+	ch  chan interface{}
+}
+
+func (l *channelListener) Handle(ev interface{}) {
+	if reflect.TypeOf(ev) != l.typ {
+		return
+	}
+
+	select {
+	case l.ch <- ev:
+	default:
+		log.Printf("[WARN] Dropped event %T because channel is full\n", ev)
+	}
 }
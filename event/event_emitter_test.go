@@ -2,6 +2,7 @@ package event
 
 import (
 	"testing"
+	"time"
 )
 
 type testListener struct {
@@ -24,3 +25,85 @@ func TestEmitter(t *testing.T) {
 		t.Fatal(x)
 	}
 }
+
+func TestRemoveListener(t *testing.T) {
+	e := NewEmitter()
+
+	l := &testListener{}
+	s := e.AddListener(l)
+	s.Remove()
+
+	e.Emit(10)
+
+	if x := l.last; x != nil {
+		t.Fatal(x)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	e := NewEmitter()
+	ch, _ := e.Subscribe(DevicePaired{}, 1)
+
+	e.Emit(DeviceUnpaired{})
+	e.Emit(DevicePaired{})
+
+	select {
+	case ev := <-ch:
+		if _, ok := ev.(DevicePaired); ok == false {
+			t.Fatal(ev)
+		}
+	default:
+		t.Fatal("expected event")
+	}
+}
+
+type blockingListener struct {
+	ch chan interface{}
+}
+
+func (l *blockingListener) Handle(e interface{}) {
+	l.ch <- e
+}
+
+func TestAsyncEmitter(t *testing.T) {
+	e := NewAsyncEmitter(2)
+	defer e.Stop()
+
+	l := &blockingListener{make(chan interface{})}
+	e.AddListener(l)
+
+	// Doesn't block although the listener is not ready
+	e.Emit(1)
+
+	select {
+	case x := <-l.ch:
+		if x != 1 {
+			t.Fatal(x)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected event")
+	}
+}
+
+func TestAsyncEmitterKeepsPairingEvents(t *testing.T) {
+	e := NewAsyncEmitter(1)
+	defer e.Stop()
+
+	l := &blockingListener{make(chan interface{})}
+	e.AddListener(l)
+
+	for i := 0; i < 10; i++ {
+		e.Emit(DevicePaired{})
+	}
+
+	for i := 0; i < 10; i++ {
+		select {
+		case ev := <-l.ch:
+			if _, ok := ev.(DevicePaired); ok == false {
+				t.Fatal(ev)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected event %d", i)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+// Package firmware implements the firmware update service of an accessory.
+//
+// A Manager provides the Firmware Update service and stages firmware images, which
+// are received on a HomeKit Data Stream (see hds.Server.HandleDataSend) or passed to
+// Manager.Stage from any other source. The device receives the image via the
+// UpdateProvider interface and applies or discards it when requested.
+package firmware
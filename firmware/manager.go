@@ -0,0 +1,192 @@
+package firmware
+
+import (
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/hds"
+	"github.com/brutella/hc/service"
+	"github.com/brutella/log"
+
+	"errors"
+	"io"
+	"sync"
+)
+
+// DataStreamType is the type of the data streams on which firmware images are received.
+const DataStreamType = "accessory.firmware"
+
+// ErrBusy is returned when a firmware image is staged or applied while another update is in progress.
+var ErrBusy = errors.New("Firmware update in progress")
+
+// ErrNotStaged is returned when no firmware is staged.
+var ErrNotStaged = errors.New("No firmware staged")
+
+var errStreamClosed = errors.New("Data stream closed")
+
+// Manager manages the firmware updates of an accessory.
+//
+// The manager implements hds.DataSendHandler to receive firmware images on
+// a HomeKit Data Stream of type DataStreamType.
+type Manager struct {
+	Service               *service.FirmwareUpdate
+	StagedFirmwareVersion *characteristic.StagedFirmwareVersion
+
+	provider UpdateProvider
+	state    State
+	version  string
+	mutex    sync.Mutex
+}
+
+// NewManager returns a manager which passes firmware updates to provider.
+// The service of the manager must be added to the accessory.
+func NewManager(provider UpdateProvider) *Manager {
+	m := Manager{
+		Service:               service.NewFirmwareUpdate(),
+		StagedFirmwareVersion: characteristic.NewStagedFirmwareVersion(),
+		provider:              provider,
+		state:                 StateIdle,
+	}
+
+	m.Service.AddCharacteristic(m.StagedFirmwareVersion.Characteristic)
+	m.Service.FirmwareUpdateReadiness.SetValue(readiness(true))
+	m.Service.FirmwareUpdateStatus.SetValue(status(StateIdle, ""))
+
+	return &m
+}
+
+// State returns the state of the firmware update and the version of the staged firmware.
+func (m *Manager) State() (State, string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.state, m.version
+}
+
+// Stage stages the firmware image of version, which is read from r.
+// A previously staged firmware is replaced.
+func (m *Manager) Stage(version string, r io.Reader) error {
+	m.mutex.Lock()
+	if m.state == StateStaging || m.state == StateApplying {
+		m.mutex.Unlock()
+		return ErrBusy
+	}
+	m.state = StateStaging
+	m.version = ""
+	m.mutex.Unlock()
+
+	m.update(StateStaging, "")
+	log.Println("[INFO] Staging firmware", version)
+
+	if err := m.provider.Stage(version, r); err != nil {
+		log.Println("[ERRO] Staging firmware failed", err)
+		m.setState(StateFailed, "")
+		return err
+	}
+
+	m.setState(StateStaged, version)
+
+	return nil
+}
+
+// Apply applies the staged firmware.
+func (m *Manager) Apply() error {
+	m.mutex.Lock()
+	if m.state != StateStaged {
+		m.mutex.Unlock()
+		return ErrNotStaged
+	}
+	version := m.version
+	m.state = StateApplying
+	m.mutex.Unlock()
+
+	m.update(StateApplying, version)
+	log.Println("[INFO] Applying firmware", version)
+
+	if err := m.provider.Apply(version); err != nil {
+		log.Println("[ERRO] Applying firmware failed", err)
+		m.setState(StateFailed, version)
+		return err
+	}
+
+	return nil
+}
+
+// Abort discards the staged firmware.
+func (m *Manager) Abort() error {
+	m.mutex.Lock()
+	if m.state == StateStaging || m.state == StateApplying {
+		m.mutex.Unlock()
+		return ErrBusy
+	}
+	m.mutex.Unlock()
+
+	if err := m.provider.Abort(); err != nil {
+		return err
+	}
+
+	m.setState(StateIdle, "")
+
+	return nil
+}
+
+// HandleOpen receives a firmware image on the data stream st.
+// The version of the firmware is the "version" value of the open request.
+func (m *Manager) HandleOpen(st *hds.DataStream, body map[string]interface{}) int64 {
+	version, _ := body["version"].(string)
+	if len(version) == 0 {
+		return hds.CloseReasonInvalidConfiguration
+	}
+
+	if s, _ := m.State(); s == StateStaging || s == StateApplying {
+		return hds.CloseReasonBusy
+	}
+
+	r, w := io.Pipe()
+	st.OnData(func(packets []interface{}, endOfStream bool) {
+		for _, p := range packets {
+			packet, _ := p.(map[string]interface{})
+			if data, ok := packet["data"].([]byte); ok == true {
+				if _, err := w.Write(data); err != nil {
+					return
+				}
+			}
+		}
+
+		if endOfStream == true {
+			w.Close()
+		}
+	})
+
+	go func() {
+		<-st.Done()
+		w.CloseWithError(errStreamClosed)
+	}()
+
+	go func() {
+		err := m.Stage(version, r)
+		// Unblocks writes when the image was not read completely
+		r.CloseWithError(errStreamClosed)
+
+		if err != nil {
+			st.Close(hds.CloseReasonUnexpectedFailure)
+		} else {
+			st.Close(hds.CloseReasonNormal)
+		}
+	}()
+
+	return hds.CloseReasonNormal
+}
+
+func (m *Manager) setState(state State, version string) {
+	m.mutex.Lock()
+	m.state = state
+	m.version = version
+	m.mutex.Unlock()
+
+	m.update(state, version)
+}
+
+// update updates the characteristics of the service.
+func (m *Manager) update(state State, version string) {
+	m.Service.FirmwareUpdateStatus.SetValue(status(state, version))
+	m.StagedFirmwareVersion.SetValue(version)
+}
@@ -0,0 +1,117 @@
+package firmware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/brutella/hc/util"
+)
+
+type testProvider struct {
+	image   []byte
+	applied string
+	err     error
+}
+
+func (p *testProvider) Stage(version string, r io.Reader) error {
+	if p.err != nil {
+		return p.err
+	}
+
+	b, err := ioutil.ReadAll(r)
+	p.image = b
+
+	return err
+}
+
+func (p *testProvider) Apply(version string) error {
+	p.applied = version
+	return nil
+}
+
+func (p *testProvider) Abort() error {
+	p.image = nil
+	return nil
+}
+
+func statusState(t *testing.T, m *Manager) State {
+	tlv, err := util.NewTLV8ContainerFromReader(bytes.NewBuffer(m.Service.FirmwareUpdateStatus.GetValue()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return State(tlv.GetByte(tagStatusState))
+}
+
+func TestStageAndApply(t *testing.T) {
+	p := &testProvider{}
+	m := NewManager(p)
+
+	if err := m.Apply(); err != ErrNotStaged {
+		t.Fatal(err)
+	}
+
+	if err := m.Stage("1.1", bytes.NewBufferString("image")); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := string(p.image), "image"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := statusState(t, m), StateStaged; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := m.StagedFirmwareVersion.GetValue(), "1.1"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if err := m.Apply(); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := p.applied, "1.1"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if s, _ := m.State(); s != StateApplying {
+		t.Fatal(s)
+	}
+}
+
+func TestAbort(t *testing.T) {
+	p := &testProvider{}
+	m := NewManager(p)
+
+	if err := m.Stage("1.1", bytes.NewBufferString("image")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Abort(); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.image != nil {
+		t.Fatal(p.image)
+	}
+	if is, want := statusState(t, m), StateIdle; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := m.StagedFirmwareVersion.GetValue(), ""; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestStageFailed(t *testing.T) {
+	p := &testProvider{err: errors.New("invalid image")}
+	m := NewManager(p)
+
+	if err := m.Stage("1.1", bytes.NewBufferString("image")); err != p.err {
+		t.Fatal(err)
+	}
+
+	if is, want := statusState(t, m), StateFailed; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,18 @@
+package firmware
+
+import (
+	"io"
+)
+
+// UpdateProvider is implemented by a device to receive firmware updates.
+type UpdateProvider interface {
+	// Stage stores the firmware image of version, which is read from r.
+	// The image must not be applied until Apply is called.
+	Stage(version string, r io.Reader) error
+
+	// Apply applies the staged firmware of version, e.g. by rebooting into the new firmware.
+	Apply(version string) error
+
+	// Abort discards the staged firmware.
+	Abort() error
+}
@@ -0,0 +1,73 @@
+package firmware
+
+import (
+	"github.com/brutella/hc/util"
+)
+
+// State is the state of a firmware update.
+type State byte
+
+const (
+	// StateIdle is the state when no firmware is staged.
+	StateIdle State = 0x00
+
+	// StateStaging is the state while the firmware image is received.
+	StateStaging State = 0x01
+
+	// StateStaged is the state when the firmware image is staged and can be applied.
+	StateStaged State = 0x02
+
+	// StateApplying is the state while the staged firmware is applied.
+	StateApplying State = 0x03
+
+	// StateFailed is the state when staging or applying the firmware failed.
+	StateFailed State = 0x04
+)
+
+func (s State) String() string {
+	switch s {
+	case StateIdle:
+		return "Idle"
+	case StateStaging:
+		return "Staging"
+	case StateStaged:
+		return "Staged"
+	case StateApplying:
+		return "Applying"
+	case StateFailed:
+		return "Failed"
+	}
+
+	return "Unknown"
+}
+
+// TLV8 tags of the firmware update characteristics
+const (
+	tagReadinessReady = 0x01
+
+	tagStatusState   = 0x01
+	tagStatusVersion = 0x02
+)
+
+// readiness returns the TLV8 value of the Firmware Update Readiness characteristic.
+func readiness(ready bool) []byte {
+	tlv := util.NewTLV8Container()
+	if ready == true {
+		tlv.SetByte(tagReadinessReady, 1)
+	} else {
+		tlv.SetByte(tagReadinessReady, 0)
+	}
+
+	return tlv.BytesBuffer().Bytes()
+}
+
+// status returns the TLV8 value of the Firmware Update Status characteristic.
+func status(state State, version string) []byte {
+	tlv := util.NewTLV8Container()
+	tlv.SetByte(tagStatusState, byte(state))
+	if len(version) > 0 {
+		tlv.SetString(tagStatusVersion, version)
+	}
+
+	return tlv.BytesBuffer().Bytes()
+}
@@ -36,13 +36,17 @@ func New{{.StructName}}() *{{.StructName}} {
     {{if .HasMaxValue}}char.SetMaxValue({{.MaxValue}}){{end}}
     {{if .HasStepValue}}char.SetStepValue({{.StepValue}}){{end}}
     {{if .HasDefaultValue}}char.SetValue({{.DefaultValue}}){{end}}
-    {{if .UnitName}}char.Unit = {{.UnitName}}{{end}}
+    {{if .UnitName}}char.Unit = {{.UnitName}}{{end}}{{if .HasMaxLen}}
+    char.SetMaxLen({{.MaxLen}}){{end}}{{if .HasMaxDataLen}}
+    char.SetMaxDataLen({{.MaxDataLen}}){{end}}{{if .AlwaysNotify}}
+    char.AlwaysNotify = true{{end}}
     
 	return &{{.StructName}}{char}
 }`
 
 // Characteristic holds characteristic template data
 type Characteristic struct {
+	Name               string      // Name of the characteristic (e.g. Current Temperature)
 	EmbeddedStructName string      // Name of the embedded struct (e.g. Int)
 	FormatTypeName     string      // Name of the format type (e.g. FormatInt32)
 	StructName         string      // Name of the struct (e.g. Brightness)
@@ -55,12 +59,16 @@ type Characteristic struct {
 	MaxValue           interface{} // e.g. 100
 	StepValue          interface{} // e.g. 1
 	UnitName           string      // Name of the unit e.g. UnitPercentage
+	MaxLen             interface{} // Max length of strings e.g. 64
+	MaxDataLen         interface{} // Max length of data e.g. 2097152
+	AlwaysNotify       bool        // Notify when the value didn't change e.g. for button events
 
 	Consts []ConstDecl
 }
 
 func NewCharacteristic(char *CharacteristicMetadata) *Characteristic {
 	data := Characteristic{
+		Name:               char.Name,
 		EmbeddedStructName: embeddedStructNames[char.Format],
 		FormatTypeName:     formatConstants[char.Format],
 		StructName:         structName(char),
@@ -73,6 +81,9 @@ func NewCharacteristic(char *CharacteristicMetadata) *Characteristic {
 		MaxValue:           maxValue(char),
 		StepValue:          stepValue(char),
 		UnitName:           unitName(char),
+		MaxLen:             constraintWithKey(char, "MaximumLength"),
+		MaxDataLen:         constraintWithKey(char, "MaximumDataLength"),
+		AlwaysNotify:       eventOnlyCharacteristics[char.Name],
 		Consts:             constDecls(char),
 	}
 
@@ -94,6 +105,16 @@ func (d Characteristic) HasStepValue() bool {
 	return d.StepValue != nil
 }
 
+// HasMaxLen returns true if characteristic has a max length
+func (d Characteristic) HasMaxLen() bool {
+	return d.MaxLen != nil
+}
+
+// HasMaxDataLen returns true if characteristic has a max data length
+func (d Characteristic) HasMaxDataLen() bool {
+	return d.MaxDataLen != nil
+}
+
 // HasConsts returns true if characteristic has const declarations
 func (d Characteristic) HasConsts() bool {
 	return len(d.Consts) > 0
@@ -156,6 +177,13 @@ func CharacteristicGoCode(char *CharacteristicMetadata) ([]byte, error) {
 	return buf.Bytes(), err
 }
 
+// eventOnlyCharacteristics are characteristics whose value represents an event (e.g. a button press).
+// Events are sent for every update, even if the value didn't change.
+var eventOnlyCharacteristics = map[string]bool{
+	"Programmable Switch Event": true,
+	"Button Event":              true,
+}
+
 var formatConstants = map[string]string{
 	"string": "FormatString",
 	"bool":   "FormatBool",
@@ -167,6 +195,7 @@ var formatConstants = map[string]string{
 	"uint64": "FormatUInt64",
 	"int64":  "FormatInt64",
 	"tlv8":   "FormatTLV8",
+	"data":   "FormatData",
 }
 
 var constTypes = map[string]string{
@@ -177,9 +206,10 @@ var constTypes = map[string]string{
 	"uint16": "int",
 	"uint32": "int",
 	"int32":  "int",
-	"uint64": "int",
+	"uint64": "uint64",
 	"int64":  "int",
 	"tlv8":   "[]byte",
+	"data":   "[]byte",
 }
 
 var embeddedStructNames = map[string]string{
@@ -190,8 +220,10 @@ var embeddedStructNames = map[string]string{
 	"uint16": "Int",
 	"uint32": "Int",
 	"int32":  "Int",
+	"uint64": "UInt64",
 	"int64":  "Int",
 	"tlv8":   "Bytes",
+	"data":   "Bytes",
 }
 
 // isReadable returns true the characteristic contains the readable property
@@ -222,7 +254,7 @@ func defaultValue(char *CharacteristicMetadata) interface{} {
 		}
 
 		return 0
-	case "tlv8":
+	case "tlv8", "data":
 		return "[]byte{}"
 	default:
 		break
@@ -280,6 +312,8 @@ func permissionDecl(char *CharacteristicMetadata) string {
 			perms = append(perms, "PermWrite")
 		case "cnotify":
 			perms = append(perms, "PermEvents")
+		case "writeResponse":
+			perms = append(perms, "PermWriteResponse")
 		case "uncnotify":
 			// TODO(mah)
 			break
@@ -299,6 +333,10 @@ func unitName(char *CharacteristicMetadata) string {
 		return "UnitArcDegrees"
 	case "celsius":
 		return "UnitCelsius"
+	case "lux":
+		return "UnitLux"
+	case "seconds":
+		return "UnitSeconds"
 	default:
 		return ""
 	}
@@ -0,0 +1,74 @@
+package gen
+
+import (
+	"bytes"
+	"text/template"
+)
+
+// ServiceSpecFileName is the name of the file which contains the service specs.
+const ServiceSpecFileName = "specs.go"
+
+// ServiceSpecTemplate is the template for the required and optional characteristics of every service.
+const ServiceSpecTemplate = `// THIS FILE IS AUTO-GENERATED
+package service
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+var specs = map[string]Spec{ {{range .}}
+    {{.TypeName}}: Spec{
+        Name: "{{.Name}}",
+        Required: []CharacteristicSpec{ {{range .Required}}
+            {characteristic.{{.TypeName}}, "{{.Name}}"},{{end}}
+        },
+        Optional: []CharacteristicSpec{ {{range .Optional}}
+            {characteristic.{{.TypeName}}, "{{.Name}}"},{{end}}
+        },
+    },{{end}}
+}
+`
+
+// serviceSpec holds service spec template data
+type serviceSpec struct {
+	Name     string
+	TypeName string
+	Required []*Characteristic
+	Optional []*Characteristic
+}
+
+// ServiceSpecGoCode returns the go code for the specs of all services
+func ServiceSpecGoCode(svcs []*ServiceMetadata, chars []*CharacteristicMetadata) ([]byte, error) {
+	var data []serviceSpec
+	for _, svc := range svcs {
+		data = append(data, serviceSpec{
+			Name:     svc.Name,
+			TypeName: serviceTypeName(svc),
+			Required: requiredCharacteristics(svc, chars),
+			Optional: optionalCharacteristics(svc, chars),
+		})
+	}
+
+	t, err := template.New("Spec Template").Parse(ServiceSpecTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = t.Execute(&buf, data)
+
+	return buf.Bytes(), err
+}
+
+// optionalCharacteristics returns the optional characteristics of a service, which are
+// defined in the metadata.
+func optionalCharacteristics(svc *ServiceMetadata, chars []*CharacteristicMetadata) []*Characteristic {
+	var optional = []*Characteristic{}
+	for _, uuid := range svc.OptionalCharacteristics {
+		if char := charWithUUID(uuid, chars); char != nil {
+			optional = append(optional, NewCharacteristic(char))
+		}
+	}
+
+	return optional
+}
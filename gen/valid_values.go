@@ -0,0 +1,66 @@
+package gen
+
+import (
+	"bytes"
+	"sort"
+	"text/template"
+)
+
+// ValidValuesFileName is the name of the file which contains the names of the valid values.
+const ValidValuesFileName = "valid_values.go"
+
+// ValidValuesTemplate is the template for the names of the valid values of integer characteristics.
+const ValidValuesTemplate = `// THIS FILE IS AUTO-GENERATED
+package characteristic
+
+// validValues are the names of the valid values of integer characteristics by type
+var validValues = map[string]map[int]string{ {{range .}}
+    {{.TypeName}}: map[int]string{ {{range .Consts}}
+        {{.Identifier}}: "{{.Name}}",{{end}}
+    },{{end}}
+}
+`
+
+// validValue holds the template data of a valid value
+type validValue struct {
+	Identifier string
+	Name       string
+}
+
+// validValues holds the template data of the valid values of a characteristic
+type validValues struct {
+	TypeName string
+	Consts   []validValue
+}
+
+// ValidValuesGoCode returns the go code for the names of the valid values of integer characteristics.
+func ValidValuesGoCode(chars []*CharacteristicMetadata) ([]byte, error) {
+	var data []validValues
+	for _, char := range chars {
+		values := constrainedValues(char)
+		if values == nil || constTypes[char.Format] != "int" {
+			continue
+		}
+
+		decls := constDecls(char)
+		vv := validValues{TypeName: typeName(char)}
+		for _, decl := range decls {
+			vv.Consts = append(vv.Consts, validValue{decl.Identifier, values[decl.Value.(string)].(string)})
+		}
+		data = append(data, vv)
+	}
+
+	sort.Slice(data, func(i, j int) bool {
+		return data[i].TypeName < data[j].TypeName
+	})
+
+	t, err := template.New("Valid Values Template").Parse(ValidValuesTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = t.Execute(&buf, data)
+
+	return buf.Bytes(), err
+}
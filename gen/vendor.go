@@ -0,0 +1,86 @@
+package gen
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+)
+
+// VendorCharStructTemplate is template for a characteristic struct of a vendor package (e.g. characteristic/eve).
+//
+// Vendor characteristics are not defined by Apple and therefore use their full UUID as type.
+const VendorCharStructTemplate = `// THIS FILE IS AUTO-GENERATED
+package {{.Package}}
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+const {{.TypeName}} = "{{.TypeValue}}"
+
+type {{.StructName}} struct {
+    *characteristic.{{.EmbeddedStructName}}
+}
+
+func New{{.StructName}}() *{{.StructName}} {
+    char := characteristic.New{{.EmbeddedStructName}}({{.TypeName}})
+    char.Format = characteristic.{{.FormatTypeName}}
+    char.Perms = {{.PermsDecl}}
+    {{if .HasMinValue}}char.SetMinValue({{.MinValue}}){{end}}
+    {{if .HasMaxValue}}char.SetMaxValue({{.MaxValue}}){{end}}
+    {{if .HasStepValue}}char.SetStepValue({{.StepValue}}){{end}}
+    {{if .HasDefaultValue}}char.SetValue({{.DefaultValue}}){{end}}
+    {{if .UnitName}}char.Unit = {{.UnitName}}{{end}}
+
+	return &{{.StructName}}{char}
+}`
+
+// VendorCharacteristic holds template data of a vendor characteristic.
+type VendorCharacteristic struct {
+	*Characteristic
+	Package string // Name of the vendor package (e.g. eve)
+}
+
+// vendorUnits maps units used by vendor characteristics to the constants in the vendor package.
+var vendorUnits = map[string]string{
+	"W":   "UnitWatt",
+	"kWh": "UnitKilowattHour",
+	"V":   "UnitVolt",
+	"A":   "UnitAmpere",
+}
+
+// NewVendorCharacteristic returns the template data for a characteristic in the vendor package pkg.
+func NewVendorCharacteristic(char *CharacteristicMetadata, pkg string) *VendorCharacteristic {
+	data := NewCharacteristic(char)
+	data.TypeValue = char.UUID
+	data.PermsDecl = qualifiedPermissionDecl(char)
+	if unit, ok := vendorUnits[char.Unit]; ok == true {
+		data.UnitName = unit
+	} else if len(data.UnitName) > 0 {
+		data.UnitName = "characteristic." + data.UnitName
+	}
+
+	return &VendorCharacteristic{data, pkg}
+}
+
+// VendorCharacteristicGoCode returns the go code for a characteristic file in the vendor package pkg.
+func VendorCharacteristicGoCode(char *CharacteristicMetadata, pkg string) ([]byte, error) {
+	var err error
+	var buf bytes.Buffer
+
+	data := NewVendorCharacteristic(char, pkg)
+
+	t := template.New("Vendor Template")
+
+	t, err = t.Parse(VendorCharStructTemplate)
+	t.Execute(&buf, data)
+
+	return buf.Bytes(), err
+}
+
+// qualifiedPermissionDecl returns the permissions declaration with package qualified identifiers
+// e.g. []string{characteristic.PermRead, characteristic.PermEvents}
+func qualifiedPermissionDecl(char *CharacteristicMetadata) string {
+	decl := permissionDecl(char)
+	return strings.Replace(decl, "Perm", "characteristic.Perm", -1)
+}
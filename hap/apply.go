@@ -0,0 +1,14 @@
+package hap
+
+import (
+	"github.com/brutella/hc/netio/controller"
+	"github.com/brutella/hc/server"
+)
+
+func (t *ipTransport) Apply(values map[controller.CharacteristicID]interface{}) error {
+	// Values are applied exclusively from reads and writes of clients
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return server.NewCharacteristicController(t.serverConfig()).Apply(values)
+}
@@ -0,0 +1,34 @@
+package hap
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/netio/controller"
+)
+
+func TestApply(t *testing.T) {
+	transport := newTestTransport(t)
+	transport.container = accessory.NewContainer()
+	transport.mutex = &sync.Mutex{}
+
+	a := accessory.NewSwitch(accessory.Info{Name: "Switch"})
+	transport.addAccessory(a.Accessory)
+
+	var remote bool
+	a.Switch.On.OnValueRemoteUpdate(func(on bool) {
+		remote = on
+	})
+
+	err := transport.Apply(map[controller.CharacteristicID]interface{}{
+		controller.CharacteristicID{AID: a.ID, IID: a.Switch.On.ID}: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := remote, true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
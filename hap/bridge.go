@@ -0,0 +1,28 @@
+package hap
+
+import (
+	"fmt"
+
+	"github.com/brutella/log"
+)
+
+func (t *ipTransport) SetChildOnline(aid int64, online bool) error {
+	a := t.container.AccessoryByAID(aid)
+	if a == nil {
+		return fmt.Errorf("Unknown accessory %d", aid)
+	}
+
+	// The first accessory acts as the bridge
+	if len(t.container.Accessories) > 1 && a == t.container.Accessories[0] {
+		return fmt.Errorf("Accessory %d is the bridge", aid)
+	}
+
+	if a.IsReachable() != online {
+		log.Printf("[INFO] Accessory %d is online: %v\n", aid, online)
+	}
+
+	a.FailReadsWhenUnreachable = true
+	a.SetReachable(online)
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package hap
+
+import (
+	"testing"
+
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/characteristic"
+)
+
+func TestSetChildOnline(t *testing.T) {
+	transport := newTestTransport(t)
+	transport.container = accessory.NewContainer()
+
+	bridge := accessory.New(accessory.Info{Name: "Bridge"}, accessory.TypeBridge)
+	child := accessory.NewSwitch(accessory.Info{Name: "Switch"})
+	fault := characteristic.NewStatusFault()
+	child.Switch.AddCharacteristic(fault.Characteristic)
+
+	transport.container.AddAccessory(bridge)
+	transport.container.AddAccessory(child.Accessory)
+
+	var events int
+	fault.OnValueUpdate(func(c *characteristic.Characteristic, new, old interface{}) {
+		events++
+	})
+
+	if err := transport.SetChildOnline(child.ID, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := fault.GetValue(), characteristic.StatusFaultGeneralFault; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := events, 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if child.FailReadsWhenUnreachable == false {
+		t.Fatal("expected reads to fail")
+	}
+
+	if err := transport.SetChildOnline(child.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if is, want := fault.GetValue(), characteristic.StatusFaultNoFault; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if err := transport.SetChildOnline(bridge.ID, false); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := transport.SetChildOnline(99, false); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,100 @@
+package hap
+
+import (
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/coap"
+	"github.com/brutella/hc/netio"
+	"github.com/brutella/hc/server"
+	"github.com/brutella/log"
+
+	"net"
+	"strings"
+)
+
+type coapTransport struct {
+	*ipTransport
+}
+
+// NewCoAPTransport creates an experimental transport to provide accessories via HAP over CoAP,
+// e.g. in a Thread network behind an OpenThread border router. The transport is published
+// via mDNS as "_hap._udp" service.
+//
+// The storage, pairings and pin are handled like for the IP transport (see NewIPTransport).
+// The config port is the UDP port of the CoAP server.
+func NewCoAPTransport(config Config, a *accessory.Accessory, as ...*accessory.Accessory) (Transport, error) {
+	t, err := NewIPTransport(config, a, as...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &coapTransport{ipTransport: t.(*ipTransport)}, nil
+}
+
+func (t *coapTransport) Start() {
+	t.started = t.currentConfig().Clock.Now()
+
+	c := activatedPacketConn()
+	if c == nil {
+		var err error
+		if c, err = t.listenUDP(); err != nil {
+			log.Println("[ERRO] Could not start CoAP server", err)
+			return
+		}
+	}
+
+	for {
+		s := coap.NewServerWithConn(c, server.NewRouter(t.serverConfig()), t.context)
+		if t.setServer(s.Stop) == false {
+			// The transport was stopped
+			s.Stop()
+			return
+		}
+
+		t.publish(s.Port(), MDNSServiceTypeUDP)
+		t.notifyReady()
+		t.setReady(s.Port())
+		t.restarted(nil)
+
+		// Listen until Stop() is called
+		s.ListenAndServe()
+
+		r, ok := t.restartRequest()
+		if ok == false {
+			return
+		}
+
+		var err error
+		if c, err = t.listenUDP(); err != nil {
+			log.Println("[ERRO] Could not listen", err)
+			r.err = err
+			t.setConfig(r.previous)
+			if c, err = t.listenUDP(); err != nil {
+				log.Println("[ERRO] Could not start CoAP server", err)
+				t.restarted(err)
+				return
+			}
+		}
+	}
+}
+
+// listenUDP returns a udp connection for the port, listen address and interface of the config.
+func (t *coapTransport) listenUDP() (*net.UDPConn, error) {
+	config := t.currentConfig()
+	addr := net.JoinHostPort(config.ListenAddress, strings.TrimPrefix(config.Port, ":"))
+	pc, err := netio.ListenPacket("udp", addr, config.Interface)
+	if err != nil {
+		return nil, err
+	}
+
+	return pc.(*net.UDPConn), nil
+}
+
+// Stop stops the transport by unpublishing the mDNS service and canceling scheduled jobs.
+func (t *coapTransport) Stop() {
+	notifyStopping()
+	t.scheduler.Stop()
+	t.emitter.Stop()
+	t.unmapPort()
+	t.stopServing()
+	t.unlockStorage()
+}
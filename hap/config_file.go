@@ -0,0 +1,228 @@
+package hap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Log levels of the transport
+const (
+	LogLevelInfo    = "info"
+	LogLevelVerbose = "verbose"
+)
+
+// envPrefix is the prefix of the environment variables of ConfigFromEnv
+const envPrefix = "HC_"
+
+// configKeys are the keys of the config values, which can be loaded from
+// environment variables (e.g. HC_STORAGE_PATH) or config files (e.g. "storage_path").
+var configKeys = []string{
+	"storage_path",
+	"provisioning_path",
+	"port",
+	"ip",
+	"listen_address",
+	"interface",
+	"pin",
+	"setup_id",
+	"log_level",
+}
+
+// ConfigFromEnv returns a config whose values are set from the environment variables
+// HC_STORAGE_PATH, HC_PROVISIONING_PATH, HC_PORT, HC_IP, HC_LISTEN_ADDRESS, HC_INTERFACE, HC_PIN, HC_SETUP_ID and HC_LOG_LEVEL.
+// Unset variables keep the default values.
+func ConfigFromEnv() (Config, error) {
+	var c Config
+	for _, key := range configKeys {
+		if value, ok := os.LookupEnv(envPrefix + strings.ToUpper(key)); ok == true {
+			if err := c.set(key, value); err != nil {
+				return c, err
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// ConfigFromFile returns a config whose values are loaded from a JSON (.json) or TOML (.toml) file.
+// The keys of the file are storage_path, provisioning_path, port, ip, listen_address, interface, pin, setup_id and log_level.
+//
+//	{"storage_path": "/var/lib/bridge", "port": "12345", "pin": "00102003"}
+//
+// Only top-level keys with string, integer and boolean values are supported in TOML files.
+func ConfigFromFile(path string) (Config, error) {
+	var c Config
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+
+	var values map[string]string
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		values, err = parseJSONConfig(b)
+	case ".toml":
+		values, err = parseTOMLConfig(bytes.NewReader(b))
+	default:
+		err = fmt.Errorf("Unsupported config file format %s", ext)
+	}
+
+	if err != nil {
+		return c, err
+	}
+
+	for key, value := range values {
+		if err := c.set(key, value); err != nil {
+			return c, err
+		}
+	}
+
+	return c, nil
+}
+
+// set sets the config value of key.
+func (c *Config) set(key, value string) error {
+	switch key {
+	case "storage_path":
+		c.StoragePath = value
+	case "provisioning_path":
+		c.ProvisioningPath = value
+	case "port":
+		if _, err := strconv.ParseUint(value, 10, 16); err != nil {
+			return fmt.Errorf("Invalid port %s", value)
+		}
+		c.Port = value
+	case "ip":
+		c.IP = value
+	case "listen_address":
+		c.ListenAddress = value
+	case "interface":
+		c.Interface = value
+	case "pin":
+		if _, err := NewPin(value); err != nil {
+			return err
+		}
+		c.Pin = value
+	case "setup_id":
+		if err := validateSetupID(value); err != nil {
+			return err
+		}
+		c.SetupID = value
+	case "log_level":
+		if value != LogLevelInfo && value != LogLevelVerbose {
+			return fmt.Errorf("Invalid log level %s", value)
+		}
+		c.LogLevel = value
+	default:
+		return fmt.Errorf("Unknown config key %s", key)
+	}
+
+	return nil
+}
+
+func parseJSONConfig(b []byte) (map[string]string, error) {
+	var raw map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+	for key, v := range raw {
+		switch v := v.(type) {
+		case string:
+			values[key] = v
+		case json.Number:
+			values[key] = v.String()
+		case bool:
+			values[key] = strconv.FormatBool(v)
+		default:
+			return nil, fmt.Errorf("Invalid value of config key %s", key)
+		}
+	}
+
+	return values, nil
+}
+
+// parseTOMLConfig parses the top-level key/value pairs of a TOML file.
+func parseTOMLConfig(r io.Reader) (map[string]string, error) {
+	values := map[string]string{}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			return nil, fmt.Errorf("Unsupported table in line %d", n)
+		}
+
+		i := strings.Index(line, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("Invalid line %d", n)
+		}
+
+		key := strings.TrimSpace(line[:i])
+		value, err := parseTOMLValue(strings.TrimSpace(line[i+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value in line %d: %v", n, err)
+		}
+
+		values[key] = value
+	}
+
+	return values, scanner.Err()
+}
+
+// parseTOMLValue parses a string, integer or boolean value with an optional comment.
+func parseTOMLValue(s string) (string, error) {
+	if strings.HasPrefix(s, `"`) {
+		// Find the closing quote, which is not escaped
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '"':
+				if rest := strings.TrimSpace(s[i+1:]); len(rest) > 0 && strings.HasPrefix(rest, "#") == false {
+					return "", fmt.Errorf("unexpected %s", rest)
+				}
+				return strconv.Unquote(s[:i+1])
+			}
+		}
+		return "", fmt.Errorf("unterminated string")
+	}
+
+	if strings.HasPrefix(s, "'") {
+		i := strings.Index(s[1:], "'")
+		if i < 0 {
+			return "", fmt.Errorf("unterminated string")
+		}
+		return s[1 : i+1], nil
+	}
+
+	if i := strings.Index(s, "#"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+
+	if s == "true" || s == "false" {
+		return s, nil
+	}
+
+	if _, err := strconv.ParseInt(strings.Replace(s, "_", "", -1), 10, 64); err != nil {
+		return "", fmt.Errorf("unsupported value %s", s)
+	}
+
+	return strings.Replace(s, "_", "", -1), nil
+}
@@ -0,0 +1,109 @@
+package hap
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	os.Setenv("HC_PIN", "00102003")
+	os.Setenv("HC_PORT", "12345")
+	os.Setenv("HC_LOG_LEVEL", "verbose")
+	defer os.Unsetenv("HC_PIN")
+	defer os.Unsetenv("HC_PORT")
+	defer os.Unsetenv("HC_LOG_LEVEL")
+
+	c, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := c.Pin, "00102003"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := c.Port, "12345"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := c.LogLevel, LogLevelVerbose; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestConfigFromEnvWithInvalidPort(t *testing.T) {
+	os.Setenv("HC_PORT", "http")
+	defer os.Unsetenv("HC_PORT")
+
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestConfigFromJSONFile(t *testing.T) {
+	path := writeConfigFile(t, "config.json", `{"storage_path": "/var/lib/bridge", "port": 12345, "interface": "eth0"}`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	c, err := ConfigFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := c.StoragePath, "/var/lib/bridge"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := c.Port, "12345"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := c.Interface, "eth0"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestConfigFromTOMLFile(t *testing.T) {
+	content := `# Bridge
+storage_path = "/var/lib/bridge" # data
+port = 12345
+pin = '00102003'
+`
+	path := writeConfigFile(t, "config.toml", content)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	c, err := ConfigFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := c.StoragePath, "/var/lib/bridge"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := c.Port, "12345"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := c.Pin, "00102003"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestConfigFromFileWithUnknownKey(t *testing.T) {
+	path := writeConfigFile(t, "config.toml", `name = "Bridge"`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	if _, err := ConfigFromFile(path); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,49 @@
+package hap
+
+import (
+	"net"
+	"strconv"
+)
+
+// Endpoint describes where and as which device a transport is reachable.
+type Endpoint struct {
+	// ListenAddress is the address on which the transport listens, e.g. "[::]:51826".
+	// The address is empty when the transport wasn't started.
+	ListenAddress string
+
+	// IP is the ip address which is published via mDNS
+	IP string
+
+	// Port is the port which is published via mDNS, e.g. the external port of a port mapping.
+	// The port is 0 when the transport wasn't started.
+	Port int
+
+	// DeviceID is the device id (id) of the accessory, e.g. "12:34:56:78:90:AB"
+	DeviceID string
+
+	// Configuration is the current configuration number (c#)
+	Configuration int64
+}
+
+// Endpoint returns the endpoint of the transport.
+func (t *ipTransport) Endpoint() Endpoint {
+	config := t.currentConfig()
+	e := Endpoint{
+		IP:            config.IP,
+		DeviceID:      t.device.Name(),
+		Configuration: t.configurationNumber(),
+	}
+
+	select {
+	case <-t.ready:
+		e.ListenAddress = net.JoinHostPort(config.ListenAddress, strconv.Itoa(t.listenPort()))
+	default:
+	}
+
+	if mdns := t.currentMDNS(); mdns != nil {
+		e.IP = mdns.IP()
+		e.Port = mdns.Port()
+	}
+
+	return e
+}
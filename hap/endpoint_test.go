@@ -0,0 +1,58 @@
+package hap
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestEndpoint(t *testing.T) {
+	transport := newTestTransport(t)
+	transport.config = Config{IP: "192.168.1.2"}
+	transport.configuration = 3
+
+	e := transport.Endpoint()
+	if is, want := e.ListenAddress, ""; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := e.DeviceID, transport.device.Name(); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := e.Configuration, int64(3); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	transport.mdns = NewMDNSService("Test", e.DeviceID, "192.168.1.2", 23456, 1)
+	transport.setReady(12345)
+
+	e = transport.Endpoint()
+	if is, want := e.ListenAddress, ":12345"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := e.IP, "192.168.1.2"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := e.Port, 23456; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestEndpointWhileStarting(t *testing.T) {
+	transport := newTestTransport(t)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		transport.setReady(12345)
+	}()
+	transport.Endpoint()
+	wg.Wait()
+
+	if is, want := transport.Endpoint().ListenAddress, ":12345"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -5,13 +5,20 @@ import (
 	"errors"
 	"io/ioutil"
 	"net"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/audit"
 	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/crypto"
 	"github.com/brutella/hc/db"
 	"github.com/brutella/hc/event"
+	"github.com/brutella/hc/nat"
 	"github.com/brutella/hc/netio"
+	"github.com/brutella/hc/netio/pair"
 	"github.com/brutella/hc/server"
 	"github.com/brutella/hc/util"
 	"github.com/brutella/log"
@@ -21,51 +28,258 @@ import (
 // Config provides basic configuration for an IP transport
 type Config struct {
 	// Path to the storage
-	// When empty, the tranport stores the data inside a folder named exactly like the accessory
+	// When empty, the transport stores the data inside a folder named by its device id in
+	// DefaultStorageRoot. The folder is found by the serial number of the accessory, or
+	// by its name when no serial number is set. A folder named exactly like the accessory,
+	// which was used by previous versions, is moved to DefaultStorageRoot.
 	StoragePath string
 
+	// ProvisioningPath is the path to a read-only directory with immutable provisioning
+	// data, e.g. the identity and setup verifier of the accessory.
+	// When set, the data in the storage path overrides the provisioning data and
+	// the storage path only has to contain the writable state (e.g. pairings).
+	ProvisioningPath string
+
 	// Port on which transport is reachable e.g. 12345
 	// When empty, the transport uses a random port
 	Port string
 
 	// IP on which clients can connect.
+	// When empty, the listen address or the first ip address of the interface is used.
 	IP string
 
+	// ListenAddress is the ip address on which the transport listens, e.g. "192.168.1.2".
+	// The transport only listens on the address family of the ip (IPv4 or IPv6).
+	// When empty, the transport listens on all addresses.
+	ListenAddress string
+
+	// Interface is the name of the network interface on which the transport accepts connections, e.g. "eth0".
+	// This is only supported on Linux. When empty, connections are accepted on all interfaces.
+	Interface string
+
 	// Pin with has to be entered on iOS client to pair with the accessory
 	// When empty, the pin 00102003 is used
 	Pin string
+
+	// SetupSalt and SetupVerifier are computed from the pin with NewSetupVerifier.
+	// When set, the pin is not used, so it doesn't have to be stored on the accessory.
+	// The salt and verifier are stored in the storage path and used when the
+	// config specifies neither a pin nor a verifier.
+	SetupSalt     []byte
+	SetupVerifier []byte
+
+	// TokenProvider provides the Apple-issued software token to pair with MFi software authentication.
+	// When nil, the accessory can only be paired without authentication.
+	TokenProvider pair.TokenProvider
+
+	// PairSetupProgress is called when pair setup progresses (M1 to M6), e.g. to blink
+	// a LED while pairing and to turn it on when pairing finished successfully.
+	// The function is called on the goroutine which handles the pair setup request.
+	PairSetupProgress pair.SetupProgressFunc
+
+	// PairingWindow is the duration in which pair setup is accepted after EnablePairing
+	// was called, e.g. after pressing a button on the accessory. Pair setup requests outside
+	// of the window are rejected with kTLVError_Unavailable.
+	// When empty, pair setup is always accepted.
+	PairingWindow time.Duration
+
+	// DisplaySetupCode is called with a new random setup code for every pair setup, which has to be
+	// shown on the display of the accessory. The pin and setup verifier are not used in this case.
+	// The function is called with an empty string when the code is invalidated, because pairing
+	// finished or failed, or the code expired after SetupCodeTimeout.
+	DisplaySetupCode func(code string)
+
+	// SetupCodeTimeout is the duration after which a setup code of DisplaySetupCode expires.
+	// When empty, the code expires after 5 minutes.
+	SetupCodeTimeout time.Duration
+
+	// SetupCodes are revocable setup codes with independent lifetimes, e.g. an installer code
+	// and an owner code, which are added to the codes in the storage (see Transport.AddSetupCode).
+	// While there are valid setup codes, the pin and setup verifier are not used to pair.
+	SetupCodes []SetupCode
+
+	// SetupID is the 4-character setup id of a setup payload (see SetupPayload).
+	// When empty, the accessory can't be paired by scanning a QR code or NFC tag.
+	SetupID string
+
+	// AuditSink stores the audit log of security-relevant operations, e.g. pairings and failed pair setup attempts.
+	// When nil, no audit log is recorded.
+	AuditSink audit.Sink
+
+	// AdvertisedPort is the port which is published via mDNS, e.g. the external port of a port mapping.
+	// When empty, the port of the server is published.
+	AdvertisedPort string
+
+	// PortMapper maps the server port on the gateway (e.g. nat.DiscoverNATPMP()), which makes
+	// the accessory reachable from other network segments. When set and no AdvertisedPort is
+	// specified, the external port is published and the mapping is renewed periodically.
+	PortMapper nat.PortMapper
+
+	// SystemMDNS is true when the service is registered with the Bonjour daemon of the system
+	// (mDNSResponder on macOS, Bonjour for Windows) instead of the mDNS responder of the transport.
+	// This avoids conflicts with the system daemon, which also uses the mDNS port.
+	SystemMDNS bool
+
+	// MDNS configures the record TTLs and announcements of the mDNS service.
+	MDNS MDNSConfig
+
+	// CharacteristicTimeout is the deadline of the read and write callbacks of characteristics.
+	// When a callback takes longer, e.g. because of a blocked I2C read, the client receives
+	// the status netio.StatusOperationTimedOut and the value of the late callback is dropped.
+	// When empty, there is no deadline.
+	CharacteristicTimeout time.Duration
+
+	// SlowCharacteristicThreshold is the duration after which a callback of a characteristic
+	// is logged as slow and reported to SlowCharacteristic. When empty, 1 second is used.
+	SlowCharacteristicThreshold time.Duration
+
+	// SlowCharacteristic is called with the duration of slow callbacks, e.g. to record a metric.
+	SlowCharacteristic func(aid, iid int64, d time.Duration)
+
+	// WriteRetry is the policy of writes to connections (e.g. events), which failed with a temporary
+	// error. When the retries fail too, the connection is closed. When nil, netio.DefaultRetryPolicy is used.
+	WriteRetry *netio.RetryPolicy
+
+	// WriteTimeout is the deadline of writes to connections, after which a controller which
+	// stopped reading (e.g. a suspended phone) is disconnected. When empty, 10 seconds is used.
+	WriteTimeout time.Duration
+
+	// SlowConsumerBytes is the number of unacknowledged bytes above which a connection is slow.
+	// When empty, 32 KiB is used.
+	SlowConsumerBytes int
+
+	// SlowConsumerTimeout is the duration after which a slow connection is closed,
+	// e.g. of a misbehaving hub. When empty, 30 seconds is used.
+	SlowConsumerTimeout time.Duration
+
+	// ConsumerEvicted is called when a slow connection was closed.
+	ConsumerEvicted func(ev event.ConsumerEvicted)
+
+	// EventOnSubscribe is true when an event with the current value of a characteristic
+	// is sent to a controller which enables events of the characteristic.
+	// Hubs then resynchronize their state after reconnecting without reading the values.
+	EventOnSubscribe bool
+
+	// AcceptFailed is called when accepting a connection failed with a temporary error,
+	// e.g. to record a metric when the process ran out of file descriptors.
+	// Accepting is retried with exponential backoff.
+	AcceptFailed func(ev event.AcceptFailed)
+
+	// LogLevel is the log level of the config file or environment, e.g. LogLevelVerbose.
+	// The transport doesn't change the global logger; the application enables
+	// verbose messages, e.g. by setting log.Verbose when the level is LogLevelVerbose.
+	LogLevel string
+
+	// Clock is used to run scheduled jobs (see Transport.Schedule), e.g. a fake clock in tests.
+	// When nil, the system clock is used.
+	Clock util.Clock
 }
 
+// defaultSlowCharacteristicThreshold is the duration after which callbacks of characteristics are logged as slow
+const defaultSlowCharacteristicThreshold = time.Second
+
+// defaultWriteTimeout is the deadline of writes to connections
+const defaultWriteTimeout = 10 * time.Second
+
+// eventQueueSize is the number of events which are queued until they are handled.
+// Pairing events are queued without a limit (see event.AsyncEmitter).
+const eventQueueSize = 16
+
 type ipTransport struct {
-	config  Config
+	// config is replaced on restart and guarded by configMutex (see currentConfig)
+	config      Config
+	configMutex sync.RWMutex
+
 	context netio.HAPContext
-	server  server.Server
 	mutex   *sync.Mutex
-	mdns    *MDNSService
 
 	storage  util.Storage
 	database db.Database
 
+	// storageIndex finds the storage by storageKey, when the config has no storage path
+	storageIndex *storageIndex
+	storageKey   string
+
+	// lock of the storage path, which is released when the transport stops
+	lock *util.DirLock
+
+	// Configuration number (c#) which is incremented when the accessory information changes
+	configuration      int64
+	configurationMutex sync.Mutex
+
+	// rangesMutex serializes updates of the stored value ranges
+	rangesMutex sync.Mutex
+
 	name      string
 	device    netio.SecuredDevice
 	container *accessory.Container
 
 	// Used to communicate between different parts of the program (e.g. successful pairing with HomeKit)
-	emitter event.Emitter
+	emitter *event.AsyncEmitter
+
+	scheduler *Scheduler
+
+	// watchdog is the job which sends keep-alive notifications to systemd
+	watchdog      *Job
+	watchdogMutex sync.Mutex
+
+	auditLog *audit.Log
+
+	// started is the time when the transport was started
+	started time.Time
+
+	// ready is closed when the transport accepts connections and is published via mDNS
+	ready     chan struct{}
+	readyOnce sync.Once
+
+	// port is the port on which the transport is reachable, after it was started.
+	// It is guarded by serverMutex (see listenPort).
+	port int
+
+	// stopServer stops the running server, which makes Start listen again
+	// when there is a pending restart request. The server, the mDNS service
+	// and the restart request are guarded by serverMutex.
+	stopServer  func()
+	mdns        *MDNSService
+	restart     *restart
+	stopped     bool
+	serverMutex sync.Mutex
+
+	// restartMutex serializes restarts
+	restartMutex sync.Mutex
+
+	// mapping is the port mapping on the gateway
+	mapping      *portMapping
+	mappingMutex sync.Mutex
+
+	// setupCodes provides a random setup code for every pair setup, if the accessory has a display
+	setupCodes *setupCodeDisplay
+
+	// codes are the revocable setup codes
+	codes *setupCodes
+
+	// consumers tracks connections which don't acknowledge the sent data
+	consumers *slowConsumers
+
+	// snapshots are the events which are sent after a controller subscribed to characteristics
+	snapshots *subscribeSnapshots
+
+	// pairingUntil is the end of the pairing window
+	pairingUntil time.Time
+	pairingMutex sync.Mutex
 }
 
 // NewIPTransport creates a transport to provide accessories over IP.
 //
 // The IP transports stores the crypto keys inside a database, which
-// is by default inside a folder at the current working directory.
-// The folder is named exactly as the accessory name.
+// is by default inside a folder in DefaultStorageRoot (see Config.StoragePath).
 //
 // The transports can contain more than one accessory. If this is the
-// case, the first accessory acts as the HomeKit bridge.
+// case, the first accessory acts as the HomeKit bridge. When the name of the
+// first accessory changes, the mDNS service is published with the new name.
 //
-// *Important:* Changing the name of the accessory, or letting multiple
-// transports store the data inside the same database lead to
-// unexpected behavior – don't do that.
+// *Important:* Letting multiple transports store the data inside the
+// same database leads to unexpected behavior – don't do that.
 //
 // The transport is secured with an 8-digit pin, which must be entered
 // by an iOS client to successfully pair with the accessory. If the
@@ -77,20 +291,29 @@ func NewIPTransport(config Config, a *accessory.Accessory, as ...*accessory.Acce
 		log.Fatal("Invalid empty name for first accessory")
 	}
 
-	ip, err := getFirstLocalIPAddr()
+	ip, err := localIPAddr(config)
 	if err != nil {
 		return nil, err
 	}
 
 	default_config := Config{
-		StoragePath: name,
-		Pin:         "00102003",
-		Port:        "",
-		IP:          ip.String(),
+		Pin:  "00102003",
+		Port: "",
+		IP:   ip.String(),
 	}
 
+	var index *storageIndex
+	key := storageKey(name, a.Info.SerialNumber.GetValue())
 	if dir := config.StoragePath; len(dir) > 0 {
 		default_config.StoragePath = dir
+	} else {
+		if index, err = newStorageIndex(DefaultStorageRoot); err != nil {
+			return nil, err
+		}
+
+		if default_config.StoragePath, err = index.path(key, name, "."); err != nil {
+			return nil, err
+		}
 	}
 
 	if pin := config.Pin; len(pin) > 0 {
@@ -105,89 +328,352 @@ func NewIPTransport(config Config, a *accessory.Accessory, as ...*accessory.Acce
 		default_config.IP = ip
 	}
 
+	default_config.TokenProvider = config.TokenProvider
+	default_config.PairSetupProgress = config.PairSetupProgress
+	default_config.PairingWindow = config.PairingWindow
+	default_config.DisplaySetupCode = config.DisplaySetupCode
+	default_config.SetupCodeTimeout = config.SetupCodeTimeout
+	default_config.AuditSink = config.AuditSink
+	default_config.CharacteristicTimeout = config.CharacteristicTimeout
+	default_config.SlowCharacteristicThreshold = config.SlowCharacteristicThreshold
+	if default_config.SlowCharacteristicThreshold == 0 {
+		default_config.SlowCharacteristicThreshold = defaultSlowCharacteristicThreshold
+	}
+	default_config.SlowCharacteristic = config.SlowCharacteristic
+	default_config.ListenAddress = config.ListenAddress
+	default_config.Interface = config.Interface
+	default_config.AdvertisedPort = config.AdvertisedPort
+	default_config.PortMapper = config.PortMapper
+	default_config.SystemMDNS = config.SystemMDNS
+	default_config.MDNS = config.MDNS
+	default_config.WriteRetry = config.WriteRetry
+	default_config.WriteTimeout = config.WriteTimeout
+	default_config.SlowConsumerBytes = config.SlowConsumerBytes
+	default_config.SlowConsumerTimeout = config.SlowConsumerTimeout
+	default_config.ConsumerEvicted = config.ConsumerEvicted
+	default_config.AcceptFailed = config.AcceptFailed
+	default_config.EventOnSubscribe = config.EventOnSubscribe
+	if default_config.WriteTimeout == 0 {
+		default_config.WriteTimeout = defaultWriteTimeout
+	}
+	default_config.Clock = config.Clock
+	if default_config.Clock == nil {
+		default_config.Clock = util.SystemClock
+	}
+
+	if id := config.SetupID; len(id) > 0 {
+		if err := validateSetupID(id); err != nil {
+			return nil, err
+		}
+		default_config.SetupID = id
+	}
+
 	storage, err := util.NewFileStorage(default_config.StoragePath)
 	if err != nil {
 		return nil, err
 	}
 
+	if dir := config.ProvisioningPath; len(dir) > 0 {
+		provisioning, err := util.NewReadOnlyFileStorage(dir)
+		if err != nil {
+			return nil, err
+		}
+		default_config.ProvisioningPath = dir
+		storage = util.NewLayeredStorage(provisioning, storage)
+	}
+
+	// Another process using the same storage would corrupt the pairings
+	lock, err := util.LockDir(default_config.StoragePath)
+	if err != nil {
+		return nil, err
+	}
+
 	// Find transport uuid which appears as "id" txt record in mDNS and
 	// must be unique and stay the same over time
 	uuid := transportUUIDInStorage(storage)
-	database := db.NewDatabaseWithStorage(storage)
 
-	hap_pin, err := NewPin(default_config.Pin)
-	if err != nil {
+	// Upgrade the stored data of previous versions
+	if err := db.Migrate(storage); err != nil {
+		lock.Unlock()
 		return nil, err
 	}
+	database := db.NewDatabaseWithStorage(storage)
 
-	device, err := netio.NewSecuredDevice(uuid, hap_pin, database)
+	salt, verifier := config.SetupSalt, config.SetupVerifier
+	if len(salt) > 0 && len(verifier) > 0 {
+		// Store the provisioned verifier to not require it in the config anymore
+		if err := saveSetupVerifierInStorage(storage, salt, verifier); err != nil {
+			lock.Unlock()
+			return nil, err
+		}
+	} else if len(config.Pin) == 0 {
+		salt, verifier = setupVerifierInStorage(storage)
+	}
+
+	var device netio.SecuredDevice
+	if len(salt) > 0 && len(verifier) > 0 {
+		// The pin is not known and must not be used
+		default_config.Pin = ""
+		default_config.SetupSalt = salt
+		default_config.SetupVerifier = verifier
+		device, err = netio.NewSetupVerifierDevice(uuid, salt, verifier, database)
+	} else {
+		var hap_pin string
+		if hap_pin, err = NewPin(default_config.Pin); err != nil {
+			lock.Unlock()
+			return nil, err
+		}
+
+		device, err = netio.NewSecuredDevice(uuid, hap_pin, database)
+	}
 
 	t := &ipTransport{
-		database:  database,
-		name:      name,
-		device:    device,
-		config:    default_config,
-		container: accessory.NewContainer(),
-		mutex:     &sync.Mutex{},
-		context:   netio.NewContextForSecuredDevice(device),
-		emitter:   event.NewEmitter(),
+		storage:       storage,
+		lock:          lock,
+		configuration: configurationInStorage(storage),
+		database:      database,
+		name:          name,
+		storageIndex:  index,
+		storageKey:    key,
+		device:        device,
+		config:        default_config,
+		container:     accessory.NewContainer(),
+		mutex:         &sync.Mutex{},
+		context:       netio.NewContextForSecuredDevice(device),
+		emitter:       event.NewAsyncEmitter(eventQueueSize),
+		scheduler:     NewSchedulerWithClock(default_config.Clock),
+		ready:         make(chan struct{}),
+	}
+
+	if config.AuditSink != nil {
+		t.auditLog = audit.NewLog(config.AuditSink)
 	}
 
-	t.addAccessory(a)
-	for _, a := range as {
-		t.addAccessory(a)
+	if config.DisplaySetupCode != nil {
+		t.setupCodes = newSetupCodeDisplay(config.DisplaySetupCode, config.SetupCodeTimeout, default_config.Clock)
 	}
 
+	t.codes = newSetupCodes(storage, default_config.Clock)
+	for _, c := range config.SetupCodes {
+		if err := t.codes.Add(c); err != nil {
+			lock.Unlock()
+			return nil, err
+		}
+	}
+
+	// Invalid accessories are rejected, because clients refuse to add them
+	for _, a := range append([]*accessory.Accessory{a}, as...) {
+		if err := t.addAccessory(a); err != nil {
+			lock.Unlock()
+			return nil, err
+		}
+	}
+
+	// Ranges which changed since the last start make clients reload the accessories
+	t.updateValueRanges(t.container.Accessories...)
+
+	// Names which were changed at runtime replace the names of the accessories
+	t.restoreNames()
+	t.name = a.Info.Name.GetValue()
+
+	// Metadata of the application is stored in the database
+	for _, a := range t.container.Accessories {
+		t.restoreMetadata(a)
+	}
+
+	a.Info.Name.OnValueUpdate(func(c *characteristic.Characteristic, new, old interface{}) {
+		if name, ok := new.(string); ok == true && len(name) > 0 {
+			t.rename(name)
+		}
+	})
+
+	t.consumers = newSlowConsumers(default_config.SlowConsumerBytes, default_config.SlowConsumerTimeout)
+	t.snapshots = newSubscribeSnapshots(t.container)
+	t.scheduler.Schedule(Every(t.consumers.interval()), t.evictSlowConsumers)
+
 	t.emitter.AddListener(t)
 
 	return t, err
 }
 
 func (t *ipTransport) Start() {
+	t.started = t.currentConfig().Clock.Now()
+	log.Println("[INFO] Crypto", crypto.Selected)
+
+	ln := activatedListener()
+	for {
+		// Create server which handles incoming tcp connections
+		config := t.serverConfig()
+		config.Listener = ln
+		s := server.NewServer(config)
+		if t.setServer(s.Stop) == false {
+			// The transport was stopped
+			s.Stop()
+			return
+		}
 
-	// Create server which handles incoming tcp connections
-	config := server.Config{
-		Port:      t.config.Port,
+		// Publish server port which might be different then `t.config.Port`
+		t.publish(int(to.Int64(s.Port())), MDNSServiceTypeTCP)
+		t.notifyReady()
+		t.setReady(int(to.Int64(s.Port())))
+		t.restarted(nil)
+
+		// Listen until server.Stop() is called
+		s.ListenAndServe()
+
+		r, ok := t.restartRequest()
+		if ok == false {
+			return
+		}
+
+		var err error
+		if ln, err = t.listenTCP(); err != nil {
+			log.Println("[ERRO] Could not listen", err)
+			r.err = err
+			t.setConfig(r.previous)
+			if ln, err = t.listenTCP(); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+}
+
+// listenTCP returns a tcp listener for the port, listen address and interface of the config.
+func (t *ipTransport) listenTCP() (*net.TCPListener, error) {
+	config := t.currentConfig()
+	addr := net.JoinHostPort(config.ListenAddress, strings.TrimPrefix(config.Port, ":"))
+	ln, err := netio.Listen("tcp", addr, config.Interface)
+	if err != nil {
+		return nil, err
+	}
+
+	return ln.(*net.TCPListener), nil
+}
+
+// serverConfig returns the configuration of the endpoints.
+func (t *ipTransport) serverConfig() server.Config {
+	config := t.currentConfig()
+	c := server.Config{
+		Port:      config.Port,
 		Context:   t.context,
 		Database:  t.database,
 		Container: t.container,
 		Device:    t.device,
 		Mutex:     t.mutex,
 		Emitter:   t.emitter,
+
+		TokenProvider:     config.TokenProvider,
+		PairSetupProgress: t.pairSetupProgress,
+		PairingAvailable:  t.pairingAvailable,
+		AuditLog:          t.auditLog,
+		ListenAddress:     config.ListenAddress,
+		Interface:         config.Interface,
+
+		CharacteristicTimeout:       config.CharacteristicTimeout,
+		SlowCharacteristicThreshold: config.SlowCharacteristicThreshold,
+		SlowCharacteristic:          config.SlowCharacteristic,
+		WriteRetry:                  config.WriteRetry,
+		WriteTimeout:                config.WriteTimeout,
 	}
 
-	s := server.NewServer(config)
-	t.server = s
+	if config.EventOnSubscribe == true {
+		c.Subscribed = t.snapshots.subscribed
+		c.ConnState = t.snapshots.connState
+	}
+
+	if t.setupCodes != nil {
+		c.SetupCodeProvider = t.setupCodes
+	} else if t.codes != nil {
+		c.SetupCodeProvider = t.codes
+	}
+
+	return c
+}
+
+// publish announces the transport on port via mDNS.
+func (t *ipTransport) publish(port int, serviceType string) {
+	config := t.currentConfig()
 
 	// Publish accessory ip
-	ip := t.config.IP
+	ip := config.IP
 	log.Println("[INFO] Accessory IP is", ip)
 
-	// Publish server port which might be different then `t.config.Port`
-	portInt64 := to.Int64(s.Port())
-
-	mdns := NewMDNSService(t.name, t.device.Name(), ip, int(portInt64), int64(t.container.AccessoryType()))
-	t.mdns = mdns
+	protocol := nat.ProtocolTCP
+	if serviceType == MDNSServiceTypeUDP {
+		protocol = nat.ProtocolUDP
+	}
+	port = t.advertisedPort(protocol, port)
+
+	mdns := NewMDNSService(t.name, t.device.Name(), ip, port, int64(t.container.AccessoryType()))
+	mdns.SetServiceType(serviceType)
+	mdns.SetConfiguration(t.configurationNumber())
+	mdns.SetSystemResponder(config.SystemMDNS)
+	mdns.SetConfig(config.MDNS)
+	mdns.SetClock(config.Clock)
+	if config.TokenProvider != nil {
+		mdns.SetFeatures(MDNSFeatureSoftwareAuthentication)
+	}
+	if id := config.SetupID; len(id) > 0 {
+		mdns.SetSetupHash(SetupHash(id, t.device.Name()))
+	}
 
 	// Paired accessories must not be reachable for other clients since iOS 9
 	if t.isPaired() {
 		mdns.SetReachable(false)
 	}
 
-	mdns.Publish()
+	t.serverMutex.Lock()
+	defer t.serverMutex.Unlock()
+
+	if t.stopped == true {
+		return
+	}
+
+	// Replace the service of a previous listener
+	if previous := t.mdns; previous != nil && previous.IsPublished() == true {
+		previous.Stop()
+	}
+	t.mdns = mdns
 
-	// Listen until server.Stop() is called
-	s.ListenAndServe()
+	mdns.Publish()
 }
 
 // Stop stops the ip transport by unpublishing the mDNS service.
 func (t *ipTransport) Stop() {
-	if t.mdns != nil {
-		t.mdns.Stop()
+	notifyStopping()
+	t.scheduler.Stop()
+	t.emitter.Stop()
+	t.unmapPort()
+	t.stopServing()
+	t.unlockStorage()
+}
+
+// unlockStorage releases the lock of the storage path.
+func (t *ipTransport) unlockStorage() {
+	if t.lock != nil {
+		if err := t.lock.Unlock(); err != nil {
+			log.Println("[ERRO] Could not unlock storage", err)
+		}
+		t.lock = nil
 	}
+}
+
+func (t *ipTransport) Schedule(s Schedule, fn func()) *Job {
+	return t.scheduler.Schedule(s, fn)
+}
 
-	if t.server != nil {
-		t.server.Stop()
+func (t *ipTransport) Announce() {
+	if mdns := t.currentMDNS(); mdns != nil {
+		mdns.Announce()
+	}
+}
+
+func (t *ipTransport) Reverify() {
+	for _, conn := range t.context.ActiveConnections() {
+		if s := t.context.GetSessionForConnection(conn); s != nil && s.Encrypter() != nil {
+			log.Printf("[INFO] Close session of %s to verify again\n", conn.RemoteAddr())
+			conn.Close()
+		}
 	}
 }
 
@@ -205,14 +691,17 @@ func (t *ipTransport) isPaired() bool {
 }
 
 func (t *ipTransport) updateMDNSReachability() {
-	if mdns := t.mdns; mdns != nil {
+	if mdns := t.currentMDNS(); mdns != nil {
 		mdns.SetReachable(t.isPaired() == false)
 		mdns.Update()
 	}
 }
 
-func (t *ipTransport) addAccessory(a *accessory.Accessory) {
-	t.container.AddAccessory(a)
+// addAccessory adds a to the transport and returns an error when a is invalid.
+func (t *ipTransport) addAccessory(a *accessory.Accessory) error {
+	if err := t.container.AddAccessory(a); err != nil {
+		return err
+	}
 
 	for _, s := range a.Services {
 		for _, c := range s.Characteristics {
@@ -232,8 +721,66 @@ func (t *ipTransport) addAccessory(a *accessory.Accessory) {
 				}
 			}
 			c.OnValueUpdate(onChange)
+
+			// Clients reload the accessories with the new min, max and step values
+			// when the configuration number changes
+			c.OnValueRangeUpdate(func(c *characteristic.Characteristic) {
+				t.updateValueRanges(a)
+			})
 		}
 	}
+
+	// The accessory information characteristics don't support events. When they change
+	// (e.g. after a firmware update) the configuration number is incremented, which
+	// makes clients reload the accessories.
+	info := []*characteristic.Characteristic{
+		a.Info.Model.Characteristic,
+		a.Info.SerialNumber.Characteristic,
+		a.Info.FirmwareRevision.Characteristic,
+	}
+	for _, c := range info {
+		c.OnValueUpdate(func(c *characteristic.Characteristic, new, old interface{}) {
+			// UpdateInfo changes several values, but increments the configuration number once
+			if a.IsUpdatingInfo() == false {
+				t.updateConfiguration()
+			}
+		})
+	}
+	a.OnInfoUpdate(t.updateConfiguration)
+
+	return nil
+}
+
+// maxConfiguration is the largest configuration number (c#), after which it wraps to 1
+const maxConfiguration = 65535
+
+// updateConfiguration increments and stores the configuration number and updates the mDNS txt records.
+// The configuration number wraps to 1 after maxConfiguration.
+func (t *ipTransport) updateConfiguration() {
+	t.configurationMutex.Lock()
+	defer t.configurationMutex.Unlock()
+
+	if t.configuration >= maxConfiguration {
+		t.configuration = 1
+	} else {
+		t.configuration++
+	}
+	if err := t.storage.Set("configuration", []byte(strconv.FormatInt(t.configuration, 10))); err != nil {
+		log.Println("[ERRO]", err)
+	}
+
+	if mdns := t.currentMDNS(); mdns != nil {
+		mdns.SetConfiguration(t.configuration)
+		mdns.Update()
+	}
+}
+
+// configurationNumber returns the current configuration number (c#).
+func (t *ipTransport) configurationNumber() int64 {
+	t.configurationMutex.Lock()
+	defer t.configurationMutex.Unlock()
+
+	return t.configuration
 }
 
 func (t *ipTransport) notifyListener(a *accessory.Accessory, c *characteristic.Characteristic, except net.Conn) {
@@ -242,19 +789,39 @@ func (t *ipTransport) notifyListener(a *accessory.Accessory, c *characteristic.C
 		if conn == except {
 			continue
 		}
-		resp, err := netio.New(a, c)
+
+		sendEvent(conn, a, c)
+	}
+}
+
+// sendEvent sends an event with the value of c to conn.
+func sendEvent(conn net.Conn, a *accessory.Accessory, c *characteristic.Characteristic) {
+	// Connections of other transports send events without HTTP framing
+	if ec, ok := conn.(netio.EventConn); ok == true {
+		body, err := netio.Body(a, c)
 		if err != nil {
 			log.Fatal(err)
 		}
+		if err := ec.WriteEvent(body.Bytes()); err != nil {
+			log.Printf("[WARN] Could not send event to %s: %v\n", conn.RemoteAddr(), err)
+		}
+		return
+	}
+
+	resp, err := netio.New(a, c)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-		// Write response into buffer to replace HTTP protocol
-		// specifier with EVENT as required by HAP
-		var buffer = new(bytes.Buffer)
-		resp.Write(buffer)
-		bytes, err := ioutil.ReadAll(buffer)
-		bytes = netio.FixProtocolSpecifier(bytes)
-		log.Printf("[VERB] %s <- %s", conn.RemoteAddr(), string(bytes))
-		conn.Write(bytes)
+	// Write response into buffer to replace HTTP protocol
+	// specifier with EVENT as required by HAP
+	var buffer = new(bytes.Buffer)
+	resp.Write(buffer)
+	bytes, err := ioutil.ReadAll(buffer)
+	bytes = netio.FixProtocolSpecifier(bytes)
+	log.Printf("[VERB] %s <- %s", conn.RemoteAddr(), string(bytes))
+	if _, err := conn.Write(bytes); err != nil {
+		log.Printf("[WARN] Could not send event to %s: %v\n", conn.RemoteAddr(), err)
 	}
 }
 
@@ -273,6 +840,43 @@ func transportUUIDInStorage(storage util.Storage) string {
 	return string(uuid)
 }
 
+// setupVerifierInStorage returns the SRP salt and verifier stored in storage.
+// If no verifier is stored, nil is returned.
+func setupVerifierInStorage(storage util.Storage) ([]byte, []byte) {
+	salt, err := storage.Get("setup-salt")
+	if err != nil || len(salt) == 0 {
+		return nil, nil
+	}
+
+	verifier, err := storage.Get("setup-verifier")
+	if err != nil || len(verifier) == 0 {
+		return nil, nil
+	}
+
+	return salt, verifier
+}
+
+// saveSetupVerifierInStorage stores the SRP salt and verifier in storage.
+func saveSetupVerifierInStorage(storage util.Storage, salt, verifier []byte) error {
+	if err := storage.Set("setup-salt", salt); err != nil {
+		return err
+	}
+
+	return storage.Set("setup-verifier", verifier)
+}
+
+// configurationInStorage returns the configuration number stored in storage.
+// If no valid configuration number is stored, 1 is returned.
+func configurationInStorage(storage util.Storage) int64 {
+	if b, err := storage.Get("configuration"); err == nil && len(b) > 0 {
+		if c := to.Int64(string(b)); c >= 1 && c <= maxConfiguration {
+			return c
+		}
+	}
+
+	return 1
+}
+
 // Handles event which are sent when pairing with a device is added or removed
 func (t *ipTransport) Handle(ev interface{}) {
 	switch ev.(type) {
@@ -282,11 +886,44 @@ func (t *ipTransport) Handle(ev interface{}) {
 	case event.DeviceUnpaired:
 		log.Printf("[INFO] Event: unpaired with device")
 		t.updateMDNSReachability()
+	case event.ConsumerEvicted:
+		if f := t.currentConfig().ConsumerEvicted; f != nil {
+			f(ev.(event.ConsumerEvicted))
+		}
+	case event.AcceptFailed:
+		if f := t.currentConfig().AcceptFailed; f != nil {
+			f(ev.(event.AcceptFailed))
+		}
 	default:
 		break
 	}
 }
 
+// localIPAddr returns the ip address which is advertised via mDNS for config.
+// The address is the listen address or the first IPv4 address of the configured
+// interface. Otherwise the first local IPv4 address is used.
+func localIPAddr(config Config) (net.IP, error) {
+	if ip := net.ParseIP(config.ListenAddress); ip != nil && ip.IsUnspecified() == false {
+		return ip, nil
+	}
+
+	if len(config.Interface) > 0 {
+		iface, err := net.InterfaceByName(config.Interface)
+		if err != nil {
+			return nil, err
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, err
+		}
+
+		return firstIPv4Addr(addrs)
+	}
+
+	return getFirstLocalIPAddr()
+}
+
 // GetFirstLocalIPAddress returns the first available IP address of the local machine
 // This is a fix for Beaglebone Black where net.LookupIP(hostname) return no IP address.
 func getFirstLocalIPAddr() (net.IP, error) {
@@ -295,6 +932,11 @@ func getFirstLocalIPAddr() (net.IP, error) {
 		return nil, err
 	}
 
+	return firstIPv4Addr(addrs)
+}
+
+// firstIPv4Addr returns the first IPv4 address in addrs which is not a loopback address.
+func firstIPv4Addr(addrs []net.Addr) (net.IP, error) {
 	for _, addr := range addrs {
 		var ip net.IP
 		switch v := addr.(type) {
@@ -0,0 +1,131 @@
+package hap
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/netio"
+	"github.com/brutella/hc/util"
+)
+
+func TestSetupVerifierInStorage(t *testing.T) {
+	storage, err := util.NewTempFileStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if salt, verifier := setupVerifierInStorage(storage); salt != nil || verifier != nil {
+		t.Fatal("expected no verifier")
+	}
+
+	salt, verifier, err := NewSetupVerifier("00102003")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := saveSetupVerifierInStorage(storage, salt, verifier); err != nil {
+		t.Fatal(err)
+	}
+
+	s, v := setupVerifierInStorage(storage)
+	if bytes.Equal(s, salt) == false {
+		t.Fatalf("is=%X want=%X", s, salt)
+	}
+
+	if bytes.Equal(v, verifier) == false {
+		t.Fatalf("is=%X want=%X", v, verifier)
+	}
+}
+
+func TestLocalIPAddrOfListenAddress(t *testing.T) {
+	ip, err := localIPAddr(Config{ListenAddress: "192.168.1.2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := ip.String(), "192.168.1.2"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestLocalIPAddrOfUnknownInterface(t *testing.T) {
+	if _, err := localIPAddr(Config{Interface: "unknown0"}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func benchmarkNotifyListener(b *testing.B, n int) {
+	transport := newTestTransport(b)
+
+	var conns []net.Conn
+	for i := 0; i < n; i++ {
+		server, client := net.Pipe()
+		go io.Copy(ioutil.Discard, client)
+		conns = append(conns, client)
+		netio.NewHAPConnection(server, transport.context)
+	}
+	defer func() {
+		for _, c := range conns {
+			c.Close()
+		}
+	}()
+
+	l := accessory.NewLightbulb(accessory.Info{Name: "Lightbulb"})
+	c := l.Lightbulb.On.Characteristic
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		transport.notifyListener(l.Accessory, c, nil)
+	}
+}
+
+// Benchmarks sending a characteristic event to 1, 10 and 50 connections.
+func BenchmarkNotifyListener(b *testing.B) {
+	for _, n := range []int{1, 10, 50} {
+		b.Run(fmt.Sprintf("conns=%d", n), func(b *testing.B) {
+			benchmarkNotifyListener(b, n)
+		})
+	}
+}
+
+func TestUpdateInfoIncrementsConfigurationOnce(t *testing.T) {
+	transport := newTestTransport(t)
+	transport.container = accessory.NewContainer()
+	a := accessory.New(accessory.Info{Name: "Lamp", Model: "A", Firmware: "1.0.0"}, accessory.TypeLightbulb)
+	if err := transport.addAccessory(a); err != nil {
+		t.Fatal(err)
+	}
+
+	a.UpdateInfo(accessory.Info{Model: "B", SerialNumber: "002", Firmware: "1.0.1"})
+	if is, want := transport.configurationNumber(), int64(1); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	a.Info.FirmwareRevision.SetValue("1.0.2")
+	if is, want := transport.configurationNumber(), int64(2); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestConfigurationWraps(t *testing.T) {
+	transport := newTestTransport(t)
+	if err := transport.storage.Set("configuration", []byte("65535")); err != nil {
+		t.Fatal(err)
+	}
+	transport.configuration = configurationInStorage(transport.storage)
+
+	transport.updateConfiguration()
+	if is, want := transport.configurationNumber(), int64(1); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := configurationInStorage(transport.storage), int64(1); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
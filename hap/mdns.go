@@ -1,6 +1,8 @@
 package hap
 
 import (
+	"github.com/brutella/hc/dnssd"
+	"github.com/brutella/hc/util"
 	"github.com/brutella/log"
 	"github.com/gosexy/to"
 	"github.com/oleksandr/bonjour"
@@ -8,27 +10,99 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
+	"time"
 )
 
+// Pairing feature flags (ff) of the mDNS service
+const (
+	MDNSFeatureHardwareAuthentication = 0x01
+	MDNSFeatureSoftwareAuthentication = 0x02
+)
+
+// Service types of the mDNS service
+const (
+	MDNSServiceTypeTCP = "_hap._tcp."
+	MDNSServiceTypeUDP = "_hap._udp." // HAP over CoAP
+)
+
+// MDNSConfig configures how a mDNS service is announced (see RFC 6762).
+//
+// The responder probes for name conflicts (RFC 6762 section 8.1) and announces
+// the service twice when it is published. Probing is not configurable.
+type MDNSConfig struct {
+	// TTL is the time-to-live of the announced records.
+	// When 0, the default of the responder is used.
+	TTL time.Duration
+
+	// Announcements is the number of additional announcements, which are sent
+	// when the service is announced again (see MDNSService.Announce).
+	Announcements int
+
+	// AnnounceInterval is the interval between the announcements, which is doubled
+	// after every announcement (RFC 6762 section 8.3). When 0, 1 second is used.
+	AnnounceInterval time.Duration
+}
+
 // MDNSService represents a mDNS service.
 type MDNSService struct {
+	serviceType        string
 	name               string
 	ip                 string
 	port               int
 	protocol           string // Protocol version (pv) (Default 1.0)
 	id                 string
-	configuration      int64 // c#
-	state              int64 // s#
-	mfiCompliant       bool  // ff
-	reachable          bool  // sf
-	categoryIdentifier int64 // ci (see AccessoryType)
+	configuration      int64  // c#
+	state              int64  // s#
+	features           int64  // ff
+	reachable          bool   // sf
+	categoryIdentifier int64  // ci (see AccessoryType)
+	setupHash          string // sh
+
+	server    responder
+	announced time.Time
+	config    MDNSConfig
+	mutex     sync.Mutex
+
+	clock util.Clock
+
+	// timer and generation of the repeated announcements
+	timer      util.Timer
+	generation int
+
+	// system is true when the service is registered with the Bonjour daemon of the system
+	system bool
+}
+
+// responder announces a service via mDNS.
+type responder interface {
+	SetText(text []string)
+	TTL(ttl uint32)
+	Shutdown()
+}
+
+// systemResponder is a responder which uses the Bonjour daemon of the system.
+type systemResponder struct {
+	service *dnssd.Service
+}
+
+func (r *systemResponder) SetText(text []string) {
+	if err := r.service.SetText(text); err != nil {
+		log.Println("[ERRO] Could not update txt records", err)
+	}
+}
 
-	server *bonjour.Server
+// TTL does nothing because the daemon manages the time-to-live of the records.
+func (r *systemResponder) TTL(ttl uint32) {}
+
+func (r *systemResponder) Shutdown() {
+	r.service.Stop()
 }
 
 // NewMDNSService returns a new service based for the bridge name, id and port.
 func NewMDNSService(name string, id string, ip string, port int, category int64) *MDNSService {
 	return &MDNSService{
+		serviceType:        MDNSServiceTypeTCP,
 		name:               name,
 		ip:                 ip,
 		port:               port,
@@ -36,14 +110,18 @@ func NewMDNSService(name string, id string, ip string, port int, category int64)
 		id:                 id,
 		configuration:      1,
 		state:              1,
-		mfiCompliant:       false,
+		features:           0,
 		reachable:          true,
 		categoryIdentifier: category,
+		clock:              util.SystemClock,
 	}
 }
 
 // IsPublished returns true when the service is published.
 func (s *MDNSService) IsPublished() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	return s.server != nil
 }
 
@@ -51,6 +129,77 @@ func (s *MDNSService) SetReachable(r bool) {
 	s.reachable = r
 }
 
+// SetConfiguration sets the configuration number (c#).
+func (s *MDNSService) SetConfiguration(c int64) {
+	s.configuration = c
+}
+
+// SetPort sets the port of the service. The service must be published again to announce the new port.
+func (s *MDNSService) SetPort(port int) {
+	s.port = port
+}
+
+// Name returns the name of the service.
+func (s *MDNSService) Name() string {
+	return s.name
+}
+
+// SetName sets the name of the service. The service must be published again to announce the new name.
+func (s *MDNSService) SetName(name string) {
+	s.name = name
+}
+
+// IP returns the ip address of the service.
+func (s *MDNSService) IP() string {
+	return s.ip
+}
+
+// Port returns the port of the service.
+func (s *MDNSService) Port() int {
+	return s.port
+}
+
+// SetServiceType sets the service type, e.g. MDNSServiceTypeUDP.
+func (s *MDNSService) SetServiceType(t string) {
+	s.serviceType = t
+}
+
+// SetFeatures sets the pairing feature flags (ff), e.g. MDNSFeatureSoftwareAuthentication.
+func (s *MDNSService) SetFeatures(f int64) {
+	s.features = f
+}
+
+// SetSetupHash sets the setup hash (sh), which is required to pair via a setup payload.
+func (s *MDNSService) SetSetupHash(sh string) {
+	s.setupHash = sh
+}
+
+// SetConfig sets the configuration of the announcements.
+func (s *MDNSService) SetConfig(c MDNSConfig) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.config = c
+	if s.server != nil && c.TTL > 0 {
+		s.server.TTL(uint32(c.TTL / time.Second))
+	}
+}
+
+// SetClock sets the clock of the announcements, e.g. a fake clock in tests.
+func (s *MDNSService) SetClock(c util.Clock) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.clock = c
+}
+
+// SetSystemResponder sets whether the service is registered with the Bonjour daemon of the
+// system (macOS and Windows), instead of being announced by a responder in the process.
+// If the system has no Bonjour daemon, the responder in the process is used.
+func (s *MDNSService) SetSystemResponder(system bool) {
+	s.system = system
+}
+
 // Publish announces the service for the machine's ip address on a random port using mDNS.
 func (s *MDNSService) Publish() error {
 	// Host should end with '.'
@@ -65,38 +214,137 @@ func (s *MDNSService) Publish() error {
 	// [Radar] http://openradar.appspot.com/radar?id=4931940373233664
 	stripped := strings.Replace(s.name, " ", "_", -1)
 
-	server, err := bonjour.RegisterProxy(stripped, "_hap._tcp.", "", s.port, host, s.ip, text, nil)
-	if err != nil {
-		log.Fatal(err)
+	var server responder
+	if s.system == true {
+		// The daemon announces the service for all addresses of the machine
+		service, err := dnssd.Register(stripped, strings.TrimSuffix(s.serviceType, "."), s.port, text)
+		if err == nil {
+			server = &systemResponder{service}
+		} else {
+			log.Println("[WARN] Could not register service with the system", err)
+		}
+	}
+
+	if server == nil {
+		bs, err := bonjour.RegisterProxy(stripped, s.serviceType, "", s.port, host, s.ip, text, nil)
+		if err != nil {
+			log.Fatal(err)
+		}
+		server = bs
 	}
 
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if ttl := s.config.TTL; ttl > 0 {
+		server.TTL(uint32(ttl / time.Second))
+	}
 	s.server = server
-	return err
+	s.announced = s.clock.Now()
+
+	return nil
 }
 
 // Update updates the mDNS txt records.
 func (s *MDNSService) Update() {
-	if s.server != nil {
-		s.server.SetText(s.txtRecords())
+	if s.IsPublished() == true {
+		s.Announce()
 		log.Println("[INFO]", s.txtRecords())
 	}
 }
 
+// Announce announces the service records immediately, e.g. after the network changed.
+// The announcement is repeated as specified by the config.
+func (s *MDNSService) Announce() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.server == nil {
+		return
+	}
+
+	s.announce()
+
+	// Cancel previous announcements
+	s.cancelAnnouncements()
+
+	if n := s.config.Announcements; n > 0 {
+		interval := s.config.AnnounceInterval
+		if interval <= 0 {
+			interval = time.Second
+		}
+
+		s.scheduleAnnouncements(s.generation, n, interval)
+	}
+}
+
+// announce sends the records. The caller must hold the mutex.
+func (s *MDNSService) announce() {
+	// Setting the txt records makes the responder announce the records
+	s.server.SetText(s.txtRecords())
+	s.announced = s.clock.Now()
+}
+
+// scheduleAnnouncements announces the service n times after interval,
+// which is doubled after every announcement. The caller must hold the mutex.
+func (s *MDNSService) scheduleAnnouncements(generation, n int, interval time.Duration) {
+	s.timer = s.clock.AfterFunc(interval, func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		if generation != s.generation || s.server == nil {
+			return
+		}
+
+		s.announce()
+		if n > 1 {
+			s.scheduleAnnouncements(generation, n-1, interval*2)
+		}
+	})
+}
+
+// cancelAnnouncements cancels the scheduled announcements. The caller must hold the mutex.
+func (s *MDNSService) cancelAnnouncements() {
+	s.generation++
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+}
+
+// LastAnnounce returns the time when the service was last published or announced.
+func (s *MDNSService) LastAnnounce() time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.announced
+}
+
 // Stop stops the running mDNS service.
 func (s *MDNSService) Stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.cancelAnnouncements()
 	s.server.Shutdown()
 	s.server = nil
 }
 
 func (s *MDNSService) txtRecords() []string {
-	return []string{
+	records := []string{
 		fmt.Sprintf("pv=%s", s.protocol),
 		fmt.Sprintf("id=%s", s.id),
 		fmt.Sprintf("c#=%d", s.configuration),
 		fmt.Sprintf("s#=%d", s.state),
 		fmt.Sprintf("sf=%d", to.Int64(s.reachable)),
-		fmt.Sprintf("ff=%d", to.Int64(s.mfiCompliant)),
+		fmt.Sprintf("ff=%d", s.features),
 		fmt.Sprintf("md=%s", s.name),
 		fmt.Sprintf("ci=%d", s.categoryIdentifier),
 	}
+
+	if len(s.setupHash) > 0 {
+		records = append(records, fmt.Sprintf("sh=%s", s.setupHash))
+	}
+
+	return records
 }
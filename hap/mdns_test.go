@@ -3,6 +3,7 @@ package hap
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestMDNS(t *testing.T) {
@@ -40,3 +41,60 @@ func TestReachable(t *testing.T) {
 		t.Fatal(expect)
 	}
 }
+
+func TestConfiguration(t *testing.T) {
+	mdns := NewMDNSService("My MDNS Service", "1234", "127.0.0.1", 5010, 1)
+	mdns.SetConfiguration(2)
+
+	if is, want := mdns.txtRecords()[2], "c#=2"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestSetupHashRecord(t *testing.T) {
+	mdns := NewMDNSService("My MDNS Service", "1234", "127.0.0.1", 5010, 1)
+	mdns.SetSetupHash("mG9CAA==")
+
+	records := mdns.txtRecords()
+	if is, want := records[len(records)-1], "sh=mG9CAA=="; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestSystemResponderFallback(t *testing.T) {
+	mdns := NewMDNSService("My MDNS Service", "1234", "127.0.0.1", 5010, 1)
+	mdns.SetSystemResponder(true)
+
+	if err := mdns.Publish(); err != nil {
+		t.Fatal(err)
+	}
+	defer mdns.Stop()
+
+	if is, want := mdns.IsPublished(), true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestAnnounce(t *testing.T) {
+	mdns := NewMDNSService("My MDNS Service", "1234", "127.0.0.1", 5010, 1)
+	mdns.SetConfig(MDNSConfig{TTL: time.Minute, Announcements: 2, AnnounceInterval: 10 * time.Millisecond})
+
+	// Announcing an unpublished service does nothing
+	mdns.Announce()
+	if is := mdns.LastAnnounce(); is.IsZero() == false {
+		t.Fatal(is)
+	}
+
+	if err := mdns.Publish(); err != nil {
+		t.Fatal(err)
+	}
+	defer mdns.Stop()
+
+	published := mdns.LastAnnounce()
+	mdns.Announce()
+	time.Sleep(50 * time.Millisecond)
+
+	if is := mdns.LastAnnounce(); is.After(published) == false {
+		t.Fatalf("is=%v want after %v", is, published)
+	}
+}
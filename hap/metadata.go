@@ -0,0 +1,27 @@
+package hap
+
+import (
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/log"
+)
+
+// restoreMetadata sets the stored metadata of a and stores the metadata when it changes.
+func (t *ipTransport) restoreMetadata(a *accessory.Accessory) {
+	aid := a.GetID()
+	if m, err := t.database.AccessoryMetadata(aid); err != nil {
+		log.Println("[WARN] Could not restore metadata", err)
+	} else {
+		a.RestoreMetadata(m)
+	}
+
+	// Metadata which was set before the transport was created replaces the stored values
+	if err := t.database.SaveAccessoryMetadata(aid, a.AllMetadata()); err != nil {
+		log.Println("[ERRO] Could not store metadata", err)
+	}
+
+	a.OnMetadataChange(func(m map[string]string) {
+		if err := t.database.SaveAccessoryMetadata(aid, m); err != nil {
+			log.Println("[ERRO] Could not store metadata", err)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package hap
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/brutella/hc/util"
+	"github.com/brutella/log"
+)
+
+// namesKey is the storage key of the names, which were set with RenameAccessory and RenameService
+const namesKey = "names"
+
+// nameKey returns the key of the name of the accessory with id aid, or of its service
+// with the instance id iid if iid is greater than 0.
+func nameKey(aid, iid int64) string {
+	if iid > 0 {
+		return fmt.Sprintf("%d.%d", aid, iid)
+	}
+
+	return fmt.Sprintf("%d", aid)
+}
+
+// namesInStorage returns the stored names by key.
+func namesInStorage(storage util.Storage) map[string]string {
+	names := map[string]string{}
+	if b, err := storage.Get(namesKey); err == nil && len(b) > 0 {
+		if err := json.Unmarshal(b, &names); err != nil {
+			log.Println("[WARN] Invalid names", err)
+		}
+	}
+
+	return names
+}
+
+// saveName stores the name for key.
+func saveName(storage util.Storage, key, name string) error {
+	names := namesInStorage(storage)
+	names[key] = name
+
+	b, err := json.Marshal(names)
+	if err != nil {
+		return err
+	}
+
+	return storage.Set(namesKey, b)
+}
+
+// RenameAccessory sets and stores the name of the accessory with id aid.
+// The name must not be used by another accessory of the transport.
+func (t *ipTransport) RenameAccessory(aid int64, name string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	// The storage would not be found by the configured name anymore
+	if aid == t.container.Accessories[0].GetID() && t.storageIndex != nil && t.storageKey == storageKey(t.name, "") {
+		return fmt.Errorf("Accessory %d needs a serial number or storage path to be renamed", aid)
+	}
+
+	if err := t.container.RenameAccessory(aid, name); err != nil {
+		return err
+	}
+
+	// The first accessory updates the configuration number when renamed (see ipTransport.rename)
+	if aid != t.container.Accessories[0].GetID() {
+		t.updateConfiguration()
+	}
+
+	return saveName(t.storage, nameKey(aid, 0), name)
+}
+
+// RenameService sets and stores the name of the service with instance id iid of the
+// accessory with id aid. The name must not be used by another service of the accessory.
+func (t *ipTransport) RenameService(aid, iid int64, name string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if err := t.container.RenameService(aid, iid, name); err != nil {
+		return err
+	}
+	t.updateConfiguration()
+
+	return saveName(t.storage, nameKey(aid, iid), name)
+}
+
+// restoreNames sets the stored names of the accessories and services.
+func (t *ipTransport) restoreNames() {
+	names := namesInStorage(t.storage)
+	for _, a := range t.container.Accessories {
+		if name, ok := names[nameKey(a.GetID(), 0)]; ok == true {
+			if err := t.container.RenameAccessory(a.GetID(), name); err != nil {
+				log.Println("[WARN] Could not restore name", err)
+			}
+		}
+
+		for _, s := range a.Services {
+			if name, ok := names[nameKey(a.GetID(), s.ID)]; ok == true {
+				if err := t.container.RenameService(a.GetID(), s.ID, name); err != nil {
+					log.Println("[WARN] Could not restore name", err)
+				}
+			}
+		}
+	}
+}
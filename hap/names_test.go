@@ -0,0 +1,38 @@
+package hap
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/brutella/hc/accessory"
+)
+
+func TestRenameAccessoryIsRestored(t *testing.T) {
+	transport := newTestTransport(t)
+	transport.mutex = &sync.Mutex{}
+	transport.container = accessory.NewContainer()
+	bridge := accessory.New(accessory.Info{Name: "Bridge", SerialNumber: "001"}, accessory.TypeBridge)
+	lamp := accessory.New(accessory.Info{Name: "Lamp"}, accessory.TypeLightbulb)
+	transport.container.AddAccessory(bridge)
+	transport.container.AddAccessory(lamp)
+
+	if err := transport.RenameAccessory(lamp.GetID(), "Bridge"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if err := transport.RenameAccessory(lamp.GetID(), "Desk Lamp"); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := transport.configuration, int64(1); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// The name is restored after a restart
+	lamp.Info.Name.SetValue("Lamp")
+	transport.restoreNames()
+
+	if is, want := lamp.Name(), "Desk Lamp"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,31 @@
+package hap
+
+import (
+	"github.com/brutella/log"
+)
+
+func (t *ipTransport) EnablePairing() {
+	config := t.currentConfig()
+	if config.PairingWindow <= 0 {
+		return
+	}
+
+	t.pairingMutex.Lock()
+	defer t.pairingMutex.Unlock()
+
+	t.pairingUntil = config.Clock.Now().Add(config.PairingWindow)
+	log.Println("[INFO] Pairing enabled until", t.pairingUntil)
+}
+
+// pairingAvailable returns true when pair setup is accepted.
+func (t *ipTransport) pairingAvailable() bool {
+	config := t.currentConfig()
+	if config.PairingWindow <= 0 {
+		return true
+	}
+
+	t.pairingMutex.Lock()
+	defer t.pairingMutex.Unlock()
+
+	return config.Clock.Now().Before(t.pairingUntil)
+}
@@ -0,0 +1,36 @@
+package hap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brutella/hc/util"
+)
+
+func TestPairingWindow(t *testing.T) {
+	transport := newTestTransport(t)
+	transport.config.Clock = util.SystemClock
+	transport.config.PairingWindow = time.Minute
+
+	if transport.pairingAvailable() == true {
+		t.Fatal("expected pairing to be unavailable")
+	}
+
+	transport.EnablePairing()
+	if transport.pairingAvailable() == false {
+		t.Fatal("expected pairing to be available")
+	}
+
+	transport.pairingUntil = time.Now().Add(-time.Second)
+	if transport.pairingAvailable() == true {
+		t.Fatal("expected pairing window to be closed")
+	}
+}
+
+func TestPairingWithoutWindow(t *testing.T) {
+	transport := newTestTransport(t)
+
+	if transport.pairingAvailable() == false {
+		t.Fatal("expected pairing to be available")
+	}
+}
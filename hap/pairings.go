@@ -0,0 +1,57 @@
+package hap
+
+import (
+	"fmt"
+
+	"github.com/brutella/hc/audit"
+	"github.com/brutella/hc/db"
+	"github.com/brutella/hc/event"
+	"github.com/brutella/log"
+)
+
+// RemovePairing removes the pairing of the controller with username in the same way
+// as the /pairings endpoint: the DeviceUnpaired event is emitted, which updates the
+// mDNS status flag, and the removal is recorded in the audit log.
+// The other controllers verify their pairing again.
+func (t *ipTransport) RemovePairing(username string) error {
+	controllers, err := db.Controllers(t.database)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range controllers {
+		if e.Name == username {
+			t.unpair(e)
+			t.auditLog.Record(audit.Record{Operation: audit.OperationPairingRemoved, Username: username})
+			t.Reverify()
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Pairing %s not found", username)
+}
+
+// ResetPairings removes the pairings of all controllers, so that the accessory can be paired again.
+// The DeviceUnpaired event is emitted for every controller and the reset is recorded in the audit log.
+func (t *ipTransport) ResetPairings() error {
+	controllers, err := db.Controllers(t.database)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range controllers {
+		t.unpair(e)
+	}
+	t.auditLog.Record(audit.Record{Operation: audit.OperationFactoryReset})
+	t.Reverify()
+	t.Announce()
+
+	return nil
+}
+
+// unpair deletes the controller entity e and emits the DeviceUnpaired event.
+func (t *ipTransport) unpair(e db.Entity) {
+	log.Printf("[INFO] Remove LTPK for client '%s'\n", e.Name)
+	t.database.DeleteEntity(e)
+	t.emitter.Emit(event.DeviceUnpaired{Username: e.Name})
+}
@@ -0,0 +1,56 @@
+package hap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brutella/hc/db"
+	"github.com/brutella/hc/event"
+)
+
+func TestRemovePairing(t *testing.T) {
+	transport := newTestTransport(t)
+	transport.emitter = event.NewAsyncEmitter(eventQueueSize)
+	defer transport.emitter.Stop()
+
+	ch, _ := transport.emitter.Subscribe(event.DeviceUnpaired{}, 1)
+	transport.database.SaveEntity(db.NewEntity("Alice", []byte{0x01}, nil))
+
+	if err := transport.RemovePairing("Bob"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if err := transport.RemovePairing("Alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	if transport.isPaired() == true {
+		t.Fatal("expected no pairings")
+	}
+
+	select {
+	case ev := <-ch:
+		if is, want := ev.(event.DeviceUnpaired).Username, "Alice"; is != want {
+			t.Fatalf("is=%v want=%v", is, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected event")
+	}
+}
+
+func TestResetPairings(t *testing.T) {
+	transport := newTestTransport(t)
+	transport.emitter = event.NewAsyncEmitter(eventQueueSize)
+	defer transport.emitter.Stop()
+
+	transport.database.SaveEntity(db.NewEntity("Alice", []byte{0x01}, nil))
+	transport.database.SaveEntity(db.NewEntity("Bob", []byte{0x02}, nil))
+
+	if err := transport.ResetPairings(); err != nil {
+		t.Fatal(err)
+	}
+
+	if transport.isPaired() == true {
+		t.Fatal("expected no pairings")
+	}
+}
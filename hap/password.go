@@ -2,7 +2,12 @@ package hap
 
 import (
 	"bytes"
+	"crypto/rand"
 	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/brutella/hc/netio/pair"
 )
 
 // NewPin returns a HomeKit compatible pin string from a 8-numbers strings e.g. '01020304'.
@@ -29,3 +34,48 @@ func NewPin(pin string) (string, error) {
 
 	return fmtPin, nil
 }
+
+// NewSetupVerifier returns a random salt and the SRP verifier for a 8-numbers pin e.g. '01020304'.
+//
+// The salt and verifier can be computed when the accessory is manufactured
+// and used in the transport config instead of the pin.
+func NewSetupVerifier(pin string) ([]byte /*salt*/, []byte /*verifier*/, error) {
+	fmtPin, err := NewPin(pin)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pair.NewSetupVerifier(fmtPin)
+}
+
+// trivialPins are not allowed as setup code
+var trivialPins = []string{
+	"00000000", "11111111", "22222222", "33333333", "44444444",
+	"55555555", "66666666", "77777777", "88888888", "99999999",
+	"12345678", "87654321",
+}
+
+// NewRandomPin returns a random HomeKit compatible pin string e.g. '012-34-567'.
+func NewRandomPin() (string, error) {
+	for {
+		n, err := rand.Int(rand.Reader, big.NewInt(100000000))
+		if err != nil {
+			return "", err
+		}
+
+		pin := fmt.Sprintf("%08d", n.Int64())
+		if isTrivialPin(pin) == false {
+			return NewPin(pin)
+		}
+	}
+}
+
+func isTrivialPin(pin string) bool {
+	for _, p := range trivialPins {
+		if p == pin {
+			return true
+		}
+	}
+
+	return false
+}
@@ -37,3 +37,42 @@ func TestInvalidPin(t *testing.T) {
 		t.Fatal("expected error")
 	}
 }
+
+func TestSetupVerifier(t *testing.T) {
+	salt, verifier, err := NewSetupVerifier("00011222")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := len(salt), 16; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if len(verifier) == 0 {
+		t.Fatal("expected verifier")
+	}
+
+	if _, _, err := NewSetupVerifier("12345678"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestRandomPin(t *testing.T) {
+	pin, err := NewRandomPin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := len(pin), 10; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestTrivialPin(t *testing.T) {
+	if isTrivialPin("87654321") == false {
+		t.Fatal("expected trivial pin")
+	}
+	if isTrivialPin("00011222") == true {
+		t.Fatal("expected non-trivial pin")
+	}
+}
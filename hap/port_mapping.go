@@ -0,0 +1,117 @@
+package hap
+
+import (
+	"github.com/brutella/hc/nat"
+	"github.com/brutella/log"
+	"github.com/gosexy/to"
+
+	"time"
+)
+
+// portMappingLifetime is the requested lifetime of a port mapping on the gateway
+const portMappingLifetime = 2 * time.Hour
+
+// portMapping is a mapping of the server port to an external port on the gateway.
+type portMapping struct {
+	mapper   nat.PortMapper
+	protocol string
+	port     int
+	external int
+
+	// renewal is the job which renews the mapping
+	renewal *Job
+}
+
+// advertisedPort returns the port which is published via mDNS for the server port.
+// If the transport uses a port mapper, the server port is mapped on the gateway
+// and the external port is returned.
+func (t *ipTransport) advertisedPort(protocol string, port int) int {
+	config := t.currentConfig()
+	if p := config.AdvertisedPort; len(p) > 0 {
+		return int(to.Int64(p))
+	}
+
+	mapper := config.PortMapper
+	if mapper == nil {
+		return port
+	}
+
+	external, lifetime, err := mapper.MapPort(protocol, port, portMappingLifetime)
+	if err != nil {
+		log.Println("[ERRO] Could not map port", port, err)
+		return port
+	}
+
+	log.Printf("[INFO] Mapped port %d to external port %d\n", port, external)
+	m := &portMapping{mapper: mapper, protocol: protocol, port: port, external: external}
+
+	t.mappingMutex.Lock()
+	t.mapping = m
+	t.scheduleRenewal(m, lifetime)
+	t.mappingMutex.Unlock()
+
+	return external
+}
+
+// scheduleRenewal renews the port mapping m after half of its lifetime.
+// The caller must hold the mapping mutex.
+func (t *ipTransport) scheduleRenewal(m *portMapping, lifetime time.Duration) {
+	if lifetime <= 0 {
+		lifetime = portMappingLifetime
+	}
+
+	m.renewal = t.scheduler.Schedule(After(lifetime/2), func() {
+		t.renew(m)
+	})
+}
+
+// renew renews the port mapping m, unless it was removed.
+func (t *ipTransport) renew(m *portMapping) {
+	t.mappingMutex.Lock()
+	defer t.mappingMutex.Unlock()
+
+	// The mapping was removed or replaced, e.g. after a restart
+	if t.mapping != m {
+		return
+	}
+
+	external, lifetime, err := m.mapper.MapPort(m.protocol, m.port, portMappingLifetime)
+	if err != nil {
+		// Try again in a minute
+		log.Println("[ERRO] Could not renew port mapping", err)
+		t.scheduleRenewal(m, 2*time.Minute)
+		return
+	}
+
+	if external != m.external {
+		log.Printf("[INFO] External port changed from %d to %d\n", m.external, external)
+		m.external = external
+		if mdns := t.currentMDNS(); mdns != nil {
+			mdns.Stop()
+			mdns.SetPort(external)
+			mdns.Publish()
+		}
+	}
+
+	t.scheduleRenewal(m, lifetime)
+}
+
+// unmapPort cancels the renewal and removes the port mapping from the gateway.
+func (t *ipTransport) unmapPort() {
+	t.mappingMutex.Lock()
+	defer t.mappingMutex.Unlock()
+
+	m := t.mapping
+	if m == nil {
+		return
+	}
+
+	if m.renewal != nil {
+		m.renewal.Cancel()
+	}
+
+	if err := m.mapper.UnmapPort(m.protocol, m.port); err != nil {
+		log.Println("[ERRO] Could not remove port mapping", err)
+	}
+	t.mapping = nil
+}
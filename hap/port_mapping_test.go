@@ -0,0 +1,82 @@
+package hap
+
+import (
+	"github.com/brutella/hc/haptest"
+
+	"testing"
+	"time"
+)
+
+type testPortMapper struct {
+	mapped map[int]int
+	calls  int
+}
+
+func (m *testPortMapper) MapPort(protocol string, port int, lifetime time.Duration) (int, time.Duration, error) {
+	m.calls++
+	m.mapped[port] = port + 1000
+	return port + 1000, lifetime, nil
+}
+
+func (m *testPortMapper) UnmapPort(protocol string, port int) error {
+	delete(m.mapped, port)
+	return nil
+}
+
+func TestAdvertisedPort(t *testing.T) {
+	transport := newTestTransport(t)
+
+	if is, want := transport.advertisedPort("tcp", 12345), 12345; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	transport.config.AdvertisedPort = "80"
+	if is, want := transport.advertisedPort("tcp", 12345), 80; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestPortMapping(t *testing.T) {
+	mapper := &testPortMapper{mapped: map[int]int{}}
+	transport := newTestTransport(t)
+	transport.scheduler = NewScheduler()
+	transport.config.PortMapper = mapper
+	defer transport.scheduler.Stop()
+
+	if is, want := transport.advertisedPort("tcp", 12345), 13345; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := len(mapper.mapped), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	transport.unmapPort()
+
+	if is, want := len(mapper.mapped), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestPortMappingRenewalCanceled(t *testing.T) {
+	mapper := &testPortMapper{mapped: map[int]int{}}
+	clock := haptest.NewClock(time.Now())
+	transport := newTestTransport(t)
+	transport.scheduler = NewSchedulerWithClock(clock)
+	transport.config.PortMapper = mapper
+	defer transport.scheduler.Stop()
+
+	transport.advertisedPort("tcp", 12345)
+	clock.Advance(portMappingLifetime / 2)
+
+	if is, want := mapper.calls, 2; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	transport.unmapPort()
+	clock.Advance(portMappingLifetime)
+
+	if is, want := mapper.calls, 2; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
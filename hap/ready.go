@@ -0,0 +1,26 @@
+package hap
+
+// Started returns a channel which is closed when the transport was started.
+func (t *ipTransport) Started() <-chan struct{} {
+	return t.ready
+}
+
+// setReady sets the port of the started transport and closes the ready channel,
+// when the transport was started the first time.
+func (t *ipTransport) setReady(port int) {
+	t.serverMutex.Lock()
+	t.port = port
+	t.serverMutex.Unlock()
+
+	t.readyOnce.Do(func() {
+		close(t.ready)
+	})
+}
+
+// listenPort returns the port of the started transport, or 0 when it wasn't started.
+func (t *ipTransport) listenPort() int {
+	t.serverMutex.Lock()
+	defer t.serverMutex.Unlock()
+
+	return t.port
+}
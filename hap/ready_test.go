@@ -0,0 +1,37 @@
+package hap
+
+import (
+	"testing"
+)
+
+func TestStarted(t *testing.T) {
+	transport := newTestTransport(t)
+
+	select {
+	case <-transport.Started():
+		t.Fatal("transport is not started")
+	default:
+	}
+
+	if is, want := transport.Status().Port, 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	transport.setReady(12345)
+
+	select {
+	case <-transport.Started():
+	default:
+		t.Fatal("transport is started")
+	}
+
+	if is, want := transport.Status().Port, 12345; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// The port changes when the transport is restarted
+	transport.setReady(23456)
+	if is, want := transport.Status().Port, 23456; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
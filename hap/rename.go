@@ -0,0 +1,41 @@
+package hap
+
+import (
+	"github.com/brutella/log"
+)
+
+// rename publishes the mDNS service with a new name and updates the storage index,
+// if the storage was found by the name of the accessory.
+func (t *ipTransport) rename(name string) {
+	if name == t.name {
+		return
+	}
+
+	log.Println("[INFO] Renaming", t.name, "to", name)
+
+	if index := t.storageIndex; index != nil {
+		key := storageKey(name, "")
+		if t.storageKey == storageKey(t.name, "") {
+			if err := index.rename(t.storageKey, key); err != nil {
+				log.Println("[ERRO] Could not update storage index", err)
+			}
+			t.storageKey = key
+		}
+	}
+
+	t.name = name
+	if mdns := t.currentMDNS(); mdns != nil {
+		published := mdns.IsPublished()
+		if published == true {
+			mdns.Stop()
+		}
+
+		mdns.SetName(name)
+		if published == true {
+			mdns.Publish()
+		}
+	}
+
+	// Clients reload the accessory information when the configuration changes
+	t.updateConfiguration()
+}
@@ -0,0 +1,186 @@
+package hap
+
+// restart is a request to listen again with a new network configuration.
+type restart struct {
+	// previous is the configuration which is restored when the transport can't listen
+	previous Config
+
+	// err is the error when the transport couldn't listen with the new configuration
+	err error
+
+	done chan error
+}
+
+// Restart stops the listener and mDNS service and starts them again with the network
+// configuration of config. The fields Port, IP, ListenAddress, Interface, AdvertisedPort,
+// PortMapper, SystemMDNS and MDNS are used; all other fields of config are ignored.
+//
+// When the transport can't listen with the new configuration, it listens with
+// the previous configuration again and the error is returned.
+func (t *ipTransport) Restart(config Config) error {
+	t.restartMutex.Lock()
+	defer t.restartMutex.Unlock()
+
+	c, err := t.networkConfig(config)
+	if err != nil {
+		return err
+	}
+
+	stop := t.serverStopper()
+	if stop == nil {
+		// The new configuration is used when the transport is started
+		t.setConfig(c)
+		return nil
+	}
+
+	r := &restart{previous: t.currentConfig(), done: make(chan error, 1)}
+
+	// Cancel the jobs of the running listener; they are scheduled again when it listens
+	t.stopWatchdog()
+	t.unmapPort()
+	if mdns := t.takeMDNS(); mdns != nil {
+		mdns.Stop()
+	}
+
+	t.setConfig(c)
+	t.serverMutex.Lock()
+	t.restart = r
+	t.serverMutex.Unlock()
+	stop()
+
+	return <-r.done
+}
+
+// networkConfig returns the config of the transport with the network configuration of config.
+func (t *ipTransport) networkConfig(config Config) (Config, error) {
+	c := t.currentConfig()
+
+	ip, err := localIPAddr(config)
+	if err != nil {
+		return c, err
+	}
+
+	c.IP = ip.String()
+	if len(config.IP) > 0 {
+		c.IP = config.IP
+	}
+
+	c.Port = ""
+	if port := config.Port; len(port) > 0 {
+		c.Port = ":" + port
+	}
+
+	c.ListenAddress = config.ListenAddress
+	c.Interface = config.Interface
+	c.AdvertisedPort = config.AdvertisedPort
+	c.PortMapper = config.PortMapper
+	c.SystemMDNS = config.SystemMDNS
+	c.MDNS = config.MDNS
+
+	return c, nil
+}
+
+// currentConfig returns the config of the transport, which is replaced on restart.
+func (t *ipTransport) currentConfig() Config {
+	t.configMutex.RLock()
+	defer t.configMutex.RUnlock()
+
+	return t.config
+}
+
+// setConfig replaces the config of the transport.
+func (t *ipTransport) setConfig(c Config) {
+	t.configMutex.Lock()
+	defer t.configMutex.Unlock()
+
+	t.config = c
+}
+
+// setServer sets the function which stops the running server.
+// It returns false when the transport was stopped.
+func (t *ipTransport) setServer(stop func()) bool {
+	t.serverMutex.Lock()
+	defer t.serverMutex.Unlock()
+
+	if t.stopped == true {
+		return false
+	}
+	t.stopServer = stop
+
+	return true
+}
+
+// stopServing stops the running server and the mDNS service.
+// Servers and services which are set afterwards are not used.
+func (t *ipTransport) stopServing() {
+	t.serverMutex.Lock()
+	t.stopped = true
+	stop := t.stopServer
+	t.serverMutex.Unlock()
+
+	if mdns := t.takeMDNS(); mdns != nil {
+		mdns.Stop()
+	}
+
+	if stop != nil {
+		stop()
+	}
+}
+
+// currentMDNS returns the mDNS service of the running server, or nil.
+func (t *ipTransport) currentMDNS() *MDNSService {
+	t.serverMutex.Lock()
+	defer t.serverMutex.Unlock()
+
+	return t.mdns
+}
+
+// takeMDNS removes the published mDNS service from the transport and returns it, or nil.
+func (t *ipTransport) takeMDNS() *MDNSService {
+	t.serverMutex.Lock()
+	defer t.serverMutex.Unlock()
+
+	mdns := t.mdns
+	t.mdns = nil
+	if mdns != nil && mdns.IsPublished() == false {
+		return nil
+	}
+
+	return mdns
+}
+
+// serverStopper returns the function which stops the running server,
+// or nil when the transport wasn't started.
+func (t *ipTransport) serverStopper() func() {
+	t.serverMutex.Lock()
+	defer t.serverMutex.Unlock()
+
+	return t.stopServer
+}
+
+// restartRequest returns the pending restart request, after the server stopped.
+// It returns false when the transport was stopped.
+func (t *ipTransport) restartRequest() (*restart, bool) {
+	t.serverMutex.Lock()
+	defer t.serverMutex.Unlock()
+
+	return t.restart, t.restart != nil
+}
+
+// restarted finishes the pending restart request, after the transport listens and is published again.
+func (t *ipTransport) restarted(err error) {
+	t.serverMutex.Lock()
+	r := t.restart
+	t.restart = nil
+	t.serverMutex.Unlock()
+
+	if r == nil {
+		return
+	}
+
+	if err == nil {
+		err = r.err
+	}
+
+	r.done <- err
+}
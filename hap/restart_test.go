@@ -0,0 +1,100 @@
+package hap
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/brutella/hc/accessory"
+)
+
+func TestRestart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restart")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := Config{StoragePath: dir, ListenAddress: "127.0.0.1"}
+	a := accessory.NewSwitch(accessory.Info{Name: "Switch"})
+	transport, err := NewIPTransport(config, a.Accessory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer transport.Stop()
+
+	go transport.Start()
+	<-transport.Started()
+
+	port := transport.Status().Port
+	id := transport.Endpoint().DeviceID
+
+	// Listen on a new random port
+	config.Port = "0"
+	if err := transport.Restart(config); err != nil {
+		t.Fatal(err)
+	}
+
+	e := transport.Endpoint()
+	if is, want := e.DeviceID, id; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if e.Port == port {
+		t.Fatal("expected new port")
+	}
+
+	conn, err := net.Dial("tcp", e.ListenAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	// The previous configuration is restored when the port is used
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	_, used, _ := net.SplitHostPort(ln.Addr().String())
+	config.Port = used
+	if err := transport.Restart(config); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if conn, err = net.Dial("tcp", transport.Endpoint().ListenAddress); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+}
+
+func TestStartAfterStop(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restart")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := Config{StoragePath: dir, ListenAddress: "127.0.0.1"}
+	a := accessory.NewSwitch(accessory.Info{Name: "Switch"})
+	transport, err := NewIPTransport(config, a.Accessory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		transport.Start()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("transport listens after it was stopped")
+	}
+}
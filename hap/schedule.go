@@ -0,0 +1,198 @@
+package hap
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule returns the times at which a scheduled job runs.
+type Schedule interface {
+	// Next returns the next time after t at which the job runs.
+	// When the job should not run again, the zero time is returned.
+	Next(t time.Time) time.Time
+}
+
+type everySchedule struct {
+	d time.Duration
+}
+
+// Every returns a schedule which runs a job repeatedly every d.
+func Every(d time.Duration) Schedule {
+	return &everySchedule{d}
+}
+
+func (s *everySchedule) Next(t time.Time) time.Time {
+	if s.d <= 0 {
+		return time.Time{}
+	}
+
+	return t.Add(s.d)
+}
+
+type afterSchedule struct {
+	d    time.Duration
+	done bool
+}
+
+// After returns a schedule which runs a job once after d,
+// e.g. to turn off an outlet after some minutes.
+//
+// The schedule must not be used for more than one job.
+func After(d time.Duration) Schedule {
+	return &afterSchedule{d: d}
+}
+
+func (s *afterSchedule) Next(t time.Time) time.Time {
+	if s.done == true {
+		return time.Time{}
+	}
+	s.done = true
+
+	return t.Add(s.d)
+}
+
+// cronSchedule is a schedule in the cron format.
+// Each field is a bit set of the allowed values.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// true when the day fields are restricted
+	domRestricted, dowRestricted bool
+}
+
+type cronField struct {
+	min, max int
+}
+
+var cronFields = []cronField{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 6},  // day of week (0 is sunday)
+}
+
+// Cron returns a schedule for a cron expression with the five fields
+// minute, hour, day of month, month and day of week, e.g. "30 7 * * 1-5".
+// Fields may contain "*", numbers, ranges ("1-5"), lists ("1,3") and steps ("*/15").
+// The times are in the time zone of the time passed to Next.
+func Cron(expr string) (Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("Invalid cron expression %s", expr)
+	}
+
+	var values [5]uint64
+	for i, f := range fields {
+		v, err := parseCronField(f, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid cron expression %s: %v", expr, err)
+		}
+		values[i] = v
+	}
+
+	s := cronSchedule{
+		minute:        values[0],
+		hour:          values[1],
+		dom:           values[2],
+		month:         values[3],
+		dow:           values[4],
+		domRestricted: fields[2] != "*",
+		dowRestricted: fields[4] != "*",
+	}
+
+	return &s, nil
+}
+
+// parseCronField returns the bit set of the values of field f.
+func parseCronField(f string, r cronField) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(f, ",") {
+		step := 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			s, err := strconv.Atoi(item[i+1:])
+			if err != nil || s <= 0 {
+				return 0, errors.New("invalid step " + item)
+			}
+			step = s
+			item = item[:i]
+		}
+
+		min, max := r.min, r.max
+		if item != "*" {
+			bounds := strings.SplitN(item, "-", 2)
+			v, err := strconv.Atoi(bounds[0])
+			if err != nil {
+				return 0, errors.New("invalid value " + item)
+			}
+			min, max = v, v
+
+			if len(bounds) == 2 {
+				if max, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, errors.New("invalid value " + item)
+				}
+			} else if step > 1 {
+				// "5/15" is the same as "5-max/15"
+				max = r.max
+			}
+		}
+
+		if min < r.min || max > r.max || min > max {
+			return 0, errors.New("value out of range " + item)
+		}
+
+		for v := min; v <= max; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	// Give up after 5 years, e.g. for "0 0 31 2 *"
+	end := t.AddDate(5, 0, 0)
+	for t.Before(end) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if s.matchesDay(t) == false {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// matchesDay returns true when the day of t matches the schedule.
+// Like in cron, a day matches either field when both day fields are restricted.
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.domRestricted == true && s.dowRestricted == true {
+		return dom || dow
+	}
+
+	return dom && dow
+}
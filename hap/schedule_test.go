@@ -0,0 +1,78 @@
+package hap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	s, err := Cron("30 7 * * 1-5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Saturday
+	now := time.Date(2016, 1, 2, 8, 0, 0, 0, time.UTC)
+	if is, want := s.Next(now), time.Date(2016, 1, 4, 7, 30, 0, 0, time.UTC); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestCronStep(t *testing.T) {
+	s, err := Cron("*/15 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2016, 1, 1, 23, 50, 10, 0, time.UTC)
+	if is, want := s.Next(now), time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestInvalidCron(t *testing.T) {
+	for _, expr := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "a * * * *"} {
+		if _, err := Cron(expr); err == nil {
+			t.Fatalf("expected error for %s", expr)
+		}
+	}
+}
+
+func TestSchedulerAfter(t *testing.T) {
+	s := NewScheduler()
+	done := make(chan bool, 2)
+	s.Schedule(After(10*time.Millisecond), func() {
+		done <- true
+	})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job did not run")
+	}
+
+	select {
+	case <-done:
+		t.Fatal("job ran twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSchedulerStop(t *testing.T) {
+	s := NewScheduler()
+	done := make(chan bool, 1)
+	s.Schedule(Every(20*time.Millisecond), func() {
+		done <- true
+	})
+	s.Stop()
+
+	select {
+	case <-done:
+		t.Fatal("job ran after stop")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if is, want := len(s.jobs), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,109 @@
+package hap
+
+import (
+	"sync"
+	"time"
+
+	"github.com/brutella/hc/util"
+)
+
+// Scheduler runs functions at the times of a schedule.
+// All jobs are canceled when the scheduler is stopped.
+type Scheduler struct {
+	mutex   sync.Mutex
+	jobs    map[*Job]bool
+	stopped bool
+	clock   util.Clock
+}
+
+// Job is a function which is run by a scheduler.
+type Job struct {
+	scheduler *Scheduler
+	schedule  Schedule
+	fn        func()
+	timer     util.Timer
+}
+
+// NewScheduler returns a scheduler.
+func NewScheduler() *Scheduler {
+	return NewSchedulerWithClock(util.SystemClock)
+}
+
+// NewSchedulerWithClock returns a scheduler which runs jobs at the times of clock.
+func NewSchedulerWithClock(clock util.Clock) *Scheduler {
+	return &Scheduler{
+		jobs:  map[*Job]bool{},
+		clock: clock,
+	}
+}
+
+// Schedule runs fn at the times of s until the job is canceled or the scheduler is stopped.
+// When the scheduler is already stopped, fn is never run.
+func (s *Scheduler) Schedule(sch Schedule, fn func()) *Job {
+	j := &Job{
+		scheduler: s,
+		schedule:  sch,
+		fn:        fn,
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stopped == false {
+		s.jobs[j] = true
+		s.scheduleNext(j, s.clock.Now())
+	}
+
+	return j
+}
+
+// Stop cancels all jobs.
+func (s *Scheduler) Stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for j := range s.jobs {
+		j.timer.Stop()
+		delete(s.jobs, j)
+	}
+	s.stopped = true
+}
+
+// Cancel cancels the job. The function is not run again.
+func (j *Job) Cancel() {
+	s := j.scheduler
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.jobs[j] == true {
+		j.timer.Stop()
+		delete(s.jobs, j)
+	}
+}
+
+// scheduleNext starts the timer of j for the next time after now.
+// The job is removed when the schedule has no next time.
+func (s *Scheduler) scheduleNext(j *Job, now time.Time) {
+	next := j.schedule.Next(now)
+	if next.IsZero() {
+		delete(s.jobs, j)
+		return
+	}
+
+	j.timer = s.clock.AfterFunc(next.Sub(now), func() {
+		s.run(j)
+	})
+}
+
+func (s *Scheduler) run(j *Job) {
+	s.mutex.Lock()
+	if s.jobs[j] == false {
+		// Canceled while the timer fired
+		s.mutex.Unlock()
+		return
+	}
+	s.scheduleNext(j, s.clock.Now())
+	s.mutex.Unlock()
+
+	j.fn()
+}
@@ -0,0 +1,102 @@
+package hap
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Setup flags describe how an accessory can be paired.
+const (
+	SetupFlagNFC uint8 = 1 // accessory has a programmable NFC tag
+	SetupFlagIP  uint8 = 2 // accessory supports HAP over IP
+	SetupFlagBLE uint8 = 4 // accessory supports HAP over Bluetooth LE
+	SetupFlagWAC uint8 = 8 // accessory supports Wireless Accessory Configuration
+)
+
+// ndefURIIdentifierNone is the identifier code of URIs without prefix
+const ndefURIIdentifierNone = 0x00
+
+// SetupPayload is the payload of the setup code on a QR code or NFC tag.
+// The payload is encoded as X-HM URI, which is scanned by an iOS device to pair with the accessory.
+type SetupPayload struct {
+	// Pin is the 8-digit setup code without dashes, e.g. "00102003"
+	Pin string
+
+	// SetupID is a 4-character alphanumeric identifier, e.g. "1QJ8".
+	// The same setup id must be set in the transport config.
+	SetupID string
+
+	// Category is the accessory category (see accessory.AccessoryType).
+	Category int64
+
+	// Flags is a combination of SetupFlagNFC, SetupFlagIP,…
+	Flags uint8
+}
+
+// URI returns the X-HM URI of the payload.
+func (p SetupPayload) URI() (string, error) {
+	if _, err := NewPin(p.Pin); err != nil {
+		return "", err
+	}
+
+	if err := validateSetupID(p.SetupID); err != nil {
+		return "", err
+	}
+
+	pin, _ := strconv.ParseUint(p.Pin, 10, 64)
+
+	// The lower 27 bits contain the pin, followed by 4 bits for the flags and 8 bits for the category.
+	// The version and reserved bits are always 0.
+	v := pin&0x7FFFFFF | uint64(p.Flags&0xF)<<27 | uint64(p.Category&0xFF)<<31
+
+	encoded := strings.ToUpper(strconv.FormatUint(v, 36))
+	for len(encoded) < 9 {
+		encoded = "0" + encoded
+	}
+
+	return "X-HM://" + encoded + p.SetupID, nil
+}
+
+// NDEF returns a NDEF message with the X-HM URI, which can be written to a NFC tag.
+func (p SetupPayload) NDEF() ([]byte, error) {
+	uri, err := p.URI()
+	if err != nil {
+		return nil, err
+	}
+
+	payload := append([]byte{ndefURIIdentifierNone}, []byte(uri)...)
+
+	// Short record with the message begin and end flags and the well-known type "U"
+	header := []byte{
+		0xD1,
+		0x01,
+		byte(len(payload)),
+		'U',
+	}
+
+	return append(header, payload...), nil
+}
+
+// SetupHash returns the value of the "sh" mDNS txt record, which is used by
+// iOS to find the accessory of a setup payload.
+func SetupHash(setupID, deviceID string) string {
+	h := sha512.Sum512([]byte(setupID + deviceID))
+	return base64.StdEncoding.EncodeToString(h[:4])
+}
+
+func validateSetupID(id string) error {
+	if len(id) != 4 {
+		return errors.New("Setup id must be 4 characters long")
+	}
+
+	for _, r := range id {
+		if (r < '0' || r > '9') && (r < 'A' || r > 'Z') {
+			return errors.New("Setup id must only contain numbers and uppercase letters")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package hap
+
+import (
+	"github.com/brutella/hc/netio/pair"
+	"github.com/brutella/hc/util"
+	"github.com/brutella/log"
+
+	"sync"
+	"time"
+)
+
+// defaultSetupCodeTimeout is the duration after which a displayed setup code expires
+const defaultSetupCodeTimeout = 5 * time.Minute
+
+// setupCodeDisplay implements pair.SetupCodeProvider by generating a random
+// setup code for every pair setup, which is shown on the display of the accessory.
+type setupCodeDisplay struct {
+	display func(code string)
+	timeout time.Duration
+	clock   util.Clock
+
+	code  string
+	timer util.Timer
+	mutex sync.Mutex
+}
+
+func newSetupCodeDisplay(display func(code string), timeout time.Duration, clock util.Clock) *setupCodeDisplay {
+	if timeout <= 0 {
+		timeout = defaultSetupCodeTimeout
+	}
+
+	return &setupCodeDisplay{
+		display: display,
+		timeout: timeout,
+		clock:   clock,
+	}
+}
+
+func (d *setupCodeDisplay) NewSetupCode() (string, error) {
+	code, err := NewRandomPin()
+	if err != nil {
+		return "", err
+	}
+
+	d.mutex.Lock()
+	d.stopTimer()
+	d.code = code
+	d.timer = d.clock.AfterFunc(d.timeout, func() {
+		d.expire(code)
+	})
+	d.mutex.Unlock()
+
+	d.display(code)
+
+	return code, nil
+}
+
+func (d *setupCodeDisplay) IsValid(code string) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return len(code) > 0 && code == d.code
+}
+
+// Invalidate invalidates the current setup code.
+func (d *setupCodeDisplay) Invalidate() {
+	d.mutex.Lock()
+	if len(d.code) == 0 {
+		d.mutex.Unlock()
+		return
+	}
+	d.stopTimer()
+	d.code = ""
+	d.mutex.Unlock()
+
+	d.display("")
+}
+
+// expire invalidates code, if it is still the current setup code.
+func (d *setupCodeDisplay) expire(code string) {
+	if d.IsValid(code) == true {
+		log.Println("[INFO] Setup code expired")
+		d.Invalidate()
+	}
+}
+
+// stopTimer stops the expiration timer. The caller must hold the mutex.
+func (d *setupCodeDisplay) stopTimer() {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+}
+
+// pairSetupProgress invalidates the setup code when pair setup finished
+// and reports the progress to the PairSetupProgress function of the config.
+func (t *ipTransport) pairSetupProgress(p pair.SetupProgress) {
+	if t.setupCodes != nil && (p.Done() == true || p.Err != nil) {
+		t.setupCodes.Invalidate()
+	}
+
+	if f := t.currentConfig().PairSetupProgress; f != nil {
+		f(p)
+	}
+}
@@ -0,0 +1,91 @@
+package hap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brutella/hc/netio/pair"
+	"github.com/brutella/hc/util"
+)
+
+func TestSetupCodeDisplay(t *testing.T) {
+	var displayed []string
+	d := newSetupCodeDisplay(func(code string) {
+		displayed = append(displayed, code)
+	}, time.Minute, util.SystemClock)
+
+	code, err := d.NewSetupCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.IsValid(code) == false {
+		t.Fatal("expected code to be valid")
+	}
+
+	// A new code replaces the previous code
+	next, err := d.NewSetupCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if next != code && d.IsValid(code) == true {
+		t.Fatal("expected previous code to be invalid")
+	}
+
+	d.Invalidate()
+	if d.IsValid(next) == true {
+		t.Fatal("expected code to be invalid")
+	}
+
+	if is, want := len(displayed), 3; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := displayed[2], ""; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestSetupCodeExpires(t *testing.T) {
+	cleared := make(chan struct{})
+	d := newSetupCodeDisplay(func(code string) {
+		if code == "" {
+			close(cleared)
+		}
+	}, 10*time.Millisecond, util.SystemClock)
+
+	code, err := d.NewSetupCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-cleared:
+	case <-time.After(time.Second):
+		t.Fatal("expected code to expire")
+	}
+
+	if d.IsValid(code) == true {
+		t.Fatal("expected code to be invalid")
+	}
+}
+
+func TestSetupCodeInvalidatedAfterPairing(t *testing.T) {
+	transport := newTestTransport(t)
+	transport.setupCodes = newSetupCodeDisplay(func(string) {}, time.Minute, util.SystemClock)
+
+	code, err := transport.setupCodes.NewSetupCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transport.pairSetupProgress(pair.SetupProgress{Step: pair.PairStepVerifyResponse})
+	if transport.setupCodes.IsValid(code) == false {
+		t.Fatal("expected code to be valid")
+	}
+
+	transport.pairSetupProgress(pair.SetupProgress{Step: pair.PairStepKeyExchangeResponse})
+	if transport.setupCodes.IsValid(code) == true {
+		t.Fatal("expected code to be invalid")
+	}
+}
@@ -0,0 +1,288 @@
+package hap
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brutella/hc/netio/pair"
+	"github.com/brutella/hc/util"
+	"github.com/brutella/log"
+)
+
+// SetupCode is a revocable setup code, e.g. an installer code or an owner code.
+type SetupCode struct {
+	// Label identifies the code, e.g. "installer"
+	Label string `json:"label"`
+
+	// Code is the 8-digit setup code, e.g. "48291735" or "482-91-735".
+	// The code is not stored in plaintext and is empty in the codes returned by Transport.SetupCodes.
+	Code string `json:"-"`
+
+	// Active makes the code the one which is used for pair setup (see Transport.AddSetupCode).
+	Active bool `json:"active,omitempty"`
+
+	// Expires is the time when the code expires. The code doesn't expire when the time is zero.
+	Expires time.Time `json:"expires,omitempty"`
+}
+
+// expired returns true when the code is expired at now.
+func (c SetupCode) expired(now time.Time) bool {
+	return c.Expires.IsZero() == false && now.After(c.Expires) == true
+}
+
+// storedSetupCode is a setup code in the storage, which is stored as SRP salt and verifier.
+type storedSetupCode struct {
+	SetupCode
+	Salt     []byte `json:"salt"`
+	Verifier []byte `json:"verifier"`
+
+	// PlainCode is the code of a setup code which was stored in plaintext;
+	// the code is replaced by a salt and verifier when the codes are read.
+	PlainCode string `json:"code,omitempty"`
+}
+
+// setupCodesKey is the storage key of the setup codes
+const setupCodesKey = "setup-codes"
+
+// setupCodes implements pair.SetupVerifierProvider for the setup codes in a storage.
+//
+// A pair setup can only be verified with one code, because the server public key (M2)
+// is derived from the code. Therefore only one code is active per pair setup attempt:
+// the valid code which was added as Active, or the first valid code by label when no valid
+// code is marked active. Other valid codes are not accepted until they become active.
+// When there are no valid codes, the pin or setup verifier of the device is used.
+type setupCodes struct {
+	storage util.Storage
+	clock   util.Clock
+	mutex   sync.Mutex
+}
+
+func newSetupCodes(storage util.Storage, clock util.Clock) *setupCodes {
+	return &setupCodes{storage: storage, clock: clock}
+}
+
+// Add adds c or replaces the code with the same label.
+// When c is active, the other codes are not active anymore.
+func (s *setupCodes) Add(c SetupCode) error {
+	if len(c.Label) == 0 {
+		return errors.New("Invalid empty setup code label")
+	}
+
+	code, err := NewPin(strings.Replace(c.Code, "-", "", -1))
+	if err != nil {
+		return err
+	}
+
+	stored, err := newStoredSetupCode(c, code)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var codes []storedSetupCode
+	for _, other := range s.codes() {
+		if other.Label == c.Label {
+			continue
+		}
+		if c.Active == true {
+			other.Active = false
+		}
+		codes = append(codes, other)
+	}
+
+	return s.save(append(codes, stored))
+}
+
+// Revoke removes the code with label.
+func (s *setupCodes) Revoke(label string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	codes := s.codes()
+	for i, c := range codes {
+		if c.Label == label {
+			log.Println("[INFO] Revoked setup code", label)
+			return s.save(append(codes[:i], codes[i+1:]...))
+		}
+	}
+
+	return fmt.Errorf("Unknown setup code %s", label)
+}
+
+// Valid returns the codes which are not expired, sorted by label.
+// The codes don't contain the code itself.
+func (s *setupCodes) Valid() []SetupCode {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var codes []SetupCode
+	for _, c := range s.valid() {
+		codes = append(codes, c.SetupCode)
+	}
+
+	return codes
+}
+
+// NewSetupCode returns an empty code, because the codes are not stored in plaintext.
+// The pair setup uses NewSetupVerifier instead.
+func (s *setupCodes) NewSetupCode() (string, error) {
+	return "", nil
+}
+
+// IsValid returns true when code is one of the valid codes.
+func (s *setupCodes) IsValid(code string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, c := range s.valid() {
+		if subtle.ConstantTimeCompare(pair.SetupVerifier(code, c.Salt), c.Verifier) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewSetupVerifier returns the label, salt and verifier of the active code.
+func (s *setupCodes) NewSetupVerifier() (string, []byte, []byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	c, ok := s.active()
+	if ok == false {
+		return "", nil, nil, nil
+	}
+
+	return c.Label, c.Salt, c.Verifier, nil
+}
+
+// IsActive returns true when the code with label is the active code.
+func (s *setupCodes) IsActive(label string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	c, ok := s.active()
+	return ok == true && c.Label == label
+}
+
+// active returns the active code. The caller must hold the mutex.
+func (s *setupCodes) active() (storedSetupCode, bool) {
+	codes := s.valid()
+	if len(codes) == 0 {
+		return storedSetupCode{}, false
+	}
+
+	for _, c := range codes {
+		if c.Active == true {
+			return c, true
+		}
+	}
+
+	return codes[0], true
+}
+
+// valid returns the codes which are not expired, sorted by label. The caller must hold the mutex.
+func (s *setupCodes) valid() []storedSetupCode {
+	now := s.clock.Now()
+
+	var valid []storedSetupCode
+	for _, c := range s.codes() {
+		if c.expired(now) == false {
+			valid = append(valid, c)
+		}
+	}
+
+	sort.Stable(byLabel(valid))
+
+	return valid
+}
+
+// codes returns the stored codes. Codes which were stored in plaintext are
+// replaced by their salt and verifier. The caller must hold the mutex.
+func (s *setupCodes) codes() []storedSetupCode {
+	b, err := s.storage.Get(setupCodesKey)
+	if err != nil || len(b) == 0 {
+		return nil
+	}
+
+	var codes []storedSetupCode
+	if err := json.Unmarshal(b, &codes); err != nil {
+		log.Println("[WARN] Invalid setup codes", err)
+		return nil
+	}
+
+	migrated := false
+	for i, c := range codes {
+		if len(c.PlainCode) == 0 {
+			continue
+		}
+
+		stored, err := newStoredSetupCode(c.SetupCode, c.PlainCode)
+		if err != nil {
+			log.Println("[WARN] Invalid setup code", c.Label, err)
+			continue
+		}
+		codes[i] = stored
+		migrated = true
+	}
+
+	if migrated == true {
+		log.Println("[INFO] Replacing plaintext setup codes by verifiers")
+		if err := s.save(codes); err != nil {
+			log.Println("[WARN] Could not save setup codes", err)
+		}
+	}
+
+	return codes
+}
+
+// save stores codes. The caller must hold the mutex.
+func (s *setupCodes) save(codes []storedSetupCode) error {
+	b, err := json.Marshal(codes)
+	if err != nil {
+		return err
+	}
+
+	return s.storage.Set(setupCodesKey, b)
+}
+
+// newStoredSetupCode returns c with a random salt and the verifier of code (e.g. "482-91-735").
+func newStoredSetupCode(c SetupCode, code string) (storedSetupCode, error) {
+	salt, verifier, err := pair.NewSetupVerifier(code)
+	if err != nil {
+		return storedSetupCode{}, err
+	}
+
+	c.Code = ""
+
+	return storedSetupCode{SetupCode: c, Salt: salt, Verifier: verifier}, nil
+}
+
+type byLabel []storedSetupCode
+
+func (cs byLabel) Len() int           { return len(cs) }
+func (cs byLabel) Swap(i, j int)      { cs[i], cs[j] = cs[j], cs[i] }
+func (cs byLabel) Less(i, j int) bool { return cs[i].Label < cs[j].Label }
+
+// AddSetupCode adds a revocable setup code.
+func (t *ipTransport) AddSetupCode(c SetupCode) error {
+	return t.codes.Add(c)
+}
+
+// RevokeSetupCode removes the setup code with label.
+func (t *ipTransport) RevokeSetupCode(label string) error {
+	return t.codes.Revoke(label)
+}
+
+// SetupCodes returns the setup codes which are not expired.
+func (t *ipTransport) SetupCodes() []SetupCode {
+	return t.codes.Valid()
+}
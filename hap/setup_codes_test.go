@@ -0,0 +1,142 @@
+package hap
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brutella/hc/netio/pair"
+	"github.com/brutella/hc/util"
+)
+
+func newTestSetupCodes(t *testing.T) *setupCodes {
+	storage, err := util.NewTempFileStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return newSetupCodes(storage, util.SystemClock)
+}
+
+func TestSetupCodes(t *testing.T) {
+	codes := newTestSetupCodes(t)
+
+	if label, _, _, err := codes.NewSetupVerifier(); err != nil || len(label) > 0 {
+		t.Fatal("expected no code", label, err)
+	}
+
+	if err := codes.Add(SetupCode{Label: "owner", Code: "48291735"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := codes.Add(SetupCode{Label: "installer", Code: "193-84-726", Expires: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := codes.Add(SetupCode{Label: "old", Code: "29384756", Expires: time.Now().Add(-time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := len(codes.Valid()), 2; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := codes.IsValid("482-91-735"), true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := codes.IsValid("293-84-756"), false; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// The first valid code by label is active when no code is marked active
+	label, _, _, _ := codes.NewSetupVerifier()
+	if is, want := label, "installer"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if err := codes.Add(SetupCode{Label: "owner", Code: "48291735", Active: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	label, _, _, _ = codes.NewSetupVerifier()
+	if is, want := label, "owner"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := codes.IsActive("installer"), false; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if err := codes.Revoke("installer"); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := codes.IsValid("193-84-726"), false; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if err := codes.Revoke("installer"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestInvalidSetupCode(t *testing.T) {
+	codes := newTestSetupCodes(t)
+
+	if err := codes.Add(SetupCode{Label: "owner", Code: "1234"}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if err := codes.Add(SetupCode{Code: "48291735"}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestSetupCodesAreNotStoredInPlaintext(t *testing.T) {
+	codes := newTestSetupCodes(t)
+	if err := codes.Add(SetupCode{Label: "owner", Code: "48291735"}); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := codes.storage.Get(setupCodesKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(b), "482-91-735") == true {
+		t.Fatal("unexpected plaintext code", string(b))
+	}
+
+	if is, want := len(codes.Valid()[0].Code), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestPlaintextSetupCodesAreMigrated(t *testing.T) {
+	codes := newTestSetupCodes(t)
+	codes.storage.Set(setupCodesKey, []byte(`[{"label":"owner","code":"482-91-735"}]`))
+
+	if is, want := codes.IsValid("482-91-735"), true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	b, _ := codes.storage.Get(setupCodesKey)
+	if strings.Contains(string(b), "482-91-735") == true {
+		t.Fatal("unexpected plaintext code", string(b))
+	}
+}
+
+func TestActiveSetupCodeAfterWrongCode(t *testing.T) {
+	transport := newTestTransport(t)
+	transport.codes = newTestSetupCodes(t)
+	transport.codes.Add(SetupCode{Label: "installer", Code: "19384726"})
+	transport.codes.Add(SetupCode{Label: "owner", Code: "48291735"})
+
+	transport.pairSetupProgress(pair.SetupProgress{Step: pair.PairStepVerifyResponse, Err: pair.ErrInvalidSetupCode})
+
+	label, _, _, _ := transport.codes.NewSetupVerifier()
+	if is, want := label, "installer"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,65 @@
+package hap
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSetupURI(t *testing.T) {
+	p := SetupPayload{
+		Pin:      "00102003",
+		SetupID:  "1QJ8",
+		Category: 5,
+		Flags:    SetupFlagIP,
+	}
+
+	uri, err := p.URI()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := uri, "X-HM://00520NTRN1QJ8"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestSetupNDEF(t *testing.T) {
+	p := SetupPayload{
+		Pin:      "00102003",
+		SetupID:  "1QJ8",
+		Category: 5,
+		Flags:    SetupFlagIP | SetupFlagNFC,
+	}
+
+	uri, err := p.URI()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := p.NDEF()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := []byte{0xD1, 0x01, byte(len(uri) + 1), 'U', 0x00}
+	if is, want := b[:5], header; bytes.Equal(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := string(b[5:]), uri; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestInvalidSetupID(t *testing.T) {
+	p := SetupPayload{Pin: "00102003", SetupID: "1qj8"}
+	if _, err := p.URI(); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestSetupHash(t *testing.T) {
+	if is, want := SetupHash("1QJ8", "12:34:56:78:9A:BC"), "mG9CAA=="; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
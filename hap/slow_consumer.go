@@ -0,0 +1,103 @@
+package hap
+
+import (
+	"net"
+	"time"
+
+	"github.com/brutella/hc/event"
+	"github.com/brutella/hc/netio"
+	"github.com/brutella/log"
+)
+
+const (
+	// defaultSlowConsumerBytes is the number of unacknowledged bytes above which a connection is slow
+	defaultSlowConsumerBytes = 32 * 1024
+
+	// defaultSlowConsumerTimeout is the duration after which a slow connection is closed
+	defaultSlowConsumerTimeout = 30 * time.Second
+)
+
+// slowConsumers tracks since when connections have too many unacknowledged bytes.
+type slowConsumers struct {
+	threshold int
+	timeout   time.Duration
+	since     map[net.Conn]time.Time
+}
+
+func newSlowConsumers(threshold int, timeout time.Duration) *slowConsumers {
+	if threshold <= 0 {
+		threshold = defaultSlowConsumerBytes
+	}
+
+	if timeout <= 0 {
+		timeout = defaultSlowConsumerTimeout
+	}
+
+	return &slowConsumers{
+		threshold: threshold,
+		timeout:   timeout,
+		since:     map[net.Conn]time.Time{},
+	}
+}
+
+// interval returns the interval in which the connections are checked.
+func (s *slowConsumers) interval() time.Duration {
+	if d := s.timeout / 4; d > time.Second {
+		return d
+	}
+
+	return time.Second
+}
+
+// check returns the connections of conns, which had too many unacknowledged bytes
+// for longer than the timeout at now.
+func (s *slowConsumers) check(conns []net.Conn, now time.Time) []net.Conn {
+	active := map[net.Conn]bool{}
+	var slow []net.Conn
+	for _, conn := range conns {
+		active[conn] = true
+
+		fc, ok := conn.(netio.FlowConn)
+		if ok == false || fc.FlowStats().Unacknowledged < s.threshold {
+			delete(s.since, conn)
+			continue
+		}
+
+		since, ok := s.since[conn]
+		if ok == false {
+			s.since[conn] = now
+			continue
+		}
+
+		if now.Sub(since) >= s.timeout {
+			slow = append(slow, conn)
+			delete(s.since, conn)
+		}
+	}
+
+	// Forget closed connections
+	for conn := range s.since {
+		if active[conn] == false {
+			delete(s.since, conn)
+		}
+	}
+
+	return slow
+}
+
+// evictSlowConsumers closes the connections which didn't acknowledge the sent data for too long.
+func (t *ipTransport) evictSlowConsumers() {
+	for _, conn := range t.consumers.check(t.context.ActiveConnections(), t.currentConfig().Clock.Now()) {
+		ev := event.ConsumerEvicted{Addr: conn.RemoteAddr().String()}
+		if fc, ok := conn.(netio.FlowConn); ok == true {
+			ev.Unacknowledged = fc.FlowStats().Unacknowledged
+		}
+		if info, ok := t.context.Connections().Info(conn); ok == true {
+			ev.Controller = info.Controller
+		}
+
+		log.Printf("[WARN] Closing connection to %s which didn't acknowledge %d bytes\n", ev.Addr, ev.Unacknowledged)
+		conn.Close()
+		t.emitter.Emit(ev)
+	}
+}
@@ -0,0 +1,61 @@
+package hap
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/brutella/hc/netio"
+)
+
+type flowConn struct {
+	net.Conn
+	unacknowledged int
+}
+
+func (c *flowConn) FlowStats() netio.FlowStats {
+	return netio.FlowStats{Unacknowledged: c.unacknowledged}
+}
+
+func TestSlowConsumers(t *testing.T) {
+	s := newSlowConsumers(100, time.Minute)
+	slow := &flowConn{unacknowledged: 200}
+	fast := &flowConn{unacknowledged: 10}
+	conns := []net.Conn{slow, fast}
+
+	now := time.Now()
+	if is, want := len(s.check(conns, now)), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := len(s.check(conns, now.Add(30*time.Second))), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	evicted := s.check(conns, now.Add(time.Minute))
+	if is, want := len(evicted), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := evicted[0], net.Conn(slow); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestSlowConsumerRecovers(t *testing.T) {
+	s := newSlowConsumers(100, time.Minute)
+	conn := &flowConn{unacknowledged: 200}
+	conns := []net.Conn{conn}
+
+	now := time.Now()
+	s.check(conns, now)
+
+	// The peer acknowledged the data
+	conn.unacknowledged = 0
+	s.check(conns, now.Add(30*time.Second))
+
+	conn.unacknowledged = 200
+	if is, want := len(s.check(conns, now.Add(time.Minute))), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,98 @@
+package hap
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Status is a snapshot of the state of a transport.
+// It is used by supervisors and health checks to detect a transport which stopped working.
+type Status struct {
+	// Port is the port on which the transport is reachable, or 0 when the transport wasn't started
+	Port int
+
+	// Advertising is true when the transport is published via mDNS
+	Advertising bool
+
+	// Paired is the number of paired controllers
+	Paired int
+
+	// ActiveConnections is the number of open connections
+	ActiveConnections int
+
+	// LastAnnounce is the time when the mDNS service was last published or updated.
+	// The time is zero when the service was never published.
+	LastAnnounce time.Time
+
+	// StorageErr is the error when the storage could not be read, otherwise nil
+	StorageErr error
+
+	// Uptime is the duration since the transport started
+	Uptime time.Duration
+}
+
+// Healthy returns true when the transport advertises the accessory and its storage works.
+func (s Status) Healthy() bool {
+	return s.Advertising == true && s.StorageErr == nil
+}
+
+// healthKey is the storage key which is used to check the storage health
+const healthKey = "health"
+
+// Status returns the current status of the transport.
+func (t *ipTransport) Status() Status {
+	s := Status{
+		ActiveConnections: len(t.context.ActiveConnections()),
+		StorageErr:        t.checkStorage(),
+	}
+
+	// The transport itself is stored in the database and isn't a paired controller
+	if es, err := t.database.Entities(); err == nil && len(es) > 1 {
+		s.Paired = len(es) - 1
+	}
+
+	if mdns := t.currentMDNS(); mdns != nil {
+		s.Advertising = mdns.IsPublished()
+		s.LastAnnounce = mdns.LastAnnounce()
+	}
+
+	select {
+	case <-t.ready:
+		s.Port = t.listenPort()
+	default:
+	}
+
+	if t.started.IsZero() == false {
+		s.Uptime = t.currentConfig().Clock.Now().Sub(t.started)
+	}
+
+	return s
+}
+
+// checkStorage reads a value from the storage and returns an error
+// when the storage doesn't work. The value is only written when it doesn't exist yet,
+// because the status is requested periodically (e.g. by the systemd watchdog)
+// and frequent writes wear out flash storage.
+func (t *ipTransport) checkStorage() error {
+	if read, err := t.storage.Get(healthKey); err == nil && len(read) > 0 {
+		return nil
+	}
+
+	b := []byte(strconv.FormatInt(time.Now().UnixNano(), 10))
+	if err := t.storage.Set(healthKey, b); err != nil {
+		return err
+	}
+
+	read, err := t.storage.Get(healthKey)
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(read, b) == false {
+		return errors.New("Storage returned wrong value")
+	}
+
+	return nil
+}
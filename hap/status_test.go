@@ -0,0 +1,78 @@
+package hap
+
+import (
+	"testing"
+
+	"github.com/brutella/hc/db"
+	"github.com/brutella/hc/netio"
+	"github.com/brutella/hc/util"
+)
+
+func newTestTransport(t testing.TB) *ipTransport {
+	storage, err := util.NewTempFileStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	database := db.NewDatabaseWithStorage(storage)
+	device, err := netio.NewSecuredDevice("Test", "001-02-003", database)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &ipTransport{
+		storage:  storage,
+		database: database,
+		device:   device,
+		context:  netio.NewContextForSecuredDevice(device),
+		ready:    make(chan struct{}),
+	}
+}
+
+func TestStatus(t *testing.T) {
+	transport := newTestTransport(t)
+
+	s := transport.Status()
+	if s.StorageErr != nil {
+		t.Fatal(s.StorageErr)
+	}
+
+	if is, want := s.Paired, 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := s.Advertising, false; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := s.Healthy(), false; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	transport.database.SaveEntity(db.NewEntity("Controller", []byte{0x01}, nil))
+
+	if is, want := transport.Status().Paired, 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestStatusDoesNotRewriteStorage(t *testing.T) {
+	transport := newTestTransport(t)
+	transport.Status()
+
+	b, err := transport.storage.Get(healthKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transport.Status()
+
+	read, err := transport.storage.Get(healthKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := string(read), string(b); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
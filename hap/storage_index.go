@@ -0,0 +1,135 @@
+package hap
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/brutella/hc/netio"
+	"github.com/brutella/hc/util"
+	"github.com/brutella/log"
+)
+
+// DefaultStorageRoot is the directory which contains the storage of transports
+// without a storage path. The storage of every transport is a folder named by its device id.
+const DefaultStorageRoot = "hc"
+
+// storageIndexKey is the key of the index, which maps accessories to device ids
+const storageIndexKey = "index"
+
+// storageIndex finds the storage of an accessory by its identity (serial number or name)
+// inside a root directory.
+type storageIndex struct {
+	root    string
+	storage util.Storage
+}
+
+// newStorageIndex returns the index of the storages in root.
+func newStorageIndex(root string) (*storageIndex, error) {
+	storage, err := util.NewFileStorage(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storageIndex{root: root, storage: storage}, nil
+}
+
+// storageKey returns the key by which the storage of the accessory with name and serial number is found.
+// The serial number is used when available, because it doesn't change when the accessory is renamed.
+func storageKey(name, serial string) string {
+	if len(serial) > 0 {
+		return "serial:" + serial
+	}
+
+	return "name:" + name
+}
+
+// entries returns the device ids by key.
+func (i *storageIndex) entries() map[string]string {
+	entries := map[string]string{}
+	if b, err := i.storage.Get(storageIndexKey); err == nil && len(b) > 0 {
+		if err := json.Unmarshal(b, &entries); err != nil {
+			log.Println("[WARN] Invalid storage index", err)
+		}
+	}
+
+	return entries
+}
+
+// set stores the device id for key.
+func (i *storageIndex) set(key, id string) error {
+	entries := i.entries()
+	entries[key] = id
+
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	return i.storage.Set(storageIndexKey, b)
+}
+
+// rename moves the device id of key to a new key.
+func (i *storageIndex) rename(key, newKey string) error {
+	entries := i.entries()
+	id, ok := entries[key]
+	if ok == false {
+		return nil
+	}
+
+	delete(entries, key)
+	entries[newKey] = id
+
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	return i.storage.Set(storageIndexKey, b)
+}
+
+// path returns the storage path of the accessory with name and key.
+// The storage of previous versions, which is a folder named like the accessory in legacy,
+// is moved to the root. A new device id is created when the accessory has no storage yet.
+func (i *storageIndex) path(key, name, legacy string) (string, error) {
+	if id, ok := i.entries()[key]; ok == true {
+		return i.dir(id), nil
+	}
+
+	old := filepath.Join(legacy, name)
+	if filepath.Clean(old) == filepath.Clean(i.root) {
+		old = ""
+	}
+
+	if storage, err := util.NewReadOnlyFileStorage(old); err == nil {
+		if b, err := storage.Get("uuid"); err == nil && len(b) > 0 {
+			id := string(b)
+			dir := i.dir(id)
+			log.Println("[INFO] Moving storage", old, "to", dir)
+			if err := os.Rename(old, dir); err != nil {
+				return "", err
+			}
+
+			return dir, i.set(key, id)
+		}
+	}
+
+	id := netio.MAC48Address(util.RandomHexString())
+	dir := i.dir(id)
+	storage, err := util.NewFileStorage(dir)
+	if err != nil {
+		return "", err
+	}
+
+	if err := storage.Set("uuid", []byte(id)); err != nil {
+		return "", err
+	}
+
+	return dir, i.set(key, id)
+}
+
+// dir returns the storage path of the device with id.
+func (i *storageIndex) dir(id string) string {
+	return filepath.Join(i.root, strings.Replace(id, ":", "", -1))
+}
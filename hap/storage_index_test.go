@@ -0,0 +1,139 @@
+package hap
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/brutella/hc/util"
+)
+
+func TestStorageIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "index")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	index, err := newStorageIndex(filepath.Join(dir, DefaultStorageRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := storageKey("Lamp", "")
+	path, err := index.path(key, "Lamp", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storage, err := util.NewFileStorage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := transportUUIDInStorage(storage), index.entries()[key]; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// The storage is found after renaming
+	if err := index.rename(key, storageKey("Light", "")); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := index.path(storageKey("Light", ""), "Light", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := p, path; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestStorageIndexMigration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "index")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The storage of previous versions is named like the accessory
+	legacy, err := util.NewFileStorage(filepath.Join(dir, "Lamp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy.Set("uuid", []byte("AA:BB:CC:DD:EE:FF"))
+	legacy.Set("configuration", []byte("5"))
+
+	index, err := newStorageIndex(filepath.Join(dir, DefaultStorageRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := index.path(storageKey("Lamp", "001"), "Lamp", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := path, filepath.Join(dir, DefaultStorageRoot, "AABBCCDDEEFF"); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "Lamp")); os.IsNotExist(err) == false {
+		t.Fatal("expected legacy storage to be moved")
+	}
+
+	storage, err := util.NewFileStorage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := configurationInStorage(storage), int64(5); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestRename(t *testing.T) {
+	dir, err := ioutil.TempDir("", "index")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	index, err := newStorageIndex(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := storageKey("Lamp", "")
+	path, err := index.path(key, "Lamp", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transport := newTestTransport(t)
+	transport.name = "Lamp"
+	transport.storageIndex = index
+	transport.storageKey = key
+	transport.mdns = NewMDNSService("Lamp", "AA:BB:CC:DD:EE:FF", "127.0.0.1", 12345, 1)
+
+	transport.rename("Light")
+
+	if is, want := transport.mdns.Name(), "Light"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := transport.configuration, int64(1); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	p, err := index.path(storageKey("Light", ""), "Light", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := p, path; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,71 @@
+package hap
+
+import (
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/netio"
+)
+
+// subscribeSnapshots sends an event with the current value of a characteristic
+// to a connection, which enabled events of the characteristic.
+//
+// The events are sent after the response of the write request was written,
+// because a controller doesn't expect an event in the middle of a response.
+type subscribeSnapshots struct {
+	container *accessory.Container
+
+	mutex   sync.Mutex
+	pending map[net.Conn][]netio.Subscription
+}
+
+func newSubscribeSnapshots(container *accessory.Container) *subscribeSnapshots {
+	return &subscribeSnapshots{
+		container: container,
+		pending:   map[net.Conn][]netio.Subscription{},
+	}
+}
+
+// subscribed remembers that conn enabled events for the characteristic with the ids aid and iid.
+func (s *subscribeSnapshots) subscribed(conn net.Conn, aid, iid int64, enabled bool) {
+	if enabled == false || conn == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	s.pending[conn] = append(s.pending[conn], netio.Subscription{AID: aid, IID: iid})
+	s.mutex.Unlock()
+}
+
+// connState sends the pending events when the response to conn was written,
+// and drops them when conn is closed.
+func (s *subscribeSnapshots) connState(conn net.Conn, state http.ConnState) {
+	switch state {
+	case http.StateIdle:
+		for _, sub := range s.take(conn) {
+			a := s.container.AccessoryByAID(sub.AID)
+			if a == nil {
+				continue
+			}
+
+			if c := s.container.CharacteristicByIDs(sub.AID, sub.IID); c != nil {
+				sendEvent(conn, a, c)
+			}
+		}
+	case http.StateClosed, http.StateHijacked:
+		s.take(conn)
+	}
+}
+
+// take returns and removes the pending events of conn.
+func (s *subscribeSnapshots) take(conn net.Conn) []netio.Subscription {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	subs := s.pending[conn]
+	delete(s.pending, conn)
+
+	return subs
+}
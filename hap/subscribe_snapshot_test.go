@@ -0,0 +1,60 @@
+package hap
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/brutella/hc/accessory"
+)
+
+func TestSubscribeSnapshot(t *testing.T) {
+	container := accessory.NewContainer()
+	l := accessory.NewLightbulb(accessory.Info{Name: "Lightbulb"})
+	container.AddAccessory(l.Accessory)
+	l.Lightbulb.On.SetValue(true)
+
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	s := newSubscribeSnapshots(container)
+	aid, iid := l.Accessory.GetID(), l.Lightbulb.On.GetID()
+	s.subscribed(server, aid, iid, true)
+
+	line := make(chan string)
+	go func() {
+		str, _ := bufio.NewReader(client).ReadString('\n')
+		line <- str
+	}()
+
+	s.connState(server, http.StateIdle)
+
+	if is := <-line; strings.HasPrefix(is, "EVENT/1.0 200 OK") == false {
+		t.Fatalf("invalid event %s", is)
+	}
+
+	if is, want := len(s.pending), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestSubscribeSnapshotClosed(t *testing.T) {
+	s := newSubscribeSnapshots(accessory.NewContainer())
+
+	server, client := net.Pipe()
+	defer client.Close()
+
+	s.subscribed(server, 1, 10, true)
+	s.subscribed(server, 1, 11, false)
+	if is, want := len(s.pending[server]), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	s.connState(server, http.StateClosed)
+	if is, want := len(s.pending), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
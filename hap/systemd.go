@@ -0,0 +1,80 @@
+package hap
+
+import (
+	"github.com/brutella/hc/systemd"
+	"github.com/brutella/log"
+
+	"net"
+)
+
+// activatedListener returns the first TCP socket which is passed by systemd (socket activation).
+// It returns nil when the process was not started by socket activation.
+func activatedListener() *net.TCPListener {
+	for _, ln := range systemd.Listeners() {
+		if tcp, ok := ln.(*net.TCPListener); ok == true {
+			log.Println("[INFO] Using socket", tcp.Addr(), "passed by systemd")
+			return tcp
+		}
+		ln.Close()
+	}
+
+	return nil
+}
+
+// activatedPacketConn returns the first UDP socket which is passed by systemd (socket activation).
+// It returns nil when the process was not started by socket activation.
+func activatedPacketConn() *net.UDPConn {
+	for _, c := range systemd.PacketConns() {
+		if udp, ok := c.(*net.UDPConn); ok == true {
+			log.Println("[INFO] Using socket", udp.LocalAddr(), "passed by systemd")
+			return udp
+		}
+		c.Close()
+	}
+
+	return nil
+}
+
+// notifyReady notifies systemd that the transport is ready. If the service
+// has a watchdog, the transport sends keep-alive notifications while it's healthy.
+// The keep-alive notifications of a previous call are canceled.
+func (t *ipTransport) notifyReady() {
+	if _, err := systemd.Notify(systemd.StateReady); err != nil {
+		log.Println("[ERRO] Could not notify systemd", err)
+	}
+
+	t.stopWatchdog()
+
+	if d := systemd.WatchdogInterval(); d > 0 {
+		job := t.scheduler.Schedule(Every(d/2), func() {
+			if s := t.Status(); s.Healthy() == false {
+				log.Printf("[WARN] Transport is unhealthy %+v\n", s)
+				return
+			}
+
+			systemd.Notify(systemd.StateWatchdog)
+		})
+
+		t.watchdogMutex.Lock()
+		t.watchdog = job
+		t.watchdogMutex.Unlock()
+	}
+}
+
+// stopWatchdog cancels the keep-alive notifications to systemd.
+func (t *ipTransport) stopWatchdog() {
+	t.watchdogMutex.Lock()
+	defer t.watchdogMutex.Unlock()
+
+	if t.watchdog != nil {
+		t.watchdog.Cancel()
+		t.watchdog = nil
+	}
+}
+
+// notifyStopping notifies systemd that the transport is stopping.
+func notifyStopping() {
+	if _, err := systemd.Notify(systemd.StateStopping); err != nil {
+		log.Println("[ERRO] Could not notify systemd", err)
+	}
+}
@@ -1,12 +1,86 @@
 package hap
 
-import ()
+import (
+	"github.com/brutella/hc/netio/controller"
+)
 
 // Transport provides accessories over a network.
 type Transport interface {
 	// Start starts the transport
 	Start()
 
-	// Stop stops the transport
+	// Started returns a channel which is closed when the transport accepts connections
+	// and is published via mDNS. Status().Port is the port of the transport afterwards,
+	// e.g. when a random port was used.
+	Started() <-chan struct{}
+
+	// Stop stops the transport and cancels all scheduled jobs
 	Stop()
+
+	// Restart stops the listener and mDNS service and starts them again with the network
+	// configuration of config, e.g. a new Port or Interface. The storage and pairings are not
+	// changed, so controllers don't have to pair again.
+	Restart(config Config) error
+
+	// Schedule runs fn at the times of s, e.g. Every(time.Minute) or After(5*time.Minute),
+	// until the job is canceled or the transport is stopped.
+	Schedule(s Schedule, fn func()) *Job
+
+	// Reverify closes the encrypted sessions of all controllers, which
+	// forces them to verify the pairing again and negotiate new session keys.
+	// This is useful to test long-lived sessions.
+	Reverify()
+
+	// EnablePairing opens the pairing window (see Config.PairingWindow), e.g. when a button
+	// on the accessory is pressed. Pair setup is accepted until the window closes.
+	EnablePairing()
+
+	// AddSetupCode adds a revocable setup code (see Config.SetupCodes), or replaces
+	// the code with the same label. The code is stored as SRP salt and verifier.
+	// Only one code is active per pair setup attempt, e.g. the code which was added as active.
+	AddSetupCode(c SetupCode) error
+
+	// RevokeSetupCode removes the setup code with label. Existing pairings are not removed.
+	RevokeSetupCode(label string) error
+
+	// SetupCodes returns the setup codes which are not expired.
+	SetupCodes() []SetupCode
+
+	// SetChildOnline sets whether the device of the bridged accessory with id aid is online.
+	// When offline, the Status Fault characteristics of the accessory are set to a general fault,
+	// controllers receive events for the changes, and reads fail with a communication failure.
+	SetChildOnline(aid int64, online bool) error
+
+	// RenameAccessory sets the name of the accessory with id aid, e.g. when the user renamed the
+	// device in the application. The name is stored and replaces the name of the accessory on
+	// the next start. The name must be unique within the transport.
+	RenameAccessory(aid int64, name string) error
+
+	// RenameService sets and stores the name of the service with instance id iid of the accessory
+	// with id aid. The name must be unique within the accessory.
+	RenameService(aid, iid int64, name string) error
+
+	// RemovePairing removes the pairing of the controller with username in the same way
+	// as a controller removes a pairing. The removal is recorded in the audit log
+	// and the other controllers verify their pairing again.
+	RemovePairing(username string) error
+
+	// ResetPairings removes the pairings of all controllers, so that the accessory can be
+	// paired again. The reset is recorded in the audit log.
+	ResetPairings() error
+
+	// Announce announces the mDNS service immediately, e.g. after the network changed.
+	Announce()
+
+	// Status returns a snapshot of the transport state, e.g. whether the
+	// transport is advertised and how many controllers are connected.
+	Status() Status
+
+	// Endpoint returns the addresses and identity of the transport, e.g. to register
+	// the accessory in a provisioning system after the transport was started.
+	Endpoint() Endpoint
+
+	// Apply writes values to the characteristics in the same way as a write by a client,
+	// e.g. to set a scene from an automation. See controller.CharacteristicController.Apply.
+	Apply(values map[controller.CharacteristicID]interface{}) error
 }
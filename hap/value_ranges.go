@@ -0,0 +1,68 @@
+package hap
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/log"
+)
+
+// rangesKey is the storage key of the value ranges which were announced to clients
+const rangesKey = "value-ranges"
+
+// valueRange returns the min, max and step value of c.
+func valueRange(c *characteristic.Characteristic) string {
+	return fmt.Sprintf("%v %v %v", c.MinValue, c.MaxValue, c.StepValue)
+}
+
+// updateValueRanges stores the value ranges of the characteristics of as and increments
+// the configuration number once, when a range differs from the stored range.
+// Ranges which are set to the same values at every start don't change the configuration number.
+func (t *ipTransport) updateValueRanges(as ...*accessory.Accessory) {
+	t.rangesMutex.Lock()
+	defer t.rangesMutex.Unlock()
+
+	ranges := map[string]string{}
+	b, err := t.storage.Get(rangesKey)
+	stored := err == nil && len(b) > 0
+	if stored == true {
+		if err := json.Unmarshal(b, &ranges); err != nil {
+			log.Println("[WARN] Invalid value ranges", err)
+		}
+	}
+
+	changed := false
+	for _, a := range as {
+		for _, s := range a.Services {
+			for _, c := range s.Characteristics {
+				if c.MinValue == nil && c.MaxValue == nil && c.StepValue == nil {
+					continue
+				}
+
+				key := nameKey(a.GetID(), c.GetID())
+				if r := valueRange(c); ranges[key] != r {
+					ranges[key] = r
+					changed = true
+				}
+			}
+		}
+	}
+
+	if changed == false {
+		return
+	}
+
+	if b, err = json.Marshal(ranges); err == nil {
+		err = t.storage.Set(rangesKey, b)
+	}
+	if err != nil {
+		log.Println("[ERRO] Could not store value ranges", err)
+	}
+
+	// The ranges of the first start are announced with the initial configuration number
+	if stored == true {
+		t.updateConfiguration()
+	}
+}
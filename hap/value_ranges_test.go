@@ -0,0 +1,42 @@
+package hap
+
+import (
+	"testing"
+
+	"github.com/brutella/hc/accessory"
+)
+
+func newTestTransportWithLightbulb(t *testing.T, transport *ipTransport) *accessory.Lightbulb {
+	transport.container = accessory.NewContainer()
+	acc := accessory.NewLightbulb(accessory.Info{Name: "Lamp"})
+	if err := transport.addAccessory(acc.Accessory); err != nil {
+		t.Fatal(err)
+	}
+
+	return acc
+}
+
+func TestValueRangesAtStart(t *testing.T) {
+	transport := newTestTransport(t)
+	acc := newTestTransportWithLightbulb(t, transport)
+	acc.Lightbulb.Brightness.SetRange(10, 90, 5)
+	transport.updateValueRanges(transport.container.Accessories...)
+
+	if is, want := transport.configurationNumber(), int64(0); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// The same range at the next start doesn't change the configuration number
+	acc = newTestTransportWithLightbulb(t, transport)
+	acc.Lightbulb.Brightness.SetRange(10, 90, 5)
+	transport.updateValueRanges(transport.container.Accessories...)
+
+	if is, want := transport.configurationNumber(), int64(0); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	acc.Lightbulb.Brightness.SetRange(0, 100, 1)
+	if is, want := transport.configurationNumber(), int64(1); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
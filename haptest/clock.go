@@ -0,0 +1,98 @@
+package haptest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/brutella/hc/util"
+)
+
+// Clock is a fake clock (util.Clock) whose time only changes by calling Advance.
+type Clock struct {
+	now    time.Time
+	timers []*timer
+	mutex  sync.Mutex
+}
+
+type timer struct {
+	clock *Clock
+	when  time.Time
+	f     func()
+}
+
+// NewClock returns a clock which starts at now.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+func (c *Clock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *Clock) AfterFunc(d time.Duration, f func()) util.Timer {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	t := &timer{clock: c, when: c.now.Add(d), f: f}
+	c.timers = append(c.timers, t)
+
+	return t
+}
+
+// Advance advances the time by d and calls the functions of the expired timers
+// in the order of their expiration. The functions are called on the calling goroutine.
+func (c *Clock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	end := c.now.Add(d)
+	for {
+		t := c.next(end)
+		if t == nil {
+			break
+		}
+
+		c.now = t.when
+		c.mutex.Unlock()
+		t.f()
+		c.mutex.Lock()
+	}
+	c.now = end
+	c.mutex.Unlock()
+}
+
+// next removes and returns the first timer which expires until end.
+// The caller must hold the mutex.
+func (c *Clock) next(end time.Time) *timer {
+	index := -1
+	for i, t := range c.timers {
+		if t.when.After(end) == false && (index < 0 || t.when.Before(c.timers[index].when)) {
+			index = i
+		}
+	}
+
+	if index < 0 {
+		return nil
+	}
+
+	t := c.timers[index]
+	c.timers = append(c.timers[:index], c.timers[index+1:]...)
+
+	return t
+}
+
+func (t *timer) Stop() bool {
+	c := t.clock
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for i, other := range c.timers {
+		if other == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,40 @@
+package haptest
+
+import (
+	"net"
+)
+
+// Addr is the address of a connection returned by Pipe.
+type Addr string
+
+func (a Addr) Network() string {
+	return "pipe"
+}
+
+func (a Addr) String() string {
+	return string(a)
+}
+
+type conn struct {
+	net.Conn
+	local, remote net.Addr
+}
+
+func (c *conn) LocalAddr() net.Addr {
+	return c.local
+}
+
+func (c *conn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+// Pipe returns a synchronous in-memory connection between a controller and an accessory.
+// Data written to one connection is read from the other one. Unlike net.Pipe, the connections
+// have distinct addresses ("controller" and "accessory").
+func Pipe() (controller net.Conn, accessory net.Conn) {
+	c, a := net.Pipe()
+	controller = &conn{c, Addr("controller"), Addr("accessory")}
+	accessory = &conn{a, Addr("accessory"), Addr("controller")}
+
+	return
+}
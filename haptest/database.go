@@ -0,0 +1,22 @@
+package haptest
+
+import (
+	"github.com/brutella/hc/db"
+	"github.com/brutella/hc/netio"
+)
+
+// NewDatabase returns a database which stores the entities in memory.
+func NewDatabase() db.Database {
+	return db.NewDatabaseWithStorage(NewStorage())
+}
+
+// NewContext returns a context for an accessory named name, which is paired with the pin.
+// The keys of the accessory are stored in an in-memory database.
+func NewContext(name, pin string) (netio.HAPContext, error) {
+	device, err := netio.NewSecuredDevice(name, pin, NewDatabase())
+	if err != nil {
+		return nil, err
+	}
+
+	return netio.NewContextForSecuredDevice(device), nil
+}
@@ -0,0 +1,6 @@
+// Package haptest provides fakes to unit test applications which use the hap package.
+//
+// The fakes don't access the file system or the network and are deterministic:
+// Storage and Database keep their data in memory, Pipe connects a controller
+// and an accessory in memory, and Clock controls the time of scheduled jobs.
+package haptest
@@ -0,0 +1,136 @@
+package haptest
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/db"
+	"github.com/brutella/hc/hap"
+)
+
+func TestStorage(t *testing.T) {
+	s := NewStorage()
+	if _, err := s.Get("key"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	s.Set("key", []byte{0x01})
+	if b, err := s.Get("key"); err != nil || len(b) != 1 {
+		t.Fatal(b, err)
+	}
+
+	s.Err = errors.New("disk full")
+	if err := s.Set("key", []byte{0x02}); err != s.Err {
+		t.Fatal(err)
+	}
+}
+
+func TestDatabase(t *testing.T) {
+	database := NewDatabase()
+	database.SaveEntity(db.NewEntity("Controller", []byte{0x01}, nil))
+
+	es, err := database.Entities()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := len(es), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestContext(t *testing.T) {
+	ctx, err := NewContext("Accessory", "00102003")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := ctx.GetSecuredDevice().Name(), "Accessory"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestPipe(t *testing.T) {
+	controller, accessory := Pipe()
+	defer controller.Close()
+
+	if is, want := accessory.RemoteAddr().String(), "controller"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	go func() {
+		accessory.Write([]byte("hello"))
+		accessory.Close()
+	}()
+
+	b := make([]byte, 5)
+	if _, err := io.ReadFull(controller, b); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := string(b), "hello"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestClockRunsScheduledJobs(t *testing.T) {
+	clock := NewClock(time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := hap.NewSchedulerWithClock(clock)
+	defer s.Stop()
+
+	var runs int
+	job := s.Schedule(hap.Every(time.Minute), func() {
+		runs++
+	})
+
+	clock.Advance(3*time.Minute + 30*time.Second)
+	if is, want := runs, 3; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	job.Cancel()
+	clock.Advance(time.Hour)
+	if is, want := runs, 3; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestClockRunsValveCountdown(t *testing.T) {
+	clock := NewClock(time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC))
+	v := accessory.NewValve(accessory.Info{Name: "Sprinkler"}, characteristic.ValveTypeIrrigation)
+	v.SetClock(clock)
+
+	if is, want := v.Type, accessory.TypeSprinkler; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	v.SetDuration.SetValue(60)
+	v.Valve.Active.SetValue(characteristic.ActiveActive)
+
+	if is, want := v.Valve.InUse.GetValue(), characteristic.InUseInUse; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := v.RemainingDuration.GetValue(), 60; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	clock.Advance(20 * time.Second)
+	if is, want := v.RemainingDuration.GetValue(), 40; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	clock.Advance(40 * time.Second)
+	if is, want := v.Valve.Active.GetValue(), characteristic.ActiveInactive; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := v.Valve.InUse.GetValue(), characteristic.InUseNotInUse; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := v.RemainingDuration.GetValue(), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
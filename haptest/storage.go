@@ -0,0 +1,93 @@
+package haptest
+
+import (
+	"strings"
+	"sync"
+)
+
+// Storage is an in-memory storage (util.Storage).
+type Storage struct {
+	// Err is returned by every method when not nil, e.g. to simulate a full disk
+	Err error
+
+	data  map[string][]byte
+	mutex sync.Mutex
+}
+
+// NewStorage returns an empty storage.
+func NewStorage() *Storage {
+	return &Storage{data: map[string][]byte{}}
+}
+
+func (s *Storage) Set(key string, value []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.Err != nil {
+		return s.Err
+	}
+
+	b := make([]byte, len(value))
+	copy(b, value)
+	s.data[key] = b
+
+	return nil
+}
+
+func (s *Storage) Get(key string) ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.Err != nil {
+		return nil, s.Err
+	}
+
+	v, ok := s.data[key]
+	if ok == false {
+		return nil, &KeyNotFoundError{key}
+	}
+
+	b := make([]byte, len(v))
+	copy(b, v)
+
+	return b, nil
+}
+
+func (s *Storage) Delete(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.Err != nil {
+		return s.Err
+	}
+
+	delete(s.data, key)
+	return nil
+}
+
+func (s *Storage) KeysWithSuffix(suffix string) ([]string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.Err != nil {
+		return nil, s.Err
+	}
+
+	var keys []string
+	for k := range s.data {
+		if strings.HasSuffix(k, suffix) {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys, nil
+}
+
+// KeyNotFoundError is returned when a key is not stored.
+type KeyNotFoundError struct {
+	Key string
+}
+
+func (e *KeyNotFoundError) Error() string {
+	return "No value for key " + e.Key
+}
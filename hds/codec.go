@@ -0,0 +1,406 @@
+package hds
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+// Tags of the HDS data format
+const (
+	tagTrue                 byte = 0x01
+	tagFalse                byte = 0x02
+	tagTerminator           byte = 0x03
+	tagNull                 byte = 0x04
+	tagUUID                 byte = 0x05
+	tagDate                 byte = 0x06
+	tagIntegerMinusOne      byte = 0x07
+	tagIntegerRangeStart    byte = 0x08 // 0
+	tagIntegerRangeStop     byte = 0x2F // 39
+	tagInt8                 byte = 0x30
+	tagInt16                byte = 0x31
+	tagInt32                byte = 0x32
+	tagInt64                byte = 0x33
+	tagFloat32              byte = 0x35
+	tagFloat64              byte = 0x36
+	tagStringLengthStart    byte = 0x40 // length 0
+	tagStringLengthStop     byte = 0x60 // length 32
+	tagStringLength8        byte = 0x61
+	tagStringLength16       byte = 0x62
+	tagStringLength32       byte = 0x63
+	tagStringLength64       byte = 0x64
+	tagStringNullTerminated byte = 0x6F
+	tagDataLengthStart      byte = 0x70 // length 0
+	tagDataLengthStop       byte = 0x90 // length 32
+	tagDataLength8          byte = 0x91
+	tagDataLength16         byte = 0x92
+	tagDataLength32         byte = 0x93
+	tagDataLength64         byte = 0x94
+	tagCompressionStart     byte = 0xA0
+	tagCompressionStop      byte = 0xCF
+	tagArrayLengthStart     byte = 0xD0 // length 0
+	tagArrayLengthStop      byte = 0xDE // length 14
+	tagArrayTerminated      byte = 0xDF
+	tagDictLengthStart      byte = 0xE0 // length 0
+	tagDictLengthStop       byte = 0xEE // length 14
+	tagDictTerminated       byte = 0xEF
+)
+
+// dateReference is the reference date of encoded dates.
+var dateReference = time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// UUID is a 16 byte uuid value.
+type UUID [16]byte
+
+// Encode returns the HDS data format representation of v.
+//
+// Supported types are nil, bool, integers, floats, string, []byte, UUID, time.Time,
+// []interface{} and map[string]interface{}.
+func Encode(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encode(&buf, v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func encode(buf *bytes.Buffer, v interface{}) error {
+	switch t := v.(type) {
+	case nil:
+		buf.WriteByte(tagNull)
+	case bool:
+		if t == true {
+			buf.WriteByte(tagTrue)
+		} else {
+			buf.WriteByte(tagFalse)
+		}
+	case int:
+		encodeInt(buf, int64(t))
+	case int8:
+		encodeInt(buf, int64(t))
+	case int16:
+		encodeInt(buf, int64(t))
+	case int32:
+		encodeInt(buf, int64(t))
+	case int64:
+		encodeInt(buf, t)
+	case uint8:
+		encodeInt(buf, int64(t))
+	case uint16:
+		encodeInt(buf, int64(t))
+	case uint32:
+		encodeInt(buf, int64(t))
+	case uint:
+		if uint64(t) > math.MaxInt64 {
+			return fmt.Errorf("hds: integer %d overflows int64", t)
+		}
+		encodeInt(buf, int64(t))
+	case uint64:
+		if t > math.MaxInt64 {
+			return fmt.Errorf("hds: integer %d overflows int64", t)
+		}
+		encodeInt(buf, int64(t))
+	case float32:
+		buf.WriteByte(tagFloat32)
+		binary.Write(buf, binary.LittleEndian, t)
+	case float64:
+		buf.WriteByte(tagFloat64)
+		binary.Write(buf, binary.LittleEndian, t)
+	case string:
+		encodeLength(buf, uint64(len(t)), tagStringLengthStart, tagStringLengthStop, tagStringLength8)
+		buf.WriteString(t)
+	case []byte:
+		encodeLength(buf, uint64(len(t)), tagDataLengthStart, tagDataLengthStop, tagDataLength8)
+		buf.Write(t)
+	case UUID:
+		buf.WriteByte(tagUUID)
+		buf.Write(t[:])
+	case time.Time:
+		buf.WriteByte(tagDate)
+		binary.Write(buf, binary.LittleEndian, t.Sub(dateReference).Seconds())
+	case []interface{}:
+		if len(t) <= int(tagArrayLengthStop-tagArrayLengthStart) {
+			buf.WriteByte(tagArrayLengthStart + byte(len(t)))
+		} else {
+			buf.WriteByte(tagArrayTerminated)
+		}
+		for _, e := range t {
+			if err := encode(buf, e); err != nil {
+				return err
+			}
+		}
+		if len(t) > int(tagArrayLengthStop-tagArrayLengthStart) {
+			buf.WriteByte(tagTerminator)
+		}
+	case map[string]interface{}:
+		// Sort keys to get a deterministic encoding
+		var keys []string
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		if len(t) <= int(tagDictLengthStop-tagDictLengthStart) {
+			buf.WriteByte(tagDictLengthStart + byte(len(t)))
+		} else {
+			buf.WriteByte(tagDictTerminated)
+		}
+		for _, k := range keys {
+			encode(buf, k)
+			if err := encode(buf, t[k]); err != nil {
+				return err
+			}
+		}
+		if len(t) > int(tagDictLengthStop-tagDictLengthStart) {
+			buf.WriteByte(tagTerminator)
+		}
+	default:
+		return fmt.Errorf("hds: unsupported type %T", v)
+	}
+
+	return nil
+}
+
+func encodeInt(buf *bytes.Buffer, i int64) {
+	switch {
+	case i == -1:
+		buf.WriteByte(tagIntegerMinusOne)
+	case i >= 0 && i < int64(tagIntegerRangeStop-tagIntegerRangeStart):
+		// The last value of the range is not used because
+		// some implementations don't decode it
+		buf.WriteByte(tagIntegerRangeStart + byte(i))
+	case i >= math.MinInt8 && i <= math.MaxInt8:
+		buf.WriteByte(tagInt8)
+		buf.WriteByte(byte(int8(i)))
+	case i >= math.MinInt16 && i <= math.MaxInt16:
+		buf.WriteByte(tagInt16)
+		binary.Write(buf, binary.LittleEndian, int16(i))
+	case i >= math.MinInt32 && i <= math.MaxInt32:
+		buf.WriteByte(tagInt32)
+		binary.Write(buf, binary.LittleEndian, int32(i))
+	default:
+		buf.WriteByte(tagInt64)
+		binary.Write(buf, binary.LittleEndian, i)
+	}
+}
+
+// encodeLength writes the tag for a string or data value of length n.
+func encodeLength(buf *bytes.Buffer, n uint64, start, stop, length8 byte) {
+	switch {
+	case n <= uint64(stop-start):
+		buf.WriteByte(start + byte(n))
+	case n <= math.MaxUint8:
+		buf.WriteByte(length8)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(length8 + 1)
+		binary.Write(buf, binary.LittleEndian, uint16(n))
+	case n <= math.MaxUint32:
+		buf.WriteByte(length8 + 2)
+		binary.Write(buf, binary.LittleEndian, uint32(n))
+	default:
+		buf.WriteByte(length8 + 3)
+		binary.Write(buf, binary.LittleEndian, n)
+	}
+}
+
+var errTerminator = errors.New("hds: unexpected terminator")
+
+// Decode returns the value of the HDS data format representation b.
+//
+// Integers are returned as int64, floats as float64, strings as string, data as []byte,
+// arrays as []interface{} and dictionaries as map[string]interface{}.
+func Decode(b []byte) (interface{}, error) {
+	d := decoder{r: bytes.NewReader(b)}
+	v, err := d.decode()
+	if err == errTerminator {
+		return nil, err
+	}
+
+	return v, err
+}
+
+type decoder struct {
+	r *bytes.Reader
+
+	// Decoded values which can be referenced by compression tags
+	tracked []interface{}
+}
+
+func (d *decoder) decode() (interface{}, error) {
+	tag, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case tag == tagTrue:
+		return true, nil
+	case tag == tagFalse:
+		return false, nil
+	case tag == tagTerminator:
+		return nil, errTerminator
+	case tag == tagNull:
+		return nil, nil
+	case tag == tagUUID:
+		var u UUID
+		if _, err := io.ReadFull(d.r, u[:]); err != nil {
+			return nil, err
+		}
+		return d.track(u), nil
+	case tag == tagDate:
+		var f float64
+		if err := binary.Read(d.r, binary.LittleEndian, &f); err != nil {
+			return nil, err
+		}
+		date := dateReference.Add(time.Duration(f * float64(time.Second)))
+		return d.track(date), nil
+	case tag == tagIntegerMinusOne:
+		return int64(-1), nil
+	case tag >= tagIntegerRangeStart && tag <= tagIntegerRangeStop:
+		return int64(tag - tagIntegerRangeStart), nil
+	case tag == tagInt8:
+		var i int8
+		err = binary.Read(d.r, binary.LittleEndian, &i)
+		return d.track(int64(i)), err
+	case tag == tagInt16:
+		var i int16
+		err = binary.Read(d.r, binary.LittleEndian, &i)
+		return d.track(int64(i)), err
+	case tag == tagInt32:
+		var i int32
+		err = binary.Read(d.r, binary.LittleEndian, &i)
+		return d.track(int64(i)), err
+	case tag == tagInt64:
+		var i int64
+		err = binary.Read(d.r, binary.LittleEndian, &i)
+		return d.track(i), err
+	case tag == tagFloat32:
+		var f float32
+		err = binary.Read(d.r, binary.LittleEndian, &f)
+		return d.track(float64(f)), err
+	case tag == tagFloat64:
+		var f float64
+		err = binary.Read(d.r, binary.LittleEndian, &f)
+		return d.track(f), err
+	case tag >= tagStringLengthStart && tag <= tagStringLengthStop:
+		b, err := d.read(uint64(tag - tagStringLengthStart))
+		return d.track(string(b)), err
+	case tag >= tagStringLength8 && tag <= tagStringLength64:
+		n, err := d.readLength(tag - tagStringLength8)
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.read(n)
+		return d.track(string(b)), err
+	case tag == tagStringNullTerminated:
+		var buf bytes.Buffer
+		for {
+			c, err := d.r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			if c == 0x00 {
+				break
+			}
+			buf.WriteByte(c)
+		}
+		return d.track(buf.String()), nil
+	case tag >= tagDataLengthStart && tag <= tagDataLengthStop:
+		b, err := d.read(uint64(tag - tagDataLengthStart))
+		return d.track(b), err
+	case tag >= tagDataLength8 && tag <= tagDataLength64:
+		n, err := d.readLength(tag - tagDataLength8)
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.read(n)
+		return d.track(b), err
+	case tag >= tagCompressionStart && tag <= tagCompressionStop:
+		i := int(tag - tagCompressionStart)
+		if i >= len(d.tracked) {
+			return nil, fmt.Errorf("hds: invalid compression index %d", i)
+		}
+		return d.tracked[i], nil
+	case tag >= tagArrayLengthStart && tag <= tagArrayTerminated:
+		arr := []interface{}{}
+		for i := 0; tag == tagArrayTerminated || i < int(tag-tagArrayLengthStart); i++ {
+			v, err := d.decode()
+			if err == errTerminator && tag == tagArrayTerminated {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		return arr, nil
+	case tag >= tagDictLengthStart && tag <= tagDictTerminated:
+		dict := map[string]interface{}{}
+		for i := 0; tag == tagDictTerminated || i < int(tag-tagDictLengthStart); i++ {
+			k, err := d.decode()
+			if err == errTerminator && tag == tagDictTerminated {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			key, ok := k.(string)
+			if ok == false {
+				return nil, fmt.Errorf("hds: unsupported dictionary key %v", k)
+			}
+			v, err := d.decode()
+			if err != nil {
+				return nil, err
+			}
+			dict[key] = v
+		}
+		return dict, nil
+	}
+
+	return nil, fmt.Errorf("hds: unsupported tag 0x%X", tag)
+}
+
+func (d *decoder) track(v interface{}) interface{} {
+	d.tracked = append(d.tracked, v)
+	return v
+}
+
+// readLength reads a little endian length of 1, 2, 4 or 8 bytes.
+func (d *decoder) readLength(size byte) (uint64, error) {
+	var err error
+	switch size {
+	case 0:
+		var n uint8
+		err = binary.Read(d.r, binary.LittleEndian, &n)
+		return uint64(n), err
+	case 1:
+		var n uint16
+		err = binary.Read(d.r, binary.LittleEndian, &n)
+		return uint64(n), err
+	case 2:
+		var n uint32
+		err = binary.Read(d.r, binary.LittleEndian, &n)
+		return uint64(n), err
+	}
+
+	var n uint64
+	err = binary.Read(d.r, binary.LittleEndian, &n)
+	return n, err
+}
+
+func (d *decoder) read(n uint64) ([]byte, error) {
+	if n > uint64(d.r.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	b := make([]byte, n)
+	_, err := io.ReadFull(d.r, b)
+	return b, err
+}
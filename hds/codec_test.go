@@ -0,0 +1,84 @@
+package hds
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	values := []interface{}{
+		nil,
+		true,
+		false,
+		int64(-1),
+		int64(0),
+		int64(38),
+		int64(39),
+		int64(-100),
+		int64(1000),
+		int64(100000),
+		int64(10000000000),
+		float64(1.5),
+		"",
+		"hello",
+		"a string which is longer than thirty-two characters",
+		[]byte{0x01, 0x02},
+		UUID{0x01},
+		[]interface{}{int64(1), "two"},
+		map[string]interface{}{"protocol": "control", "request": "hello", "id": int64(1)},
+	}
+
+	for _, v := range values {
+		b, err := Encode(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded, err := Decode(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if is, want := decoded, v; reflect.DeepEqual(is, want) == false {
+			t.Fatalf("is=%v want=%v", is, want)
+		}
+	}
+}
+
+func TestEncodeSmallInteger(t *testing.T) {
+	b, err := Encode(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := b, []byte{0x0D}; bytes.Equal(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestDecodeTerminated(t *testing.T) {
+	// ["a", 1]
+	b := []byte{tagArrayTerminated, 0x41, 'a', 0x09, tagTerminator}
+	v, err := Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := v, []interface{}{"a", int64(1)}; reflect.DeepEqual(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestDecodeCompression(t *testing.T) {
+	// ["abc", <reference to "abc">]
+	b := []byte{0xD2, 0x43, 'a', 'b', 'c', tagCompressionStart}
+	v, err := Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := v, []interface{}{"abc", "abc"}; reflect.DeepEqual(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,283 @@
+package hds
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/brutella/log"
+)
+
+// ProtocolDataSend is the protocol to transfer data streams, e.g. recordings or Siri audio
+const ProtocolDataSend = "dataSend"
+
+const (
+	topicOpen  = "open"
+	topicData  = "data"
+	topicClose = "close"
+	topicAck   = "ack"
+
+	dataSendTargetController = "controller"
+)
+
+// Reasons to close a data stream
+const (
+	CloseReasonNormal               = 0
+	CloseReasonNotAllowed           = 1
+	CloseReasonBusy                 = 2
+	CloseReasonCancelled            = 3
+	CloseReasonUnsupported          = 4
+	CloseReasonUnexpectedFailure    = 5
+	CloseReasonTimeout              = 6
+	CloseReasonBadData              = 7
+	CloseReasonProtocolError        = 8
+	CloseReasonInvalidConfiguration = 9
+)
+
+// A DataSendHandler handles the data streams of a type (e.g. "ipcamera.recording") which are opened by the controller.
+type DataSendHandler interface {
+	// HandleOpen is called when the controller opens a stream. The handler returns
+	// CloseReasonNormal to accept the stream, or another reason to reject it.
+	//
+	// Data must only be sent after the open response, see Session.AfterResponse.
+	HandleOpen(st *DataStream, body map[string]interface{}) int64
+}
+
+// DataFunc is called with the packets which the controller sends on a data stream.
+type DataFunc func(packets []interface{}, endOfStream bool)
+
+// DataStream is a stream of the dataSend protocol.
+type DataStream struct {
+	ID   int64
+	Type string
+
+	session *Session
+	done    chan struct{}
+	once    sync.Once
+
+	onData DataFunc
+	mutex  sync.Mutex
+}
+
+// Session returns the session of the stream.
+func (st *DataStream) Session() *Session {
+	return st.session
+}
+
+// Done returns a channel which is closed when the stream is closed.
+func (st *DataStream) Done() <-chan struct{} {
+	return st.done
+}
+
+// OnData sets the function which is called when the controller sends data on the stream,
+// e.g. a firmware image. The function is called on the goroutine of the session.
+func (st *DataStream) OnData(fn DataFunc) {
+	st.mutex.Lock()
+	st.onData = fn
+	st.mutex.Unlock()
+}
+
+func (st *DataStream) receive(packets []interface{}, endOfStream bool) {
+	st.mutex.Lock()
+	fn := st.onData
+	st.mutex.Unlock()
+
+	if fn != nil {
+		fn(packets, endOfStream)
+	}
+}
+
+// SendData sends packets on the stream. Every packet is a dictionary
+// with the keys "data" and "metadata".
+func (st *DataStream) SendData(packets []interface{}, endOfStream bool) error {
+	select {
+	case <-st.done:
+		return ErrSessionClosed
+	default:
+	}
+
+	body := map[string]interface{}{
+		"streamId": st.ID,
+		"packets":  packets,
+	}
+	if endOfStream == true {
+		body["endOfStream"] = true
+	}
+
+	return st.session.SendEvent(ProtocolDataSend, topicData, body)
+}
+
+// Close closes the stream with reason.
+func (st *DataStream) Close(reason int64) error {
+	select {
+	case <-st.done:
+		// Already closed
+		return nil
+	default:
+	}
+
+	st.session.dataSend().remove(st)
+
+	body := map[string]interface{}{
+		"streamId": st.ID,
+		"reason":   reason,
+	}
+
+	return st.session.SendEvent(ProtocolDataSend, topicClose, body)
+}
+
+func (st *DataStream) finish() {
+	st.once.Do(func() {
+		close(st.done)
+	})
+}
+
+// OpenDataStream opens a data stream of type typ to the controller, e.g. to send Siri audio.
+func (s *Session) OpenDataStream(typ string) (*DataStream, error) {
+	body := map[string]interface{}{
+		"target": dataSendTargetController,
+		"type":   typ,
+	}
+
+	res, err := s.SendRequest(ProtocolDataSend, topicOpen, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Status != StatusSuccess {
+		return nil, errors.New("hds: controller rejected data stream")
+	}
+
+	id, ok := res.Body["streamId"].(int64)
+	if ok == false {
+		return nil, errors.New("hds: missing stream id")
+	}
+
+	st := newDataStream(s, id, typ)
+	s.dataSend().add(st)
+
+	return st, nil
+}
+
+func newDataStream(s *Session, id int64, typ string) *DataStream {
+	return &DataStream{
+		ID:      id,
+		Type:    typ,
+		session: s,
+		done:    make(chan struct{}),
+	}
+}
+
+// dataSendKey identifies a data stream of a session
+type dataSendKey struct {
+	session *Session
+	id      int64
+}
+
+// dataSend implements the dataSend protocol and dispatches streams to handlers by type.
+type dataSend struct {
+	mutex    sync.Mutex
+	handlers map[string]DataSendHandler
+	streams  map[dataSendKey]*DataStream
+}
+
+func newDataSend() *dataSend {
+	return &dataSend{
+		handlers: map[string]DataSendHandler{},
+		streams:  map[dataSendKey]*DataStream{},
+	}
+}
+
+func (d *dataSend) handle(typ string, h DataSendHandler) {
+	d.mutex.Lock()
+	d.handlers[typ] = h
+	d.mutex.Unlock()
+}
+
+func (d *dataSend) add(st *DataStream) {
+	d.mutex.Lock()
+	d.streams[dataSendKey{st.session, st.ID}] = st
+	d.mutex.Unlock()
+}
+
+func (d *dataSend) remove(st *DataStream) {
+	d.mutex.Lock()
+	delete(d.streams, dataSendKey{st.session, st.ID})
+	d.mutex.Unlock()
+
+	st.finish()
+}
+
+// removeSession removes the streams of a closed session.
+func (d *dataSend) removeSession(s *Session) {
+	d.mutex.Lock()
+	var streams []*DataStream
+	for key, st := range d.streams {
+		if key.session == s {
+			streams = append(streams, st)
+			delete(d.streams, key)
+		}
+	}
+	d.mutex.Unlock()
+
+	for _, st := range streams {
+		st.finish()
+	}
+}
+
+func (d *dataSend) HandleEvent(s *Session, topic string, body map[string]interface{}) {
+	id, _ := body["streamId"].(int64)
+
+	d.mutex.Lock()
+	st, ok := d.streams[dataSendKey{s, id}]
+	d.mutex.Unlock()
+
+	if ok == false {
+		log.Printf("[VERB] hds: Event %s for unknown data stream %d\n", topic, id)
+		return
+	}
+
+	switch topic {
+	case topicData:
+		packets, _ := body["packets"].([]interface{})
+		endOfStream, _ := body["endOfStream"].(bool)
+		st.receive(packets, endOfStream)
+	case topicClose:
+		reason, _ := body["reason"].(int64)
+		log.Printf("[VERB] hds: Data stream %d closed by controller (reason %d)\n", id, reason)
+		d.remove(st)
+	case topicAck:
+		// The controller received the end of the stream
+		d.remove(st)
+	}
+}
+
+func (d *dataSend) HandleRequest(s *Session, topic string, body map[string]interface{}) (int64, map[string]interface{}) {
+	if topic != topicOpen {
+		return StatusProtocolError, map[string]interface{}{"status": int64(CloseReasonUnsupported)}
+	}
+
+	id, _ := body["streamId"].(int64)
+	typ, _ := body["type"].(string)
+
+	d.mutex.Lock()
+	h, ok := d.handlers[typ]
+	_, exists := d.streams[dataSendKey{s, id}]
+	d.mutex.Unlock()
+
+	if ok == false {
+		return StatusProtocolError, map[string]interface{}{"status": int64(CloseReasonUnsupported)}
+	}
+
+	if exists == true {
+		return StatusProtocolError, map[string]interface{}{"status": int64(CloseReasonBusy)}
+	}
+
+	st := newDataStream(s, id, typ)
+	if reason := h.HandleOpen(st, body); reason != CloseReasonNormal {
+		return StatusProtocolError, map[string]interface{}{"status": reason}
+	}
+
+	d.add(st)
+
+	return StatusSuccess, map[string]interface{}{"status": int64(StatusSuccess)}
+}
@@ -0,0 +1,36 @@
+package hds
+
+import (
+	"testing"
+)
+
+func TestDataStreamReceivesData(t *testing.T) {
+	d := newDataSend()
+	s := &Session{}
+	st := newDataStream(s, 1, "test")
+	d.add(st)
+
+	var data []byte
+	var end bool
+	st.OnData(func(packets []interface{}, endOfStream bool) {
+		for _, p := range packets {
+			packet := p.(map[string]interface{})
+			data = append(data, packet["data"].([]byte)...)
+		}
+		end = endOfStream
+	})
+
+	body := map[string]interface{}{
+		"streamId":    int64(1),
+		"packets":     []interface{}{map[string]interface{}{"data": []byte{0x01, 0x02}}},
+		"endOfStream": true,
+	}
+	d.HandleEvent(s, topicData, body)
+
+	if is, want := len(data), 2; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if end == false {
+		t.Fatal("expected end of stream")
+	}
+}
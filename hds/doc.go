@@ -0,0 +1,12 @@
+// Package hds implements the HomeKit Data Stream (HDS) protocol.
+//
+// A HomeKit Data Stream is a dedicated TCP connection between a controller and the accessory.
+// The controller requests a new stream by writing to the Setup Data Stream Transport characteristic
+// of the Data Stream Transport Management service. The accessory responds with the port of the
+// stream server and a key salt. Both sides then derive the encryption keys from the shared key
+// of the HAP session, which was negotiated during pair verify.
+//
+// Every frame on the stream is encrypted with ChaCha20-Poly1305 and contains a header and
+// a message, which are both encoded in the HDS data format. Messages are events,
+// requests or responses of a protocol (e.g. "control" or "dataSend").
+package hds
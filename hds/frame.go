@@ -0,0 +1,106 @@
+package hds
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/brutella/hc/crypto/chacha20poly1305"
+)
+
+const (
+	// frameTypeEncrypted is the type of an encrypted frame
+	frameTypeEncrypted byte = 0x01
+
+	// maxPayloadLength is the maximum length of a frame payload
+	maxPayloadLength = 0xFFFFF
+
+	frameHeaderLength = 4
+	authTagLength     = 16
+)
+
+// frameCipher encrypts or decrypts frames in one direction of a stream.
+//
+// The nonce is a little endian counter which is incremented for every
+// successfully sealed or opened frame.
+type frameCipher struct {
+	key   [32]byte
+	count uint64
+}
+
+func newFrameCipher(key [32]byte) *frameCipher {
+	return &frameCipher{key: key}
+}
+
+func (c *frameCipher) nonce() []byte {
+	var nonce [8]byte
+	binary.LittleEndian.PutUint64(nonce[:], c.count)
+
+	return nonce[:]
+}
+
+// seal returns an encrypted frame for payload
+//
+//	[ type (1 byte) ] [ length (3 bytes) ] [ encrypted payload ] [ auth tag (16 bytes) ]
+func (c *frameCipher) seal(payload []byte) ([]byte, error) {
+	if len(payload) > maxPayloadLength {
+		return nil, fmt.Errorf("hds: payload length %d exceeds maximum", len(payload))
+	}
+
+	header := frameHeader(len(payload))
+	encrypted, mac, err := chacha20poly1305.EncryptAndSeal(c.key[:], c.nonce(), payload, header)
+	if err != nil {
+		return nil, err
+	}
+	c.count++
+
+	frame := append(header, encrypted...)
+	return append(frame, mac[:]...), nil
+}
+
+// open returns the decrypted payload of a frame.
+func (c *frameCipher) open(header, body []byte) ([]byte, error) {
+	if len(body) < authTagLength {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	var mac [16]byte
+	encrypted := body[:len(body)-authTagLength]
+	copy(mac[:], body[len(body)-authTagLength:])
+
+	payload, err := chacha20poly1305.DecryptAndVerify(c.key[:], c.nonce(), encrypted, mac, header)
+	if err != nil {
+		return nil, err
+	}
+	c.count++
+
+	return payload, nil
+}
+
+func frameHeader(length int) []byte {
+	return []byte{frameTypeEncrypted, byte(length >> 16), byte(length >> 8), byte(length)}
+}
+
+// readFrame reads the header and the encrypted body (including the auth tag) of a frame.
+func readFrame(r io.Reader) ([]byte, []byte, error) {
+	header := make([]byte, frameHeaderLength)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, nil, err
+	}
+
+	if header[0] != frameTypeEncrypted {
+		return nil, nil, fmt.Errorf("hds: unsupported frame type %d", header[0])
+	}
+
+	length := int(header[1])<<16 | int(header[2])<<8 | int(header[3])
+	if length > maxPayloadLength {
+		return nil, nil, fmt.Errorf("hds: payload length %d exceeds maximum", length)
+	}
+
+	body := make([]byte, length+authTagLength)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, nil, err
+	}
+
+	return header, body, nil
+}
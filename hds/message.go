@@ -0,0 +1,145 @@
+package hds
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MessageType is the type of a message.
+type MessageType string
+
+const (
+	// MessageTypeEvent is a message which doesn't expect a response
+	MessageTypeEvent MessageType = "event"
+
+	// MessageTypeRequest is a message which expects a response with the same id
+	MessageTypeRequest MessageType = "request"
+
+	// MessageTypeResponse is the response to a request
+	MessageTypeResponse MessageType = "response"
+)
+
+// Status codes of responses
+const (
+	StatusSuccess         = 0
+	StatusOutOfMemory     = 1
+	StatusTimeout         = 2
+	StatusHeaderError     = 3
+	StatusPayloadError    = 4
+	StatusMissingProtocol = 5
+	StatusProtocolError   = 6
+)
+
+// Header keys
+const (
+	headerKeyProtocol = "protocol"
+	headerKeyID       = "id"
+	headerKeyStatus   = "status"
+)
+
+// Message is an event, request or response of a protocol.
+type Message struct {
+	Type     MessageType
+	Protocol string
+	Topic    string
+
+	// ID identifies a request and the corresponding response
+	ID int64
+
+	// Status is the status code of a response
+	Status int64
+
+	Body map[string]interface{}
+}
+
+// encode returns the payload of the message
+//
+//	[ header length (1 byte) ] [ header ] [ body ]
+func (m *Message) encode() ([]byte, error) {
+	header := map[string]interface{}{
+		headerKeyProtocol: m.Protocol,
+		string(m.Type):    m.Topic,
+	}
+
+	switch m.Type {
+	case MessageTypeRequest:
+		header[headerKeyID] = m.ID
+	case MessageTypeResponse:
+		header[headerKeyID] = m.ID
+		header[headerKeyStatus] = m.Status
+	}
+
+	h, err := Encode(header)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(h) > 0xFF {
+		return nil, errors.New("hds: header too long")
+	}
+
+	body := m.Body
+	if body == nil {
+		body = map[string]interface{}{}
+	}
+
+	b, err := Encode(body)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := append([]byte{byte(len(h))}, h...)
+	return append(payload, b...), nil
+}
+
+// decodeMessage returns the message of payload.
+func decodeMessage(payload []byte) (*Message, error) {
+	if len(payload) == 0 {
+		return nil, errors.New("hds: empty payload")
+	}
+
+	length := int(payload[0])
+	if len(payload) < 1+length {
+		return nil, errors.New("hds: invalid header length")
+	}
+
+	v, err := Decode(payload[1 : 1+length])
+	if err != nil {
+		return nil, err
+	}
+
+	header, ok := v.(map[string]interface{})
+	if ok == false {
+		return nil, fmt.Errorf("hds: invalid header %v", v)
+	}
+
+	m := Message{}
+	m.Protocol, _ = header[headerKeyProtocol].(string)
+	for _, t := range []MessageType{MessageTypeEvent, MessageTypeRequest, MessageTypeResponse} {
+		if topic, ok := header[string(t)].(string); ok == true {
+			m.Type = t
+			m.Topic = topic
+		}
+	}
+
+	if len(m.Type) == 0 {
+		return nil, fmt.Errorf("hds: unknown message type in header %v", header)
+	}
+
+	m.ID, _ = header[headerKeyID].(int64)
+	m.Status, _ = header[headerKeyStatus].(int64)
+
+	m.Body = map[string]interface{}{}
+	if rest := payload[1+length:]; len(rest) > 0 {
+		v, err := Decode(rest)
+		if err != nil {
+			return nil, err
+		}
+
+		if body, ok := v.(map[string]interface{}); ok == true {
+			m.Body = body
+		}
+	}
+
+	return &m, nil
+}
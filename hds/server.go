@@ -0,0 +1,314 @@
+package hds
+
+import (
+	"encoding/base64"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
+	"github.com/brutella/hc/util"
+	"github.com/brutella/log"
+)
+
+// ProtocolControl is the protocol to set up a data stream
+const ProtocolControl = "control"
+
+// Version is the version of the supported data stream protocol
+const Version = "1.0"
+
+// A Handler handles the messages of a protocol.
+type Handler interface {
+	// HandleEvent is called when an event was received.
+	HandleEvent(s *Session, topic string, body map[string]interface{})
+
+	// HandleRequest is called when a request was received and
+	// returns the status and body of the response.
+	HandleRequest(s *Session, topic string, body map[string]interface{}) (int64, map[string]interface{})
+}
+
+// SessionFunc is called with a data stream session.
+type SessionFunc func(s *Session)
+
+// Server accepts data streams which were set up via the
+// Setup Data Stream Transport characteristic.
+type Server struct {
+	listener *net.TCPListener
+	port     int
+
+	mutex        sync.Mutex
+	pending      []*pendingSession
+	sessions     map[*Session]bool
+	handlers     map[string]Handler
+	sessionFuncs []SessionFunc
+	stopped      bool
+
+	dataSend *dataSend
+	clock    util.Clock
+}
+
+// NewServer returns a server which listens on port.
+// If port is empty, a free port is used.
+func NewServer(port string) (*Server, error) {
+	ln, err := net.Listen("tcp", port)
+	if err != nil {
+		return nil, err
+	}
+
+	_, p, _ := net.SplitHostPort(ln.Addr().String())
+	n, _ := strconv.Atoi(p)
+
+	s := Server{
+		listener: ln.(*net.TCPListener),
+		port:     n,
+		sessions: map[*Session]bool{},
+		handlers: map[string]Handler{},
+		dataSend: newDataSend(),
+		clock:    util.SystemClock,
+	}
+	s.Handle(ProtocolControl, &controlHandler{})
+	s.Handle(ProtocolDataSend, s.dataSend)
+
+	return &s, nil
+}
+
+// SetClock sets the clock which is used for timeouts, e.g. a fake clock in tests.
+func (s *Server) SetClock(c util.Clock) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.clock = c
+}
+
+// Port returns the port on which the server listens to.
+func (s *Server) Port() int {
+	return s.port
+}
+
+// Handle registers the handler for the protocol.
+func (s *Server) Handle(protocol string, h Handler) {
+	s.mutex.Lock()
+	s.handlers[protocol] = h
+	s.mutex.Unlock()
+}
+
+// HandleDataSend registers the handler for data streams of type typ, which are opened by the controller.
+func (s *Server) HandleDataSend(typ string, h DataSendHandler) {
+	s.dataSend.handle(typ, h)
+}
+
+// OnSession calls fn when a new data stream session was established.
+func (s *Server) OnSession(fn SessionFunc) {
+	s.mutex.Lock()
+	s.sessionFuncs = append(s.sessionFuncs, fn)
+	s.mutex.Unlock()
+}
+
+// Sessions returns the established sessions.
+func (s *Server) Sessions() []*Session {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var sessions []*Session
+	for session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+
+	return sessions
+}
+
+// Setup configures the characteristics of the Data Stream Transport Management service
+// so that controllers can set up data streams to this server.
+func (s *Server) Setup(svc *service.DataStreamTransportManagement) {
+	svc.SupportedDataStreamTransportConfiguration.SetValue(supportedConfiguration())
+	svc.Version.SetValue(Version)
+	svc.SetupDataStreamTransport.OnValueUpdateFromConn(func(conn net.Conn, c *characteristic.Characteristic, newValue, oldValue interface{}) {
+		var res []byte
+		key, err := sharedKeyForConnection(conn)
+		if err == nil {
+			var b []byte
+			str, _ := newValue.(string)
+			if b, err = base64.StdEncoding.DecodeString(str); err == nil {
+				res, err = s.HandleSetup(b, key)
+			}
+		}
+
+		if err != nil {
+			log.Println("[ERRO] hds:", err)
+			res = setupResponse(setupStatusGenericError)
+		}
+
+		// The value is returned as write response
+		c.Value = base64.StdEncoding.EncodeToString(res)
+	})
+}
+
+// HandleSetup handles a write request of the Setup Data Stream Transport characteristic
+// and returns the write response. The data stream keys are derived from sharedKey.
+func (s *Server) HandleSetup(b []byte, sharedKey [32]byte) ([]byte, error) {
+	controllerSalt, err := parseSetupRequest(b)
+	if err != nil {
+		return nil, err
+	}
+
+	accessorySalt, err := accessoryKeySalt()
+	if err != nil {
+		return nil, err
+	}
+
+	var salt []byte
+	salt = append(salt, controllerSalt...)
+	salt = append(salt, accessorySalt...)
+	p, err := newPendingSession(sharedKey, salt, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	stopped := s.stopped
+	if stopped == false {
+		s.pending = append(s.unexpiredPending(), p)
+	}
+	s.mutex.Unlock()
+
+	if stopped == true {
+		return setupResponse(setupStatusBusy), nil
+	}
+
+	out := util.NewTLV8Container()
+	out.SetByte(tagSetupStatus, setupStatusSuccess)
+	out.SetBytes(tagSetupSessionParameters, sessionParameters(s.port))
+	out.SetBytes(tagSetupAccessoryKeySalt, accessorySalt)
+
+	return out.BytesBuffer().Bytes(), nil
+}
+
+// ListenAndServe accepts data stream connections until the server is stopped.
+func (s *Server) ListenAndServe() error {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			s.mutex.Lock()
+			stopped := s.stopped
+			s.mutex.Unlock()
+			if stopped == true {
+				return nil
+			}
+			return err
+		}
+
+		go s.serveConn(conn)
+	}
+}
+
+// Stop stops the server and closes all sessions.
+func (s *Server) Stop() {
+	s.mutex.Lock()
+	s.stopped = true
+	s.pending = nil
+	sessions := s.sessions
+	s.sessions = map[*Session]bool{}
+	s.mutex.Unlock()
+
+	s.listener.Close()
+	for session := range sessions {
+		session.Close()
+	}
+}
+
+func (s *Server) handler(protocol string) Handler {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.handlers[protocol]
+}
+
+// serveConn identifies the pending session of a new connection by decrypting the first frame.
+func (s *Server) serveConn(conn net.Conn) {
+	conn.SetReadDeadline(time.Now().Add(pendingTimeout))
+	header, body, err := readFrame(conn)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	var payload []byte
+	var session *Session
+
+	s.mutex.Lock()
+	pending := s.unexpiredPending()
+	for i, p := range pending {
+		if payload, err = p.decrypter.open(header, body); err == nil {
+			session = newSession(conn, p, s)
+			s.sessions[session] = true
+			pending = append(pending[:i], pending[i+1:]...)
+			break
+		}
+	}
+	s.pending = pending
+	funcs := s.sessionFuncs
+	s.mutex.Unlock()
+
+	if session == nil {
+		log.Println("[WARN] hds: Could not identify connection from", conn.RemoteAddr())
+		conn.Close()
+		return
+	}
+
+	log.Println("[VERB] hds: New session from", conn.RemoteAddr())
+	session.OnClose(s.removeSession)
+	for _, fn := range funcs {
+		fn(session)
+	}
+
+	session.handlePayload(payload)
+	session.serve()
+}
+
+func (s *Server) removeSession(session *Session) {
+	s.mutex.Lock()
+	delete(s.sessions, session)
+	s.mutex.Unlock()
+
+	s.dataSend.removeSession(session)
+}
+
+// now returns the current time of the clock.
+func (s *Server) now() time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.clock.Now()
+}
+
+// unexpiredPending returns the pending sessions which are not expired.
+// The caller must hold the mutex.
+func (s *Server) unexpiredPending() []*pendingSession {
+	var pending []*pendingSession
+	for _, p := range s.pending {
+		if p.isExpired(s.clock.Now()) == false {
+			pending = append(pending, p)
+		}
+	}
+
+	return pending
+}
+
+// controlHandler handles the control protocol.
+type controlHandler struct{}
+
+func (h *controlHandler) HandleEvent(s *Session, topic string, body map[string]interface{}) {
+	log.Println("[VERB] hds: Unhandled control event", topic)
+}
+
+func (h *controlHandler) HandleRequest(s *Session, topic string, body map[string]interface{}) (int64, map[string]interface{}) {
+	switch topic {
+	case "hello":
+		return StatusSuccess, map[string]interface{}{}
+	}
+
+	return StatusProtocolError, map[string]interface{}{}
+}
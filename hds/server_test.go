@@ -0,0 +1,117 @@
+package hds
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/brutella/hc/crypto/hkdf"
+	"github.com/brutella/hc/util"
+)
+
+func TestDataStream(t *testing.T) {
+	server, err := NewServer("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+	go server.ListenAndServe()
+
+	var sharedKey [32]byte
+	copy(sharedKey[:], []byte("shared key of the HAP session"))
+
+	controllerSalt := bytes.Repeat([]byte{0x01}, keySaltLength)
+	req := util.NewTLV8Container()
+	req.SetByte(tagSetupCommandType, sessionCommandStartSession)
+	req.SetByte(tagSetupTransportType, transportTypeTCP)
+	req.SetBytes(tagSetupControllerKeySalt, controllerSalt)
+
+	b, err := server.HandleSetup(req.BytesBuffer().Bytes(), sharedKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := util.NewTLV8ContainerFromReader(bytes.NewBuffer(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := res.GetByte(tagSetupStatus), setupStatusSuccess; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	params, err := util.NewTLV8ContainerFromReader(bytes.NewBuffer(res.GetBytes(tagSetupSessionParameters)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port := int(binary.LittleEndian.Uint16(params.GetBytes(tagSessionTCPListeningPort)))
+	if is, want := port, server.Port(); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// Derive the keys as the controller
+	salt := append(controllerSalt, res.GetBytes(tagSetupAccessoryKeySalt)...)
+	writeKey, _ := hkdf.Sha512(sharedKey[:], salt, []byte("HDS-Write-Encryption-Key"))
+	readKey, _ := hkdf.Sha512(sharedKey[:], salt, []byte("HDS-Read-Encryption-Key"))
+	encrypter := newFrameCipher(writeKey)
+	decrypter := newFrameCipher(readKey)
+
+	conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	hello := Message{Type: MessageTypeRequest, Protocol: ProtocolControl, Topic: "hello", ID: 1}
+	payload, err := hello.encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame, err := encrypter.seal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+
+	header, body, err := readFrame(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err = decrypter.open(header, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := decodeMessage(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := m.Type, MessageTypeResponse; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := m.Topic, "hello"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := m.ID, int64(1); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := m.Status, int64(StatusSuccess); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := len(server.Sessions()), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,236 @@
+package hds
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/brutella/hc/util"
+	"github.com/brutella/log"
+)
+
+// requestTimeout is the duration to wait for the response of a request
+const requestTimeout = 10 * time.Second
+
+// ErrSessionClosed is returned when sending on a closed session.
+var ErrSessionClosed = errors.New("hds: session closed")
+
+// Session is an established data stream to a controller.
+type Session struct {
+	conn   net.Conn
+	server *Server
+
+	encrypter *frameCipher
+	decrypter *frameCipher
+
+	// synchronize writes and access to requests
+	mutex      sync.Mutex
+	nextID     int64
+	requests   map[int64]chan *Message
+	closed     bool
+	closeFuncs []SessionFunc
+
+	// funcs which are called after the current response was sent
+	afterResponse []func()
+}
+
+func newSession(conn net.Conn, p *pendingSession, server *Server) *Session {
+	return &Session{
+		conn:      conn,
+		server:    server,
+		encrypter: p.encrypter,
+		decrypter: p.decrypter,
+		requests:  map[int64]chan *Message{},
+	}
+}
+
+func (s *Session) dataSend() *dataSend {
+	return s.server.dataSend
+}
+
+// RemoteAddr returns the address of the controller.
+func (s *Session) RemoteAddr() net.Addr {
+	return s.conn.RemoteAddr()
+}
+
+// OnClose calls fn when the session is closed.
+func (s *Session) OnClose(fn SessionFunc) {
+	s.mutex.Lock()
+	s.closeFuncs = append(s.closeFuncs, fn)
+	s.mutex.Unlock()
+}
+
+// SendEvent sends an event to the controller.
+func (s *Session) SendEvent(protocol, topic string, body map[string]interface{}) error {
+	return s.send(&Message{Type: MessageTypeEvent, Protocol: protocol, Topic: topic, Body: body})
+}
+
+// SendRequest sends a request to the controller and returns the response.
+//
+// Don't call this method from a Handler because responses are
+// read by the same goroutine which calls the handler.
+func (s *Session) SendRequest(protocol, topic string, body map[string]interface{}) (*Message, error) {
+	s.mutex.Lock()
+	id := s.nextID
+	s.nextID++
+	ch := make(chan *Message, 1)
+	s.requests[id] = ch
+	s.mutex.Unlock()
+
+	defer func() {
+		s.mutex.Lock()
+		delete(s.requests, id)
+		s.mutex.Unlock()
+	}()
+
+	if err := s.send(&Message{Type: MessageTypeRequest, Protocol: protocol, Topic: topic, ID: id, Body: body}); err != nil {
+		return nil, err
+	}
+
+	timeout := make(chan struct{})
+	t := s.clock().AfterFunc(requestTimeout, func() {
+		close(timeout)
+	})
+	defer t.Stop()
+
+	select {
+	case m, ok := <-ch:
+		if ok == false {
+			return nil, ErrSessionClosed
+		}
+		return m, nil
+	case <-timeout:
+		return nil, errors.New("hds: request timed out")
+	}
+}
+
+// clock returns the clock of the server.
+func (s *Session) clock() util.Clock {
+	if s.server == nil {
+		return util.SystemClock
+	}
+
+	s.server.mutex.Lock()
+	defer s.server.mutex.Unlock()
+
+	return s.server.clock
+}
+
+// AfterResponse calls fn after the response of the currently handled request was sent.
+// This method must only be called from Handler.HandleRequest, e.g. to start sending
+// events which the controller only expects after the response.
+func (s *Session) AfterResponse(fn func()) {
+	s.afterResponse = append(s.afterResponse, fn)
+}
+
+// Close closes the session.
+func (s *Session) Close() error {
+	s.mutex.Lock()
+	if s.closed == true {
+		s.mutex.Unlock()
+		return nil
+	}
+
+	s.closed = true
+	for id, ch := range s.requests {
+		close(ch)
+		delete(s.requests, id)
+	}
+	funcs := s.closeFuncs
+	s.mutex.Unlock()
+
+	err := s.conn.Close()
+	for _, fn := range funcs {
+		fn(s)
+	}
+
+	return err
+}
+
+func (s *Session) send(m *Message) error {
+	payload, err := m.encode()
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed == true {
+		return ErrSessionClosed
+	}
+
+	frame, err := s.encrypter.seal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.conn.Write(frame)
+	return err
+}
+
+// serve reads and handles frames until the connection is closed.
+func (s *Session) serve() {
+	defer s.Close()
+
+	for {
+		header, body, err := readFrame(s.conn)
+		if err != nil {
+			return
+		}
+
+		payload, err := s.decrypter.open(header, body)
+		if err != nil {
+			log.Println("[ERRO] hds:", err)
+			return
+		}
+
+		s.handlePayload(payload)
+	}
+}
+
+func (s *Session) handlePayload(payload []byte) {
+	m, err := decodeMessage(payload)
+	if err != nil {
+		log.Println("[ERRO] hds:", err)
+		return
+	}
+
+	switch m.Type {
+	case MessageTypeEvent:
+		if h := s.server.handler(m.Protocol); h != nil {
+			h.HandleEvent(s, m.Topic, m.Body)
+		} else {
+			log.Printf("[WARN] hds: No handler for event %s.%s\n", m.Protocol, m.Topic)
+		}
+	case MessageTypeRequest:
+		res := Message{Type: MessageTypeResponse, Protocol: m.Protocol, Topic: m.Topic, ID: m.ID}
+		if h := s.server.handler(m.Protocol); h != nil {
+			res.Status, res.Body = h.HandleRequest(s, m.Topic, m.Body)
+		} else {
+			log.Printf("[WARN] hds: No handler for request %s.%s\n", m.Protocol, m.Topic)
+			res.Status = StatusMissingProtocol
+		}
+
+		if err := s.send(&res); err != nil {
+			log.Println("[ERRO] hds:", err)
+		}
+
+		funcs := s.afterResponse
+		s.afterResponse = nil
+		for _, fn := range funcs {
+			fn()
+		}
+	case MessageTypeResponse:
+		s.mutex.Lock()
+		if ch, ok := s.requests[m.ID]; ok == true {
+			select {
+			case ch <- m:
+			default:
+				// Ignore duplicate responses
+			}
+		}
+		s.mutex.Unlock()
+	}
+}
@@ -0,0 +1,165 @@
+package hds
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/brutella/hc/crypto/hkdf"
+	"github.com/brutella/hc/netio"
+	"github.com/brutella/hc/util"
+)
+
+// TLV8 tags of the Setup Data Stream Transport write request
+const (
+	tagSetupCommandType       = 0x01
+	tagSetupTransportType     = 0x02
+	tagSetupControllerKeySalt = 0x03
+)
+
+// TLV8 tags of the Setup Data Stream Transport write response
+const (
+	tagSetupStatus            = 0x01
+	tagSetupSessionParameters = 0x02
+	tagSetupAccessoryKeySalt  = 0x03
+)
+
+// TLV8 tags of the transport session parameters and configuration
+const (
+	tagSessionTCPListeningPort         = 0x01
+	tagSupportedTransportConfiguration = 0x01
+	tagConfigurationTransportType      = 0x01
+)
+
+const (
+	sessionCommandStartSession byte = 0
+	transportTypeTCP           byte = 0
+
+	setupStatusSuccess      byte = 0
+	setupStatusGenericError byte = 1
+	setupStatusBusy         byte = 2
+)
+
+const (
+	keySaltLength = 32
+
+	// pendingTimeout is the duration in which the controller has to connect after setup
+	pendingTimeout = 10 * time.Second
+)
+
+// pendingSession is a stream which was set up but to which the controller has not connected yet.
+type pendingSession struct {
+	encrypter *frameCipher
+	decrypter *frameCipher
+	created   time.Time
+}
+
+func (p *pendingSession) isExpired(now time.Time) bool {
+	return now.Sub(p.created) > pendingTimeout
+}
+
+// newPendingSession returns a pending session with keys derived from the shared key and salt,
+// which is created at the time now.
+func newPendingSession(sharedKey [32]byte, salt []byte, now time.Time) (*pendingSession, error) {
+	// Accessory to controller
+	encryptKey, err := hkdf.Sha512(sharedKey[:], salt, []byte("HDS-Read-Encryption-Key"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Controller to accessory
+	decryptKey, err := hkdf.Sha512(sharedKey[:], salt, []byte("HDS-Write-Encryption-Key"))
+	if err != nil {
+		return nil, err
+	}
+
+	p := pendingSession{
+		encrypter: newFrameCipher(encryptKey),
+		decrypter: newFrameCipher(decryptKey),
+		created:   now,
+	}
+
+	return &p, nil
+}
+
+// supportedConfiguration returns the TLV8 value of the Supported Data Stream Transport Configuration characteristic.
+func supportedConfiguration() []byte {
+	transport := util.NewTLV8Container()
+	transport.SetByte(tagConfigurationTransportType, transportTypeTCP)
+
+	c := util.NewTLV8Container()
+	c.SetBytes(tagSupportedTransportConfiguration, transport.BytesBuffer().Bytes())
+
+	return c.BytesBuffer().Bytes()
+}
+
+// setupResponse returns a write response with status.
+func setupResponse(status byte) []byte {
+	c := util.NewTLV8Container()
+	c.SetByte(tagSetupStatus, status)
+
+	return c.BytesBuffer().Bytes()
+}
+
+// parseSetupRequest returns the controller key salt of a setup write request.
+func parseSetupRequest(b []byte) ([]byte, error) {
+	in, err := util.NewTLV8ContainerFromReader(bytes.NewBuffer(b))
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd := in.GetByte(tagSetupCommandType); cmd != sessionCommandStartSession {
+		return nil, fmt.Errorf("hds: unsupported session command %d", cmd)
+	}
+
+	if typ := in.GetByte(tagSetupTransportType); typ != transportTypeTCP {
+		return nil, fmt.Errorf("hds: unsupported transport type %d", typ)
+	}
+
+	salt := in.GetBytes(tagSetupControllerKeySalt)
+	if len(salt) != keySaltLength {
+		return nil, fmt.Errorf("hds: invalid controller key salt length %d", len(salt))
+	}
+
+	return salt, nil
+}
+
+// accessoryKeySalt returns a random key salt.
+func accessoryKeySalt() ([]byte, error) {
+	salt := make([]byte, keySaltLength)
+	_, err := rand.Read(salt)
+
+	return salt, err
+}
+
+// sessionParameters returns the TLV8 encoded parameters of a tcp transport session.
+func sessionParameters(port int) []byte {
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, uint16(port))
+
+	c := util.NewTLV8Container()
+	c.SetBytes(tagSessionTCPListeningPort, b)
+
+	return c.BytesBuffer().Bytes()
+}
+
+// sharedKeyForConnection returns the shared key which was negotiated
+// during pair verify of the HAP connection conn.
+func sharedKeyForConnection(conn net.Conn) ([32]byte, error) {
+	var key [32]byte
+	c, ok := conn.(netio.SessionConn)
+	if ok == false {
+		return key, errors.New("hds: no HAP connection")
+	}
+
+	session := c.Context().GetSessionForConnection(c)
+	if session == nil || session.PairVerifyHandler() == nil {
+		return key, errors.New("hds: connection is not verified")
+	}
+
+	return session.PairVerifyHandler().SharedKey(), nil
+}
@@ -0,0 +1,7 @@
+// Package nat implements port mappings on network gateways.
+//
+// A port mapping makes an accessory reachable from a different network
+// segment, e.g. for a HomeKit hub behind a router. The package implements
+// NAT-PMP (RFC 6886). Other protocols (e.g. UPnP IGD) can be used by
+// implementing the PortMapper interface.
+package nat
@@ -0,0 +1,57 @@
+package nat
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strings"
+)
+
+// DefaultGateway returns the ip address of the default gateway.
+// The gateway is read from /proc/net/route and is therefore only found on Linux.
+func DefaultGateway() (net.IP, error) {
+	f, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return defaultGatewayFromRoutes(f)
+}
+
+// defaultGatewayFromRoutes returns the gateway of the default route in a routing table
+// formatted like /proc/net/route.
+//
+//	Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+//	eth0	00000000	0101A8C0	0003	0	0	0	00000000	0	0	0
+func defaultGatewayFromRoutes(r io.Reader) (net.IP, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[1] != "00000000" {
+			continue
+		}
+
+		b, err := hex.DecodeString(fields[2])
+		if err != nil || len(b) != 4 {
+			continue
+		}
+
+		// The address is stored in host byte order (little endian)
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, binary.LittleEndian.Uint32(b))
+		if ip.IsUnspecified() == false {
+			return ip, nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, errors.New("No default gateway found")
+}
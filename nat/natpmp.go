@@ -0,0 +1,119 @@
+package nat
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+)
+
+// natpmpPort is the UDP port of a NAT-PMP gateway
+const natpmpPort = 5351
+
+// NAT-PMP opcodes
+const (
+	natpmpOpMapUDP byte = 1
+	natpmpOpMapTCP byte = 2
+)
+
+// natpmpRetries is the number of requests which are sent until the gateway responds.
+// The first request times out after 250ms, the timeout is doubled for every retry.
+const natpmpRetries = 4
+
+type natpmp struct {
+	addr string
+}
+
+// NewNATPMP returns a port mapper which uses NAT-PMP to map ports on the gateway.
+func NewNATPMP(gateway net.IP) PortMapper {
+	return &natpmp{addr: net.JoinHostPort(gateway.String(), fmt.Sprintf("%d", natpmpPort))}
+}
+
+// DiscoverNATPMP returns a NAT-PMP port mapper for the default gateway.
+func DiscoverNATPMP() (PortMapper, error) {
+	gateway, err := DefaultGateway()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNATPMP(gateway), nil
+}
+
+func (n *natpmp) MapPort(protocol string, port int, lifetime time.Duration) (int, time.Duration, error) {
+	return n.mapPort(protocol, port, port, lifetime)
+}
+
+func (n *natpmp) UnmapPort(protocol string, port int) error {
+	_, _, err := n.mapPort(protocol, port, 0, 0)
+	return err
+}
+
+// mapPort sends a mapping request and returns the mapped external port and lifetime.
+// A request with an external port and lifetime of 0 removes the mapping.
+func (n *natpmp) mapPort(protocol string, port, external int, lifetime time.Duration) (int, time.Duration, error) {
+	var op byte
+	switch protocol {
+	case ProtocolTCP:
+		op = natpmpOpMapTCP
+	case ProtocolUDP:
+		op = natpmpOpMapUDP
+	default:
+		return 0, 0, fmt.Errorf("Unsupported protocol %s", protocol)
+	}
+
+	// version (0), opcode, reserved, internal port, suggested external port, lifetime in seconds
+	req := make([]byte, 12)
+	req[1] = op
+	binary.BigEndian.PutUint16(req[4:6], uint16(port))
+	binary.BigEndian.PutUint16(req[6:8], uint16(external))
+	binary.BigEndian.PutUint32(req[8:12], uint32(lifetime/time.Second))
+
+	res, err := n.request(req, 16)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if res[0] != 0 || res[1] != 128+op {
+		return 0, 0, fmt.Errorf("Invalid NAT-PMP response %X", res[:2])
+	}
+
+	if code := binary.BigEndian.Uint16(res[2:4]); code != 0 {
+		return 0, 0, fmt.Errorf("NAT-PMP request failed with result code %d", code)
+	}
+
+	mapped := int(binary.BigEndian.Uint16(res[10:12]))
+	granted := time.Duration(binary.BigEndian.Uint32(res[12:16])) * time.Second
+
+	return mapped, granted, nil
+}
+
+// request sends req to the gateway and returns the response of at least min bytes.
+func (n *natpmp) request(req []byte, min int) ([]byte, error) {
+	conn, err := net.Dial("udp", n.addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	timeout := 250 * time.Millisecond
+	buf := make([]byte, 16)
+	for i := 0; i < natpmpRetries; i++ {
+		if _, err := conn.Write(req); err != nil {
+			return nil, err
+		}
+
+		conn.SetReadDeadline(time.Now().Add(timeout))
+		l, err := conn.Read(buf)
+		if err == nil && l >= min {
+			return buf[:l], nil
+		}
+
+		if ne, ok := err.(net.Error); err != nil && (ok == false || ne.Timeout() == false) {
+			return nil, err
+		}
+
+		timeout *= 2
+	}
+
+	return nil, fmt.Errorf("NAT-PMP gateway %s did not respond", n.addr)
+}
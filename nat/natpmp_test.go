@@ -0,0 +1,93 @@
+package nat
+
+import (
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// gateway is a NAT-PMP gateway which maps every port to port+1000.
+func gateway(t *testing.T) (*net.UDPConn, PortMapper) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		buf := make([]byte, 12)
+		for {
+			n, addr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+
+			if n != 12 {
+				continue
+			}
+
+			res := make([]byte, 16)
+			res[1] = 128 + buf[1]
+			copy(res[8:10], buf[4:6])
+			port := binary.BigEndian.Uint16(buf[4:6])
+			if binary.BigEndian.Uint32(buf[8:12]) > 0 {
+				binary.BigEndian.PutUint16(res[10:12], port+1000)
+			}
+			copy(res[12:16], buf[8:12])
+			conn.WriteToUDP(res, addr)
+		}
+	}()
+
+	return conn, &natpmp{addr: conn.LocalAddr().String()}
+}
+
+func TestNATPMPMapPort(t *testing.T) {
+	conn, mapper := gateway(t)
+	defer conn.Close()
+
+	external, lifetime, err := mapper.MapPort(ProtocolTCP, 12345, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := external, 13345; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := lifetime, time.Hour; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if err := mapper.UnmapPort(ProtocolTCP, 12345); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNATPMPInvalidProtocol(t *testing.T) {
+	conn, mapper := gateway(t)
+	defer conn.Close()
+
+	if _, _, err := mapper.MapPort("sctp", 12345, time.Hour); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestDefaultGatewayFromRoutes(t *testing.T) {
+	routes := `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+eth0	0001A8C0	00000000	0001	0	0	0	00FFFFFF	0	0	0
+eth0	00000000	0101A8C0	0003	0	0	0	00000000	0	0	0
+`
+	ip, err := defaultGatewayFromRoutes(strings.NewReader(routes))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := ip.String(), "192.168.1.1"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if _, err := defaultGatewayFromRoutes(strings.NewReader("")); err == nil {
+		t.Fatal("expected error")
+	}
+}
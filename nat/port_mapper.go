@@ -0,0 +1,22 @@
+package nat
+
+import (
+	"time"
+)
+
+// Protocols of a port mapping
+const (
+	ProtocolTCP = "tcp"
+	ProtocolUDP = "udp"
+)
+
+// PortMapper maps ports of the gateway to local ports.
+type PortMapper interface {
+	// MapPort requests a mapping of an external port to the local port for the duration of lifetime.
+	// The mapping must be renewed before the returned lifetime expires by calling MapPort again.
+	// The method returns the external port and the lifetime granted by the gateway.
+	MapPort(protocol string, port int, lifetime time.Duration) (external int, granted time.Duration, err error)
+
+	// UnmapPort removes the mapping of the local port.
+	UnmapPort(protocol string, port int) error
+}
@@ -0,0 +1,23 @@
+package netio
+
+import (
+	"net"
+)
+
+// A SessionConn is a connection which has a session in a HAP context.
+// The connections of all transports implement this interface.
+type SessionConn interface {
+	net.Conn
+
+	// Context returns the context of the connection.
+	Context() HAPContext
+}
+
+// An EventConn is a connection which sends events without HTTP framing,
+// e.g. the connection to a peer of a CoAP transport.
+type EventConn interface {
+	net.Conn
+
+	// WriteEvent sends the json body of an event.
+	WriteEvent(body []byte) error
+}
@@ -10,6 +10,7 @@ import (
 	"bufio"
 	"io"
 	"io/ioutil"
+	"sync"
 )
 
 // HAPConnection is a connection connection based on HAP protocol which encrypts and decrypts the data.
@@ -24,7 +25,21 @@ type HAPConnection struct {
 	context    HAPContext
 
 	// Used to buffer reads
+	reader     *bufio.Reader
 	readBuffer io.Reader
+
+	// Serializes writes of responses and events
+	writeMutex sync.Mutex
+
+	// Retry is the policy of writes which failed with a temporary error
+	Retry RetryPolicy
+
+	// flow counts the written bytes
+	flow flowCounter
+
+	// WriteTimeout is the deadline of writes, e.g. of events to a controller which stopped reading.
+	// When 0, writes have no deadline.
+	WriteTimeout time.Duration
 }
 
 // NewHAPConnection returns a hap connection.
@@ -32,6 +47,8 @@ func NewHAPConnection(connection net.Conn, context HAPContext) *HAPConnection {
 	conn := &HAPConnection{
 		connection: connection,
 		context:    context,
+		reader:     bufio.NewReader(connection),
+		Retry:      DefaultRetryPolicy,
 	}
 
 	// Setup new session for the connection
@@ -44,18 +61,45 @@ func NewHAPConnection(connection net.Conn, context HAPContext) *HAPConnection {
 // EncryptedWrite encrypts and writes bytes to the connection.
 // The method returns the number of written bytes and an error when writing failed.
 func (con *HAPConnection) EncryptedWrite(b []byte) (int, error) {
+	con.writeMutex.Lock()
+	defer con.writeMutex.Unlock()
+
 	var buffer bytes.Buffer
 	buffer.Write(b)
 	encrypted, err := con.getEncrypter().Encrypt(&buffer)
 
 	if err != nil {
 		log.Println("[ERRO] Encryption failed:", err)
-		err = con.connection.Close()
+		err = con.Close()
 		return 0, err
 	}
 
 	encryptedBytes, err := ioutil.ReadAll(encrypted)
-	n, err := con.connection.Write(encryptedBytes)
+	n, err := con.write(corruptFrame(encryptedBytes))
+
+	return n, err
+}
+
+// write writes b to the connection with retries. The caller must hold the write mutex.
+// When the write still fails with a temporary error or exceeds the write timeout,
+// the connection is closed, which removes its session and subscriptions.
+func (con *HAPConnection) write(b []byte) (int, error) {
+	if con.WriteTimeout > 0 {
+		con.connection.SetWriteDeadline(time.Now().Add(con.WriteTimeout))
+		defer con.connection.SetWriteDeadline(time.Time{})
+	}
+
+	con.flow.begin(len(b))
+	n, err := con.Retry.write(con.connection, b)
+	con.flow.end(len(b), n)
+
+	if err != nil && isTemporary(err) == true {
+		log.Printf("[WARN] Could not write to %s after %d retries: %v\n", con.RemoteAddr(), con.Retry.Attempts, err)
+		con.Close()
+	} else if err != nil && isTimeout(err) == true {
+		log.Printf("[WARN] Write to %s timed out: %v\n", con.RemoteAddr(), err)
+		con.Close()
+	}
 
 	return n, err
 }
@@ -64,11 +108,10 @@ func (con *HAPConnection) EncryptedWrite(b []byte) (int, error) {
 // The method returns the number of read bytes and an error when reading failed.
 func (con *HAPConnection) DecryptedRead(b []byte) (int, error) {
 	if con.readBuffer == nil {
-		buffered := bufio.NewReader(con.connection)
-		decrypted, err := con.getDecrypter().Decrypt(buffered)
+		decrypted, err := con.getDecrypter().Decrypt(con.reader)
 		if err != nil {
 			log.Println("[ERRO] Decryption failed:", err)
-			err = con.connection.Close()
+			err = con.Close()
 			return 0, err
 		}
 
@@ -81,26 +124,59 @@ func (con *HAPConnection) DecryptedRead(b []byte) (int, error) {
 		con.readBuffer = nil
 	}
 
+	// The end of the decrypted data is not the end of the connection
+	if err == io.EOF {
+		if n == 0 {
+			return con.Read(b)
+		}
+		err = nil
+	}
+
 	return n, err
 }
 
 // Write writes bytes to the connection.
 // The written bytes are encrypted when possible.
 func (con *HAPConnection) Write(b []byte) (int, error) {
+	if err := injectWriteFault(con); err != nil {
+		return 0, err
+	}
+
 	if con.getEncrypter() != nil {
 		return con.EncryptedWrite(b)
 	}
 
-	return con.connection.Write(b)
+	con.writeMutex.Lock()
+	defer con.writeMutex.Unlock()
+
+	return con.write(b)
 }
 
 // Read reads bytes from the connection. The read bytes are decrypted when possible.
+//
+// The method waits for incoming data before the decrypter is determined.
+// This way a read, which started before the session keys changed (e.g. after pair verify),
+// decrypts the data with the current keys.
 func (con *HAPConnection) Read(b []byte) (int, error) {
+	if con.readBuffer == nil {
+		// Wait until data is available
+		if _, err := con.reader.Peek(1); err != nil {
+			return 0, err
+		}
+	}
+
 	if con.getDecrypter() != nil {
 		return con.DecryptedRead(b)
 	}
 
-	return con.connection.Read(b)
+	return con.reader.Read(b)
+}
+
+// Controller returns the name of the controller which verified the connection,
+// or an empty string if the connection is not verified.
+func (con *HAPConnection) Controller() string {
+	info, _ := con.context.Connections().Info(con)
+	return info.Controller
 }
 
 // Close closes the connection and deletes the related session from the context.
@@ -108,7 +184,7 @@ func (con *HAPConnection) Close() error {
 	log.Println("[INFO] Close connection and remove session")
 
 	// Remove session from the context
-	con.context.DeleteSessionForConnection(con.connection)
+	con.context.DeleteSessionForConnection(con)
 
 	return con.connection.Close()
 }
@@ -138,9 +214,14 @@ func (con *HAPConnection) SetWriteDeadline(t time.Time) error {
 	return con.connection.SetWriteDeadline(t)
 }
 
+// Context returns the context of the connection.
+func (con *HAPConnection) Context() HAPContext {
+	return con.context
+}
+
 // getEncrypter returns the session's Encrypter, otherwise nil
 func (con *HAPConnection) getEncrypter() crypto.Encrypter {
-	session := con.context.GetSessionForConnection(con.connection)
+	session := con.context.GetSessionForConnection(con)
 	if session != nil {
 		return session.Encrypter()
 	}
@@ -150,7 +231,7 @@ func (con *HAPConnection) getEncrypter() crypto.Encrypter {
 
 // getDecrypter returns the session's Decrypter, otherwise nil
 func (con *HAPConnection) getDecrypter() crypto.Decrypter {
-	session := con.context.GetSessionForConnection(con.connection)
+	session := con.context.GetSessionForConnection(con)
 	if session != nil {
 		return session.Decrypter()
 	}
@@ -0,0 +1,264 @@
+package netio
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/brutella/hc/crypto"
+)
+
+func TestHAPConnectionOverPipe(t *testing.T) {
+	var key [32]byte
+	copy(key[:], []byte("shared key negotiated by verify"))
+
+	server, err := crypto.NewSecureSessionFromSharedKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := crypto.NewSecureClientSessionFromSharedKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := NewContextForSecuredDevice(nil)
+	local, remote := net.Pipe()
+	conn := NewHAPConnection(local, ctx)
+	ctx.GetSessionForConnection(conn).SetCryptographer(server)
+
+	go func() {
+		encrypted, err := client.Encrypt(bytes.NewBufferString("GET /accessories HTTP/1.1\r\n"))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		b, _ := ioutil.ReadAll(encrypted)
+		remote.Write(b)
+	}()
+
+	b := make([]byte, 64)
+	n, err := conn.Read(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := string(b[:n]), "GET /accessories HTTP/1.1\r\n"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	conn.Close()
+	if s := ctx.GetSessionForConnection(conn); s != nil {
+		t.Fatal(s)
+	}
+}
+
+// Tests that the session keys can be rotated on a connection,
+// while the http server is reading the next request in the background.
+func TestHAPConnectionKeyRotation(t *testing.T) {
+	var keys [2][32]byte
+	copy(keys[0][:], []byte("first key"))
+	copy(keys[1][:], []byte("second key"))
+
+	ctx := NewContextForSecuredDevice(nil)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		if r.URL.Path != "/verify" {
+			w.Write([]byte("ok"))
+			return
+		}
+
+		i, _ := strconv.Atoi(r.URL.Query().Get("key"))
+		server, _ := crypto.NewSecureSessionFromSharedKey(keys[i])
+
+		session := ctx.GetSessionForRequest(r)
+		w.Header().Set("Content-Length", "8")
+		w.Write([]byte("verified"))
+		session.SetCryptographer(server)
+		w.(http.Flusher).Flush()
+		session.ActivateCryptographer()
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go Serve(ln, handler, ctx)
+
+	c, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.SetDeadline(time.Now().Add(5 * time.Second))
+	r := bufio.NewReader(c)
+
+	request := func(client crypto.Cryptographer, path string) string {
+		req := "POST " + path + " HTTP/1.1\r\nHost: hc\r\nContent-Length: 2\r\n\r\n{}"
+		b := []byte(req)
+		if client != nil {
+			encrypted, _ := client.Encrypt(bytes.NewBufferString(req))
+			b, _ = ioutil.ReadAll(encrypted)
+		}
+		if _, err := c.Write(b); err != nil {
+			t.Fatal(err)
+		}
+
+		var in io.Reader = r
+		if client != nil {
+			decrypted, err := client.Decrypt(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			in = decrypted
+		}
+
+		resp, err := http.ReadResponse(bufio.NewReader(in), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+
+		return string(body)
+	}
+
+	if is, want := request(nil, "/verify?key=0"), "verified"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	first, _ := crypto.NewSecureClientSessionFromSharedKey(keys[0])
+	if is, want := request(first, "/accessories"), "ok"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// Verify again on the encrypted connection
+	if is, want := request(first, "/verify?key=1"), "verified"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	second, _ := crypto.NewSecureClientSessionFromSharedKey(keys[1])
+	for i := 0; i < 3; i++ {
+		if is, want := request(second, "/accessories"), "ok"; is != want {
+			t.Fatalf("is=%v want=%v", is, want)
+		}
+	}
+}
+
+func TestCloseReleasesSession(t *testing.T) {
+	var key [32]byte
+	server, err := crypto.NewSecureSessionFromSharedKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := NewContextForSecuredDevice(nil)
+	local, remote := net.Pipe()
+	defer remote.Close()
+
+	conn := NewHAPConnection(local, ctx)
+	session := ctx.GetSessionForConnection(conn)
+	session.SetCryptographer(server)
+	session.ActivateCryptographer()
+
+	conn.Close()
+
+	if is, want := len(ctx.ActiveConnections()), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if session.Encrypter() != nil {
+		t.Fatal("session keys not released")
+	}
+}
+
+func TestConnectDisconnectCycles(t *testing.T) {
+	var key [32]byte
+	ctx := NewContextForSecuredDevice(nil)
+
+	cycle := func() {
+		local, remote := net.Pipe()
+		conn := NewHAPConnection(local, ctx)
+		cryptographer, _ := crypto.NewSecureSessionFromSharedKey(key)
+		ctx.GetSessionForConnection(conn).SetCryptographer(cryptographer)
+
+		// The controller disconnects
+		remote.Close()
+		if _, err := conn.Read(make([]byte, 1)); err == nil {
+			t.Fatal("expected error")
+		}
+		conn.Close()
+	}
+
+	// Warm up to allocate lazily initialized memory
+	for i := 0; i < 100; i++ {
+		cycle()
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	for i := 0; i < 5000; i++ {
+		cycle()
+	}
+
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+
+	if is, want := len(ctx.ActiveConnections()), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// Every connection allocates at least a 4 KB read buffer, which would
+	// add up to 20 MB if connections were retained.
+	if growth := int64(after.HeapAlloc) - int64(before.HeapAlloc); growth > 1<<20 {
+		t.Fatalf("heap grew by %d bytes", growth)
+	}
+}
+
+func TestWriteTimeout(t *testing.T) {
+	ctx := NewContextForSecuredDevice(nil)
+	local, remote := net.Pipe()
+	defer remote.Close()
+
+	conn := NewHAPConnection(local, ctx)
+	conn.WriteTimeout = 20 * time.Millisecond
+
+	// The remote end never reads
+	if _, err := conn.Write([]byte("EVENT/1.0 200 OK\r\n")); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if is, want := ctx.Connections().Len(), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestFlowStats(t *testing.T) {
+	ctx := NewContextForSecuredDevice(nil)
+	local, remote := net.Pipe()
+	defer remote.Close()
+
+	conn := NewHAPConnection(local, ctx)
+	go ioutil.ReadAll(remote)
+
+	if _, err := conn.Write([]byte("EVENT/1.0 200 OK\r\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	s := conn.FlowStats()
+	if is, want := s.BytesWritten, uint64(18); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := s.Unacknowledged, 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
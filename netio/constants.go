@@ -34,4 +34,7 @@ const (
 
 	// HTTPContentTypeHAPJson is the HTTP content type for json data
 	HTTPContentTypeHAPJson = "application/hap+json"
+
+	// HTTPContentTypeOctetStream is the HTTP content type for secure messages
+	HTTPContentTypeOctetStream = "application/octet-stream"
 )
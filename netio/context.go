@@ -1,6 +1,7 @@
 package netio
 
 import (
+	"context"
 	"net"
 	"net/http"
 	"sync"
@@ -15,15 +16,13 @@ type Context interface {
 
 // HAPContext sits on top of a normal context and provides convenient methods to store
 // and access session objects for a specific connection/request.
+//
+// Sessions are stored per connection. The connection of a request is carried
+// by the request context (see WithConnection), which the listener sets up
+// for every accepted connection.
 type HAPContext interface {
 	Context
 
-	// Returns a key to uniquely identify the connection
-	GetKey(c net.Conn) interface{}
-
-	// Returns the same key as for the underlying connection
-	GetConnectionKey(r *http.Request) interface{}
-
 	// Setter and getter for session
 	SetSessionForConnection(s Session, c net.Conn)
 	GetSessionForConnection(c net.Conn) Session
@@ -33,14 +32,32 @@ type HAPContext interface {
 	// Returns a list of active connections
 	ActiveConnections() []net.Conn
 
+	// Connections returns the registry of the active connections
+	Connections() *ConnectionRegistry
+
 	// Setter and getter for bridge
 	SetSecuredDevice(b SecuredDevice)
 	GetSecuredDevice() SecuredDevice
 }
 
+type connContextKey struct{}
+
+// WithConnection returns a copy of ctx which carries the connection c.
+// The returned context is used as the context of requests received over c.
+func WithConnection(ctx context.Context, c net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey{}, c)
+}
+
+// ConnectionFromContext returns the connection carried by ctx, or nil if there is none.
+func ConnectionFromContext(ctx context.Context) net.Conn {
+	c, _ := ctx.Value(connContextKey{}).(net.Conn)
+	return c
+}
+
 // HAPContext implementation
-type context struct {
-	storage map[interface{}]interface{}
+type hapContext struct {
+	storage     map[interface{}]interface{}
+	connections *ConnectionRegistry
 
 	// synchronize access because object is used by different goroutines
 	mutex *sync.Mutex
@@ -48,82 +65,77 @@ type context struct {
 
 // NewContextForSecuredDevice returns a new HAPContext
 func NewContextForSecuredDevice(b SecuredDevice) HAPContext {
-	ctx := context{
-		storage: map[interface{}]interface{}{},
-		mutex:   &sync.Mutex{},
+	ctx := hapContext{
+		storage:     map[interface{}]interface{}{},
+		connections: NewConnectionRegistry(),
+		mutex:       &sync.Mutex{},
 	}
 	ctx.SetSecuredDevice(b)
 	return &ctx
 }
 
-func (ctx *context) GetKey(c net.Conn) interface{} {
-	return c.RemoteAddr().String()
-}
-
-func (ctx *context) GetConnectionKey(r *http.Request) interface{} {
-	return r.RemoteAddr
-}
-
-func (ctx *context) Set(key, val interface{}) {
+func (ctx *hapContext) Set(key, val interface{}) {
 	ctx.mutex.Lock()
 	defer ctx.mutex.Unlock()
 	ctx.storage[key] = val
 }
 
-func (ctx *context) Get(key interface{}) interface{} {
+func (ctx *hapContext) Get(key interface{}) interface{} {
 	ctx.mutex.Lock()
 	defer ctx.mutex.Unlock()
 	return ctx.storage[key]
 }
 
-func (ctx *context) Delete(key interface{}) {
+func (ctx *hapContext) Delete(key interface{}) {
 	ctx.mutex.Lock()
 	defer ctx.mutex.Unlock()
 	delete(ctx.storage, key)
 }
 
 // HAP Context
-func (ctx *context) SetSessionForConnection(s Session, c net.Conn) {
-	key := ctx.GetKey(c)
-	ctx.Set(key, s)
+func (ctx *hapContext) SetSessionForConnection(s Session, c net.Conn) {
+	ctx.connections.Add(c, s)
 }
 
-func (ctx *context) GetSessionForConnection(c net.Conn) Session {
-	key := ctx.GetKey(c)
-	if session, ok := ctx.Get(key).(Session); ok == true {
-		return session
-	}
-	return nil
+func (ctx *hapContext) GetSessionForConnection(c net.Conn) Session {
+	return ctx.connections.Session(c)
 }
 
-func (ctx *context) GetSessionForRequest(r *http.Request) Session {
-	key := ctx.GetConnectionKey(r)
-	return ctx.Get(key).(Session)
+// GetSessionForRequest returns the session of the connection in the request context,
+// or nil if the request has no connection.
+func (ctx *hapContext) GetSessionForRequest(r *http.Request) Session {
+	if c := ConnectionFromContext(r.Context()); c != nil {
+		return ctx.GetSessionForConnection(c)
+	}
+
+	return nil
 }
 
-func (ctx *context) DeleteSessionForConnection(c net.Conn) {
-	key := ctx.GetKey(c)
-	ctx.Delete(key)
+// DeleteSessionForConnection removes the session of the connection c and releases its keys.
+func (ctx *hapContext) DeleteSessionForConnection(c net.Conn) {
+	if s, ok := ctx.connections.Remove(c); ok == true && s != nil {
+		s.Close()
+	}
 }
 
-// Returns a list of active connections
-func (ctx *context) ActiveConnections() []net.Conn {
+// Returns a list of active connections ordered by their id
+func (ctx *hapContext) ActiveConnections() []net.Conn {
 	var connections []net.Conn
-	ctx.mutex.Lock()
-	defer ctx.mutex.Unlock()
-	for _, v := range ctx.storage {
-		if s, ok := v.(Session); ok == true {
-			connections = append(connections, s.Connection())
-		}
+	for _, info := range ctx.connections.Connections() {
+		connections = append(connections, info.Conn)
 	}
 
 	return connections
 }
 
-func (ctx *context) SetSecuredDevice(d SecuredDevice) {
+func (ctx *hapContext) Connections() *ConnectionRegistry {
+	return ctx.connections
+}
+
+func (ctx *hapContext) SetSecuredDevice(d SecuredDevice) {
 	ctx.Set("device", d)
 }
 
-func (ctx *context) GetSecuredDevice() SecuredDevice {
+func (ctx *hapContext) GetSecuredDevice() SecuredDevice {
 	return ctx.Get("device").(SecuredDevice)
 }
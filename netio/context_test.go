@@ -0,0 +1,38 @@
+package netio
+
+import (
+	"net"
+	"net/http"
+	"testing"
+)
+
+func TestSessionForRequest(t *testing.T) {
+	ctx := NewContextForSecuredDevice(nil)
+
+	conn := &net.TCPConn{}
+	session := NewSession(conn)
+	ctx.SetSessionForConnection(session, conn)
+
+	r, err := http.NewRequest(MethodGET, "/accessories", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := ctx.GetSessionForRequest(r); s != nil {
+		t.Fatal(s)
+	}
+
+	r = r.WithContext(WithConnection(r.Context(), conn))
+	if is, want := ctx.GetSessionForRequest(r), session; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	ctx.DeleteSessionForConnection(conn)
+	if s := ctx.GetSessionForRequest(r); s != nil {
+		t.Fatal(s)
+	}
+
+	if is, want := len(ctx.ActiveConnections()), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
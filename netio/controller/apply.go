@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"github.com/brutella/hc/netio"
+	"github.com/brutella/hc/netio/data"
+
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CharacteristicID identifies a characteristic by the accessory id and instance id.
+type CharacteristicID struct {
+	AID int64
+	IID int64
+}
+
+func (id CharacteristicID) String() string {
+	return fmt.Sprintf("%d.%d", id.AID, id.IID)
+}
+
+// ApplyError is returned when values could not be applied.
+type ApplyError struct {
+	// Statuses contains the HAP status code of every characteristic which failed.
+	Statuses map[CharacteristicID]int
+}
+
+func (e *ApplyError) Error() string {
+	var ids []string
+	for id, status := range e.Statuses {
+		ids = append(ids, fmt.Sprintf("%v (%d)", id, status))
+	}
+	sort.Strings(ids)
+
+	return "applying values failed: " + strings.Join(ids, ", ")
+}
+
+// Apply writes values to the characteristics in the same way as a write by a client:
+// the values are validated, functions registered with OnBeforeRemoteUpdate and OnValueUpdateFromConn
+// are called, and events are sent to all connected clients.
+//
+// When a characteristic doesn't exist or is read-only, no value is written. A value which is
+// rejected by a characteristic doesn't roll back the values written before.
+// In both cases an *ApplyError is returned.
+func (ctr *CharacteristicController) Apply(values map[CharacteristicID]interface{}) error {
+	statuses := map[CharacteristicID]int{}
+	var chars []data.Characteristic
+	for id, v := range values {
+		if c := ctr.GetCharacteristic(id.AID, id.IID); c == nil {
+			statuses[id] = netio.StatusResourceDoesNotExist
+		} else if c.IsWritable() == false {
+			statuses[id] = netio.StatusReadOnlyCharacteristic
+		}
+		chars = append(chars, data.Characteristic{AccessoryID: id.AID, CharacteristicID: id.IID, Value: v})
+	}
+
+	if len(statuses) > 0 {
+		return &ApplyError{statuses}
+	}
+
+	// Values are written in the order of the ids
+	sort.Stable(byID(chars))
+
+	written, _, failed := ctr.write(chars, localConn{})
+	if failed == false {
+		return nil
+	}
+
+	for _, c := range written {
+		if status, ok := c.Status.(int); ok == true && status != netio.StatusSuccess {
+			statuses[CharacteristicID{c.AccessoryID, c.CharacteristicID}] = status
+		}
+	}
+
+	return &ApplyError{statuses}
+}
+
+type byID []data.Characteristic
+
+func (s byID) Len() int      { return len(s) }
+func (s byID) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byID) Less(i, j int) bool {
+	if s[i].AccessoryID != s[j].AccessoryID {
+		return s[i].AccessoryID < s[j].AccessoryID
+	}
+	return s[i].CharacteristicID < s[j].CharacteristicID
+}
+
+// localConn is the connection over which values are applied by the accessory itself.
+// It is not a connection of a client and therefore all clients receive events.
+type localConn struct{}
+
+func (localConn) Read(b []byte) (int, error)         { return 0, io.EOF }
+func (localConn) Write(b []byte) (int, error)        { return len(b), nil }
+func (localConn) Close() error                       { return nil }
+func (localConn) LocalAddr() net.Addr                { return localAddr{} }
+func (localConn) RemoteAddr() net.Addr               { return localAddr{} }
+func (localConn) SetDeadline(t time.Time) error      { return nil }
+func (localConn) SetReadDeadline(t time.Time) error  { return nil }
+func (localConn) SetWriteDeadline(t time.Time) error { return nil }
+
+type localAddr struct{}
+
+func (localAddr) Network() string { return "local" }
+func (localAddr) String() string  { return "local" }
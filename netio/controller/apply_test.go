@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/netio"
+
+	"net"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "My Switch"})
+	b := accessory.NewSwitch(accessory.Info{Name: "My Other Switch"})
+
+	var conns []net.Conn
+	a.Switch.On.OnValueRemoteUpdate(func(on bool) {})
+	a.Switch.On.OnValueUpdateFromConn(func(conn net.Conn, c *characteristic.Characteristic, new, old interface{}) {
+		conns = append(conns, conn)
+	})
+
+	m := accessory.NewContainer()
+	m.AddAccessory(a.Accessory)
+	m.AddAccessory(b.Accessory)
+
+	ctr := NewCharacteristicController(m)
+	err := ctr.Apply(map[CharacteristicID]interface{}{
+		CharacteristicID{a.ID, a.Switch.On.ID}: true,
+		CharacteristicID{b.ID, b.Switch.On.ID}: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := a.Switch.On.GetValue(), true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := b.Switch.On.GetValue(), true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := len(conns), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestApplyIsAtomic(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "My Switch"})
+
+	m := accessory.NewContainer()
+	m.AddAccessory(a.Accessory)
+
+	ctr := NewCharacteristicController(m)
+	err := ctr.Apply(map[CharacteristicID]interface{}{
+		CharacteristicID{a.ID, a.Switch.On.ID}:         true,
+		CharacteristicID{a.ID, a.Info.Name.ID}:         "Other Name",
+		CharacteristicID{a.ID + 1, a.Switch.On.ID + 1}: true,
+	})
+
+	ae, ok := err.(*ApplyError)
+	if ok == false {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if is, want := len(ae.Statuses), 2; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := ae.Statuses[CharacteristicID{a.ID, a.Info.Name.ID}], netio.StatusReadOnlyCharacteristic; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := a.Switch.On.GetValue(), false; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestApplyRejected(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "My Switch"})
+	a.Switch.On.OnBeforeRemoteUpdate(func(new interface{}) error {
+		return characteristic.NewStatusError(netio.StatusResourceBusy, "switch is jammed")
+	})
+
+	m := accessory.NewContainer()
+	m.AddAccessory(a.Accessory)
+
+	ctr := NewCharacteristicController(m)
+	err := ctr.Apply(map[CharacteristicID]interface{}{
+		CharacteristicID{a.ID, a.Switch.On.ID}: true,
+	})
+
+	ae, ok := err.(*ApplyError)
+	if ok == false {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if is, want := ae.Statuses[CharacteristicID{a.ID, a.Switch.On.ID}], netio.StatusResourceBusy; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
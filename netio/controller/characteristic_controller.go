@@ -2,28 +2,59 @@ package controller
 
 import (
 	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/audit"
 	"github.com/brutella/hc/characteristic"
 	"github.com/brutella/hc/netio"
 	"github.com/brutella/hc/netio/data"
 	"github.com/brutella/log"
-	"github.com/gosexy/to"
 
 	"bytes"
 	"encoding/json"
-
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net"
 	"net/url"
+	"runtime/debug"
 	"strings"
+	"sync"
+	"time"
 )
 
 // CharacteristicController implements the CharacteristicsHandler interface and provides
 // read (GET) and write (POST) interfaces to the managed characteristics.
 type CharacteristicController struct {
 	container *accessory.Container
+
+	// AuditLog records writes to sensitive characteristics.
+	AuditLog *audit.Log
+
+	// Timeout is the deadline of read and write callbacks of a characteristic.
+	// When a callback takes longer, the client receives the status netio.StatusOperationTimedOut
+	// and the callback continues in the background. The value which is written or read by a
+	// late callback is dropped. A value which was set before a change function timed out
+	// stays set. When zero, there is no deadline.
+	Timeout time.Duration
+
+	// SlowThreshold is the duration after which a callback is reported as slow.
+	// When zero, callbacks are not reported.
+	SlowThreshold time.Duration
+
+	// Slow is called with the duration of a callback which took longer than SlowThreshold.
+	Slow SlowFunc
+
+	// Subscribed is called when a connection enables or disables events of a characteristic.
+	Subscribed func(conn net.Conn, aid, iid int64, enabled bool)
+
+	// IsSubscribed returns true when a connection enabled events of a characteristic.
+	// It is used to answer read requests with `ev=1`. When nil, events are reported as disabled.
+	IsSubscribed func(conn net.Conn, aid, iid int64) bool
 }
 
+// SlowFunc is called when a callback of the characteristic with the accessory id aid
+// and instance id iid took d.
+type SlowFunc func(aid, iid int64, d time.Duration)
+
 // NewCharacteristicController returns a new characteristic controller.
 func NewCharacteristicController(m *accessory.Container) *CharacteristicController {
 	return &CharacteristicController{container: m}
@@ -31,81 +62,329 @@ func NewCharacteristicController(m *accessory.Container) *CharacteristicControll
 
 // HandleGetCharacteristics handles a get characteristic request like `/characteristics?id=1.4,1.5`
 func (ctr *CharacteristicController) HandleGetCharacteristics(form url.Values) (io.Reader, error) {
+	return ctr.HandleGetLocalizedCharacteristics(form, nil)
+}
+
+// HandleGetLocalizedCharacteristics handles a get characteristic request and returns
+// the localized values for the preferred locales.
+func (ctr *CharacteristicController) HandleGetLocalizedCharacteristics(form url.Values, locales []string) (io.Reader, error) {
+	return ctr.HandleGetCharacteristicsForConn(form, locales, nil)
+}
+
+// HandleGetCharacteristicsForConn handles a get characteristic request of conn like
+// `/characteristics?id=1.4,1.5&meta=1&perms=1&type=1&ev=1` and returns the localized
+// values for the preferred locales.
+//
+// If a read failed, e.g. because a characteristic doesn't exist, the method returns
+// the status of every characteristic as *netio.MultiStatus.
+//
+// The optional query parameters request the metadata (format, unit, min/max values, ...),
+// the permissions, the type and whether conn enabled events of the characteristics.
+func (ctr *CharacteristicController) HandleGetCharacteristicsForConn(form url.Values, locales []string, conn net.Conn) (io.Reader, error) {
 	var b bytes.Buffer
-	var chs []data.Characteristic
+	opts := readOptionsFromForm(form)
 
 	// id=1.4,1.5
-	paths := strings.Split(form.Get("id"), ",")
-	for _, p := range paths {
-		if ids := strings.Split(p, "."); len(ids) == 2 {
-			aid := to.Int64(ids[0]) // accessory id
-			iid := to.Int64(ids[1]) // instance id (= characteristic id)
+	ids := form.Get("id")
+	chs := make([]data.Characteristic, 0, strings.Count(ids, ",")+1)
+	for len(ids) > 0 {
+		var p string
+		if i := strings.IndexByte(ids, ','); i >= 0 {
+			p, ids = ids[:i], ids[i+1:]
+		} else {
+			p, ids = ids, ""
+		}
+
+		if aid, iid, ok := parseCharacteristicID(p); ok == true {
 			c := data.Characteristic{AccessoryID: aid, CharacteristicID: iid}
-			if ch := ctr.GetCharacteristic(aid, iid); ch != nil {
-				c.Value = ch.Value
-			} else {
+			ch := ctr.GetCharacteristic(aid, iid)
+			if ch != nil {
+				opts.apply(&c, ch)
+				if opts.events == true {
+					c.Events = ctr.IsSubscribed != nil && conn != nil && ctr.IsSubscribed(conn, aid, iid)
+				}
+			}
+
+			if ch == nil {
+				// e.g. the accessory was removed from the bridge
+				c.Status = netio.StatusResourceDoesNotExist
+			} else if status, ok := netio.InjectedStatus(); ok == true {
+				c.Status = status
+			} else if a := ctr.container.AccessoryByAID(aid); a.IsReachable() == false && a.FailReadsWhenUnreachable == true {
 				c.Status = netio.StatusServiceCommunicationFailure
+			} else if v, err := ctr.readValue(aid, ch, locales); err != nil {
+				log.Printf("[WARN] Read of characteristic with aid %d and iid %d failed: %v\n", aid, iid, err)
+				c.Status = statusForError(err)
+			} else {
+				c.Value = v
 			}
 			chs = append(chs, c)
 		}
 	}
 
-	result, err := json.Marshal(&data.Characteristics{chs})
+	// The status of every characteristic is returned when a read failed
+	failed := false
+	for _, c := range chs {
+		if c.Status != nil {
+			failed = true
+			break
+		}
+	}
+
+	if failed == true {
+		for i := range chs {
+			if chs[i].Status == nil {
+				chs[i].Status = netio.StatusSuccess
+			}
+		}
+	}
+
+	result, err := data.Characteristics{Characteristics: chs}.AppendJSON(make([]byte, 0, 64*len(chs)))
 	if err != nil {
 		log.Println("[ERRO]", err)
 	}
 
 	b.Write(result)
+	if failed == true {
+		return &netio.MultiStatus{Reader: &b}, err
+	}
+
 	return &b, err
 }
 
 // HandleUpdateCharacteristics handles an update characteristic request. The bytes must represent
 // a data.Characteristics json.
-func (ctr *CharacteristicController) HandleUpdateCharacteristics(r io.Reader, conn net.Conn) error {
+//
+// If the request asks for write responses (`"r": true`), the method returns the new values
+// of those characteristics as data.Characteristics json. If a characteristic rejected a value,
+// the method returns the status of every characteristic. Otherwise the returned reader is nil.
+func (ctr *CharacteristicController) HandleUpdateCharacteristics(r io.Reader, conn net.Conn) (io.Reader, error) {
 	b, err := ioutil.ReadAll(r)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var chars data.Characteristics
 	err = json.Unmarshal(b, &chars)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	log.Println("[VERB]", string(b))
 
-	for _, c := range chars.Characteristics {
+	statuses, responses, failed := ctr.write(chars.Characteristics, conn)
+
+	// The status of every characteristic is returned when a write failed
+	if failed == true {
+		responses = statuses
+	}
+
+	if len(responses) == 0 {
+		return nil, err
+	}
+
+	result, err := data.Characteristics{Characteristics: responses}.AppendJSON(make([]byte, 0, 64*len(responses)))
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBuffer(result), nil
+}
+
+// write writes the values of chars over conn and returns the status of every characteristic,
+// the write responses and whether a write failed.
+func (ctr *CharacteristicController) write(chars []data.Characteristic, conn net.Conn) (statuses []data.Characteristic, responses []data.Characteristic, failed bool) {
+	for _, c := range chars {
 		characteristic := ctr.GetCharacteristic(c.AccessoryID, c.CharacteristicID)
 		if characteristic == nil {
 			log.Printf("[ERRO] Could not find characteristic with aid %d and iid %d\n", c.AccessoryID, c.CharacteristicID)
+			statuses = append(statuses, data.Characteristic{
+				AccessoryID:      c.AccessoryID,
+				CharacteristicID: c.CharacteristicID,
+				Status:           netio.StatusResourceDoesNotExist,
+			})
+			failed = true
 			continue
 		}
 
-		if c.Value != nil {
-			characteristic.UpdateValueFromConnection(c.Value, conn)
+		status := netio.StatusSuccess
+		if injected, ok := netio.InjectedStatus(); ok == true {
+			status = injected
+			failed = true
+		} else if c.Value != nil && characteristic.IsWritable() == false {
+			log.Printf("[WARN] Characteristic with aid %d and iid %d is read-only\n", c.AccessoryID, c.CharacteristicID)
+			status = netio.StatusReadOnlyCharacteristic
+			failed = true
+		} else if c.Value != nil {
+			if err := ctr.updateValue(c.AccessoryID, characteristic, c.Value, conn); err != nil {
+				log.Printf("[WARN] Write of characteristic with aid %d and iid %d rejected: %v\n", c.AccessoryID, c.CharacteristicID, err)
+				status = statusForError(err)
+				failed = true
+			} else if ctr.AuditLog.IsSensitive(characteristic) == true {
+				r := audit.Record{
+					Operation:        audit.OperationCharacteristicWrite,
+					AccessoryID:      c.AccessoryID,
+					CharacteristicID: c.CharacteristicID,
+					Value:            c.Value,
+				}
+				if conn != nil && conn.RemoteAddr() != nil {
+					r.Addr = conn.RemoteAddr().String()
+				}
+				ctr.AuditLog.Record(r)
+			}
 		}
 
 		if events, ok := c.Events.(bool); ok == true {
 			characteristic.SetEventsEnabled(events)
+			if ctr.Subscribed != nil {
+				ctr.Subscribed(conn, c.AccessoryID, c.CharacteristicID, events)
+			}
+		}
+
+		statuses = append(statuses, data.Characteristic{
+			AccessoryID:      c.AccessoryID,
+			CharacteristicID: c.CharacteristicID,
+			Status:           status,
+		})
+
+		if response, ok := c.Response.(bool); ok == true && response == true {
+			responses = append(responses, data.Characteristic{
+				AccessoryID:      c.AccessoryID,
+				CharacteristicID: c.CharacteristicID,
+				Value:            characteristic.Value,
+				Status:           status,
+			})
 		}
 	}
 
-	return err
+	return
 }
 
-// GetCharacteristic returns the characteristic identified by the accessory id aid and characteristic id iid
-func (ctr *CharacteristicController) GetCharacteristic(aid int64, iid int64) *characteristic.Characteristic {
-	for _, a := range ctr.container.Accessories {
-		if a.GetID() == aid {
-			for _, s := range a.GetServices() {
-				for _, c := range s.GetCharacteristics() {
-					if c.GetID() == iid {
-						return c
-					}
-				}
-			}
+// readValue returns the localized value or the value returned by the read callback of c.
+func (ctr *CharacteristicController) readValue(aid int64, c *characteristic.Characteristic, locales []string) (interface{}, error) {
+	if c.IsLocalized() == true {
+		return c.LocalizedValue(locales), nil
+	}
+
+	var v interface{}
+	err := ctr.call(aid, c, func(commit characteristic.CommitFunc) error {
+		value, err := c.CommitReadValue(commit)
+		commit(func() {
+			v = value
+		})
+		return err
+	})
+
+	return v, err
+}
+
+// updateValue updates the value of c from conn.
+func (ctr *CharacteristicController) updateValue(aid int64, c *characteristic.Characteristic, value interface{}, conn net.Conn) error {
+	return ctr.call(aid, c, func(commit characteristic.CommitFunc) error {
+		return c.CommitValueFromConnection(value, conn, commit)
+	})
+}
+
+// call calls fn, which calls the callbacks of c, within the deadline of the controller.
+// fn must change c and its results only through commit, which drops the changes
+// after the deadline expired. A panic in fn is returned as error.
+func (ctr *CharacteristicController) call(aid int64, c *characteristic.Characteristic, fn func(commit characteristic.CommitFunc) error) error {
+	d := &deadline{}
+	call := func() error {
+		return fn(d.commit)
+	}
+
+	start := time.Now()
+	if ctr.Timeout <= 0 {
+		err := recoverCall(c, call)
+		ctr.measure(aid, c, time.Since(start))
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- recoverCall(c, call)
+	}()
+
+	timer := time.NewTimer(ctr.Timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		ctr.measure(aid, c, time.Since(start))
+		return err
+	case <-timer.C:
+		d.expire()
+		log.Printf("[WARN] Callback of characteristic with aid %d and iid %d timed out after %v\n", aid, c.ID, ctr.Timeout)
+		go func() {
+			<-done
+			ctr.measure(aid, c, time.Since(start))
+		}()
+		return characteristic.NewStatusError(netio.StatusOperationTimedOut, "timed out")
+	}
+}
+
+// deadline drops the changes of a callback after it expired.
+type deadline struct {
+	mutex   sync.Mutex
+	expired bool
+}
+
+// commit is a characteristic.CommitFunc, which calls set when the deadline didn't expire.
+func (d *deadline) commit(set func()) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.expired == true {
+		return false
+	}
+	set()
+
+	return true
+}
+
+// expire drops all further changes. A running change is finished before.
+func (d *deadline) expire() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.expired = true
+}
+
+// measure reports a callback of c which took d, if it was slow.
+func (ctr *CharacteristicController) measure(aid int64, c *characteristic.Characteristic, d time.Duration) {
+	if ctr.SlowThreshold <= 0 || d < ctr.SlowThreshold {
+		return
+	}
+
+	log.Printf("[WARN] Callback of characteristic with aid %d and iid %d took %v\n", aid, c.ID, d)
+	if ctr.Slow != nil {
+		ctr.Slow(aid, c.ID, d)
+	}
+}
+
+// recoverCall calls fn and returns a panic in fn as error.
+func recoverCall(c *characteristic.Characteristic, fn func() error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			log.Printf("[ERRO] Panic in callback of characteristic %d: %v\n%s", c.ID, v, debug.Stack())
+			err = characteristic.NewStatusError(netio.StatusServiceCommunicationFailure, fmt.Sprint(v))
 		}
+	}()
+
+	return fn()
+}
+
+// statusForError returns the HAP status code of an error returned by a characteristic.
+func statusForError(err error) int {
+	if se, ok := err.(*characteristic.StatusError); ok == true {
+		return se.Status
 	}
-	return nil
+
+	return netio.StatusServiceCommunicationFailure
+}
+
+// GetCharacteristic returns the characteristic identified by the accessory id aid and characteristic id iid
+func (ctr *CharacteristicController) GetCharacteristic(aid int64, iid int64) *characteristic.Characteristic {
+	return ctr.container.CharacteristicByIDs(aid, iid)
 }
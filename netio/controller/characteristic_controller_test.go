@@ -2,7 +2,9 @@ package controller
 
 import (
 	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/audit"
 	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/netio"
 	"github.com/brutella/hc/netio/data"
 	"github.com/brutella/hc/service"
 
@@ -10,8 +12,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/url"
 	"testing"
+	"time"
 )
 
 func idsString(accessoryID, characteristicID int64) url.Values {
@@ -112,13 +116,540 @@ func TestPutCharacteristic(t *testing.T) {
 	buffer.Write(b)
 
 	controller := NewCharacteristicController(m)
-	err = controller.HandleUpdateCharacteristics(&buffer, characteristic.TestConn)
+	res, err := controller.HandleUpdateCharacteristics(&buffer, characteristic.TestConn)
 
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	if res != nil {
+		t.Fatal("expected no response")
+	}
+
 	if is, want := a.Switch.On.GetValue(), true; is != want {
 		t.Fatalf("is=%v want=%v", is, want)
 	}
 }
+
+func TestPutCharacteristicWithResponse(t *testing.T) {
+	info := accessory.Info{
+		Name: "My Switch",
+	}
+
+	a := accessory.NewSwitch(info)
+	a.Switch.On.SetValue(false)
+
+	m := accessory.NewContainer()
+	m.AddAccessory(a.Accessory)
+
+	char := data.Characteristic{AccessoryID: 1, CharacteristicID: a.Switch.On.ID, Value: true, Response: true}
+	b, err := json.Marshal(data.Characteristics{Characteristics: []data.Characteristic{char}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller := NewCharacteristicController(m)
+	res, err := controller.HandleUpdateCharacteristics(bytes.NewBuffer(b), characteristic.TestConn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res == nil {
+		t.Fatal("expected response")
+	}
+
+	b, err = ioutil.ReadAll(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var chars data.Characteristics
+	if err := json.Unmarshal(b, &chars); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := len(chars.Characteristics), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := chars.Characteristics[0].Value, true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestPutCharacteristicRejected(t *testing.T) {
+	info := accessory.Info{
+		Name: "My Switch",
+	}
+
+	a := accessory.NewSwitch(info)
+	a.Switch.On.SetValue(false)
+	a.Switch.On.OnBeforeRemoteUpdate(func(new interface{}) error {
+		return characteristic.NewStatusError(netio.StatusResourceBusy, "switch is jammed")
+	})
+
+	m := accessory.NewContainer()
+	m.AddAccessory(a.Accessory)
+
+	char := data.Characteristic{AccessoryID: 1, CharacteristicID: a.Switch.On.ID, Value: true}
+	b, err := json.Marshal(data.Characteristics{Characteristics: []data.Characteristic{char}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller := NewCharacteristicController(m)
+	res, err := controller.HandleUpdateCharacteristics(bytes.NewBuffer(b), characteristic.TestConn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res == nil {
+		t.Fatal("expected response")
+	}
+
+	b, err = ioutil.ReadAll(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var chars data.Characteristics
+	if err := json.Unmarshal(b, &chars); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := len(chars.Characteristics), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := chars.Characteristics[0].Status, float64(netio.StatusResourceBusy); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := a.Switch.On.GetValue(), false; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+// writeStatus writes value to the characteristic with aid and iid and returns the status in the response.
+func writeStatus(t *testing.T, controller *CharacteristicController, aid, iid int64, value interface{}) interface{} {
+	char := data.Characteristic{AccessoryID: aid, CharacteristicID: iid, Value: value}
+	b, err := json.Marshal(data.Characteristics{Characteristics: []data.Characteristic{char}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := controller.HandleUpdateCharacteristics(bytes.NewBuffer(b), characteristic.TestConn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res == nil {
+		return float64(netio.StatusSuccess)
+	}
+
+	var chars data.Characteristics
+	if err := json.NewDecoder(res).Decode(&chars); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := len(chars.Characteristics), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	return chars.Characteristics[0].Status
+}
+
+func TestPutInvalidBase64(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "My Switch"})
+	b := characteristic.NewBytes(characteristic.TypeLogs)
+	b.Format = characteristic.FormatTLV8
+	b.Perms = characteristic.PermsAll()
+	a.AddCharacteristic(a.Switch.Service, b.Characteristic)
+
+	m := accessory.NewContainer()
+	m.AddAccessory(a.Accessory)
+
+	controller := NewCharacteristicController(m)
+	if is, want := writeStatus(t, controller, 1, b.ID, "not base64!"), float64(netio.StatusInvalidValueInRequest); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestPutValueExceedingMaxLen(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "My Switch"})
+	str := characteristic.NewString(characteristic.TypeName)
+	str.Format = characteristic.FormatString
+	str.Perms = characteristic.PermsAll()
+	str.SetValue("")
+	str.SetMaxLen(4)
+	a.AddCharacteristic(a.Switch.Service, str.Characteristic)
+
+	m := accessory.NewContainer()
+	m.AddAccessory(a.Accessory)
+
+	controller := NewCharacteristicController(m)
+	if is, want := writeStatus(t, controller, 1, str.ID, "abcde"), float64(netio.StatusInvalidValueInRequest); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := str.GetValue(), ""; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := writeStatus(t, controller, 1, str.ID, "abcd"), float64(netio.StatusSuccess); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestPutValueInvalidForUnit(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "My Switch"})
+	number := characteristic.NewInt(characteristic.TypeBrightness)
+	number.Format = characteristic.FormatInt32
+	number.Unit = characteristic.UnitPercentage
+	number.Perms = characteristic.PermsAll()
+	number.SetValue(50)
+	a.AddCharacteristic(a.Switch.Service, number.Characteristic)
+
+	m := accessory.NewContainer()
+	m.AddAccessory(a.Accessory)
+
+	controller := NewCharacteristicController(m)
+	if is, want := writeStatus(t, controller, 1, number.ID, 120), float64(netio.StatusInvalidValueInRequest); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestGetCharacteristicOfUnreachableAccessory(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "My Switch"})
+	a.FailReadsWhenUnreachable = true
+	a.SetReachable(false)
+
+	m := accessory.NewContainer()
+	m.AddAccessory(a.Accessory)
+
+	controller := NewCharacteristicController(m)
+	res, err := controller.HandleGetCharacteristics(idsString(a.GetID(), a.Switch.On.GetID()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadAll(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var chars data.Characteristics
+	if err := json.Unmarshal(b, &chars); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := chars.Characteristics[0].Status, float64(netio.StatusServiceCommunicationFailure); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if chars.Characteristics[0].Value != nil {
+		t.Fatal("expected no value")
+	}
+}
+
+type memorySink struct {
+	records []audit.Record
+}
+
+func (s *memorySink) Append(r audit.Record) error {
+	s.records = append(s.records, r)
+	return nil
+}
+
+func TestPutSensitiveCharacteristic(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "My Switch"})
+	a.Switch.On.SetValue(false)
+
+	m := accessory.NewContainer()
+	m.AddAccessory(a.Accessory)
+
+	sink := &memorySink{}
+	controller := NewCharacteristicController(m)
+	controller.AuditLog = audit.NewLog(sink)
+	controller.AuditLog.AddSensitiveType(characteristic.TypeOn)
+
+	cid := a.Switch.On.GetID()
+	b, _ := json.Marshal(data.Characteristics{Characteristics: []data.Characteristic{
+		{AccessoryID: 1, CharacteristicID: cid, Value: true},
+	}})
+
+	if _, err := controller.HandleUpdateCharacteristics(bytes.NewBuffer(b), characteristic.TestConn); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := len(sink.records), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	r := sink.records[0]
+	if is, want := r.Operation, audit.OperationCharacteristicWrite; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := r.CharacteristicID, cid; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestPutCharacteristicWithPanickingCallback(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "My Switch"})
+	a.Switch.On.OnValueRemoteUpdate(func(on bool) {
+		panic("callback failed")
+	})
+
+	m := accessory.NewContainer()
+	m.AddAccessory(a.Accessory)
+
+	chars := data.Characteristics{Characteristics: []data.Characteristic{
+		{AccessoryID: 1, CharacteristicID: a.Switch.On.ID, Value: true},
+	}}
+	b, err := json.Marshal(chars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller := NewCharacteristicController(m)
+	res, err := controller.HandleUpdateCharacteristics(bytes.NewBuffer(b), characteristic.TestConn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var statuses data.Characteristics
+	if err := json.NewDecoder(res).Decode(&statuses); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := statuses.Characteristics[0].Status, float64(netio.StatusServiceCommunicationFailure); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestGetCharacteristicTimeout(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "My Switch"})
+	block := make(chan struct{})
+	defer close(block)
+	a.Switch.On.OnValueRead(func() (interface{}, error) {
+		<-block
+		return true, nil
+	})
+
+	m := accessory.NewContainer()
+	m.AddAccessory(a.Accessory)
+
+	var slow int64
+	controller := NewCharacteristicController(m)
+	controller.Timeout = 10 * time.Millisecond
+	controller.SlowThreshold = time.Millisecond
+	controller.Slow = func(aid, iid int64, d time.Duration) {
+		slow = iid
+	}
+
+	res, err := controller.HandleGetCharacteristics(idsString(1, a.Switch.On.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var chars data.Characteristics
+	if err := json.NewDecoder(res).Decode(&chars); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := chars.Characteristics[0].Status, float64(netio.StatusOperationTimedOut); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := slow, int64(0); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestPutCharacteristicTimeout(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "My Switch"})
+	a.Switch.On.SetValue(false)
+
+	block := make(chan struct{})
+	a.Switch.On.OnBeforeRemoteUpdate(func(new interface{}) error {
+		<-block
+		return nil
+	})
+
+	m := accessory.NewContainer()
+	m.AddAccessory(a.Accessory)
+
+	finished := make(chan struct{})
+	controller := NewCharacteristicController(m)
+	controller.Timeout = 10 * time.Millisecond
+	controller.SlowThreshold = time.Millisecond
+	controller.Slow = func(aid, iid int64, d time.Duration) {
+		close(finished)
+	}
+
+	if is, want := writeStatus(t, controller, 1, a.Switch.On.ID, true), float64(netio.StatusOperationTimedOut); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// The late callback must not set the value while it is read
+	close(block)
+	if _, err := controller.HandleGetCharacteristics(idsString(1, a.Switch.On.ID)); err != nil {
+		t.Fatal(err)
+	}
+	<-finished
+
+	if is, want := a.Switch.On.GetValue(), false; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestGetCharacteristicWithReadFunc(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "My Switch"})
+	a.Switch.On.OnValueRead(func() (interface{}, error) {
+		return true, nil
+	})
+
+	m := accessory.NewContainer()
+	m.AddAccessory(a.Accessory)
+
+	controller := NewCharacteristicController(m)
+	controller.Timeout = time.Second
+	res, err := controller.HandleGetCharacteristics(idsString(1, a.Switch.On.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var chars data.Characteristics
+	if err := json.NewDecoder(res).Decode(&chars); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := chars.Characteristics[0].Value, true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := a.Switch.On.GetValue(), true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestGetCharacteristicWithMetadata(t *testing.T) {
+	l := accessory.NewLightbulb(accessory.Info{Name: "My Lightbulb"})
+
+	m := accessory.NewContainer()
+	m.AddAccessory(l.Accessory)
+
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	controller := NewCharacteristicController(m)
+	controller.IsSubscribed = func(conn net.Conn, aid, iid int64) bool {
+		return conn == server && iid == l.Lightbulb.Brightness.ID
+	}
+
+	values := url.Values{}
+	values.Set("id", fmt.Sprintf("%d.%d,%d.%d", l.Accessory.ID, l.Lightbulb.On.ID, l.Accessory.ID, l.Lightbulb.Brightness.ID))
+	values.Set("meta", "1")
+	values.Set("perms", "1")
+	values.Set("type", "1")
+	values.Set("ev", "1")
+
+	res, err := controller.HandleGetCharacteristicsForConn(values, nil, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var chars data.Characteristics
+	if err := json.NewDecoder(res).Decode(&chars); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := len(chars.Characteristics), 2; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	on, brightness := chars.Characteristics[0], chars.Characteristics[1]
+	if is, want := on.Type, characteristic.TypeOn; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := on.Format, characteristic.FormatBool; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := len(on.Perms), 3; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := on.Events, false; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := brightness.Unit, characteristic.UnitPercentage; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := brightness.MaxValue, float64(100); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := brightness.Events, true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestParseCharacteristicID(t *testing.T) {
+	var tests = []struct {
+		id  string
+		aid int64
+		iid int64
+		ok  bool
+	}{
+		{"1.4", 1, 4, true},
+		{"12.345", 12, 345, true},
+		{"1.x", 1, 0, true},
+		{"1", 0, 0, false},
+		{"1.2.3", 0, 0, false},
+		{"", 0, 0, false},
+	}
+
+	for _, test := range tests {
+		aid, iid, ok := parseCharacteristicID(test.id)
+		if aid != test.aid || iid != test.iid || ok != test.ok {
+			t.Fatalf("%s is=%d.%d %v want=%d.%d %v", test.id, aid, iid, ok, test.aid, test.iid, test.ok)
+		}
+	}
+}
+
+func TestGetRemovedCharacteristic(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "My Switch"})
+
+	m := accessory.NewContainer()
+	m.AddAccessory(a.Accessory)
+
+	values := url.Values{}
+	values.Set("id", fmt.Sprintf("%d.%d,%d.%d", a.Accessory.ID, a.Switch.On.ID, a.Accessory.ID+1, a.Switch.On.ID))
+
+	controller := NewCharacteristicController(m)
+	res, err := controller.HandleGetCharacteristics(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := res.(*netio.MultiStatus); ok == false {
+		t.Fatal("expected multi-status response")
+	}
+
+	var chars data.Characteristics
+	if err := json.NewDecoder(res).Decode(&chars); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := chars.Characteristics[0].Status, float64(netio.StatusSuccess); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := chars.Characteristics[0].Value, false; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := chars.Characteristics[1].Status, float64(netio.StatusResourceDoesNotExist); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
 	"io"
 )
 
@@ -23,9 +25,109 @@ func (ctr *ContainerController) HandleGetAccessories(r io.Reader) (io.Reader, er
 	return bytes.NewBuffer(result), err
 }
 
+// HandleGetLocalizedAccessories returns the container as json bytes with
+// the localized values for the preferred locales.
+func (ctr *ContainerController) HandleGetLocalizedAccessories(r io.Reader, locales []string) (io.Reader, error) {
+	values := map[int64]map[int64]interface{}{}
+	if len(locales) > 0 {
+		ctr.container.ForEachCharacteristic(func(a *accessory.Accessory, s *service.Service, c *characteristic.Characteristic) {
+			if c.IsLocalized() == true {
+				if values[a.ID] == nil {
+					values[a.ID] = map[int64]interface{}{}
+				}
+				values[a.ID][c.ID] = c.LocalizedValue(locales)
+			}
+		})
+	}
+
+	if len(values) == 0 {
+		return ctr.HandleGetAccessories(r)
+	}
+
+	result, err := json.Marshal(ctr.container)
+	if err != nil {
+		return nil, err
+	}
+
+	if result, err = localize(result, values); err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBuffer(result), nil
+}
+
 // IdentifyAccessory calls Identify() for all accessories.
 func (ctr *ContainerController) IdentifyAccessory() {
 	for _, a := range ctr.container.Accessories {
 		a.Identify()
 	}
 }
+
+// localize replaces the characteristic values of the json encoded container b with values by aid and iid.
+// Unknown fields of accessories, services and characteristics are kept as they are.
+func localize(b []byte, values map[int64]map[int64]interface{}) ([]byte, error) {
+	var c struct {
+		Accessories []map[string]json.RawMessage `json:"accessories"`
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+
+	for _, a := range c.Accessories {
+		var aid int64
+		var services []map[string]json.RawMessage
+		if err := unmarshalFields(a, "aid", &aid, "services", &services); err != nil {
+			return nil, err
+		}
+
+		for _, s := range services {
+			var chars []map[string]json.RawMessage
+			if err := unmarshalFields(s, "characteristics", &chars); err != nil {
+				return nil, err
+			}
+
+			for _, ch := range chars {
+				var iid int64
+				if err := unmarshalFields(ch, "iid", &iid); err != nil {
+					return nil, err
+				}
+
+				if v, ok := values[aid][iid]; ok == true {
+					raw, err := json.Marshal(v)
+					if err != nil {
+						return nil, err
+					}
+					ch["value"] = raw
+				}
+			}
+
+			raw, err := json.Marshal(chars)
+			if err != nil {
+				return nil, err
+			}
+			s["characteristics"] = raw
+		}
+
+		raw, err := json.Marshal(services)
+		if err != nil {
+			return nil, err
+		}
+		a["services"] = raw
+	}
+
+	return json.Marshal(c)
+}
+
+// unmarshalFields decodes the json values of fields, which are pairs of key and value pointer.
+func unmarshalFields(fields map[string]json.RawMessage, pairs ...interface{}) error {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		key := pairs[i].(string)
+		if raw, ok := fields[key]; ok == true {
+			if err := json.Unmarshal(raw, pairs[i+1]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"testing"
 )
@@ -44,3 +45,65 @@ func TestGetAccessories(t *testing.T) {
 		t.Fatal("containers not the same")
 	}
 }
+
+func TestGetLocalizedAccessories(t *testing.T) {
+	a := accessory.NewSwitch(accessory.Info{Name: "Lamp"})
+	a.SetLocalizedName("de", "Lampe")
+
+	m := accessory.NewContainer()
+	m.AddAccessory(a.Accessory)
+
+	controller := NewContainerController(m)
+
+	r, err := controller.HandleGetLocalizedAccessories(nil, []string{"de-DE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := ioutil.ReadAll(r)
+	var returned accessory.Container
+	if err := json.Unmarshal(b, &returned); err != nil {
+		t.Fatal(err)
+	}
+
+	name := returned.Accessories[0].CharacteristicByIID(a.Info.Name.ID)
+	if is, want := name.Value, "Lampe"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// The original value is returned without a matching locale
+	r, err = controller.HandleGetLocalizedAccessories(nil, []string{"en"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ = ioutil.ReadAll(r)
+	if bytes.Contains(b, []byte("Lampe")) == true {
+		t.Fatal(string(b))
+	}
+}
+
+// Benchmarks the /accessories response of a bridge with 50 lightbulbs.
+func BenchmarkHandleGetAccessories(b *testing.B) {
+	m := accessory.NewContainer()
+	m.AddAccessory(accessory.New(accessory.Info{Name: "Bridge"}, accessory.TypeBridge))
+	for i := 0; i < 50; i++ {
+		l := accessory.NewLightbulb(accessory.Info{Name: fmt.Sprintf("Lightbulb %d", i)})
+		m.AddAccessory(l.Accessory)
+	}
+
+	controller := NewContainerController(m)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var buf bytes.Buffer
+		r, err := controller.HandleGetAccessories(&buf)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := ioutil.ReadAll(r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
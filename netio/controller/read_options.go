@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/netio/data"
+)
+
+// readOptions are the optional query parameters of a read request.
+type readOptions struct {
+	meta   bool // meta=1 includes format, unit, min/max values, min step and max lengths
+	perms  bool // perms=1 includes the permissions
+	typ    bool // type=1 includes the type
+	events bool // ev=1 includes whether events are enabled
+}
+
+func readOptionsFromForm(form url.Values) readOptions {
+	return readOptions{
+		meta:   isTrue(form.Get("meta")),
+		perms:  isTrue(form.Get("perms")),
+		typ:    isTrue(form.Get("type")),
+		events: isTrue(form.Get("ev")),
+	}
+}
+
+// apply sets the requested properties of ch in c.
+func (opts readOptions) apply(c *data.Characteristic, ch *characteristic.Characteristic) {
+	if opts.meta == true {
+		c.Format = ch.Format
+		c.Unit = ch.Unit
+		c.MinValue = ch.MinValue
+		c.MaxValue = ch.MaxValue
+		c.StepValue = ch.StepValue
+		c.MaxLen = ch.MaxLen
+		c.MaxDataLen = ch.MaxDataLen
+	}
+
+	if opts.perms == true {
+		c.Perms = ch.Perms
+	}
+
+	if opts.typ == true {
+		c.Type = ch.Type
+	}
+}
+
+// isTrue returns true for the query values "1" and "true".
+func isTrue(v string) bool {
+	return v == "1" || v == "true"
+}
+
+// parseCharacteristicID parses an id of the format "aid.iid", e.g. "1.4".
+// Invalid numbers are parsed as 0, which doesn't reference a characteristic.
+func parseCharacteristicID(s string) (aid int64, iid int64, ok bool) {
+	i := strings.IndexByte(s, '.')
+	if i < 0 || strings.IndexByte(s[i+1:], '.') >= 0 {
+		return 0, 0, false
+	}
+
+	aid, _ = strconv.ParseInt(strings.TrimSpace(s[:i]), 10, 64)
+	iid, _ = strconv.ParseInt(strings.TrimSpace(s[i+1:]), 10, 64)
+
+	return aid, iid, true
+}
@@ -28,4 +28,21 @@ type Characteristic struct {
 	// Events contains the events settings for a characteristic. Should be interpreted as boolean.
 	// The property is omited if not specified, which makes the payload smaller.
 	Events interface{} `json:"ev,omitempty"`
+
+	// Response is true when the controller requests the value in the write response. Should be interpreted as boolean.
+	// The property is omited if not specified, which makes the payload smaller.
+	Response interface{} `json:"r,omitempty"`
+
+	// Type, Perms and the metadata (Format, Unit, ...) are returned when a controller
+	// requests them with the query parameters type=1, perms=1 and meta=1.
+	// The properties are omited if not specified.
+	Type       string      `json:"type,omitempty"`
+	Perms      []string    `json:"perms,omitempty"`
+	Format     string      `json:"format,omitempty"`
+	Unit       string      `json:"unit,omitempty"`
+	MinValue   interface{} `json:"minValue,omitempty"`
+	MaxValue   interface{} `json:"maxValue,omitempty"`
+	StepValue  interface{} `json:"minStep,omitempty"`
+	MaxLen     int         `json:"maxLen,omitempty"`
+	MaxDataLen int         `json:"maxDataLen,omitempty"`
 }
@@ -0,0 +1,255 @@
+package data
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// AppendJSON appends the json encoding of cs to dst and returns the extended buffer.
+//
+// The encoding is the same as of json.Marshal, but the values of characteristics
+// (bool, integer, float and string types) are encoded without reflection and
+// allocations. Other values are encoded with json.Marshal.
+func (cs Characteristics) AppendJSON(dst []byte) ([]byte, error) {
+	dst = append(dst, `{"characteristics":`...)
+	if cs.Characteristics == nil {
+		return append(dst, "null}"...), nil
+	}
+
+	dst = append(dst, '[')
+	for i, c := range cs.Characteristics {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+
+		var err error
+		if dst, err = c.AppendJSON(dst); err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, "]}"...), nil
+}
+
+// AppendJSON appends the json encoding of c to dst and returns the extended buffer.
+func (c Characteristic) AppendJSON(dst []byte) ([]byte, error) {
+	var err error
+	dst = append(dst, `{"aid":`...)
+	dst = strconv.AppendInt(dst, c.AccessoryID, 10)
+	dst = append(dst, `,"iid":`...)
+	dst = strconv.AppendInt(dst, c.CharacteristicID, 10)
+	dst = append(dst, `,"value":`...)
+	if dst, err = AppendValue(dst, c.Value); err != nil {
+		return dst, err
+	}
+
+	if c.Status != nil {
+		dst = append(dst, `,"status":`...)
+		if dst, err = AppendValue(dst, c.Status); err != nil {
+			return dst, err
+		}
+	}
+
+	if c.Events != nil {
+		dst = append(dst, `,"ev":`...)
+		if dst, err = AppendValue(dst, c.Events); err != nil {
+			return dst, err
+		}
+	}
+
+	if c.Response != nil {
+		dst = append(dst, `,"r":`...)
+		if dst, err = AppendValue(dst, c.Response); err != nil {
+			return dst, err
+		}
+	}
+
+	if len(c.Type) > 0 {
+		dst = append(dst, `,"type":`...)
+		dst = appendString(dst, c.Type)
+	}
+
+	if len(c.Perms) > 0 {
+		dst = append(dst, `,"perms":[`...)
+		for i, p := range c.Perms {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendString(dst, p)
+		}
+		dst = append(dst, ']')
+	}
+
+	if len(c.Format) > 0 {
+		dst = append(dst, `,"format":`...)
+		dst = appendString(dst, c.Format)
+	}
+
+	if len(c.Unit) > 0 {
+		dst = append(dst, `,"unit":`...)
+		dst = appendString(dst, c.Unit)
+	}
+
+	if c.MinValue != nil {
+		dst = append(dst, `,"minValue":`...)
+		if dst, err = AppendValue(dst, c.MinValue); err != nil {
+			return dst, err
+		}
+	}
+
+	if c.MaxValue != nil {
+		dst = append(dst, `,"maxValue":`...)
+		if dst, err = AppendValue(dst, c.MaxValue); err != nil {
+			return dst, err
+		}
+	}
+
+	if c.StepValue != nil {
+		dst = append(dst, `,"minStep":`...)
+		if dst, err = AppendValue(dst, c.StepValue); err != nil {
+			return dst, err
+		}
+	}
+
+	if c.MaxLen != 0 {
+		dst = append(dst, `,"maxLen":`...)
+		dst = strconv.AppendInt(dst, int64(c.MaxLen), 10)
+	}
+
+	if c.MaxDataLen != 0 {
+		dst = append(dst, `,"maxDataLen":`...)
+		dst = strconv.AppendInt(dst, int64(c.MaxDataLen), 10)
+	}
+
+	return append(dst, '}'), nil
+}
+
+// AppendValue appends the json encoding of v to dst and returns the extended buffer.
+func AppendValue(dst []byte, v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(dst, "null"...), nil
+	case bool:
+		return strconv.AppendBool(dst, v), nil
+	case int:
+		return strconv.AppendInt(dst, int64(v), 10), nil
+	case int8:
+		return strconv.AppendInt(dst, int64(v), 10), nil
+	case int16:
+		return strconv.AppendInt(dst, int64(v), 10), nil
+	case int32:
+		return strconv.AppendInt(dst, int64(v), 10), nil
+	case int64:
+		return strconv.AppendInt(dst, v, 10), nil
+	case uint:
+		return strconv.AppendUint(dst, uint64(v), 10), nil
+	case uint8:
+		return strconv.AppendUint(dst, uint64(v), 10), nil
+	case uint16:
+		return strconv.AppendUint(dst, uint64(v), 10), nil
+	case uint32:
+		return strconv.AppendUint(dst, uint64(v), 10), nil
+	case uint64:
+		return strconv.AppendUint(dst, v, 10), nil
+	case float32:
+		if math.IsNaN(float64(v)) == false && math.IsInf(float64(v), 0) == false {
+			return appendFloat(dst, float64(v), 32), nil
+		}
+	case float64:
+		if math.IsNaN(v) == false && math.IsInf(v, 0) == false {
+			return appendFloat(dst, v, 64), nil
+		}
+	case string:
+		return appendString(dst, v), nil
+	}
+
+	// Unsupported values (e.g. NaN) fail like with json.Marshal
+	b, err := json.Marshal(v)
+	if err != nil {
+		return dst, err
+	}
+
+	return append(dst, b...), nil
+}
+
+// appendFloat appends f in the format of json.Marshal.
+func appendFloat(dst []byte, f float64, bits int) []byte {
+	abs := math.Abs(f)
+	format := byte('f')
+	if abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+
+	dst = strconv.AppendFloat(dst, f, format, -1, bits)
+	if format == 'e' {
+		// Clean up e-09 to e-9
+		n := len(dst)
+		if n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+	}
+
+	return dst
+}
+
+const hex = "0123456789abcdef"
+
+// appendString appends s as json string with the escaping of json.Marshal.
+func appendString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				dst = append(dst, '\\', b)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				// Control characters and HTML characters
+				dst = append(dst, '\\', 'u', '0', '0', hex[b>>4], hex[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+
+		// U+2028 and U+2029 are escaped for JSONP
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hex[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+
+		i += size
+	}
+
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
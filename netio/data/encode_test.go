@@ -0,0 +1,88 @@
+package data
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestAppendJSON(t *testing.T) {
+	values := []interface{}{
+		nil, true, false, 0, -1, int8(-8), int16(16), int32(-32), int64(1 << 40),
+		uint(1), uint8(255), uint16(65535), uint32(1 << 31), uint64(1 << 63),
+		float32(0.1), 21.5, 0.0, -0.5, 1e-7, 1e21, float32(1e-7), 123456789.125,
+		"", "On", `quote " and \ backslash`, "line\nbreak\ttab\r", "\x01\x1f", "<b>&</b>",
+		"ümlaut", "  ", "invalid \xff utf8", "AQIDBA==",
+		[]int{1, 2},
+	}
+
+	for _, v := range values {
+		cs := Characteristics{[]Characteristic{
+			Characteristic{AccessoryID: 1, CharacteristicID: 9, Value: v},
+			Characteristic{AccessoryID: 2, CharacteristicID: 10, Value: v, Status: -70402, Events: true, Response: false},
+			Characteristic{AccessoryID: 3, CharacteristicID: 11, Value: v, Type: "25", Perms: []string{"pr", "pw", "ev"}, Format: "float", Unit: "percentage", MinValue: 0, MaxValue: 100.5, StepValue: 1, MaxLen: 64, MaxDataLen: 256},
+		}}
+
+		want, err := json.Marshal(&cs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		is, err := cs.AppendJSON(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(is) != string(want) {
+			t.Fatalf("is=%s want=%s", is, want)
+		}
+	}
+}
+
+func TestAppendJSONEmpty(t *testing.T) {
+	for _, cs := range []Characteristics{Characteristics{}, Characteristics{[]Characteristic{}}} {
+		want, _ := json.Marshal(&cs)
+		is, err := cs.AppendJSON(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(is) != string(want) {
+			t.Fatalf("is=%s want=%s", is, want)
+		}
+	}
+}
+
+func TestAppendJSONUnsupportedValue(t *testing.T) {
+	cs := Characteristics{[]Characteristic{Characteristic{Value: math.NaN()}}}
+	if _, err := cs.AppendJSON(nil); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func BenchmarkAppendJSON(b *testing.B) {
+	cs := Characteristics{[]Characteristic{
+		Characteristic{AccessoryID: 1, CharacteristicID: 9, Value: true},
+		Characteristic{AccessoryID: 1, CharacteristicID: 10, Value: 21.5},
+		Characteristic{AccessoryID: 1, CharacteristicID: 11, Value: "Living Room"},
+	}}
+
+	buf := make([]byte, 0, 256)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf, _ = cs.AppendJSON(buf[:0])
+	}
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	cs := Characteristics{[]Characteristic{
+		Characteristic{AccessoryID: 1, CharacteristicID: 9, Value: true},
+		Characteristic{AccessoryID: 1, CharacteristicID: 10, Value: 21.5},
+		Characteristic{AccessoryID: 1, CharacteristicID: 11, Value: "Living Room"},
+	}}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		json.Marshal(&cs)
+	}
+}
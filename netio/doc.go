@@ -7,4 +7,19 @@
 //
 // When a client is already paired, it can provide public keys of other clients without
 // going through the whole pairing process again.
+//
+// # Security layer
+//
+// After pair verify, the data of a connection is split into frames and encrypted by the
+// cryptographer of the connection session (see crypto.NewSecureSessionFromSharedKey).
+//
+//	[ length (2 bytes, little endian) ] [ encrypted data (max 1024 bytes) ] [ auth tag (16 bytes) ]
+//
+// A HAPConnection wraps any net.Conn and reads and writes those frames as soon as
+// the session has a cryptographer. Custom transports reuse the security layer by
+// wrapping their connections, either individually with NewHAPConnection or by serving
+// a listener with Serve.
+//
+//	l := ... // net.Listener of e.g. a serial tunnel
+//	netio.Serve(l, server.NewRouter(config), context)
 package netio
@@ -4,6 +4,7 @@ import (
 	"github.com/brutella/hc/netio"
 	"github.com/brutella/log"
 
+	"io"
 	"io/ioutil"
 	"net/http"
 	"sync"
@@ -34,9 +35,7 @@ func (handler *Accessories) ServeHTTP(response http.ResponseWriter, request *htt
 	log.Printf("[VERB] %v GET /accessories", request.RemoteAddr)
 	response.Header().Set("Content-Type", netio.HTTPContentTypeHAPJson)
 
-	handler.mutex.Lock()
-	res, err := handler.controller.HandleGetAccessories(request.Body)
-	handler.mutex.Unlock()
+	res, err := handler.handle(request)
 
 	if err != nil {
 		log.Println("[ERRO]", err)
@@ -53,3 +52,15 @@ func (handler *Accessories) ServeHTTP(response http.ResponseWriter, request *htt
 		}
 	}
 }
+
+// handle returns the accessories while the mutex is locked.
+func (handler *Accessories) handle(request *http.Request) (io.Reader, error) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	if l, ok := handler.controller.(netio.LocalizedAccessoriesHandler); ok == true {
+		return l.HandleGetLocalizedAccessories(request.Body, netio.PreferredLocales(request))
+	}
+
+	return handler.controller.HandleGetAccessories(request.Body)
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/brutella/log"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"sync"
 )
@@ -33,36 +34,59 @@ func NewCharacteristics(context netio.HAPContext, c netio.CharacteristicsHandler
 }
 
 func (handler *Characteristics) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	res, err := handler.handle(request)
+
+	if err != nil {
+		log.Println("[ERRO]", err)
+		response.WriteHeader(http.StatusInternalServerError)
+	} else {
+		if res != nil {
+			response.Header().Set("Content-Type", netio.HTTPContentTypeHAPJson)
+			if _, ok := res.(*netio.MultiStatus); ok == true || request.Method == netio.MethodPUT {
+				// Write responses and failed reads are sent as multi-status
+				response.WriteHeader(http.StatusMultiStatus)
+			}
+			wr := netio.NewChunkedWriter(response, 2048)
+			b, _ := ioutil.ReadAll(res)
+			wr.Write(b)
+		} else {
+			response.WriteHeader(http.StatusNoContent)
+		}
+	}
+}
+
+// handle handles the request while the mutex is locked.
+// The mutex is unlocked even when a characteristic callback panics.
+func (handler *Characteristics) handle(request *http.Request) (io.Reader, error) {
 	var res io.Reader
 	var err error
 
 	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
 	switch request.Method {
 	case netio.MethodGET:
 		log.Printf("[VERB] %v GET /characteristics", request.RemoteAddr)
 		request.ParseForm()
-		res, err = handler.controller.HandleGetCharacteristics(request.Form)
+		if c, ok := handler.controller.(netio.ConnCharacteristicsHandler); ok == true {
+			var conn net.Conn
+			if session := handler.context.GetSessionForRequest(request); session != nil {
+				conn = session.Connection()
+			}
+			res, err = c.HandleGetCharacteristicsForConn(request.Form, netio.PreferredLocales(request), conn)
+		} else if l, ok := handler.controller.(netio.LocalizedCharacteristicsHandler); ok == true {
+			res, err = l.HandleGetLocalizedCharacteristics(request.Form, netio.PreferredLocales(request))
+		} else {
+			res, err = handler.controller.HandleGetCharacteristics(request.Form)
+		}
 	case netio.MethodPUT:
 		log.Printf("[VERB] %v PUT /characteristics", request.RemoteAddr)
 		session := handler.context.GetSessionForRequest(request)
 		conn := session.Connection()
-		err = handler.controller.HandleUpdateCharacteristics(request.Body, conn)
+		res, err = handler.controller.HandleUpdateCharacteristics(request.Body, conn)
 	default:
 		log.Println("[WARN] Cannot handle HTTP method", request.Method)
 	}
-	handler.mutex.Unlock()
 
-	if err != nil {
-		log.Println("[ERRO]", err)
-		response.WriteHeader(http.StatusInternalServerError)
-	} else {
-		if res != nil {
-			response.Header().Set("Content-Type", netio.HTTPContentTypeHAPJson)
-			wr := netio.NewChunkedWriter(response, 2048)
-			b, _ := ioutil.ReadAll(res)
-			wr.Write(b)
-		} else {
-			response.WriteHeader(http.StatusNoContent)
-		}
-	}
+	return res, err
 }
@@ -1,6 +1,7 @@
 package endpoint
 
 import (
+	"github.com/brutella/hc/audit"
 	"github.com/brutella/hc/db"
 	"github.com/brutella/hc/event"
 	"github.com/brutella/hc/netio"
@@ -8,6 +9,7 @@ import (
 	"github.com/brutella/hc/util"
 	"github.com/brutella/log"
 
+	"fmt"
 	"io"
 	"net/http"
 )
@@ -26,6 +28,22 @@ type PairSetup struct {
 	database db.Database
 	context  netio.HAPContext
 	emitter  event.Emitter
+
+	// TokenProvider provides the software token to pair with authentication.
+	TokenProvider pair.TokenProvider
+
+	// Progress is called when pair setup progresses.
+	Progress pair.SetupProgressFunc
+
+	// SetupCodes provides a new setup code for every pair setup.
+	SetupCodes pair.SetupCodeProvider
+
+	// Available returns false when pair setup is not available, e.g. because the pairing window is closed.
+	// When nil, pair setup is always available.
+	Available func() bool
+
+	// AuditLog records pairings and failed pair setup attempts.
+	AuditLog *audit.Log
 }
 
 // NewPairSetup returns a new handler for pairing endpoint
@@ -48,34 +66,64 @@ func (endpoint *PairSetup) ServeHTTP(response http.ResponseWriter, request *http
 	var in util.Container
 	var out util.Container
 
-	key := endpoint.context.GetConnectionKey(request)
-	session := endpoint.context.Get(key).(netio.Session)
+	session := endpoint.context.GetSessionForRequest(request)
 	ctrl := session.PairSetupHandler()
 	if ctrl == nil {
 		log.Println("[VERB] Create new pair setup controller")
 
-		if ctrl, err = pair.NewSetupServerController(endpoint.device, endpoint.database); err != nil {
-			log.Println(err)
+		c, err := pair.NewSetupServerController(endpoint.device, endpoint.database)
+		if err != nil {
+			log.Println("[ERRO]", err)
+			response.WriteHeader(http.StatusInternalServerError)
+			return
 		}
 
+		c.SetTokenProvider(endpoint.TokenProvider)
+		c.SetProgressFunc(endpoint.Progress)
+		c.SetAvailabilityFunc(endpoint.Available)
+		c.SetSetupCodeProvider(endpoint.SetupCodes)
+		ctrl = c
 		session.SetPairSetupHandler(ctrl)
 	}
 
 	if in, err = util.NewTLV8ContainerFromReader(request.Body); err == nil {
-		out, err = ctrl.Handle(in)
+		// Run the SRP computations until the client disconnects
+		if c, ok := ctrl.(*pair.SetupServerController); ok == true {
+			out, err = c.HandleContext(request.Context(), in)
+		} else {
+			out, err = ctrl.Handle(in)
+		}
 	}
 
 	if err != nil {
 		log.Println("[ERRO]", err)
+		endpoint.AuditLog.Record(audit.Record{
+			Operation: audit.OperationPairSetupFailed,
+			Addr:      request.RemoteAddr,
+			Message:   err.Error(),
+		})
 		response.WriteHeader(http.StatusInternalServerError)
 	} else {
 		io.Copy(response, out.BytesBuffer())
 
+		if code := out.GetByte(pair.TagErrCode); code != pair.ErrCodeNo.Byte() {
+			endpoint.AuditLog.Record(audit.Record{
+				Operation: audit.OperationPairSetupFailed,
+				Addr:      request.RemoteAddr,
+				Message:   fmt.Sprintf("Error code %d", code),
+			})
+		}
+
 		// Send event when key exchange is done
-		b := out.GetByte(pair.TagSequence)
-		switch pair.PairStepType(b) {
-		case pair.PairStepKeyExchangeResponse:
-			endpoint.emitter.Emit(event.DevicePaired{})
+		if c, ok := ctrl.(*pair.SetupServerController); ok == true && len(c.Username()) > 0 {
+			if pair.PairStepType(in.GetByte(pair.TagSequence)) == pair.PairStepKeyExchangeRequest {
+				endpoint.emitter.Emit(event.DevicePaired{Username: c.Username(), Permission: event.PermissionAdmin})
+				endpoint.AuditLog.Record(audit.Record{
+					Operation: audit.OperationPairingAdded,
+					Username:  c.Username(),
+					Addr:      request.RemoteAddr,
+				})
+			}
 		}
 	}
 }
@@ -10,6 +10,7 @@ import (
 
 	"io"
 	"net/http"
+	"strconv"
 )
 
 // PairVerify handles the /pair-verify endpoint and returns TLV8 encoded data
@@ -37,41 +38,67 @@ func (endpoint *PairVerify) ServeHTTP(response http.ResponseWriter, request *htt
 	log.Printf("[VERB] %v POST /pair-verify", request.RemoteAddr)
 	response.Header().Set("Content-Type", netio.HTTPContentTypePairingTLV8)
 
-	key := endpoint.context.GetConnectionKey(request)
-	session := endpoint.context.Get(key).(netio.Session)
-	ctlr := session.PairVerifyHandler()
-	if ctlr == nil {
-		log.Println("[VERB] Create new pair verify controller")
-		ctlr = pair.NewVerifyServerController(endpoint.database, endpoint.context)
-		session.SetPairVerifyHandler(ctlr)
-	}
-
 	var err error
 	var in util.Container
 	var out util.Container
 	var secSession crypto.Cryptographer
 
-	if in, err = util.NewTLV8ContainerFromReader(request.Body); err == nil {
-		out, err = ctlr.Handle(in)
+	if in, err = util.NewTLV8ContainerFromReader(request.Body); err != nil {
+		log.Println(err)
+		response.WriteHeader(http.StatusInternalServerError)
+		return
 	}
 
-	if err != nil {
+	session := endpoint.context.GetSessionForRequest(request)
+	ctlr := session.PairVerifyHandler()
+
+	// A controller may verify again on an existing connection to rotate the session keys
+	if ctlr == nil || pair.VerifyStepType(in.GetByte(pair.TagSequence)) == pair.VerifyStepStartRequest {
+		log.Println("[VERB] Create new pair verify controller")
+		ctlr = pair.NewVerifyServerController(endpoint.database, endpoint.context)
+		session.SetPairVerifyHandler(ctlr)
+	}
+
+	if out, err = ctlr.Handle(in); err != nil {
 		log.Println(err)
 		response.WriteHeader(http.StatusInternalServerError)
-	} else {
+		return
+	}
+
+	// When key verification is done, switch to a secure session
+	// based on the negotiated shared session key
+	b := out.GetByte(pair.TagSequence)
+	if pair.VerifyStepType(b) != pair.VerifyStepFinishResponse {
 		io.Copy(response, out.BytesBuffer())
+		return
+	}
+
+	if secSession, err = crypto.NewSecureSessionFromSharedKey(ctlr.SharedKey()); err != nil {
+		log.Println("[ERRO] Could not setup secure session.", err)
+		io.Copy(response, out.BytesBuffer())
+		return
+	}
+
+	log.Println("[VERB] Setup secure session")
+
+	if v, ok := ctlr.(*pair.VerifyServerController); ok == true {
+		endpoint.context.Connections().SetController(session.Connection(), v.ControllerName())
+	}
+
+	// The response must be sent with the current keys.
+	// When the response can be flushed, the new keys are used immediately afterwards,
+	// otherwise when the next request is received.
+	buf := out.BytesBuffer()
+	flusher, ok := response.(http.Flusher)
+	if ok == true {
+		response.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	}
+
+	io.Copy(response, buf)
+	session.SetCryptographer(secSession)
 
-		// When key verification is done, switch to a secure session
-		// based on the negotiated shared session key
-		b := out.GetByte(pair.TagSequence)
-		switch pair.VerifyStepType(b) {
-		case pair.VerifyStepFinishResponse:
-			if secSession, err = crypto.NewSecureSessionFromSharedKey(ctlr.SharedKey()); err == nil {
-				log.Println("[VERB] Setup secure session")
-				session.SetCryptographer(secSession)
-			} else {
-				log.Println("[ERRO] Could not setup secure session.", err)
-			}
-		}
+	if ok == true {
+		flusher.Flush()
+		session.ActivateCryptographer()
 	}
 }
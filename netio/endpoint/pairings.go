@@ -1,6 +1,7 @@
 package endpoint
 
 import (
+	"github.com/brutella/hc/audit"
 	"github.com/brutella/hc/event"
 	"github.com/brutella/hc/netio"
 	"github.com/brutella/hc/netio/pair"
@@ -18,6 +19,9 @@ type Pairing struct {
 
 	controller *pair.PairingController
 	emitter    event.Emitter
+
+	// AuditLog records pairing additions and removals.
+	AuditLog *audit.Log
 }
 
 // NewPairing returns a new handler for pairing enpdoint
@@ -50,12 +54,15 @@ func (endpoint *Pairing) ServeHTTP(response http.ResponseWriter, request *http.R
 
 		// Send events based on pairing method type
 		b := in.GetByte(pair.TagPairingMethod)
+		username := in.GetString(pair.TagUsername)
 		switch pair.PairMethodType(b) {
 		case pair.PairingMethodDelete: // pairing removed
-			endpoint.emitter.Emit(event.DeviceUnpaired{})
+			endpoint.emitter.Emit(event.DeviceUnpaired{Username: username})
+			endpoint.AuditLog.Record(audit.Record{Operation: audit.OperationPairingRemoved, Username: username, Addr: request.RemoteAddr})
 
 		case pair.PairingMethodAdd: // pairing added
-			endpoint.emitter.Emit(event.DevicePaired{})
+			endpoint.emitter.Emit(event.DevicePaired{Username: username, Permission: in.GetByte(pair.TagPermissions)})
+			endpoint.AuditLog.Record(audit.Record{Operation: audit.OperationPairingAdded, Username: username, Addr: request.RemoteAddr})
 
 		}
 	}
@@ -0,0 +1,55 @@
+package endpoint
+
+import (
+	"github.com/brutella/hc/netio"
+	"github.com/brutella/hc/netio/pair"
+	"github.com/brutella/log"
+
+	"io/ioutil"
+	"net/http"
+)
+
+// SecureMessage handles the /secure-message endpoint, which is used by
+// paired controllers to request and update the software token.
+//
+// The endpoint only responds to requests on encrypted connections.
+type SecureMessage struct {
+	http.Handler
+
+	controller *pair.TokenController
+	context    netio.HAPContext
+}
+
+// NewSecureMessage returns a new handler for the secure message endpoint.
+func NewSecureMessage(context netio.HAPContext, controller *pair.TokenController) *SecureMessage {
+	endpoint := SecureMessage{
+		controller: controller,
+		context:    context,
+	}
+
+	return &endpoint
+}
+
+func (endpoint *SecureMessage) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	log.Printf("[VERB] %v POST /secure-message", request.RemoteAddr)
+
+	session := endpoint.context.GetSessionForRequest(request)
+	if session == nil || session.Encrypter() == nil {
+		log.Println("[WARN] Secure message on unencrypted connection")
+		response.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	in, err := ioutil.ReadAll(request.Body)
+	if err == nil {
+		var out []byte
+		if out, err = endpoint.controller.Handle(in); err == nil {
+			response.Header().Set("Content-Type", netio.HTTPContentTypeOctetStream)
+			response.Write(out)
+			return
+		}
+	}
+
+	log.Println("[ERRO]", err)
+	response.WriteHeader(http.StatusBadRequest)
+}
@@ -0,0 +1,29 @@
+package netio
+
+import (
+	"time"
+)
+
+// Faults describes the faults which are injected into connections to test how
+// controllers recover from unreliable accessories.
+//
+// Faults are only injected when the package is built with the "chaos" build tag
+// (e.g. `go build -tags chaos`). Otherwise SetFaults has no effect.
+type Faults struct {
+	// Delay delays every write to a connection
+	Delay time.Duration
+
+	// DropRate is the probability (0 to 1) that a connection is closed before a write
+	DropRate float64
+
+	// CorruptRate is the probability (0 to 1) that an encrypted frame is corrupted,
+	// which makes the controller fail to decrypt the frame
+	CorruptRate float64
+
+	// StatusRate is the probability (0 to 1) that reading or writing a characteristic fails with Status
+	StatusRate float64
+
+	// Status is the HAP status code of failed characteristic requests.
+	// When 0, StatusServiceCommunicationFailure is used.
+	Status int
+}
@@ -0,0 +1,75 @@
+//go:build chaos
+// +build chaos
+
+package netio
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/brutella/log"
+)
+
+var (
+	faults      Faults
+	faultsMutex sync.Mutex
+)
+
+// SetFaults sets the faults which are injected into all connections.
+func SetFaults(f Faults) {
+	faultsMutex.Lock()
+	defer faultsMutex.Unlock()
+
+	log.Printf("[WARN] Inject faults %+v\n", f)
+	faults = f
+}
+
+func currentFaults() Faults {
+	faultsMutex.Lock()
+	defer faultsMutex.Unlock()
+
+	return faults
+}
+
+// injectWriteFault delays a write and closes the connection as configured.
+func injectWriteFault(c net.Conn) error {
+	f := currentFaults()
+	if f.Delay > 0 {
+		time.Sleep(f.Delay)
+	}
+
+	if f.DropRate > 0 && rand.Float64() < f.DropRate {
+		log.Printf("[WARN] Drop connection to %s\n", c.RemoteAddr())
+		c.Close()
+		return errors.New("Connection dropped by fault injection")
+	}
+
+	return nil
+}
+
+// corruptFrame flips a random bit of an encrypted frame as configured.
+func corruptFrame(b []byte) []byte {
+	if f := currentFaults(); len(b) > 0 && f.CorruptRate > 0 && rand.Float64() < f.CorruptRate {
+		log.Println("[WARN] Corrupt encrypted frame")
+		i := rand.Intn(len(b))
+		b[i] ^= 1 << uint(rand.Intn(8))
+	}
+
+	return b
+}
+
+// InjectedStatus returns a HAP status code and true when a characteristic request should fail.
+func InjectedStatus() (int, bool) {
+	f := currentFaults()
+	if f.StatusRate > 0 && rand.Float64() < f.StatusRate {
+		if f.Status == 0 {
+			return StatusServiceCommunicationFailure, true
+		}
+		return f.Status, true
+	}
+
+	return StatusSuccess, false
+}
@@ -0,0 +1,47 @@
+//go:build chaos
+// +build chaos
+
+package netio
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+func TestInjectedStatus(t *testing.T) {
+	SetFaults(Faults{StatusRate: 1})
+	defer SetFaults(Faults{})
+
+	status, ok := InjectedStatus()
+	if is, want := ok, true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := status, StatusServiceCommunicationFailure; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestCorruptFrame(t *testing.T) {
+	SetFaults(Faults{CorruptRate: 1})
+	defer SetFaults(Faults{})
+
+	b := []byte{0x01, 0x02, 0x03}
+	if corrupted := corruptFrame([]byte{0x01, 0x02, 0x03}); bytes.Equal(corrupted, b) == true {
+		t.Fatal("expected corrupted frame")
+	}
+}
+
+func TestDropConnection(t *testing.T) {
+	SetFaults(Faults{DropRate: 1})
+	defer SetFaults(Faults{})
+
+	local, remote := net.Pipe()
+	defer remote.Close()
+	conn := NewHAPConnection(local, NewContextForSecuredDevice(nil))
+
+	if _, err := conn.Write([]byte("HTTP/1.1 200 OK\r\n\r\n")); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,28 @@
+//go:build !chaos
+// +build !chaos
+
+package netio
+
+import (
+	"net"
+
+	"github.com/brutella/log"
+)
+
+// SetFaults does nothing because faults are only injected when built with the "chaos" build tag.
+func SetFaults(f Faults) {
+	log.Println("[WARN] Faults are only injected when built with the chaos build tag")
+}
+
+func injectWriteFault(c net.Conn) error {
+	return nil
+}
+
+func corruptFrame(b []byte) []byte {
+	return b
+}
+
+// InjectedStatus returns false because faults are only injected when built with the "chaos" build tag.
+func InjectedStatus() (int, bool) {
+	return StatusSuccess, false
+}
@@ -0,0 +1,11 @@
+package netio
+
+import (
+	"testing"
+)
+
+func TestNoInjectedStatusWithoutFaults(t *testing.T) {
+	if _, ok := InjectedStatus(); ok == true {
+		t.Fatal("expected no injected status")
+	}
+}
@@ -0,0 +1,58 @@
+package netio
+
+import (
+	"sync"
+)
+
+// FlowStats are the flow control statistics of a connection.
+type FlowStats struct {
+	// BytesWritten is the number of bytes written to the connection
+	BytesWritten uint64
+
+	// Unacknowledged is the number of bytes which were not acknowledged by the peer yet.
+	// The number includes the bytes in the send queue of the socket (only on Linux)
+	// and the bytes of writes which are in progress.
+	Unacknowledged int
+}
+
+// FlowConn is a connection which provides flow control statistics.
+type FlowConn interface {
+	FlowStats() FlowStats
+}
+
+// flowCounter counts the written and pending bytes of a connection.
+type flowCounter struct {
+	mutex   sync.Mutex
+	written uint64
+	pending int
+}
+
+func (f *flowCounter) begin(n int) {
+	f.mutex.Lock()
+	f.pending += n
+	f.mutex.Unlock()
+}
+
+func (f *flowCounter) end(n, written int) {
+	f.mutex.Lock()
+	f.pending -= n
+	f.written += uint64(written)
+	f.mutex.Unlock()
+}
+
+func (f *flowCounter) stats() FlowStats {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return FlowStats{BytesWritten: f.written, Unacknowledged: f.pending}
+}
+
+// FlowStats returns the flow control statistics of the connection.
+func (con *HAPConnection) FlowStats() FlowStats {
+	s := con.flow.stats()
+	if n, ok := sendQueueLen(con.connection); ok == true {
+		s.Unacknowledged += n
+	}
+
+	return s
+}
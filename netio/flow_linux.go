@@ -0,0 +1,34 @@
+//go:build linux
+// +build linux
+
+package netio
+
+import (
+	"net"
+	"syscall"
+	"unsafe"
+)
+
+// sendQueueLen returns the number of bytes in the send queue of the socket of c,
+// which were not acknowledged by the peer yet (SIOCOUTQ).
+func sendQueueLen(c net.Conn) (int, bool) {
+	sc, ok := c.(syscall.Conn)
+	if ok == false {
+		return 0, false
+	}
+
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return 0, false
+	}
+
+	var n int32
+	var errno syscall.Errno
+	if err := raw.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCOUTQ, uintptr(unsafe.Pointer(&n)))
+	}); err != nil || errno != 0 {
+		return 0, false
+	}
+
+	return int(n), true
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package netio
+
+import (
+	"net"
+)
+
+// sendQueueLen returns false because the send queue of sockets is only available on Linux.
+func sendQueueLen(c net.Conn) (int, bool) {
+	return 0, false
+}
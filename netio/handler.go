@@ -23,10 +23,38 @@ type AccessoriesHandler interface {
 	HandleGetAccessories(r io.Reader) (io.Reader, error)
 }
 
+// A LocalizedAccessoriesHandler returns a list of accessories as json, whose
+// values are localized for the preferred locales of the controller (see PreferredLocales).
+type LocalizedAccessoriesHandler interface {
+	HandleGetLocalizedAccessories(r io.Reader, locales []string) (io.Reader, error)
+}
+
 // A CharacteristicsHandler handles get and update characteristic.
+//
+// HandleUpdateCharacteristics returns a non-nil reader when the
+// controller requested the values of written characteristics.
 type CharacteristicsHandler interface {
 	HandleGetCharacteristics(url.Values) (io.Reader, error)
-	HandleUpdateCharacteristics(io.Reader, net.Conn) error
+	HandleUpdateCharacteristics(io.Reader, net.Conn) (io.Reader, error)
+}
+
+// A LocalizedCharacteristicsHandler returns characteristic values, which are
+// localized for the preferred locales of the controller (see PreferredLocales).
+type LocalizedCharacteristicsHandler interface {
+	HandleGetLocalizedCharacteristics(form url.Values, locales []string) (io.Reader, error)
+}
+
+// A ConnCharacteristicsHandler returns characteristic values for the connection
+// of a controller, e.g. whether the connection enabled events (`ev=1`).
+type ConnCharacteristicsHandler interface {
+	HandleGetCharacteristicsForConn(form url.Values, locales []string, conn net.Conn) (io.Reader, error)
+}
+
+// MultiStatus is returned by a CharacteristicsHandler when the response contains
+// the status of every characteristic, because a read of a characteristic failed.
+// The response is sent with the HTTP status 207 Multi-Status.
+type MultiStatus struct {
+	io.Reader
 }
 
 // IdentifyHandler calls Identify() on accessories.
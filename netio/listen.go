@@ -0,0 +1,44 @@
+package netio
+
+import (
+	"context"
+	"net"
+	"strings"
+)
+
+// Listen announces on the local address like net.Listen.
+//
+// When the address contains an ip, the network is restricted to its address family,
+// e.g. "tcp4" for an IPv4 address. When iface is not empty, the listener only accepts
+// connections which are received on the network interface with that name.
+func Listen(network, address, iface string) (net.Listener, error) {
+	lc := net.ListenConfig{Control: bindToDevice(iface)}
+	return lc.Listen(context.Background(), networkForAddress(network, address), address)
+}
+
+// ListenPacket announces on the local address like net.ListenPacket.
+// The network and interface are handled like by Listen.
+func ListenPacket(network, address, iface string) (net.PacketConn, error) {
+	lc := net.ListenConfig{Control: bindToDevice(iface)}
+	return lc.ListenPacket(context.Background(), networkForAddress(network, address), address)
+}
+
+// networkForAddress returns the network (e.g. "tcp4") for the address family of the ip in address.
+// The network is not changed when the address has no ip.
+func networkForAddress(network, address string) string {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return network
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || strings.HasSuffix(network, "4") || strings.HasSuffix(network, "6") {
+		return network
+	}
+
+	if ip.To4() != nil {
+		return network + "4"
+	}
+
+	return network + "6"
+}
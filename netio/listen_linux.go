@@ -0,0 +1,29 @@
+//go:build linux
+// +build linux
+
+package netio
+
+import (
+	"syscall"
+)
+
+// bindToDevice returns a function which binds a socket to the network interface iface (SO_BINDTODEVICE).
+// It returns nil when iface is empty.
+func bindToDevice(iface string) func(network, address string, c syscall.RawConn) error {
+	if len(iface) == 0 {
+		return nil
+	}
+
+	return func(network, address string, c syscall.RawConn) error {
+		var err error
+		cerr := c.Control(func(fd uintptr) {
+			err = syscall.BindToDevice(int(fd), iface)
+		})
+
+		if cerr != nil {
+			return cerr
+		}
+
+		return err
+	}
+}
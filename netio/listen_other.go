@@ -0,0 +1,21 @@
+//go:build !linux
+// +build !linux
+
+package netio
+
+import (
+	"errors"
+	"syscall"
+)
+
+// bindToDevice returns a function which fails because binding a socket to an interface
+// is only supported on Linux. It returns nil when iface is empty.
+func bindToDevice(iface string) func(network, address string, c syscall.RawConn) error {
+	if len(iface) == 0 {
+		return nil
+	}
+
+	return func(network, address string, c syscall.RawConn) error {
+		return errors.New("Binding to an interface is only supported on Linux")
+	}
+}
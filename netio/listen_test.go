@@ -0,0 +1,39 @@
+package netio
+
+import (
+	"net"
+	"testing"
+)
+
+func TestNetworkForAddress(t *testing.T) {
+	var tests = []struct {
+		network string
+		address string
+		want    string
+	}{
+		{"tcp", ":1234", "tcp"},
+		{"tcp", "0.0.0.0:1234", "tcp4"},
+		{"tcp", "[::1]:1234", "tcp6"},
+		{"udp", "192.168.1.2:0", "udp4"},
+		{"tcp4", "192.168.1.2:0", "tcp4"},
+		{"tcp", "localhost:1234", "tcp"},
+	}
+
+	for _, test := range tests {
+		if is, want := networkForAddress(test.network, test.address), test.want; is != want {
+			t.Fatalf("%s is=%v want=%v", test.address, is, want)
+		}
+	}
+}
+
+func TestListenOnAddress(t *testing.T) {
+	ln, err := Listen("tcp", "127.0.0.1:0", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	if is, want := ln.Addr().(*net.TCPAddr).IP.String(), "127.0.0.1"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -2,22 +2,46 @@ package netio
 
 import (
 	"net"
+	"syscall"
+	"time"
+)
+
+// AcceptFunc is called when accepting a connection failed with a temporary error
+// (e.g. EMFILE when the process ran out of file descriptors) and is retried after backoff.
+type AcceptFunc func(err error, backoff time.Duration)
+
+const (
+	// minAcceptBackoff is the delay before the first retry of a failed accept
+	minAcceptBackoff = 5 * time.Millisecond
+
+	// maxAcceptBackoff is the maximum delay between retries of a failed accept
+	maxAcceptBackoff = time.Second
 )
 
 // HAPTCPListener listens for new connection and creates HAPConnections for new connections
 type HAPTCPListener struct {
 	*net.TCPListener
 	context HAPContext
+
+	// Retry is the retry policy of the accepted connections
+	Retry RetryPolicy
+
+	// WriteTimeout is the write deadline of the accepted connections
+	WriteTimeout time.Duration
+
+	// AcceptFailed is called when accepting a connection failed with a temporary error.
+	AcceptFailed AcceptFunc
 }
 
 // NewHAPTCPListener returns a new hap tcp listener.
 func NewHAPTCPListener(l *net.TCPListener, context HAPContext) *HAPTCPListener {
-	return &HAPTCPListener{l, context}
+	return &HAPTCPListener{TCPListener: l, context: context, Retry: DefaultRetryPolicy}
 }
 
 // Accept creates and returns a HAPConnection.
+// Accepts which fail with a temporary error are retried with exponential backoff.
 func (l *HAPTCPListener) Accept() (c net.Conn, err error) {
-	conn, err := l.AcceptTCP()
+	conn, err := acceptRetry(l.acceptTCP, l.AcceptFailed)
 	if err != nil {
 		return
 	}
@@ -26,6 +50,102 @@ func (l *HAPTCPListener) Accept() (c net.Conn, err error) {
 	// conn.SetKeepAlive(true)
 	// conn.SetKeepAlivePeriod(3 * time.Minute)
 	hapConn := NewHAPConnection(conn, l.context)
+	hapConn.Retry = l.Retry
+	hapConn.WriteTimeout = l.WriteTimeout
 
 	return hapConn, err
 }
+
+func (l *HAPTCPListener) acceptTCP() (net.Conn, error) {
+	conn, err := l.AcceptTCP()
+	if err != nil {
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// HAPListener wraps the connections of any listener (e.g. of a serial tunnel
+// or websockets) in HAPConnections, so that they use the HAP security layer.
+type HAPListener struct {
+	net.Listener
+	context HAPContext
+
+	// AcceptFailed is called when accepting a connection failed with a temporary error.
+	AcceptFailed AcceptFunc
+}
+
+// NewHAPListener returns a listener which creates HAPConnections for the connections of l.
+func NewHAPListener(l net.Listener, context HAPContext) *HAPListener {
+	return &HAPListener{Listener: l, context: context}
+}
+
+// Accept creates and returns a HAPConnection.
+// Accepts which fail with a temporary error are retried with exponential backoff.
+func (l *HAPListener) Accept() (net.Conn, error) {
+	conn, err := acceptRetry(l.Listener.Accept, l.AcceptFailed)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewHAPConnection(conn, l.context), nil
+}
+
+// acceptRetry calls accept until it returns a connection or an error which is not temporary.
+// The delay between retries starts at 5ms and is doubled up to 1 second.
+func acceptRetry(accept func() (net.Conn, error), failed AcceptFunc) (net.Conn, error) {
+	var backoff time.Duration
+	for {
+		conn, err := accept()
+		if err == nil || isAcceptTemporary(err) == false {
+			return conn, err
+		}
+
+		if backoff == 0 {
+			backoff = minAcceptBackoff
+		} else {
+			backoff *= 2
+		}
+		if backoff > maxAcceptBackoff {
+			backoff = maxAcceptBackoff
+		}
+
+		if failed != nil {
+			failed(err, backoff)
+		}
+		time.Sleep(backoff)
+	}
+}
+
+// isAcceptTemporary returns true when err is a temporary error, after which accept can be retried.
+func isAcceptTemporary(err error) bool {
+	switch err := err.(type) {
+	case syscall.Errno:
+		return IsOutOfFileDescriptors(err) || err == syscall.ECONNABORTED || err == syscall.ENOBUFS || err == syscall.ENOMEM || err == syscall.EINTR
+	case *net.OpError:
+		return isAcceptTemporary(err.Err)
+	case interface{ Unwrap() error }:
+		if inner := err.Unwrap(); inner != nil {
+			return isAcceptTemporary(inner)
+		}
+	}
+
+	return false
+}
+
+// IsOutOfFileDescriptors returns true when err is caused by the
+// file descriptor limit of the process (EMFILE) or the system (ENFILE).
+func IsOutOfFileDescriptors(err error) bool {
+	switch err := err.(type) {
+	case syscall.Errno:
+		return err == syscall.EMFILE || err == syscall.ENFILE
+	case *net.OpError:
+		return IsOutOfFileDescriptors(err.Err)
+	case interface{ Unwrap() error }:
+		if inner := err.Unwrap(); inner != nil {
+			return IsOutOfFileDescriptors(inner)
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,63 @@
+package netio
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestAcceptRetryTemporaryError(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	failures := 3
+	accept := func() (net.Conn, error) {
+		if failures > 0 {
+			failures--
+			return nil, &net.OpError{Op: "accept", Net: "tcp", Err: os.NewSyscallError("accept", syscall.EMFILE)}
+		}
+		return server, nil
+	}
+
+	var backoffs []time.Duration
+	failed := func(err error, backoff time.Duration) {
+		if IsOutOfFileDescriptors(err) == false {
+			t.Fatalf("unexpected error %v", err)
+		}
+		backoffs = append(backoffs, backoff)
+	}
+
+	conn, err := acceptRetry(accept, failed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conn != server {
+		t.Fatal("invalid connection")
+	}
+
+	if is, want := len(backoffs), 3; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := backoffs[2], 4*minAcceptBackoff; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestAcceptRetryPermanentError(t *testing.T) {
+	errClosed := errors.New("use of closed network connection")
+	accept := func() (net.Conn, error) {
+		return nil, &net.OpError{Op: "accept", Net: "tcp", Err: errClosed}
+	}
+
+	failed := func(err error, backoff time.Duration) {
+		t.Fatal("accept should not be retried")
+	}
+
+	if _, err := acceptRetry(accept, failed); err == nil {
+		t.Fatal("expected error")
+	}
+}
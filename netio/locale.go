@@ -0,0 +1,57 @@
+package netio
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type locale struct {
+	tag     string
+	quality float64
+}
+
+type byQuality []locale
+
+func (l byQuality) Len() int           { return len(l) }
+func (l byQuality) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+func (l byQuality) Less(i, j int) bool { return l[i].quality > l[j].quality }
+
+// PreferredLocales returns the locales of the Accept-Language header of request
+// ordered by preference, e.g. ["de-AT", "de", "en"] for "de-AT,de;q=0.9,en;q=0.8".
+func PreferredLocales(request *http.Request) []string {
+	header := request.Header.Get("Accept-Language")
+	if len(header) == 0 {
+		return nil
+	}
+
+	var locales []locale
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if len(tag) == 0 || tag == "*" {
+			continue
+		}
+
+		l := locale{tag: tag, quality: 1}
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					l.quality = q
+				}
+			}
+		}
+		locales = append(locales, l)
+	}
+
+	sort.Stable(byQuality(locales))
+
+	var tags []string
+	for _, l := range locales {
+		tags = append(tags, l.tag)
+	}
+
+	return tags
+}
@@ -0,0 +1,24 @@
+package netio
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestPreferredLocales(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/accessories", nil)
+	r.Header.Set("Accept-Language", "en;q=0.8, de-AT, de;q=0.9, *;q=0.1")
+
+	if is, want := PreferredLocales(r), []string{"de-AT", "de", "en"}; reflect.DeepEqual(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestPreferredLocalesWithoutHeader(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/accessories", nil)
+
+	if is := PreferredLocales(r); is != nil {
+		t.Fatal(is)
+	}
+}
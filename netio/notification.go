@@ -2,7 +2,6 @@ package netio
 
 import (
 	"bytes"
-	"encoding/json"
 	"github.com/brutella/hc/accessory"
 	"github.com/brutella/hc/characteristic"
 	"github.com/brutella/hc/netio/data"
@@ -49,13 +48,11 @@ func FixProtocolSpecifier(b []byte) []byte {
 func Body(a *accessory.Accessory, c *characteristic.Characteristic) (*bytes.Buffer, error) {
 
 	ch := data.Characteristic{AccessoryID: a.GetID(), CharacteristicID: c.GetID(), Value: c.Value}
-	chars := data.Characteristics{[]data.Characteristic{ch}}
-	result, err := json.Marshal(chars)
+	chars := data.Characteristics{Characteristics: []data.Characteristic{ch}}
+	result, err := chars.AppendJSON(make([]byte, 0, 64))
 	if err != nil {
 		return nil, err
 	}
 
-	var b bytes.Buffer
-	b.Write(result)
-	return &b, err
+	return bytes.NewBuffer(result), nil
 }
@@ -28,6 +28,10 @@ const (
 
 	// ErrCodeMaxAuthenticationAttempts is code for reaching maximum number of authentication attemps error (not used)
 	ErrCodeMaxAuthenticationAttempts errCode = 0x06
+
+	// ErrCodeUnavailable is code for pairing is not available, e.g. the pairing window is closed.
+	// The code has the same value as ErrCodeMaxAuthenticationAttempts (kTLVError_Unavailable).
+	ErrCodeUnavailable errCode = 0x06
 )
 
 func (t errCode) Byte() byte {
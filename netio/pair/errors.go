@@ -5,8 +5,23 @@ import (
 	"fmt"
 )
 
+// ErrInvalidSetupCode is reported when the SRP proof of the client is wrong during pair setup,
+// e.g. because the user entered a wrong setup code.
+var ErrInvalidSetupCode = errors.New("Invalid setup code")
+
+// ErrInvalidSignature is reported when the signature of the client is wrong during pair setup.
+var ErrInvalidSignature = errors.New("Invalid signature")
+
+// ErrPairingUnavailable is reported when pair setup is not available, e.g. because the pairing window is closed.
+var ErrPairingUnavailable = errors.New("Pairing unavailable")
+
+// ErrSetupCodeExpired is reported when the setup code of a SetupCodeProvider expired during pair setup.
+var ErrSetupCodeExpired = errors.New("Setup code expired")
+
 var errInvalidClientKeyLength = errors.New("Invalid client public key size")
 
+var errInvalidEncryptedDataLength = errors.New("Invalid encrypted data size")
+
 var errInvalidPairMethod = func(m PairMethodType) error {
 	return fmt.Errorf("Invalid pairing method %v\n", m)
 }
@@ -0,0 +1,88 @@
+//go:build go1.18
+// +build go1.18
+
+package pair
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/brutella/hc/db"
+	"github.com/brutella/hc/netio"
+	"github.com/brutella/hc/util"
+)
+
+// FuzzPairSetup sends M1 and the fuzzed data to an accessory.
+// Malformed requests must be rejected without panic.
+func FuzzPairSetup(f *testing.F) {
+	database, _ := db.NewTempDatabase()
+	bridge, err := netio.NewSecuredDevice("Bridge", "001-02-003", database)
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	clientDatabase, _ := db.NewTempDatabase()
+	client, _ := netio.NewDevice("Client", clientDatabase)
+
+	// Valid M3 and M5 requests
+	controller, _ := NewSetupServerController(bridge, database)
+	clientController := NewSetupClientController("001-02-003", client, clientDatabase)
+	m2, _ := HandleReaderForHandler(clientController.InitialPairingRequest(), controller)
+	m3, _ := HandleReaderForHandler(m2, clientController)
+	m3Bytes := bytes.NewBuffer(nil)
+	m3Bytes.ReadFrom(m3)
+	f.Add(m3Bytes.Bytes())
+
+	m5 := util.NewTLV8Container()
+	m5.SetByte(TagSequence, PairStepKeyExchangeRequest.Byte())
+	m5.SetBytes(TagEncryptedData, []byte{0x01})
+	f.Add(m5.BytesBuffer().Bytes())
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		controller, err := NewSetupServerController(bridge, database)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m1 := util.NewTLV8Container()
+		m1.SetByte(TagPairingMethod, 0)
+		m1.SetByte(TagSequence, PairStepStartRequest.Byte())
+		if _, err := controller.Handle(m1); err != nil {
+			t.Fatal(err)
+		}
+
+		HandleReaderForHandler(bytes.NewReader(b), controller)
+		HandleReaderForHandler(bytes.NewReader(b), controller)
+	})
+}
+
+// FuzzPairVerify sends M1 and the fuzzed data to an accessory.
+// Malformed requests must be rejected without panic.
+func FuzzPairVerify(f *testing.F) {
+	database, _ := db.NewTempDatabase()
+	bridge, err := netio.NewSecuredDevice("Bridge", "001-02-003", database)
+	if err != nil {
+		f.Fatal(err)
+	}
+	context := netio.NewContextForSecuredDevice(bridge)
+
+	clientDatabase, _ := db.NewTempDatabase()
+	client, _ := netio.NewDevice("Client", clientDatabase)
+	clientDatabase.SaveEntity(db.NewEntity(bridge.Name(), bridge.PublicKey(), nil))
+	database.SaveEntity(db.NewEntity(client.Name(), client.PublicKey(), nil))
+
+	m3 := util.NewTLV8Container()
+	m3.SetByte(TagSequence, VerifyStepFinishRequest.Byte())
+	m3.SetBytes(TagEncryptedData, bytes.Repeat([]byte{0x01}, 8))
+	f.Add(m3.BytesBuffer().Bytes())
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		controller := NewVerifyServerController(database, context)
+		clientController := NewVerifyClientController(client, clientDatabase)
+		if _, err := HandleReaderForHandler(clientController.InitialKeyVerifyRequest(), controller); err != nil {
+			t.Fatal(err)
+		}
+
+		HandleReaderForHandler(bytes.NewReader(b), controller)
+	})
+}
@@ -0,0 +1,14 @@
+package pair
+
+// splitMAC returns the encrypted message and the 16 byte MAC of the encrypted data.
+func splitMAC(data []byte) ([]byte, [16]byte, error) {
+	var mac [16]byte
+	if len(data) < len(mac) {
+		return nil, mac, errInvalidEncryptedDataLength
+	}
+
+	message := data[:len(data)-len(mac)]
+	copy(mac[:], data[len(message):])
+
+	return message, mac, nil
+}
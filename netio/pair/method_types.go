@@ -8,7 +8,8 @@ const (
 	// PairingMethodDefault is the default pairing method.
 	PairingMethodDefault PairMethodType = 0x00
 
-	// PairingMethodMFi is used to pair with an MFi compliant accessory (not used).
+	// PairingMethodMFi is used to pair with an MFi compliant accessory,
+	// which authenticates itself with a software token (see TokenProvider).
 	PairingMethodMFi PairMethodType = 0x01
 
 	// PairingMethodAdd is used to pair a client by exchanging keys on a secured
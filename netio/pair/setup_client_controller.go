@@ -190,10 +190,10 @@ func (setup *SetupClientController) handlePairStepVerifyResponse(in util.Contain
 // Server -> Client
 // - encrpyted tlv8: bridge LTPK, bridge name, signature (of hash `H2`, bridge name, LTPK)
 func (setup *SetupClientController) handleKeyExchange(in util.Container) (util.Container, error) {
-	data := in.GetBytes(TagEncryptedData)
-	message := data[:(len(data) - 16)]
-	var mac [16]byte
-	copy(mac[:], data[len(message):]) // 16 byte (MAC)
+	message, mac, err := splitMAC(in.GetBytes(TagEncryptedData))
+	if err != nil {
+		return nil, err
+	}
 	fmt.Println("->     Message:", hex.EncodeToString(message))
 	fmt.Println("->     MAC:", hex.EncodeToString(mac[:]))
 
@@ -2,9 +2,7 @@ package pair
 
 import (
 	"github.com/brutella/hc/crypto/hkdf"
-
-	"crypto/sha512"
-	"github.com/tadglines/go-pkgs/crypto/srp"
+	"github.com/brutella/hc/crypto/srp"
 )
 
 // SetupClientSession holds the keys to pair with an accessory.
@@ -18,9 +16,11 @@ type SetupClientSession struct {
 
 // NewSetupClientSession returns a new setup client session
 func NewSetupClientSession(username string, pin string) *SetupClientSession {
-	rp, _ := srp.NewSRP(SRPGroup, sha512.New, KeyDerivativeFuncRFC2945(sha512.New, []byte(username)))
+	client, err := newSRP().NewClientSession([]byte(username), []byte(pin))
+	if err != nil {
+		panic(err)
+	}
 
-	client := rp.NewClientSession([]byte(username), []byte(pin))
 	hap := SetupClientSession{
 		session: client,
 	}
@@ -32,9 +32,9 @@ func NewSetupClientSession(username string, pin string) *SetupClientSession {
 func (s *SetupClientSession) GenerateKeys(salt []byte, otherPublicKey []byte) error {
 	privateKey, err := s.session.ComputeKey(salt, otherPublicKey)
 	if err == nil {
-		s.PublicKey = s.session.GetA()
+		s.PublicKey = s.session.PublicKey()
 		s.PrivateKey = privateKey
-		s.Proof = s.session.ComputeAuthenticator()
+		s.Proof = s.session.Proof()
 	}
 
 	return err
@@ -42,7 +42,7 @@ func (s *SetupClientSession) GenerateKeys(salt []byte, otherPublicKey []byte) er
 
 // IsServerProofValid returns true when the server proof `M2` is valid.
 func (s *SetupClientSession) IsServerProofValid(proof []byte) bool {
-	return s.session.VerifyServerAuthenticator(proof)
+	return s.session.VerifyServerProof(proof)
 }
 
 // SetupEncryptionKey calculates encryption key `K` based on salt and info.
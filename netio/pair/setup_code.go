@@ -0,0 +1,26 @@
+package pair
+
+// SetupCodeProvider provides a new setup code for every pair setup, e.g. for an accessory which
+// shows a random setup code on its display instead of using a fixed pin.
+type SetupCodeProvider interface {
+	// NewSetupCode returns a new setup code in the format XXX-XX-XXX.
+	// The code replaces previous codes. When the code is empty, the pin or
+	// setup verifier of the device is used.
+	NewSetupCode() (string, error)
+
+	// IsValid returns true when code is valid and can be used to pair.
+	IsValid(code string) bool
+}
+
+// SetupVerifierProvider is a SetupCodeProvider which provides the salt and SRP verifier
+// of a setup code instead of the code, e.g. for setup codes which are not stored in plaintext.
+type SetupVerifierProvider interface {
+	SetupCodeProvider
+
+	// NewSetupVerifier returns the label, salt and verifier of the setup code for a new pair setup.
+	// When the label is empty, the pin or setup verifier of the device is used.
+	NewSetupVerifier() (label string, salt []byte, verifier []byte, err error)
+
+	// IsActive returns true when the setup code with label can be used to pair.
+	IsActive(label string) bool
+}
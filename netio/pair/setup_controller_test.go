@@ -1,6 +1,9 @@
 package pair
 
 import (
+	"bytes"
+
+	"github.com/brutella/hc/crypto/chacha20poly1305"
 	"github.com/brutella/hc/db"
 	"github.com/brutella/hc/netio"
 	"github.com/brutella/hc/util"
@@ -77,4 +80,363 @@ func TestPairingIntegration(t *testing.T) {
 	if request != nil {
 		t.Fatal(request)
 	}
+
+	if is, want := controller.Username(), "Client"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+// Tests the pairing setup with a precomputed verifier
+func TestPairingWithSetupVerifier(t *testing.T) {
+	salt, verifier, err := NewSetupVerifier("001-02-003")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	database, _ := db.NewTempDatabase()
+	bridge, err := netio.NewSetupVerifierDevice("Macbook Bridge", salt, verifier, database)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller, err := NewSetupServerController(bridge, database)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientDatabase, _ := db.NewTempDatabase()
+	client, _ := netio.NewDevice("Client", clientDatabase)
+	clientController := NewSetupClientController("001-02-003", client, clientDatabase)
+
+	var handlers = []netio.ContainerHandler{controller, clientController, controller, clientController, controller, clientController}
+	req := clientController.InitialPairingRequest()
+	for _, h := range handlers {
+		if req, err = HandleReaderForHandler(req, h); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := database.EntityWithName("Client"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Tests the pairing setup with software authentication
+func TestPairingWithSoftwareAuthentication(t *testing.T) {
+	database, _ := db.NewTempDatabase()
+	bridge, _ := netio.NewSecuredDevice("Macbook Bridge", "001-02-003", database)
+	controller, err := NewSetupServerController(bridge, database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	controller.SetTokenProvider(&testTokenProvider{[]byte("token")})
+
+	clientDatabase, _ := db.NewTempDatabase()
+	client, _ := netio.NewDevice("Client", clientDatabase)
+	clientController := NewSetupClientController("001-02-003", client, clientDatabase)
+
+	m1 := util.NewTLV8Container()
+	m1.SetByte(TagPairingMethod, PairingMethodMFi.Byte())
+	m1.SetByte(TagSequence, PairStepStartRequest.Byte())
+
+	m2, err := controller.Handle(m1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m3, err := clientController.Handle(m2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m4, err := controller.Handle(m3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := clientController.Handle(m4); err != nil {
+		t.Fatal(err)
+	}
+
+	data := m4.GetBytes(TagEncryptedData)
+	var mac [16]byte
+	copy(mac[:], data[len(data)-16:])
+	decrypted, err := chacha20poly1305.DecryptAndVerify(clientController.session.EncryptionKey[:], []byte("PS-Msg04"), data[:len(data)-16], mac, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tlv, err := util.NewTLV8ContainerFromReader(bytes.NewReader(decrypted))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := tlv.GetString(TagMFiSignature), "token"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestPairingWithAuthenticationWithoutToken(t *testing.T) {
+	database, _ := db.NewTempDatabase()
+	bridge, _ := netio.NewSecuredDevice("Macbook Bridge", "001-02-003", database)
+	controller, _ := NewSetupServerController(bridge, database)
+
+	m1 := util.NewTLV8Container()
+	m1.SetByte(TagPairingMethod, PairingMethodMFi.Byte())
+	m1.SetByte(TagSequence, PairStepStartRequest.Byte())
+
+	if _, err := controller.Handle(m1); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// Tests that the progress of pair setup is reported
+func TestPairingProgress(t *testing.T) {
+	database, _ := db.NewTempDatabase()
+	bridge, err := netio.NewSecuredDevice("Macbook Bridge", "001-02-003", database)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller, err := NewSetupServerController(bridge, database)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var progress []SetupProgress
+	controller.SetProgressFunc(func(p SetupProgress) {
+		progress = append(progress, p)
+	})
+
+	clientDatabase, _ := db.NewTempDatabase()
+	client, _ := netio.NewDevice("Client", clientDatabase)
+	clientController := NewSetupClientController("001-02-003", client, clientDatabase)
+
+	var handlers = []netio.ContainerHandler{controller, clientController, controller, clientController, controller, clientController}
+	req := clientController.InitialPairingRequest()
+	for _, h := range handlers {
+		if req, err = HandleReaderForHandler(req, h); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if is, want := len(progress), 6; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	for i, p := range progress {
+		if is, want := p.Step, PairStepType(i+1); is != want {
+			t.Fatalf("is=%v want=%v", is, want)
+		}
+		if p.Err != nil {
+			t.Fatal(p.Err)
+		}
+	}
+
+	if x := progress[len(progress)-1]; x.Done() == false {
+		t.Fatal("expected pair setup to be done")
+	}
+}
+
+// Tests that a wrong setup code is reported
+func TestPairingProgressWithWrongSetupCode(t *testing.T) {
+	database, _ := db.NewTempDatabase()
+	bridge, err := netio.NewSecuredDevice("Macbook Bridge", "001-02-003", database)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller, err := NewSetupServerController(bridge, database)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var last SetupProgress
+	controller.SetProgressFunc(func(p SetupProgress) {
+		last = p
+	})
+
+	clientDatabase, _ := db.NewTempDatabase()
+	client, _ := netio.NewDevice("Client", clientDatabase)
+	clientController := NewSetupClientController("111-22-333", client, clientDatabase)
+
+	var handlers = []netio.ContainerHandler{controller, clientController, controller}
+	req := clientController.InitialPairingRequest()
+	for _, h := range handlers {
+		if req, err = HandleReaderForHandler(req, h); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if is, want := last.Step, PairStepVerifyResponse; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := last.Err, ErrInvalidSetupCode; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if last.Verified() == true {
+		t.Fatal("expected SRP verification to fail")
+	}
+}
+
+// Tests that pair setup is rejected when pairing is not available
+func TestPairingUnavailable(t *testing.T) {
+	database, _ := db.NewTempDatabase()
+	bridge, err := netio.NewSecuredDevice("Macbook Bridge", "001-02-003", database)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller, err := NewSetupServerController(bridge, database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	controller.SetAvailabilityFunc(func() bool { return false })
+
+	clientDatabase, _ := db.NewTempDatabase()
+	client, _ := netio.NewDevice("Client", clientDatabase)
+	clientController := NewSetupClientController("001-02-003", client, clientDatabase)
+
+	in := util.NewTLV8Container()
+	in.SetByte(TagPairingMethod, PairingMethodDefault.Byte())
+	in.SetByte(TagSequence, PairStepStartRequest.Byte())
+
+	out, err := controller.Handle(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := out.GetByte(TagErrCode), ErrCodeUnavailable.Byte(); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if len(out.GetBytes(TagPublicKey)) > 0 {
+		t.Fatal("expected no public key")
+	}
+
+	// Pair setup can be started when pairing becomes available
+	controller.SetAvailabilityFunc(nil)
+	if _, err := HandleReaderForHandler(clientController.InitialPairingRequest(), controller); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type testSetupCodes struct {
+	code  string
+	valid bool
+}
+
+func (c *testSetupCodes) NewSetupCode() (string, error) {
+	return c.code, nil
+}
+
+func (c *testSetupCodes) IsValid(code string) bool {
+	return c.valid && code == c.code
+}
+
+// Tests pairing with a setup code of a SetupCodeProvider
+func TestPairingWithSetupCodeProvider(t *testing.T) {
+	database, _ := db.NewTempDatabase()
+	bridge, err := netio.NewSecuredDevice("Macbook Bridge", "001-02-003", database)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller, err := NewSetupServerController(bridge, database)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codes := &testSetupCodes{code: "482-91-735", valid: true}
+	controller.SetSetupCodeProvider(codes)
+
+	clientDatabase, _ := db.NewTempDatabase()
+	client, _ := netio.NewDevice("Client", clientDatabase)
+	clientController := NewSetupClientController("482-91-735", client, clientDatabase)
+
+	var handlers = []netio.ContainerHandler{controller, clientController, controller, clientController, controller, clientController}
+	req := clientController.InitialPairingRequest()
+	for _, h := range handlers {
+		if req, err = HandleReaderForHandler(req, h); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if is, want := controller.Username(), "Client"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+// Tests that pairing fails when the setup code expired
+func TestPairingWithExpiredSetupCode(t *testing.T) {
+	database, _ := db.NewTempDatabase()
+	bridge, err := netio.NewSecuredDevice("Macbook Bridge", "001-02-003", database)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller, err := NewSetupServerController(bridge, database)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codes := &testSetupCodes{code: "482-91-735", valid: true}
+	controller.SetSetupCodeProvider(codes)
+
+	var last SetupProgress
+	controller.SetProgressFunc(func(p SetupProgress) {
+		last = p
+	})
+
+	clientDatabase, _ := db.NewTempDatabase()
+	client, _ := netio.NewDevice("Client", clientDatabase)
+	clientController := NewSetupClientController("482-91-735", client, clientDatabase)
+
+	req, err := HandleReaderForHandler(clientController.InitialPairingRequest(), controller)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req, err = HandleReaderForHandler(req, clientController); err != nil {
+		t.Fatal(err)
+	}
+
+	codes.valid = false
+	if _, err = HandleReaderForHandler(req, controller); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := last.Err, ErrSetupCodeExpired; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+// Tests that the pin of the device is used when the SetupCodeProvider returns an empty code
+func TestPairingWithEmptySetupCode(t *testing.T) {
+	database, _ := db.NewTempDatabase()
+	bridge, err := netio.NewSecuredDevice("Macbook Bridge", "001-02-003", database)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller, err := NewSetupServerController(bridge, database)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller.SetSetupCodeProvider(&testSetupCodes{})
+
+	clientDatabase, _ := db.NewTempDatabase()
+	client, _ := netio.NewDevice("Client", clientDatabase)
+	clientController := NewSetupClientController("001-02-003", client, clientDatabase)
+
+	var handlers = []netio.ContainerHandler{controller, clientController, controller, clientController, controller, clientController}
+	req := clientController.InitialPairingRequest()
+	for _, h := range handlers {
+		if req, err = HandleReaderForHandler(req, h); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if is, want := controller.Username(), "Client"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
 }
@@ -0,0 +1,26 @@
+package pair
+
+// SetupProgress describes the progress of pair setup, which is reported after every
+// message (M1 to M6) of the pair setup exchange.
+type SetupProgress struct {
+	// Step is the message of the pair setup exchange, e.g. PairStepVerifyResponse (M4)
+	Step PairStepType
+
+	// Err is set when pair setup failed, e.g. ErrInvalidSetupCode when the SRP proof
+	// of the client is wrong because the user entered a wrong setup code.
+	Err error
+}
+
+// Verified returns true when the client proved the knowledge of the setup code (SRP verification).
+func (p SetupProgress) Verified() bool {
+	return p.Step >= PairStepVerifyResponse && p.Err == nil
+}
+
+// Done returns true when pair setup finished successfully.
+func (p SetupProgress) Done() bool {
+	return p.Step == PairStepKeyExchangeResponse && p.Err == nil
+}
+
+// SetupProgressFunc is called when pair setup progresses, e.g. to blink a LED
+// while pairing and to turn it on when pairing finished.
+type SetupProgressFunc func(SetupProgress)
@@ -10,6 +10,7 @@ import (
 	"github.com/brutella/log"
 
 	"bytes"
+	"context"
 	"encoding/hex"
 	"errors"
 )
@@ -25,6 +26,25 @@ type SetupServerController struct {
 	session  *SetupServerSession
 	step     PairStepType
 	database db.Database
+
+	// method is the pairing method of the pair setup request
+	method PairMethodType
+	tokens TokenProvider
+
+	// progress is called when pair setup progresses
+	progress SetupProgressFunc
+
+	// available returns false when pair setup is not available
+	available func() bool
+
+	// codes provides a new setup code for every pair setup
+	codes SetupCodeProvider
+
+	// code is the setup code of the session, or its label when codes is a SetupVerifierProvider
+	code string
+
+	// username of the client after successful pairing
+	username string
 }
 
 // NewSetupServerController returns a new pair setup controller.
@@ -33,7 +53,11 @@ func NewSetupServerController(device netio.SecuredDevice, database db.Database)
 		return nil, errors.New("no private key for pairing available")
 	}
 
-	session, err := NewSetupServerSession(device.Name(), device.Pin())
+	var session *SetupServerSession
+	err := computeSRP(context.Background(), func() (err error) {
+		session, err = newDeviceSession(device)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -49,17 +73,30 @@ func NewSetupServerController(device netio.SecuredDevice, database db.Database)
 }
 
 // Handle processes a container to pair (exchange keys) with a client.
-func (setup *SetupServerController) Handle(in util.Container) (out util.Container, err error) {
+func (setup *SetupServerController) Handle(in util.Container) (util.Container, error) {
+	return setup.HandleContext(context.Background(), in)
+}
+
+// HandleContext processes a container to pair with a client like Handle.
+// The SRP computations run in a worker and are abandoned when ctx is done
+// or SRPTimeout elapsed, in which case pair setup has to start over.
+func (setup *SetupServerController) HandleContext(ctx context.Context, in util.Container) (out util.Container, err error) {
 	method := PairMethodType(in.GetByte(TagPairingMethod))
 
 	// It is valid that pair method is not sent
-	// If method set then it must be 0x00
-	if method != PairingMethodDefault {
+	// If method set then it must be 0x00 or 0x01 (with authentication)
+	if method != PairingMethodDefault && method != PairingMethodMFi {
 		return nil, errInvalidPairMethod(method)
 	}
 
 	seq := PairStepType(in.GetByte(TagSequence))
 
+	defer func() {
+		if err != nil {
+			setup.notify(seq, err)
+		}
+	}()
+
 	switch seq {
 	case PairStepStartRequest:
 		if setup.step != PairStepWaiting {
@@ -67,6 +104,28 @@ func (setup *SetupServerController) Handle(in util.Container) (out util.Containe
 			return nil, errInvalidInternalPairStep(setup.step)
 		}
 
+		if method == PairingMethodMFi && setup.tokens == nil {
+			return nil, errInvalidPairMethod(method)
+		}
+
+		if setup.available != nil && setup.available() == false {
+			log.Println("[WARN] Pair setup is not available")
+			setup.notify(seq, nil)
+			return setup.unavailable(), nil
+		}
+
+		if setup.codes != nil {
+			if err := setup.setupSessionWithNewCode(ctx); err != nil {
+				return nil, err
+			}
+		} else if setup.session == nil {
+			if err := setup.setupDeviceSession(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		setup.method = method
+		setup.notify(seq, nil)
 		out, err = setup.handlePairStart(in)
 	case PairStepVerifyRequest:
 		if setup.step != PairStepStartResponse {
@@ -74,13 +133,15 @@ func (setup *SetupServerController) Handle(in util.Container) (out util.Containe
 			return nil, errInvalidInternalPairStep(setup.step)
 		}
 
-		out, err = setup.handlePairVerify(in)
+		setup.notify(seq, nil)
+		out, err = setup.handlePairVerify(ctx, in)
 	case PairStepKeyExchangeRequest:
 		if setup.step != PairStepVerifyResponse {
 			setup.reset()
 			return nil, errInvalidInternalPairStep(setup.step)
 		}
 
+		setup.notify(seq, nil)
 		out, err = setup.handleKeyExchange(in)
 	default:
 		return nil, errInvalidPairStep(seq)
@@ -106,6 +167,8 @@ func (setup *SetupServerController) handlePairStart(in util.Container) (util.Con
 	log.Println("[VERB] <-     B:", hex.EncodeToString(out.GetBytes(TagPublicKey)))
 	log.Println("[VERB] <-     s:", hex.EncodeToString(out.GetBytes(TagSalt)))
 
+	setup.notify(setup.step, nil)
+
 	return out, nil
 }
 
@@ -117,7 +180,7 @@ func (setup *SetupServerController) handlePairStart(in util.Container) (util.Con
 // - M2: proof
 // or
 // - auth error
-func (setup *SetupServerController) handlePairVerify(in util.Container) (util.Container, error) {
+func (setup *SetupServerController) handlePairVerify(ctx context.Context, in util.Container) (util.Container, error) {
 	setup.step = PairStepVerifyResponse
 	out := util.NewTLV8Container()
 	out.SetByte(TagSequence, setup.step.Byte())
@@ -125,19 +188,37 @@ func (setup *SetupServerController) handlePairVerify(in util.Container) (util.Co
 	clientPublicKey := in.GetBytes(TagPublicKey)
 	log.Println("[VERB] ->     A:", hex.EncodeToString(clientPublicKey))
 
-	err := setup.session.SetupPrivateKeyFromClientPublicKey(clientPublicKey)
+	clientProof := in.GetBytes(TagProof)
+	log.Println("[VERB] ->     M1:", hex.EncodeToString(clientProof))
+
+	var proof []byte
+	var proofErr error
+	session := setup.session
+	err := computeSRP(ctx, func() error {
+		if err := session.SetupPrivateKeyFromClientPublicKey(clientPublicKey); err != nil {
+			return err
+		}
+		proof, proofErr = session.ProofFromClientProof(clientProof)
+		return nil
+	})
 	if err != nil {
+		// The session is still used by an abandoned computation
+		setup.session = nil
+		setup.reset()
 		return nil, err
 	}
 
-	clientProof := in.GetBytes(TagProof)
-	log.Println("[VERB] ->     M1:", hex.EncodeToString(clientProof))
-
-	proof, err := setup.session.ProofFromClientProof(clientProof)
-	if err != nil || len(proof) == 0 { // proof `M1` is wrong
+	err = proofErr
+	if setup.codeExpired() == true {
+		log.Println("[WARN] Setup code expired")
+		setup.reset()
+		out.SetByte(TagErrCode, ErrCodeAuthenticationFailed.Byte()) // return error 2
+		setup.notify(PairStepVerifyResponse, ErrSetupCodeExpired)
+	} else if err != nil || len(proof) == 0 { // proof `M1` is wrong
 		log.Println("[WARN] Proof M1 is wrong")
 		setup.reset()
 		out.SetByte(TagErrCode, ErrCodeAuthenticationFailed.Byte()) // return error 2
+		setup.notify(PairStepVerifyResponse, ErrInvalidSetupCode)
 	} else {
 		log.Println("[INFO] Proof M1 is valid")
 		err := setup.session.SetupEncryptionKey([]byte("Pair-Setup-Encrypt-Salt"), []byte("Pair-Setup-Encrypt-Info"))
@@ -147,6 +228,16 @@ func (setup *SetupServerController) handlePairVerify(in util.Container) (util.Co
 
 		// Return proof `M2`
 		out.SetBytes(TagProof, proof)
+
+		if setup.method == PairingMethodMFi {
+			encrypted, err := setup.softwareAuthentication()
+			if err != nil {
+				return nil, err
+			}
+			out.SetBytes(TagEncryptedData, encrypted)
+		}
+
+		setup.notify(PairStepVerifyResponse, nil)
 	}
 
 	log.Println("[VERB] <-     M2:", hex.EncodeToString(out.GetBytes(TagProof)))
@@ -156,6 +247,25 @@ func (setup *SetupServerController) handlePairVerify(in util.Container) (util.Co
 	return out, nil
 }
 
+// softwareAuthentication returns the encrypted software token
+// which authenticates the accessory when pairing with authentication.
+func (setup *SetupServerController) softwareAuthentication() ([]byte, error) {
+	token, err := setup.tokens.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	tlv := util.NewTLV8Container()
+	tlv.SetBytes(TagMFiSignature, token)
+
+	encrypted, mac, err := chacha20poly1305.EncryptAndSeal(setup.session.EncryptionKey[:], []byte("PS-Msg04"), tlv.BytesBuffer().Bytes(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(encrypted, mac[:]...), nil
+}
+
 // Client -> Server
 // - encrypted tlv8: entity ltpk, entity name and signature (of H, entity name, ltpk)
 // - auth tag (mac)
@@ -173,10 +283,11 @@ func (setup *SetupServerController) handleKeyExchange(in util.Container) (util.C
 
 	out.SetByte(TagSequence, setup.step.Byte())
 
-	data := in.GetBytes(TagEncryptedData)
-	message := data[:(len(data) - 16)]
-	var mac [16]byte
-	copy(mac[:], data[len(message):]) // 16 byte (MAC)
+	message, mac, err := splitMAC(in.GetBytes(TagEncryptedData))
+	if err != nil {
+		setup.reset()
+		return nil, err
+	}
 	log.Println("[VERB] ->     Message:", hex.EncodeToString(message))
 	log.Println("[VERB] ->     MAC:", hex.EncodeToString(mac[:]))
 
@@ -186,6 +297,7 @@ func (setup *SetupServerController) handleKeyExchange(in util.Container) (util.C
 		setup.reset()
 		log.Println("[ERRO]", err)
 		out.SetByte(TagErrCode, ErrCodeUnknown.Byte()) // return error 1
+		setup.notify(PairStepKeyExchangeResponse, err)
 	} else {
 		decryptedBuf := bytes.NewBuffer(decrypted)
 		in, err := util.NewTLV8ContainerFromReader(decryptedBuf)
@@ -211,11 +323,13 @@ func (setup *SetupServerController) handleKeyExchange(in util.Container) (util.C
 			log.Println("[WARN] ed25519 signature is invalid")
 			setup.reset()
 			out.SetByte(TagErrCode, ErrCodeAuthenticationFailed.Byte()) // return error 2
+			setup.notify(PairStepKeyExchangeResponse, ErrInvalidSignature)
 		} else {
 			log.Println("[VERB] ed25519 signature is valid")
 			// Store entity ltpk and name
 			entity := db.NewEntity(username, clientltpk, nil)
 			setup.database.SaveEntity(entity)
+			setup.username = username
 			log.Printf("[INFO] Stored ltpk '%s' for entity '%s'\n", hex.EncodeToString(clientltpk), username)
 
 			ltpk := setup.device.PublicKey()
@@ -246,13 +360,160 @@ func (setup *SetupServerController) handleKeyExchange(in util.Container) (util.C
 			encrypted, mac, _ := chacha20poly1305.EncryptAndSeal(setup.session.EncryptionKey[:], []byte("PS-Msg06"), tlvPairKeyExchange.BytesBuffer().Bytes(), nil)
 			out.SetByte(TagSequence, PairStepKeyExchangeRequest.Byte())
 			out.SetBytes(TagEncryptedData, append(encrypted, mac[:]...))
+			setup.notify(PairStepKeyExchangeResponse, nil)
 		}
 	}
 
 	return out, nil
 }
 
+// SetTokenProvider sets the provider of the software token, which is required
+// to pair with authentication (PairingMethodMFi).
+func (setup *SetupServerController) SetTokenProvider(p TokenProvider) {
+	setup.tokens = p
+}
+
+// SetProgressFunc sets the function which is called when pair setup progresses.
+func (setup *SetupServerController) SetProgressFunc(f SetupProgressFunc) {
+	setup.progress = f
+}
+
+// SetAvailabilityFunc sets the function which returns whether pair setup is available.
+// If the function returns false, pair setup requests are rejected with ErrCodeUnavailable.
+func (setup *SetupServerController) SetAvailabilityFunc(f func() bool) {
+	setup.available = f
+}
+
+// SetSetupCodeProvider sets the provider of the setup codes. When set, a new setup code
+// is used for every pair setup instead of the pin or setup verifier of the device.
+func (setup *SetupServerController) SetSetupCodeProvider(p SetupCodeProvider) {
+	setup.codes = p
+}
+
+// Username returns the username of the client which successfully paired,
+// or an empty string if pairing did not finish yet.
+func (setup *SetupServerController) Username() string {
+	return setup.username
+}
+
+// codeExpired returns true when the setup code of the session can't be used to pair anymore.
+func (setup *SetupServerController) codeExpired() bool {
+	if setup.codes == nil || len(setup.code) == 0 {
+		return false
+	}
+
+	if p, ok := setup.codes.(SetupVerifierProvider); ok == true {
+		return p.IsActive(setup.code) == false
+	}
+
+	return setup.codes.IsValid(setup.code) == false
+}
+
+// setupSessionWithNewCode replaces the session with a session for a new setup code.
+func (setup *SetupServerController) setupSessionWithNewCode(ctx context.Context) error {
+	if p, ok := setup.codes.(SetupVerifierProvider); ok == true {
+		return setup.setupSessionWithNewVerifier(ctx, p)
+	}
+
+	code, err := setup.codes.NewSetupCode()
+	if err != nil {
+		return err
+	}
+
+	var session *SetupServerSession
+	err = computeSRP(ctx, func() (err error) {
+		if len(code) == 0 {
+			session, err = newDeviceSession(setup.device)
+		} else {
+			session, err = NewSetupServerSession(setup.device.Name(), code)
+		}
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	setup.session = session
+	setup.code = code
+
+	return nil
+}
+
+// setupSessionWithNewVerifier replaces the session with a session for the salt and verifier
+// of a new setup code. The label of the code is used to check whether the code expired.
+func (setup *SetupServerController) setupSessionWithNewVerifier(ctx context.Context, p SetupVerifierProvider) error {
+	label, salt, verifier, err := p.NewSetupVerifier()
+	if err != nil {
+		return err
+	}
+
+	var session *SetupServerSession
+	err = computeSRP(ctx, func() (err error) {
+		if len(label) == 0 {
+			session, err = newDeviceSession(setup.device)
+		} else {
+			session, err = NewSetupServerSessionWithVerifier(setup.device.Name(), salt, verifier)
+		}
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	setup.session = session
+	setup.code = label
+
+	return nil
+}
+
+// setupDeviceSession sets the session for the pin or setup verifier of the device.
+func (setup *SetupServerController) setupDeviceSession(ctx context.Context) error {
+	var session *SetupServerSession
+	err := computeSRP(ctx, func() (err error) {
+		session, err = newDeviceSession(setup.device)
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	setup.session = session
+	setup.code = ""
+
+	return nil
+}
+
+// newDeviceSession returns a session for the pin or setup verifier of device.
+func newDeviceSession(device netio.SecuredDevice) (*SetupServerSession, error) {
+	if d, ok := device.(netio.SetupVerifierDevice); ok == true {
+		salt, verifier := d.SetupVerifier()
+		return NewSetupServerSessionWithVerifier(device.Name(), salt, verifier)
+	}
+
+	return NewSetupServerSession(device.Name(), device.Pin())
+}
+
+// unavailable returns the response for a rejected pair setup request.
+func (setup *SetupServerController) unavailable() util.Container {
+	out := util.NewTLV8Container()
+	out.SetByte(TagSequence, PairStepStartResponse.Byte())
+	out.SetByte(TagErrCode, ErrCodeUnavailable.Byte())
+	setup.notify(PairStepStartResponse, ErrPairingUnavailable)
+
+	return out
+}
+
+func (setup *SetupServerController) notify(step PairStepType, err error) {
+	if setup.progress != nil {
+		setup.progress(SetupProgress{Step: step, Err: err})
+	}
+}
+
 func (setup *SetupServerController) reset() {
 	setup.step = PairStepWaiting
+	setup.method = PairingMethodDefault
 	// TODO: reset session
 }
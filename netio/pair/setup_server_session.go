@@ -2,9 +2,7 @@ package pair
 
 import (
 	"github.com/brutella/hc/crypto/hkdf"
-
-	"crypto/sha512"
-	"github.com/tadglines/go-pkgs/crypto/srp"
+	"github.com/brutella/hc/crypto/srp"
 
 	"errors"
 )
@@ -21,35 +19,40 @@ type SetupServerSession struct {
 
 // NewSetupServerSession return a new setup server session.
 func NewSetupServerSession(username, pin string) (*SetupServerSession, error) {
-	var err error
-	pairName := []byte("Pair-Setup")
-	srp, err := srp.NewSRP(SRPGroup, sha512.New, KeyDerivativeFuncRFC2945(sha512.New, []byte(pairName)))
+	salt, verifier, err := NewSetupVerifier(pin)
+	if err != nil {
+		return nil, err
+	}
 
-	if err == nil {
-		srp.SaltLength = 16
-		salt, v, err := srp.ComputeVerifier([]byte(pin))
-		if err == nil {
-			session := srp.NewServerSession([]byte(pairName), salt, v)
-			pairing := SetupServerSession{
-				session:   session,
-				Salt:      salt,
-				PublicKey: session.GetB(),
-				Username:  []byte(username),
-			}
-			return &pairing, nil
-		}
+	return NewSetupServerSessionWithVerifier(username, salt, verifier)
+}
+
+// NewSetupServerSessionWithVerifier returns a new setup server session for a
+// precomputed salt and verifier (see NewSetupVerifier).
+func NewSetupServerSessionWithVerifier(username string, salt, verifier []byte) (*SetupServerSession, error) {
+	session, err := newSRP().NewServerSession([]byte(setupUsername), salt, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	pairing := SetupServerSession{
+		session:   session,
+		Salt:      salt,
+		PublicKey: session.PublicKey(),
+		Username:  []byte(username),
 	}
 
-	return nil, err
+	return &pairing, nil
 }
 
 // ProofFromClientProof validates client proof (`M1`) and returns authenticator or error if proof is not valid.
 func (p *SetupServerSession) ProofFromClientProof(clientProof []byte) ([]byte, error) {
-	if !p.session.VerifyClientAuthenticator(clientProof) { // Validates M1 based on S and A
+	proof, err := p.session.Proof(clientProof) // Validates M1 based on S and A
+	if err != nil {
 		return nil, errors.New("Client proof is not valid")
 	}
 
-	return p.session.ComputeAuthenticator(clientProof), nil
+	return proof, nil
 }
 
 // SetupPrivateKeyFromClientPublicKey calculates and internally sets secret key `S` based on client public key `A`
@@ -1,29 +1,36 @@
 package pair
 
 import (
-	"github.com/tadglines/go-pkgs/crypto/srp"
+	"crypto/sha512"
+
+	"github.com/brutella/hc/crypto/srp"
 )
 
 // Main SRP algorithm is described in http://srp.stanford.edu/design.html
 // The HAP uses the SRP-6a Stanford implementation with the following characteristics
 //      x = H(s | H(I | ":" | P)) -> called the key derivate function
 //      M1 = H(H(N) xor H(g), H(I), s, A, B, K)
-const (
-	SRPGroup = "rfc5054.3072" // N (modulo) => 384 byte
-)
+//
+// SRPGroup is the 3072-bit group (N => 384 byte) with the generator 5.
+var SRPGroup = srp.Group3072
+
+// setupUsername is the SRP username used by the accessory.
+const setupUsername = "Pair-Setup"
+
+func newSRP() *srp.SRP {
+	return srp.New(SRPGroup, sha512.New)
+}
+
+// NewSetupVerifier returns a random salt and the SRP verifier for a pin (e.g. "001-02-003").
+//
+// The salt and verifier can be computed when the accessory is manufactured
+// and passed to NewSetupServerSessionWithVerifier, so that the pin
+// doesn't have to be stored on the accessory.
+func NewSetupVerifier(pin string) ([]byte /*salt*/, []byte /*verifier*/, error) {
+	return newSRP().NewVerifier([]byte(setupUsername), []byte(pin))
+}
 
-// KeyDerivativeFuncRFC2945 returns the SRP-6a key derivative function which does
-//      x = H(s | H(I | ":" | P))
-func KeyDerivativeFuncRFC2945(h srp.HashFunc, username []byte) srp.KeyDerivationFunc {
-	return func(salt, pin []byte) []byte {
-		h := h()
-		h.Write(username)
-		h.Write([]byte(":"))
-		h.Write(pin)
-		t2 := h.Sum(nil)
-		h.Reset()
-		h.Write(salt)
-		h.Write(t2)
-		return h.Sum(nil)
-	}
+// SetupVerifier returns the SRP verifier for a pin (e.g. "001-02-003") and salt.
+func SetupVerifier(pin string, salt []byte) []byte {
+	return newSRP().Verifier([]byte(setupUsername), []byte(pin), salt)
 }
@@ -0,0 +1,60 @@
+package pair
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// SRPTimeout is the maximum duration of the SRP computations of one pair setup step.
+// The SRP exponentiations with the 3072-bit group take several seconds on slow CPUs.
+var SRPTimeout = 30 * time.Second
+
+// ErrSRPTimeout is returned when the SRP computations of a pair setup step didn't finish in time.
+var ErrSRPTimeout = errors.New("SRP computation timed out")
+
+// srpSlot limits the number of simultaneous SRP computations to one,
+// so that simultaneous pairing attempts don't use all CPUs of a slow device
+// and other connections are still served meanwhile.
+var srpSlot = make(chan struct{}, 1)
+
+// computeSRP runs fn in a worker goroutine and waits until fn returns,
+// the ctx is done or SRPTimeout elapsed.
+//
+// When the computation is abandoned, fn keeps running in the background until it returns.
+// fn must therefore store its results only in variables which are not used after an abandoned computation.
+func computeSRP(ctx context.Context, fn func() error) error {
+	ctx, cancel := context.WithTimeout(ctx, SRPTimeout)
+	defer cancel()
+
+	if ctx.Err() != nil {
+		return srpErr(ctx)
+	}
+
+	select {
+	case srpSlot <- struct{}{}:
+	case <-ctx.Done():
+		return srpErr(ctx)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() { <-srpSlot }()
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return srpErr(ctx)
+	}
+}
+
+func srpErr(ctx context.Context) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return ErrSRPTimeout
+	}
+
+	return ctx.Err()
+}
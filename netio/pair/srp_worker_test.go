@@ -0,0 +1,45 @@
+package pair
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestComputeSRP(t *testing.T) {
+	errCompute := errors.New("compute")
+	if is, want := computeSRP(context.Background(), func() error { return errCompute }), errCompute; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestComputeSRPTimeout(t *testing.T) {
+	timeout := SRPTimeout
+	SRPTimeout = 10 * time.Millisecond
+	defer func() { SRPTimeout = timeout }()
+
+	done := make(chan struct{})
+	err := computeSRP(context.Background(), func() error {
+		<-done
+		return nil
+	})
+	if is, want := err, ErrSRPTimeout; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// The next computation waits until the abandoned computation finished
+	close(done)
+	if err := computeSRP(context.Background(), func() error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestComputeSRPCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if is, want := computeSRP(ctx, func() error { return nil }), context.Canceled; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
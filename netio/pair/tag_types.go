@@ -29,9 +29,12 @@ const (
 	// TagSignature is the Ed25519 signature tag. The value is of type 64 bytes.
 	TagSignature = 0x0A
 
+	// TagPermissions is the permissions tag of a pairing. The value is 0x00 for regular users and 0x01 for admins.
+	TagPermissions = 0x0B
+
 	// TagMFiCertificate is the MFi certificate tag (currently not used).
 	TagMFiCertificate = 0x09
 
-	// TagMFiSignature is the MFi signature tag (currently not used).
+	// TagMFiSignature is the MFi signature tag. The value is the software token when pairing with authentication.
 	TagMFiSignature = 0x0A
 )
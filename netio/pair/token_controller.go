@@ -0,0 +1,141 @@
+package pair
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/brutella/hc/util"
+	"github.com/brutella/log"
+)
+
+// TokenProvider provides the software token which is used for MFi software authentication.
+// The token is issued by Apple and refreshed by controllers after pairing.
+type TokenProvider interface {
+	// UUID returns the UUID of the software token.
+	UUID() []byte
+
+	// Token returns the current software token.
+	Token() ([]byte, error)
+
+	// UpdateToken replaces the current token with a token sent by a controller.
+	UpdateToken(token []byte) error
+}
+
+// Opcodes of the secure message requests.
+const (
+	OpcodeTokenRequest byte = 0x10
+	OpcodeTokenUpdate  byte = 0x11
+)
+
+// Tags of the token request and update bodies.
+const (
+	TagTokenUUID = 0x01
+	TagToken     = 0x02
+)
+
+// Status codes of the secure message responses.
+const (
+	StatusSuccess          byte = 0x00
+	StatusUnsupportedPDU   byte = 0x01
+	StatusInsufficientAuth byte = 0x05
+	StatusInvalidRequest   byte = 0x06
+)
+
+const (
+	secureMessageRequestType  byte = 0x00
+	secureMessageResponseType byte = 0x02
+)
+
+// TokenController handles token requests and updates of paired controllers.
+//
+// Requests are encoded as PDU of format
+//
+//	<control (0x00)> <opcode> <transaction id> <instance id (2 bytes)> [<body length (2 bytes)> <tlv8 body>]
+//
+// Responses are encoded as
+//
+//	<control (0x02)> <transaction id> <status> [<body length (2 bytes)> <tlv8 body>]
+type TokenController struct {
+	provider TokenProvider
+}
+
+// NewTokenController returns a token controller.
+func NewTokenController(provider TokenProvider) *TokenController {
+	return &TokenController{provider}
+}
+
+// Handle processes a secure message request and returns the response.
+func (c *TokenController) Handle(request []byte) ([]byte, error) {
+	if len(request) < 5 || request[0] != secureMessageRequestType {
+		return nil, errors.New("Invalid secure message")
+	}
+
+	opcode := request[1]
+	tid := request[2]
+
+	var body []byte
+	if len(request) > 5 {
+		if len(request) < 7 {
+			return nil, errors.New("Invalid secure message body")
+		}
+		n := int(binary.LittleEndian.Uint16(request[5:7]))
+		if len(request) < 7+n {
+			return nil, errors.New("Invalid secure message body length")
+		}
+		body = request[7 : 7+n]
+	}
+
+	if c.provider == nil {
+		return response(tid, StatusUnsupportedPDU, nil), nil
+	}
+
+	switch opcode {
+	case OpcodeTokenRequest:
+		token, err := c.provider.Token()
+		if err != nil {
+			log.Println("[ERRO]", err)
+			return response(tid, StatusInsufficientAuth, nil), nil
+		}
+
+		out := util.NewTLV8Container()
+		out.SetBytes(TagTokenUUID, c.provider.UUID())
+		out.SetBytes(TagToken, token)
+
+		return response(tid, StatusSuccess, out.BytesBuffer().Bytes()), nil
+	case OpcodeTokenUpdate:
+		in, err := util.NewTLV8ContainerFromReader(bytes.NewReader(body))
+		if err != nil {
+			return response(tid, StatusInvalidRequest, nil), nil
+		}
+
+		token := in.GetBytes(TagToken)
+		if len(token) == 0 {
+			return response(tid, StatusInvalidRequest, nil), nil
+		}
+
+		if err := c.provider.UpdateToken(token); err != nil {
+			log.Println("[ERRO]", err)
+			return response(tid, StatusInvalidRequest, nil), nil
+		}
+
+		log.Println("[INFO] Software token updated")
+		return response(tid, StatusSuccess, nil), nil
+	}
+
+	log.Printf("[WARN] Unsupported secure message opcode %X\n", opcode)
+
+	return response(tid, StatusUnsupportedPDU, nil), nil
+}
+
+func response(tid, status byte, body []byte) []byte {
+	b := []byte{secureMessageResponseType, tid, status}
+	if len(body) > 0 {
+		var n [2]byte
+		binary.LittleEndian.PutUint16(n[:], uint16(len(body)))
+		b = append(b, n[:]...)
+		b = append(b, body...)
+	}
+
+	return b
+}
@@ -0,0 +1,88 @@
+package pair
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/brutella/hc/util"
+)
+
+type testTokenProvider struct {
+	token []byte
+}
+
+func (p *testTokenProvider) UUID() []byte {
+	return []byte{0x01, 0x02, 0x03, 0x04}
+}
+
+func (p *testTokenProvider) Token() ([]byte, error) {
+	return p.token, nil
+}
+
+func (p *testTokenProvider) UpdateToken(token []byte) error {
+	p.token = token
+	return nil
+}
+
+func TestTokenRequest(t *testing.T) {
+	c := NewTokenController(&testTokenProvider{[]byte("token")})
+	b, err := c.Handle([]byte{0x00, OpcodeTokenRequest, 0x2A, 0x00, 0x00})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := b[:3], []byte{0x02, 0x2A, StatusSuccess}; bytes.Equal(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	n := binary.LittleEndian.Uint16(b[3:5])
+	body, err := util.NewTLV8ContainerFromReader(bytes.NewReader(b[5 : 5+n]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := body.GetString(TagToken), "token"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := body.GetBytes(TagTokenUUID), []byte{0x01, 0x02, 0x03, 0x04}; bytes.Equal(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestTokenUpdate(t *testing.T) {
+	p := &testTokenProvider{[]byte("token")}
+	c := NewTokenController(p)
+
+	body := util.NewTLV8Container()
+	body.SetString(TagToken, "new token")
+	req := []byte{0x00, OpcodeTokenUpdate, 0x01, 0x00, 0x00}
+	req = append(req, byte(body.BytesBuffer().Len()), 0x00)
+	req = append(req, body.BytesBuffer().Bytes()...)
+
+	b, err := c.Handle(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := b, []byte{0x02, 0x01, StatusSuccess}; bytes.Equal(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := string(p.token), "new token"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestTokenRequestWithoutProvider(t *testing.T) {
+	c := NewTokenController(nil)
+	b, err := c.Handle([]byte{0x00, OpcodeTokenRequest, 0x01, 0x00, 0x00})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := b, []byte{0x02, 0x01, StatusUnsupportedPDU}; bytes.Equal(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
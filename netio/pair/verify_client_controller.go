@@ -102,10 +102,10 @@ func (verify *VerifyClientController) handlePairStepVerifyResponse(in util.Conta
 	fmt.Println("     K:", hex.EncodeToString(verify.session.EncryptionKey[:]))
 
 	// Decrypt
-	data := in.GetBytes(TagEncryptedData)
-	message := data[:(len(data) - 16)]
-	var mac [16]byte
-	copy(mac[:], data[len(message):]) // 16 byte (MAC)
+	message, mac, err := splitMAC(in.GetBytes(TagEncryptedData))
+	if err != nil {
+		return nil, err
+	}
 
 	decryptedBytes, err := chacha20poly1305.DecryptAndVerify(verify.session.EncryptionKey[:], []byte("PV-Msg02"), message, mac, nil)
 	if err != nil {
@@ -110,4 +110,66 @@ func TestPairVerifyIntegration(t *testing.T) {
 	if response != nil {
 		t.Fatal(response)
 	}
+
+	k, err := controller.BroadcastKey(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := len(k.Key), 32; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := k.Controller, client.Name(); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+// Benchmarks the complete pair verify handshake (4 steps) between a client and the server.
+func BenchmarkPairVerify(b *testing.B) {
+	storage, err := util.NewTempFileStorage()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	database := db.NewDatabaseWithStorage(storage)
+	bridge, err := netio.NewSecuredDevice("Macbook Bridge", "001-02-003", database)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	context := netio.NewContextForSecuredDevice(bridge)
+
+	clientDatabase, _ := db.NewTempDatabase()
+	if err := clientDatabase.SaveEntity(db.NewEntity(bridge.Name(), bridge.PublicKey(), nil)); err != nil {
+		b.Fatal(err)
+	}
+
+	client, _ := netio.NewDevice("HomeKit Client", clientDatabase)
+	if err := database.SaveEntity(db.NewEntity(client.Name(), client.PublicKey(), nil)); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		controller := NewVerifyServerController(database, context)
+		clientController := NewVerifyClientController(client, clientDatabase)
+
+		startResponse, err := HandleReaderForHandler(clientController.InitialKeyVerifyRequest(), controller)
+		if err != nil {
+			b.Fatal(err)
+		}
+		finishRequest, err := HandleReaderForHandler(startResponse, clientController)
+		if err != nil {
+			b.Fatal(err)
+		}
+		finishResponse, err := HandleReaderForHandler(finishRequest, controller)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := HandleReaderForHandler(finishResponse, clientController); err != nil {
+			b.Fatal(err)
+		}
+	}
 }
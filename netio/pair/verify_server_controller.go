@@ -3,9 +3,11 @@ package pair
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"github.com/brutella/hc/crypto"
 	"github.com/brutella/hc/crypto/chacha20poly1305"
+	"github.com/brutella/hc/crypto/hkdf"
 	"github.com/brutella/hc/db"
 	"github.com/brutella/hc/netio"
 	"github.com/brutella/hc/util"
@@ -22,6 +24,9 @@ type VerifyServerController struct {
 	context  netio.HAPContext
 	session  *VerifySession
 	step     VerifyStepType
+
+	// controller is the verified controller
+	controller *db.Entity
 }
 
 // NewVerifyServerController returns a new verify server controller.
@@ -36,6 +41,16 @@ func NewVerifyServerController(database db.Database, context netio.HAPContext) *
 	return &controller
 }
 
+// ControllerName returns the name of the verified controller, or an empty string
+// if no controller was verified.
+func (verify *VerifyServerController) ControllerName() string {
+	if verify.controller == nil {
+		return ""
+	}
+
+	return verify.controller.Name
+}
+
 // SharedKey returns the shared key which was negotiated with the client.
 func (verify *VerifyServerController) SharedKey() [32]byte {
 	return verify.session.SharedKey
@@ -145,10 +160,11 @@ func (verify *VerifyServerController) handlePairVerifyStart(in util.Container) (
 func (verify *VerifyServerController) handlePairVerifyFinish(in util.Container) (util.Container, error) {
 	verify.step = VerifyStepFinishResponse
 
-	data := in.GetBytes(TagEncryptedData)
-	message := data[:(len(data) - 16)]
-	var mac [16]byte
-	copy(mac[:], data[len(message):]) // 16 byte (MAC)
+	message, mac, err := splitMAC(in.GetBytes(TagEncryptedData))
+	if err != nil {
+		verify.reset()
+		return nil, err
+	}
 	log.Println("[VERB] ->     Message:", hex.EncodeToString(message))
 	log.Println("[VERB] ->     MAC:", hex.EncodeToString(mac[:]))
 
@@ -192,12 +208,35 @@ func (verify *VerifyServerController) handlePairVerifyFinish(in util.Container)
 			out.SetByte(TagErrCode, ErrCodeUnknownPeer.Byte()) // return error 4
 		} else {
 			log.Println("[VERB] signature is valid")
+			verify.controller = &entity
 		}
 	}
 
 	return out, nil
 }
 
+// BroadcastKey returns a new broadcast key for the global state number gsn.
+// The key is derived from the shared key and the public key of the verified controller.
+func (verify *VerifyServerController) BroadcastKey(gsn uint16) (db.BroadcastKey, error) {
+	if verify.controller == nil {
+		return db.BroadcastKey{}, errors.New("Controller is not verified")
+	}
+
+	key, err := hkdf.Sha512(verify.session.SharedKey[:], verify.controller.PublicKey, []byte("Broadcast-Encryption-Key"))
+	if err != nil {
+		return db.BroadcastKey{}, err
+	}
+
+	k := db.BroadcastKey{
+		Key:        key[:],
+		GSN:        gsn,
+		Controller: verify.controller.Name,
+	}
+
+	return k, nil
+}
+
 func (verify *VerifyServerController) reset() {
 	verify.step = VerifyStepWaiting
+	verify.controller = nil
 }
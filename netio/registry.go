@@ -0,0 +1,220 @@
+package netio
+
+import (
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Subscription references a characteristic for which a connection enabled events.
+type Subscription struct {
+	AID int64
+	IID int64
+}
+
+// ConnectionInfo contains the metadata of a connection.
+type ConnectionInfo struct {
+	// ID identifies the connection. IDs are assigned in ascending order and never reused.
+	ID uint64
+
+	Conn    net.Conn
+	Session Session
+
+	// Connected is the time when the connection was added.
+	Connected time.Time
+
+	// Controller is the name of the paired controller which verified the connection,
+	// or empty if the connection is not verified.
+	Controller string
+
+	// Subscriptions are the characteristics for which the connection enabled events.
+	Subscriptions []Subscription
+}
+
+type connectionEntry struct {
+	info          ConnectionInfo
+	subscriptions map[Subscription]bool
+}
+
+// ConnectionRegistry keeps track of the active connections, their sessions and metadata.
+// The registry is safe for concurrent use; Connections returns a snapshot, which can
+// be iterated while connections are added or removed.
+type ConnectionRegistry struct {
+	mutex   sync.Mutex
+	nextID  uint64
+	entries map[net.Conn]*connectionEntry
+}
+
+// NewConnectionRegistry returns an empty registry.
+func NewConnectionRegistry() *ConnectionRegistry {
+	return &ConnectionRegistry{
+		nextID:  1,
+		entries: map[net.Conn]*connectionEntry{},
+	}
+}
+
+// Add registers the connection c with its session s and returns the id of the connection.
+// If c is already registered, its session is replaced and the id stays the same.
+func (r *ConnectionRegistry) Add(c net.Conn, s Session) uint64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if e, ok := r.entries[c]; ok == true {
+		e.info.Session = s
+		return e.info.ID
+	}
+
+	e := &connectionEntry{
+		info: ConnectionInfo{
+			ID:        r.nextID,
+			Conn:      c,
+			Session:   s,
+			Connected: time.Now(),
+		},
+		subscriptions: map[Subscription]bool{},
+	}
+	r.nextID++
+	r.entries[c] = e
+
+	return e.info.ID
+}
+
+// Remove unregisters the connection c and returns its session.
+func (r *ConnectionRegistry) Remove(c net.Conn) (Session, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	e, ok := r.entries[c]
+	if ok == false {
+		return nil, false
+	}
+	delete(r.entries, c)
+
+	return e.info.Session, true
+}
+
+// Session returns the session of the connection c, or nil if c is not registered.
+func (r *ConnectionRegistry) Session(c net.Conn) Session {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if e, ok := r.entries[c]; ok == true {
+		return e.info.Session
+	}
+
+	return nil
+}
+
+// Info returns the metadata of the connection c.
+func (r *ConnectionRegistry) Info(c net.Conn) (ConnectionInfo, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if e, ok := r.entries[c]; ok == true {
+		return e.snapshot(), true
+	}
+
+	return ConnectionInfo{}, false
+}
+
+// SetController sets the name of the controller which verified the connection c.
+func (r *ConnectionRegistry) SetController(c net.Conn, name string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if e, ok := r.entries[c]; ok == true {
+		e.info.Controller = name
+	}
+}
+
+// SetSubscribed records whether the connection c enabled events for the characteristic
+// with the accessory id aid and instance id iid.
+func (r *ConnectionRegistry) SetSubscribed(c net.Conn, aid, iid int64, enabled bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	e, ok := r.entries[c]
+	if ok == false {
+		return
+	}
+
+	if enabled == true {
+		e.subscriptions[Subscription{aid, iid}] = true
+	} else {
+		delete(e.subscriptions, Subscription{aid, iid})
+	}
+}
+
+// IsSubscribed returns true when the connection c enabled events for the characteristic
+// with the accessory id aid and instance id iid.
+func (r *ConnectionRegistry) IsSubscribed(c net.Conn, aid, iid int64) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if e, ok := r.entries[c]; ok == true {
+		return e.subscriptions[Subscription{aid, iid}]
+	}
+
+	return false
+}
+
+// Connections returns a snapshot of the registered connections ordered by id.
+func (r *ConnectionRegistry) Connections() []ConnectionInfo {
+	r.mutex.Lock()
+	infos := make([]ConnectionInfo, 0, len(r.entries))
+	for _, e := range r.entries {
+		infos = append(infos, e.snapshot())
+	}
+	r.mutex.Unlock()
+
+	sort.Stable(byConnectionID(infos))
+
+	return infos
+}
+
+// Len returns the number of registered connections.
+func (r *ConnectionRegistry) Len() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return len(r.entries)
+}
+
+// CloseAll closes all registered connections.
+// Closing a connection removes it from the registry (see HAPConnection.Close).
+func (r *ConnectionRegistry) CloseAll() {
+	for _, info := range r.Connections() {
+		info.Conn.Close()
+	}
+}
+
+// snapshot returns a copy of the metadata of e.
+func (e *connectionEntry) snapshot() ConnectionInfo {
+	info := e.info
+	info.Subscriptions = make([]Subscription, 0, len(e.subscriptions))
+	for s := range e.subscriptions {
+		info.Subscriptions = append(info.Subscriptions, s)
+	}
+	sort.Stable(bySubscription(info.Subscriptions))
+
+	return info
+}
+
+type byConnectionID []ConnectionInfo
+
+func (b byConnectionID) Len() int           { return len(b) }
+func (b byConnectionID) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byConnectionID) Less(i, j int) bool { return b[i].ID < b[j].ID }
+
+type bySubscription []Subscription
+
+func (b bySubscription) Len() int      { return len(b) }
+func (b bySubscription) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b bySubscription) Less(i, j int) bool {
+	if b[i].AID != b[j].AID {
+		return b[i].AID < b[j].AID
+	}
+
+	return b[i].IID < b[j].IID
+}
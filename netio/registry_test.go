@@ -0,0 +1,94 @@
+package netio
+
+import (
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestConnectionRegistry(t *testing.T) {
+	r := NewConnectionRegistry()
+	c1, _ := net.Pipe()
+	c2, _ := net.Pipe()
+
+	id1 := r.Add(c1, NewSession(c1))
+	id2 := r.Add(c2, NewSession(c2))
+	if id1 >= id2 {
+		t.Fatal(id1, id2)
+	}
+
+	if is, want := r.Add(c1, NewSession(c1)), id1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	r.SetController(c1, "Controller")
+	r.SetSubscribed(c1, 1, 10, true)
+	r.SetSubscribed(c1, 1, 9, true)
+	r.SetSubscribed(c1, 1, 11, true)
+	r.SetSubscribed(c1, 1, 11, false)
+
+	info, ok := r.Info(c1)
+	if ok == false {
+		t.Fatal("connection not found")
+	}
+
+	if is, want := info.Controller, "Controller"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := info.Subscriptions, []Subscription{{1, 9}, {1, 10}}; reflect.DeepEqual(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	conns := r.Connections()
+	if is, want := len(conns), 2; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := conns[0].ID, id1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if _, ok := r.Remove(c1); ok == false {
+		t.Fatal("connection not removed")
+	}
+
+	if is, want := r.Len(), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestConnectionRegistryCloseAll(t *testing.T) {
+	ctx := NewContextForSecuredDevice(nil)
+
+	var remotes []net.Conn
+	for i := 0; i < 10; i++ {
+		local, remote := net.Pipe()
+		remotes = append(remotes, remote)
+		NewHAPConnection(local, ctx)
+	}
+
+	// Iterating while connections are closed concurrently is safe
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for _, info := range ctx.Connections().Connections() {
+			info.Conn.LocalAddr()
+		}
+	}()
+
+	ctx.Connections().CloseAll()
+	wg.Wait()
+
+	if is, want := len(ctx.ActiveConnections()), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	for _, c := range remotes {
+		if _, err := c.Read(make([]byte, 1)); err == nil {
+			t.Fatal("expected closed connection")
+		}
+	}
+}
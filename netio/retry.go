@@ -0,0 +1,74 @@
+package netio
+
+import (
+	"io"
+	"net"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy specifies how often a write, which failed with a temporary error
+// (e.g. EAGAIN when the send buffer is full), is retried before the connection is closed.
+type RetryPolicy struct {
+	// Attempts is the number of retries. When 0, writes are not retried.
+	Attempts int
+
+	// Backoff is the delay before the first retry, which is doubled for every retry.
+	Backoff time.Duration
+
+	// MaxBackoff is the maximum delay between retries. When 0, the delay is not limited.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is the retry policy of new connections.
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts:   3,
+	Backoff:    10 * time.Millisecond,
+	MaxBackoff: 250 * time.Millisecond,
+}
+
+// write writes b to w and retries the remaining bytes when the write failed with a temporary error.
+// The bytes are never written twice, which keeps the stream of encrypted frames intact.
+func (p RetryPolicy) write(w io.Writer, b []byte) (int, error) {
+	backoff := p.Backoff
+	written := 0
+	for attempt := 0; ; attempt++ {
+		n, err := w.Write(b[written:])
+		written += n
+		if err == nil || isTemporary(err) == false || attempt >= p.Attempts {
+			return written, err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+	}
+}
+
+// isTemporary returns true when err is a temporary error, after which a write can be retried.
+// An exceeded deadline is not temporary.
+func isTemporary(err error) bool {
+	switch err := err.(type) {
+	case syscall.Errno:
+		return err == syscall.EAGAIN || err == syscall.EWOULDBLOCK || err == syscall.ENOBUFS || err == syscall.EINTR
+	case *net.OpError:
+		return isTemporary(err.Err)
+	case interface{ Unwrap() error }:
+		if inner := err.Unwrap(); inner != nil {
+			return isTemporary(inner)
+		}
+	}
+
+	return false
+}
+
+// isTimeout returns true when err is caused by an exceeded deadline.
+func isTimeout(err error) bool {
+	if ne, ok := err.(net.Error); ok == true {
+		return ne.Timeout()
+	}
+
+	return false
+}
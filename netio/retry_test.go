@@ -0,0 +1,104 @@
+package netio
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// flakyConn writes at most one byte per write and fails with EAGAIN the first failures times.
+type flakyConn struct {
+	net.Conn
+	buffer   bytes.Buffer
+	failures int
+	err      error
+	closed   bool
+}
+
+func (c *flakyConn) Write(b []byte) (int, error) {
+	if c.failures > 0 {
+		c.failures--
+		return 0, &net.OpError{Op: "write", Net: "tcp", Err: c.err}
+	}
+
+	c.buffer.Write(b[:1])
+	if len(b) > 1 {
+		c.failures = 1
+		c.err = syscall.EAGAIN
+		return 1, &net.OpError{Op: "write", Net: "tcp", Err: syscall.EAGAIN}
+	}
+
+	return 1, nil
+}
+
+func (c *flakyConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func (c *flakyConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{}
+}
+
+func TestRetryPolicyWrite(t *testing.T) {
+	conn := &flakyConn{failures: 1, err: syscall.EAGAIN}
+	p := RetryPolicy{Attempts: 10, Backoff: time.Millisecond}
+
+	n, err := p.write(conn, []byte("abc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := n, 3; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := conn.buffer.String(), "abc"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestRetryPolicyPermanentError(t *testing.T) {
+	conn := &flakyConn{failures: 1, err: syscall.ECONNRESET}
+	p := RetryPolicy{Attempts: 10, Backoff: time.Millisecond}
+
+	if _, err := p.write(conn, []byte("abc")); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if is, want := conn.buffer.Len(), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestIsTemporary(t *testing.T) {
+	if is, want := isTemporary(&net.OpError{Err: syscall.EAGAIN}), true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := isTemporary(errors.New("closed")), false; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestConnectionClosedAfterRetries(t *testing.T) {
+	ctx := NewContextForSecuredDevice(nil)
+	flaky := &flakyConn{failures: 10, err: syscall.EAGAIN}
+	conn := NewHAPConnection(flaky, ctx)
+	conn.Retry = RetryPolicy{Attempts: 2, Backoff: time.Millisecond}
+
+	if _, err := conn.Write([]byte("event")); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if is, want := flaky.closed, true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := ctx.Connections().Len(), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
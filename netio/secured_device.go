@@ -26,3 +26,33 @@ func NewSecuredDevice(name string, pin string, database db.Database) (SecuredDev
 func (d *securedDevice) Pin() string {
 	return d.pin
 }
+
+// SetupVerifierDevice is a secured device which provides a precomputed salt
+// and SRP verifier for pair setup. The pin of the device is not used.
+type SetupVerifierDevice interface {
+	SecuredDevice
+	SetupVerifier() ([]byte /*salt*/, []byte /*verifier*/)
+}
+
+type setupVerifierDevice struct {
+	Device
+	salt     []byte
+	verifier []byte
+}
+
+// NewSetupVerifierDevice returns a device for a specific name either loaded from the database or newly created.
+// Other devices can only pair by providing the pin from which the salt and verifier were computed.
+func NewSetupVerifierDevice(name string, salt, verifier []byte, database db.Database) (SetupVerifierDevice, error) {
+	d, err := NewDevice(name, database)
+	return &setupVerifierDevice{d, salt, verifier}, err
+}
+
+// Pin returns an empty string because the pin is not known.
+func (d *setupVerifierDevice) Pin() string {
+	return ""
+}
+
+// SetupVerifier returns the salt and verifier.
+func (d *setupVerifierDevice) SetupVerifier() ([]byte, []byte) {
+	return d.salt, d.verifier
+}
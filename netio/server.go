@@ -27,7 +27,7 @@ import (
 //        context (provides variables) <---------
 //
 func ListenAndServe(addr string, handler http.Handler, context HAPContext) error {
-	server := http.Server{Addr: addr, Handler: handler}
+	server := http.Server{Addr: addr, Handler: handler, ConnContext: WithConnection}
 	ln, err := net.Listen("tcp", server.Addr)
 	if err != nil {
 		return err
@@ -37,3 +37,11 @@ func ListenAndServe(addr string, handler http.Handler, context HAPContext) error
 
 	return server.Serve(listener)
 }
+
+// Serve handles HAP requests of connections accepted by l with handler (see server.NewRouter).
+// The connections can be of any carrier (e.g. a serial tunnel, websockets or a net.Pipe in tests)
+// and are secured by the HAP security layer like TCP connections.
+func Serve(l net.Listener, handler http.Handler, context HAPContext) error {
+	server := http.Server{Handler: handler, ConnContext: WithConnection}
+	return server.Serve(NewHAPListener(l, context))
+}
@@ -3,6 +3,7 @@ package netio
 import (
 	"github.com/brutella/hc/crypto"
 	"net"
+	"sync"
 )
 
 // Session contains objects (encrypter, decrypter, pairing handler,...) used to handle the data communication.
@@ -13,9 +14,15 @@ type Session interface {
 	// Encrypter returns encrypter for outgoing data, may be nil
 	Encrypter() crypto.Encrypter
 
-	// SetCryptographer sets the new cryptographer used for en-/decryption
+	// SetCryptographer sets the new cryptographer used for en-/decryption.
+	// The cryptographer is used when the next data is received, or after ActivateCryptographer is called.
 	SetCryptographer(c crypto.Cryptographer)
 
+	// ActivateCryptographer uses the cryptographer set by SetCryptographer immediately.
+	// This is called once the response which negotiated the new keys was sent,
+	// so that events are encrypted with the new keys.
+	ActivateCryptographer()
+
 	// PairSetupHandler returns the pairing setup handler
 	PairSetupHandler() ContainerHandler
 
@@ -30,9 +37,14 @@ type Session interface {
 
 	// Connection returns the associated connection
 	Connection() net.Conn
+
+	// Close releases the keys and pairing handlers of the session.
+	// This is called when the connection of the session is closed.
+	Close()
 }
 
 type session struct {
+	mutex             sync.Mutex
 	cryptographer     crypto.Cryptographer
 	pairStartHandler  ContainerHandler
 	pairVerifyHandler PairVerifyHandler
@@ -56,17 +68,20 @@ func (s *session) Connection() net.Conn {
 }
 
 func (s *session) Decrypter() crypto.Decrypter {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	// Return the next cryptographer when possible
 	// This allows sessions to switch encryption
-	if s.nextCryptographer != nil {
-		s.cryptographer = s.nextCryptographer
-		s.nextCryptographer = nil
-	}
+	s.activate()
 
 	return s.cryptographer
 }
 
 func (s *session) Encrypter() crypto.Encrypter {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	return s.cryptographer
 }
 
@@ -79,11 +94,29 @@ func (s *session) PairVerifyHandler() PairVerifyHandler {
 }
 
 func (s *session) SetCryptographer(c crypto.Cryptographer) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	// Temporarily set the cryptographer as the nextCryptographer
 	// The nextCryptographer is used the next time Decrypter() is called.
 	// Otherwise the Encrypter() encrypts differently than the previous Decrypter()
 	s.nextCryptographer = c
 }
+
+func (s *session) ActivateCryptographer() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.activate()
+}
+
+func (s *session) activate() {
+	if s.nextCryptographer != nil {
+		s.cryptographer = s.nextCryptographer
+		s.nextCryptographer = nil
+	}
+}
+
 func (s *session) SetPairSetupHandler(c ContainerHandler) {
 	s.pairStartHandler = c
 }
@@ -91,3 +124,13 @@ func (s *session) SetPairSetupHandler(c ContainerHandler) {
 func (s *session) SetPairVerifyHandler(c PairVerifyHandler) {
 	s.pairVerifyHandler = c
 }
+
+func (s *session) Close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.cryptographer = nil
+	s.nextCryptographer = nil
+	s.pairStartHandler = nil
+	s.pairVerifyHandler = nil
+}
@@ -0,0 +1,6 @@
+// Package openapi generates an OpenAPI 3.0 document which describes the HAP endpoints
+// of the accessories in a container, e.g. to generate clients for companion apps and test tooling.
+//
+// The schemas are specific to the container: every characteristic has a schema with its
+// accessory id, instance id, type and value constraints.
+package openapi
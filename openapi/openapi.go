@@ -0,0 +1,253 @@
+package openapi
+
+import (
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
+
+	"encoding/json"
+	"fmt"
+)
+
+// Version is the OpenAPI version of the generated documents.
+const Version = "3.0.3"
+
+// object is a JSON object of the document
+type object map[string]interface{}
+
+// Generate returns the OpenAPI document for the accessories in c as JSON.
+func Generate(c *accessory.Container, title string) ([]byte, error) {
+	return json.MarshalIndent(Document(c, title), "", "  ")
+}
+
+// Document returns the OpenAPI document for the accessories in c.
+func Document(c *accessory.Container, title string) map[string]interface{} {
+	schemas := object{
+		"Status": object{
+			"type":        "integer",
+			"description": "HAP status code, e.g. -70402 for a communication failure",
+		},
+	}
+
+	var accessories, values, writes []interface{}
+	for _, a := range c.Accessories {
+		var services []interface{}
+		for _, s := range a.Services {
+			name := fmt.Sprintf("Service_%d_%d", a.GetID(), s.GetID())
+			schemas[name] = serviceSchema(a, s, schemas)
+			services = append(services, ref(name))
+		}
+
+		name := fmt.Sprintf("Accessory_%d", a.GetID())
+		schemas[name] = object{
+			"type":     "object",
+			"required": []string{"aid", "services"},
+			"properties": object{
+				"aid":      constant(a.GetID()),
+				"services": object{"type": "array", "items": object{"oneOf": services}},
+			},
+		}
+		accessories = append(accessories, ref(name))
+
+		a.ForEachCharacteristic(func(s *service.Service, ch *characteristic.Characteristic) {
+			values = append(values, valueSchema(a, ch, false))
+			if ch.IsWritable() == true {
+				writes = append(writes, valueSchema(a, ch, true))
+			}
+		})
+	}
+
+	schemas["Accessories"] = object{
+		"type":     "object",
+		"required": []string{"accessories"},
+		"properties": object{
+			"accessories": object{"type": "array", "items": object{"oneOf": accessories}},
+		},
+	}
+
+	schemas["CharacteristicValues"] = object{
+		"type": "object",
+		"properties": object{
+			"characteristics": object{"type": "array", "items": object{"oneOf": values}},
+		},
+	}
+
+	schemas["CharacteristicWrites"] = object{
+		"type":     "object",
+		"required": []string{"characteristics"},
+		"properties": object{
+			"characteristics": object{"type": "array", "items": object{"oneOf": writes}},
+		},
+	}
+
+	return object{
+		"openapi": Version,
+		"info":    object{"title": title, "version": "1"},
+		"paths":   paths(),
+		"components": object{
+			"schemas": schemas,
+		},
+	}
+}
+
+// paths returns the HAP endpoints.
+func paths() object {
+	return object{
+		"/accessories": object{
+			"get": object{
+				"summary":   "Returns the accessories",
+				"responses": object{"200": response("Accessories", "The accessories")},
+			},
+		},
+		"/characteristics": object{
+			"get": object{
+				"summary": "Returns the values of characteristics",
+				"parameters": []interface{}{
+					object{
+						"name":        "id",
+						"in":          "query",
+						"required":    true,
+						"description": "Comma separated list of aid.iid, e.g. 1.9,2.9",
+						"schema":      object{"type": "string"},
+					},
+				},
+				"responses": object{
+					"200": response("CharacteristicValues", "The values"),
+					"207": response("CharacteristicValues", "The values and the status of characteristics which failed"),
+				},
+			},
+			"put": object{
+				"summary": "Writes the values of characteristics and enables events",
+				"requestBody": object{
+					"required": true,
+					"content":  object{"application/hap+json": object{"schema": ref("CharacteristicWrites")}},
+				},
+				"responses": object{
+					"204": object{"description": "All values were written"},
+					"207": response("CharacteristicValues", "The status of every characteristic, when a write failed"),
+				},
+			},
+		},
+	}
+}
+
+// serviceSchema returns the schema of the service s of the accessory a, and adds the
+// schemas of its characteristics to schemas.
+func serviceSchema(a *accessory.Accessory, s *service.Service, schemas object) object {
+	var chars []interface{}
+	for _, ch := range s.Characteristics {
+		name := fmt.Sprintf("Characteristic_%d_%d", a.GetID(), ch.GetID())
+		schemas[name] = characteristicSchema(ch)
+		chars = append(chars, ref(name))
+	}
+
+	schema := object{
+		"type":     "object",
+		"required": []string{"iid", "type", "characteristics"},
+		"properties": object{
+			"iid":             constant(s.GetID()),
+			"type":            constant(s.Type),
+			"primary":         object{"type": "boolean"},
+			"hidden":          object{"type": "boolean"},
+			"linked":          object{"type": "array", "items": object{"type": "integer"}},
+			"characteristics": object{"type": "array", "items": object{"oneOf": chars}},
+		},
+	}
+
+	if spec, ok := service.SpecForType(s.Type); ok == true {
+		schema["title"] = spec.Name
+	}
+
+	return schema
+}
+
+// characteristicSchema returns the schema of the characteristic ch in the accessories payload.
+func characteristicSchema(ch *characteristic.Characteristic) object {
+	return object{
+		"type":     "object",
+		"required": []string{"iid", "type", "perms", "format"},
+		"properties": object{
+			"iid":    constant(ch.GetID()),
+			"type":   constant(ch.Type),
+			"perms":  object{"type": "array", "items": object{"type": "string", "enum": ch.Perms}},
+			"format": constant(ch.Format),
+			"value":  valueType(ch),
+		},
+	}
+}
+
+// valueSchema returns the schema of the value of ch in a read response or a write request.
+func valueSchema(a *accessory.Accessory, ch *characteristic.Characteristic, write bool) object {
+	properties := object{
+		"aid":   constant(a.GetID()),
+		"iid":   constant(ch.GetID()),
+		"value": valueType(ch),
+	}
+
+	required := []string{"aid", "iid"}
+	if write == true {
+		properties["ev"] = object{"type": "boolean"}
+		properties["r"] = object{"type": "boolean"}
+	} else {
+		properties["status"] = ref("Status")
+	}
+
+	return object{"type": "object", "required": required, "properties": properties}
+}
+
+// valueType returns the schema of the value of ch.
+func valueType(ch *characteristic.Characteristic) object {
+	var schema object
+	switch ch.Format {
+	case characteristic.FormatBool:
+		schema = object{"type": "boolean"}
+	case characteristic.FormatFloat:
+		schema = object{"type": "number"}
+	case characteristic.FormatUInt8, characteristic.FormatUInt16, characteristic.FormatUInt32, characteristic.FormatUInt64, characteristic.FormatInt32, characteristic.FormatInt64:
+		schema = object{"type": "integer"}
+	case characteristic.FormatTLV8, characteristic.FormatData:
+		schema = object{"type": "string", "format": "byte"}
+	default:
+		schema = object{"type": "string"}
+	}
+
+	if ch.MinValue != nil {
+		schema["minimum"] = ch.MinValue
+	}
+
+	if ch.MaxValue != nil {
+		schema["maximum"] = ch.MaxValue
+	}
+
+	if ch.StepValue != nil && schema["type"] != "number" {
+		schema["multipleOf"] = ch.StepValue
+	}
+
+	if ch.MaxLen > 0 {
+		schema["maxLength"] = ch.MaxLen
+	}
+
+	if len(ch.Unit) > 0 {
+		schema["description"] = "Unit: " + ch.Unit
+	}
+
+	return schema
+}
+
+// constant returns the schema of a constant value.
+func constant(v interface{}) object {
+	return object{"enum": []interface{}{v}}
+}
+
+// ref returns a reference to the schema with name.
+func ref(name string) object {
+	return object{"$ref": "#/components/schemas/" + name}
+}
+
+// response returns a response with a JSON body of the schema with name.
+func response(name, description string) object {
+	return object{
+		"description": description,
+		"content":     object{"application/hap+json": object{"schema": ref(name)}},
+	}
+}
@@ -0,0 +1,68 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/brutella/hc/accessory"
+)
+
+func TestGenerate(t *testing.T) {
+	c := accessory.NewContainer()
+	c.AddAccessory(accessory.NewLightbulb(accessory.Info{Name: "Lamp"}).Accessory)
+
+	b, err := Generate(c, "Lamp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		OpenAPI    string                 `json:"openapi"`
+		Paths      map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := doc.OpenAPI, Version; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	for _, path := range []string{"/accessories", "/characteristics"} {
+		if _, ok := doc.Paths[path]; ok == false {
+			t.Fatalf("missing path %s", path)
+		}
+	}
+
+	for _, name := range []string{"Accessories", "Accessory_1", "CharacteristicWrites"} {
+		if _, ok := doc.Components.Schemas[name]; ok == false {
+			t.Fatalf("missing schema %s", name)
+		}
+	}
+}
+
+func TestValueType(t *testing.T) {
+	a := accessory.NewLightbulb(accessory.Info{Name: "Lamp"})
+	brightness := a.Lightbulb.Brightness.Characteristic
+
+	schema := valueType(brightness)
+	if is, want := schema["type"], "integer"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := schema["minimum"], brightness.MinValue; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := schema["maximum"], brightness.MaxValue; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := valueType(a.Lightbulb.On.Characteristic)["type"], "boolean"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,70 @@
+package command
+
+import (
+	"github.com/brutella/hc/plugin"
+
+	"encoding/json"
+	"io/ioutil"
+)
+
+// Config is the configuration of the command devices.
+//
+//	{
+//	  "devices": [{
+//	    "name": "Fan",
+//	    "serial_number": "fan",
+//	    "kind": "switch",
+//	    "fields": {
+//	      "on": {"read": "fanctl status", "write": "fanctl set $VALUE", "timeout": "2s", "cache": "10s"}
+//	    }
+//	  }]
+//	}
+type Config struct {
+	Devices []Device `json:"devices"`
+}
+
+// Device is a device whose characteristics are bound to commands.
+type Device struct {
+	Name         string `json:"name"`
+	SerialNumber string `json:"serial_number"`
+
+	// Kind is the kind of accessory, e.g. plugin.KindSwitch.
+	Kind string `json:"kind"`
+
+	// Fields maps the characteristics of the accessory to commands.
+	// The keys are the characteristic names of the kind (see plugin.Characteristics).
+	Fields map[string]Binding `json:"fields"`
+}
+
+// Binding binds a characteristic to commands, which are executed with "sh -c".
+type Binding struct {
+	// Read is the command whose output is the value of the characteristic.
+	// The output is parsed as JSON value (e.g. true or 21.5), or used as string.
+	// When empty, the current value is returned.
+	Read string `json:"read"`
+
+	// Write is the command which is executed when a client writes the characteristic.
+	// The value is passed in the environment variable VALUE, e.g. "true" or "50".
+	// When the command fails, the write is rejected.
+	Write string `json:"write"`
+
+	// Timeout is the deadline of the commands. When zero, commands time out after 10 seconds.
+	Timeout plugin.Duration `json:"timeout"`
+
+	// Cache is the duration for which the output of the read command is reused.
+	// When zero, the command is executed for every read.
+	Cache plugin.Duration `json:"cache"`
+}
+
+// ConfigFromFile returns the config which is loaded from a JSON file.
+func ConfigFromFile(path string) (Config, error) {
+	var c Config
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+
+	err = json.Unmarshal(b, &c)
+	return c, err
+}
@@ -0,0 +1,4 @@
+// Package command provides accessories whose characteristics are bound to shell commands.
+// A write executes a command with the new value, and a read parses the output of a command,
+// as configured in a Config.
+package command
@@ -0,0 +1,146 @@
+package command
+
+import (
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/netio"
+	"github.com/brutella/hc/plugin"
+
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultTimeout is the deadline of commands of bindings without a timeout
+const defaultTimeout = 10 * time.Second
+
+// Provider provides the accessories of command devices and implements plugin.AccessoryProvider.
+type Provider struct {
+	accessories []*accessory.Accessory
+}
+
+// New returns a provider for the devices in config.
+func New(config Config) (*Provider, error) {
+	p := &Provider{}
+	for _, d := range config.Devices {
+		a, chars, err := plugin.NewAccessory(d.Kind, accessory.Info{Name: d.Name, SerialNumber: d.SerialNumber})
+		if err != nil {
+			return nil, err
+		}
+
+		for name, b := range d.Fields {
+			c, ok := chars[name]
+			if ok == false {
+				return nil, fmt.Errorf("%s has no characteristic %s", d.Name, name)
+			}
+
+			bind(c, b)
+		}
+
+		p.accessories = append(p.accessories, a)
+	}
+
+	return p, nil
+}
+
+// Accessories returns the accessories of the devices.
+func (p *Provider) Accessories() []*accessory.Accessory {
+	return p.accessories
+}
+
+// Start returns when ctx is done. The commands are executed on reads and writes of clients.
+func (p *Provider) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// bind binds the characteristic c to the commands of b.
+func bind(c *characteristic.Characteristic, b Binding) {
+	timeout := time.Duration(b.Timeout)
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	if len(b.Write) > 0 {
+		c.OnBeforeRemoteUpdate(func(new interface{}) error {
+			if _, err := run(b.Write, timeout, "VALUE="+fmt.Sprint(new)); err != nil {
+				return characteristic.NewStatusError(netio.StatusServiceCommunicationFailure, err.Error())
+			}
+			return nil
+		})
+	}
+
+	if len(b.Read) > 0 {
+		r := &reader{command: b.Read, timeout: timeout, cache: time.Duration(b.Cache), c: c}
+		c.OnValueRead(r.read)
+	}
+}
+
+// reader reads the value of a characteristic with a command and caches the value.
+type reader struct {
+	command string
+	timeout time.Duration
+	cache   time.Duration
+	c       *characteristic.Characteristic
+
+	mutex sync.Mutex
+	value interface{}
+	time  time.Time
+}
+
+func (r *reader) read() (interface{}, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.value != nil && time.Since(r.time) < r.cache {
+		return r.value, nil
+	}
+
+	out, err := run(r.command, r.timeout)
+	if err != nil {
+		return nil, characteristic.NewStatusError(netio.StatusServiceCommunicationFailure, err.Error())
+	}
+
+	r.value, r.time = plugin.Convert(parse(out), r.c), time.Now()
+
+	return r.value, nil
+}
+
+// parse returns the JSON value of out, or out as string.
+func parse(out string) interface{} {
+	out = strings.TrimSpace(out)
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(out), &v); err == nil {
+		return v
+	}
+
+	return out
+}
+
+// run executes command with "sh -c" and the environment variables env, and returns the output.
+func run(command string, timeout time.Duration, env ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("%s timed out after %v", command, timeout)
+		}
+		return "", fmt.Errorf("%s failed: %v %s", command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
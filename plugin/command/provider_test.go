@@ -0,0 +1,92 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/plugin"
+)
+
+func TestBinding(t *testing.T) {
+	dir, err := ioutil.TempDir("", "command")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	state := filepath.Join(dir, "state")
+	if err := ioutil.WriteFile(state, []byte("false\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := characteristic.NewOn().Characteristic
+	bind(c, Binding{
+		Read:  "cat " + state,
+		Write: "echo $VALUE > " + state,
+		Cache: plugin.Duration(time.Hour),
+	})
+
+	if err := c.UpdateValueFromConnection(true, characteristic.TestConn); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := string(b), "true\n"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	v, err := c.ReadValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := v, true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// The cached value is returned
+	ioutil.WriteFile(state, []byte("false\n"), 0644)
+	if v, _ := c.ReadValue(); v != true {
+		t.Fatalf("is=%v want=%v", v, true)
+	}
+}
+
+func TestFailingWrite(t *testing.T) {
+	c := characteristic.NewOn().Characteristic
+	bind(c, Binding{Write: "exit 1"})
+
+	if err := c.UpdateValueFromConnection(true, characteristic.TestConn); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if is, want := c.Value, false; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	c := characteristic.NewCurrentTemperature().Characteristic
+	bind(c, Binding{Read: "exec sleep 1", Timeout: plugin.Duration(10 * time.Millisecond)})
+
+	if _, err := c.ReadValue(); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestParse(t *testing.T) {
+	if is, want := parse("21.5\n"), 21.5; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := parse("idle\n"), "idle"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,4 @@
+// Package plugin assembles bridges from independent accessory providers, e.g. for Zigbee,
+// Z-Wave or HTTP devices. Every provider gets stable accessory ids, which don't collide
+// with the accessory ids of other providers.
+package plugin
@@ -0,0 +1,29 @@
+package plugin
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Duration is a duration which is encoded as string in JSON, e.g. "1m30s",
+// in declarative configs.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+	return nil
+}
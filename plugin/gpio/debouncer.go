@@ -0,0 +1,36 @@
+package gpio
+
+import (
+	"time"
+)
+
+// debouncer reports a value after it was stable for a duration.
+type debouncer struct {
+	duration time.Duration
+
+	value   bool
+	pending bool
+	since   time.Time
+	init    bool
+}
+
+// sample adds the value v sampled at now and returns the debounced value and
+// whether it changed. The first sample is reported immediately.
+func (d *debouncer) sample(v bool, now time.Time) (bool, bool) {
+	if d.init == false {
+		d.init = true
+		d.value, d.pending, d.since = v, v, now
+		return v, true
+	}
+
+	if v != d.pending {
+		d.pending, d.since = v, now
+	}
+
+	if d.pending != d.value && now.Sub(d.since) >= d.duration {
+		d.value = d.pending
+		return d.value, true
+	}
+
+	return d.value, false
+}
@@ -0,0 +1,7 @@
+// Package gpio provides switch accessories for Linux GPIO lines, which are accessed
+// with the sysfs interface (/sys/class/gpio), e.g. on a Raspberry Pi.
+//
+// An input line (e.g. a button or a reed switch) sets the state of the switch after
+// it was stable for the debounce duration. An output line (e.g. a relay) is set when
+// a client turns the switch on or off.
+package gpio
@@ -0,0 +1,197 @@
+package gpio
+
+import (
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/netio"
+	"github.com/brutella/log"
+
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultRoot is the path of the GPIO sysfs interface.
+const DefaultRoot = "/sys/class/gpio"
+
+// defaultPollInterval is the interval in which input lines are read
+const defaultPollInterval = 50 * time.Millisecond
+
+// Line is a GPIO line.
+type Line struct {
+	Name         string
+	SerialNumber string
+
+	// Pin is the GPIO number of the line, e.g. 17 for GPIO17.
+	Pin int
+
+	// Output is true when the line is set by the switch, e.g. for a relay.
+	// Otherwise the switch reflects the state of the input line.
+	Output bool
+
+	// ActiveLow is true when the switch is on at low level.
+	ActiveLow bool
+
+	// Debounce is the duration for which an input must be stable before the switch changes.
+	Debounce time.Duration
+}
+
+// line is a configured line and its accessory.
+type line struct {
+	config    Line
+	accessory *accessory.Switch
+	debouncer debouncer
+}
+
+// Provider provides the switch accessories of GPIO lines and implements plugin.AccessoryProvider.
+type Provider struct {
+	// Root is the path of the GPIO sysfs interface. When empty, DefaultRoot is used.
+	Root string
+
+	// PollInterval is the interval in which input lines are read. When zero, the lines are read every 50ms.
+	PollInterval time.Duration
+
+	lines []*line
+}
+
+// New returns a provider for lines.
+func New(lines []Line) *Provider {
+	p := &Provider{}
+	for _, l := range lines {
+		acc := accessory.NewSwitch(accessory.Info{Name: l.Name, SerialNumber: l.SerialNumber})
+		ln := &line{config: l, accessory: acc, debouncer: debouncer{duration: l.Debounce}}
+		if l.Output == true {
+			acc.Switch.On.OnValueRemoteUpdate(func(on bool) {
+				if err := p.write(ln, on); err != nil {
+					log.Printf("[WARN] Setting GPIO%d failed: %v\n", ln.config.Pin, err)
+				}
+			})
+		} else {
+			acc.Switch.On.OnBeforeRemoteUpdate(func(interface{}) error {
+				return characteristic.NewStatusError(netio.StatusReadOnlyCharacteristic, "input line")
+			})
+		}
+		p.lines = append(p.lines, ln)
+	}
+
+	return p
+}
+
+// Accessories returns the switch accessories of the lines.
+func (p *Provider) Accessories() []*accessory.Accessory {
+	var as []*accessory.Accessory
+	for _, l := range p.lines {
+		as = append(as, l.accessory.Accessory)
+	}
+
+	return as
+}
+
+// Start exports the lines and reads the input lines until ctx is done.
+func (p *Provider) Start(ctx context.Context) error {
+	for _, l := range p.lines {
+		if err := p.export(l); err != nil {
+			return err
+		}
+	}
+
+	interval := p.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, l := range p.lines {
+			if l.config.Output == true {
+				continue
+			}
+
+			if err := p.read(l, time.Now()); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// export makes the line available in sysfs and sets its direction.
+func (p *Provider) export(l *line) error {
+	if _, err := os.Stat(p.path(l, "")); os.IsNotExist(err) == true {
+		if err := ioutil.WriteFile(filepath.Join(p.root(), "export"), []byte(fmt.Sprint(l.config.Pin)), 0200); err != nil {
+			return err
+		}
+	}
+
+	direction := "in"
+	if l.config.Output == true {
+		direction = "out"
+	}
+
+	if err := ioutil.WriteFile(p.path(l, "direction"), []byte(direction), 0644); err != nil {
+		return err
+	}
+
+	if l.config.Output == true {
+		return p.write(l, l.accessory.Switch.On.GetValue())
+	}
+
+	return nil
+}
+
+// read reads the level of the input line and updates the switch when the level is stable.
+func (p *Provider) read(l *line, now time.Time) error {
+	b, err := ioutil.ReadFile(p.path(l, "value"))
+	if err != nil {
+		return err
+	}
+
+	on := strings.TrimSpace(string(b)) == "1"
+	if l.config.ActiveLow == true {
+		on = !on
+	}
+
+	if on, changed := l.debouncer.sample(on, now); changed == true {
+		l.accessory.Switch.On.SetValue(on)
+	}
+
+	return nil
+}
+
+// write sets the level of the output line.
+func (p *Provider) write(l *line, on bool) error {
+	if l.config.ActiveLow == true {
+		on = !on
+	}
+
+	value := "0"
+	if on == true {
+		value = "1"
+	}
+
+	return ioutil.WriteFile(p.path(l, "value"), []byte(value), 0644)
+}
+
+// path returns the path of an attribute of the line.
+func (p *Provider) path(l *line, attr string) string {
+	return filepath.Join(p.root(), fmt.Sprintf("gpio%d", l.config.Pin), attr)
+}
+
+func (p *Provider) root() string {
+	if len(p.Root) > 0 {
+		return p.Root
+	}
+
+	return DefaultRoot
+}
@@ -0,0 +1,128 @@
+package gpio
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/brutella/hc/characteristic"
+)
+
+// newTestRoot returns a directory with the sysfs files of the lines.
+func newTestRoot(t *testing.T, pins ...string) string {
+	root, err := ioutil.TempDir("", "gpio")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pin := range pins {
+		if err := os.MkdirAll(filepath.Join(root, "gpio"+pin), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return root
+}
+
+func readFile(t *testing.T, path string) string {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(b)
+}
+
+func TestOutputLine(t *testing.T) {
+	root := newTestRoot(t, "17")
+	defer os.RemoveAll(root)
+
+	p := New([]Line{{Name: "Relay", Pin: 17, Output: true, ActiveLow: true}})
+	p.Root = root
+
+	l := p.lines[0]
+	if err := p.export(l); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := readFile(t, filepath.Join(root, "gpio17", "direction")), "out"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	l.accessory.Switch.On.UpdateValueFromConnection(true, characteristic.TestConn)
+	if is, want := readFile(t, filepath.Join(root, "gpio17", "value")), "0"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestExportLine(t *testing.T) {
+	root := newTestRoot(t)
+	defer os.RemoveAll(root)
+
+	p := New([]Line{{Name: "Button", Pin: 4}})
+	p.Root = root
+
+	// The directory of the line doesn't exist in the test
+	p.export(p.lines[0])
+
+	if is, want := readFile(t, filepath.Join(root, "export")), "4"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestInputLine(t *testing.T) {
+	root := newTestRoot(t, "4")
+	defer os.RemoveAll(root)
+
+	p := New([]Line{{Name: "Button", Pin: 4, Debounce: 100 * time.Millisecond}})
+	p.Root = root
+
+	l := p.lines[0]
+	value := filepath.Join(root, "gpio4", "value")
+	set := func(level string) {
+		if err := ioutil.WriteFile(value, []byte(level+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	set("0")
+	p.read(l, now)
+
+	set("1")
+	p.read(l, now.Add(10*time.Millisecond))
+	if is, want := l.accessory.Switch.On.GetValue(), false; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	p.read(l, now.Add(110*time.Millisecond))
+	if is, want := l.accessory.Switch.On.GetValue(), true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if err := l.accessory.Switch.On.UpdateValueFromConnection(false, characteristic.TestConn); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestDebouncer(t *testing.T) {
+	d := debouncer{duration: time.Second}
+	now := time.Now()
+
+	if v, changed := d.sample(true, now); v != true || changed != true {
+		t.Fatalf("is=%v,%v", v, changed)
+	}
+
+	// A short glitch is ignored
+	if _, changed := d.sample(false, now.Add(100*time.Millisecond)); changed == true {
+		t.Fatal("unexpected change")
+	}
+	if _, changed := d.sample(true, now.Add(200*time.Millisecond)); changed == true {
+		t.Fatal("unexpected change")
+	}
+	if _, changed := d.sample(true, now.Add(2*time.Second)); changed == true {
+		t.Fatal("unexpected change")
+	}
+}
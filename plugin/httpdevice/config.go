@@ -0,0 +1,93 @@
+package httpdevice
+
+import (
+	"github.com/brutella/hc/plugin"
+
+	"encoding/json"
+	"io/ioutil"
+)
+
+// Kinds of devices
+const (
+	KindSwitch            = plugin.KindSwitch
+	KindOutlet            = plugin.KindOutlet
+	KindLightbulb         = plugin.KindLightbulb
+	KindTemperatureSensor = plugin.KindTemperatureSensor
+	KindHumiditySensor    = plugin.KindHumiditySensor
+	KindContactSensor     = plugin.KindContactSensor
+	KindMotionSensor      = plugin.KindMotionSensor
+)
+
+// Config is the configuration of the HTTP devices.
+//
+//	{
+//	  "devices": [{
+//	    "name": "Kitchen Light",
+//	    "serial_number": "kitchen-light",
+//	    "kind": "lightbulb",
+//	    "url": "http://192.168.1.20/api/status",
+//	    "interval": "10s",
+//	    "fields": {
+//	      "on": {"path": "$.light.on", "write": {"url": "http://192.168.1.20/api/light", "body": "{\"on\": {{value}}}"}},
+//	      "brightness": {"path": "$.light.level"}
+//	    }
+//	  }]
+//	}
+type Config struct {
+	Devices []Device `json:"devices"`
+}
+
+// Device is a device which provides its state as JSON at URL.
+type Device struct {
+	Name         string `json:"name"`
+	SerialNumber string `json:"serial_number"`
+
+	// Kind is the kind of accessory, e.g. KindSwitch.
+	Kind string `json:"kind"`
+
+	// URL is the URL which is polled with GET requests.
+	URL string `json:"url"`
+
+	// Interval is the poll interval. When zero, the device is polled every 30 seconds.
+	Interval plugin.Duration `json:"interval"`
+
+	// Fields maps the characteristics of the accessory to values in the JSON response.
+	// The keys are the characteristic names of the kind, e.g. "on" and "brightness" for
+	// KindLightbulb (see plugin.Characteristics).
+	Fields map[string]Field `json:"fields"`
+}
+
+// Field maps a characteristic to a value of the JSON response.
+type Field struct {
+	// Path is the path of the value, e.g. "$.sensors[0].temperature".
+	Path string `json:"path"`
+
+	// Write is the request which is sent when a client writes the characteristic.
+	// When nil, writes are not forwarded.
+	Write *Request `json:"write"`
+}
+
+// Request is an HTTP request which forwards a write.
+type Request struct {
+	// Method is the HTTP method. When empty, POST is used.
+	Method string `json:"method"`
+
+	// URL is the URL of the request. When empty, the device URL is used.
+	URL string `json:"url"`
+
+	// Body is the request body, in which "{{value}}" is replaced by the JSON encoded value.
+	Body string `json:"body"`
+}
+
+// ConfigFromFile returns the config which is loaded from a JSON file.
+func ConfigFromFile(path string) (Config, error) {
+	var c Config
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+
+	err = json.Unmarshal(b, &c)
+	return c, err
+}
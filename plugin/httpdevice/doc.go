@@ -0,0 +1,4 @@
+// Package httpdevice provides accessories for devices with a JSON/REST interface.
+// The devices are polled periodically and writes of clients are forwarded as HTTP requests,
+// as configured in a Config.
+package httpdevice
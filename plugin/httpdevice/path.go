@@ -0,0 +1,63 @@
+package httpdevice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// lookup returns the value at path in v, which is a decoded JSON value.
+// A path consists of object keys and array indexes, e.g. "$.sensors[0].temperature".
+func lookup(v interface{}, path string) (interface{}, error) {
+	path = strings.TrimPrefix(path, "$")
+	if len(path) > 0 && path[0] != '.' && path[0] != '[' {
+		path = "." + path
+	}
+
+	for len(path) > 0 {
+		switch path[0] {
+		case '.':
+			path = path[1:]
+			end := strings.IndexAny(path, ".[")
+			if end < 0 {
+				end = len(path)
+			}
+
+			obj, ok := v.(map[string]interface{})
+			if ok == false {
+				return nil, fmt.Errorf("%v is not an object", v)
+			}
+
+			key := path[:end]
+			if v, ok = obj[key]; ok == false {
+				return nil, fmt.Errorf("key %s not found", key)
+			}
+			path = path[end:]
+		case '[':
+			end := strings.Index(path, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("missing ] in path")
+			}
+
+			i, err := strconv.Atoi(path[1:end])
+			if err != nil {
+				return nil, err
+			}
+
+			arr, ok := v.([]interface{})
+			if ok == false {
+				return nil, fmt.Errorf("%v is not an array", v)
+			}
+
+			if i < 0 || i >= len(arr) {
+				return nil, fmt.Errorf("index %d out of range", i)
+			}
+			v = arr[i]
+			path = path[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid path %s", path)
+		}
+	}
+
+	return v, nil
+}
@@ -0,0 +1,39 @@
+package httpdevice
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(`{"light":{"on":true},"sensors":[{"value":21.5},{"value":22}]}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{"$.light.on", true},
+		{"light.on", true},
+		{"$.sensors[1].value", float64(22)},
+	}
+
+	for _, test := range tests {
+		is, err := lookup(v, test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if is != test.want {
+			t.Fatalf("is=%v want=%v", is, test.want)
+		}
+	}
+
+	for _, path := range []string{"$.light.off", "$.sensors[2].value", "$.light[0]", "$.sensors[0"} {
+		if _, err := lookup(v, path); err == nil {
+			t.Fatalf("expected error for %s", path)
+		}
+	}
+}
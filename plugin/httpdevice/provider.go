@@ -0,0 +1,204 @@
+package httpdevice
+
+import (
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/plugin"
+	"github.com/brutella/log"
+
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultInterval is the poll interval of devices without an interval
+const defaultInterval = 30 * time.Second
+
+// device is a configured device and its accessory.
+type device struct {
+	config          Device
+	accessory       *accessory.Accessory
+	characteristics map[string]*characteristic.Characteristic
+}
+
+// Provider provides the accessories of HTTP devices and implements plugin.AccessoryProvider.
+type Provider struct {
+	// Client sends the requests. When nil, http.DefaultClient is used.
+	Client *http.Client
+
+	devices []*device
+}
+
+// New returns a provider for the devices in config.
+func New(config Config) (*Provider, error) {
+	p := &Provider{}
+	for _, d := range config.Devices {
+		a, chars, err := plugin.NewAccessory(d.Kind, accessory.Info{Name: d.Name, SerialNumber: d.SerialNumber})
+		if err != nil {
+			return nil, err
+		}
+
+		dev := &device{config: d, accessory: a, characteristics: chars}
+		for name, f := range d.Fields {
+			c, ok := chars[name]
+			if ok == false {
+				return nil, fmt.Errorf("%s has no characteristic %s", d.Name, name)
+			}
+
+			if f.Write != nil {
+				p.forward(dev, c, *f.Write)
+			}
+		}
+
+		p.devices = append(p.devices, dev)
+	}
+
+	return p, nil
+}
+
+// Accessories returns the accessories of the devices.
+func (p *Provider) Accessories() []*accessory.Accessory {
+	var as []*accessory.Accessory
+	for _, d := range p.devices {
+		as = append(as, d.accessory)
+	}
+
+	return as
+}
+
+// Start polls the devices until ctx is done.
+func (p *Provider) Start(ctx context.Context) error {
+	done := make(chan struct{})
+	for _, d := range p.devices {
+		go func(d *device) {
+			p.poll(ctx, d)
+			done <- struct{}{}
+		}(d)
+	}
+
+	for range p.devices {
+		<-done
+	}
+
+	return nil
+}
+
+// poll updates the characteristics of d every interval until ctx is done.
+func (p *Provider) poll(ctx context.Context, d *device) {
+	interval := time.Duration(d.config.Interval)
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.update(ctx, d); err != nil {
+			log.Printf("[WARN] Polling %s failed: %v\n", d.config.Name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// update requests the state of d and updates the characteristics.
+func (p *Provider) update(ctx context.Context, d *device) error {
+	req, err := http.NewRequest("GET", d.config.URL, nil)
+	if err != nil {
+		return err
+	}
+
+	b, err := p.do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	for name, f := range d.config.Fields {
+		value, err := lookup(v, f.Path)
+		if err != nil {
+			log.Printf("[WARN] Value of %s in %s: %v\n", name, d.config.Name, err)
+			continue
+		}
+
+		c := d.characteristics[name]
+		c.UpdateValue(plugin.Convert(value, c))
+	}
+
+	return nil
+}
+
+// forward sends r when a client writes c.
+func (p *Provider) forward(d *device, c *characteristic.Characteristic, r Request) {
+	c.OnValueUpdateFromConn(func(conn net.Conn, c *characteristic.Characteristic, new, old interface{}) {
+		method := r.Method
+		if len(method) == 0 {
+			method = "POST"
+		}
+
+		url := r.URL
+		if len(url) == 0 {
+			url = d.config.URL
+		}
+
+		value, err := json.Marshal(new)
+		if err != nil {
+			log.Println("[ERRO]", err)
+			return
+		}
+
+		var body io.Reader
+		if len(r.Body) > 0 {
+			body = strings.NewReader(strings.Replace(r.Body, "{{value}}", string(value), -1))
+		}
+
+		req, err := http.NewRequest(method, url, body)
+		if err != nil {
+			log.Println("[ERRO]", err)
+			return
+		}
+
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		if _, err := p.do(req); err != nil {
+			log.Printf("[WARN] Forwarding write to %s failed: %v\n", d.config.Name, err)
+		}
+	})
+}
+
+// do sends req and returns the response body.
+func (p *Provider) do(req *http.Request) ([]byte, error) {
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("%s %s returned %s", req.Method, req.URL, res.Status)
+	}
+
+	return ioutil.ReadAll(res.Body)
+}
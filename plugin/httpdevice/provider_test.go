@@ -0,0 +1,83 @@
+package httpdevice
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/brutella/hc/characteristic"
+)
+
+func TestProvider(t *testing.T) {
+	writes := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Write([]byte(`{"light":{"on":true,"level":40}}`))
+		case "PUT":
+			b, _ := ioutil.ReadAll(r.Body)
+			writes <- string(b)
+		}
+	}))
+	defer server.Close()
+
+	var config Config
+	err := json.Unmarshal([]byte(`{"devices":[{
+		"name": "Light",
+		"serial_number": "light",
+		"kind": "lightbulb",
+		"url": "`+server.URL+`",
+		"interval": "1h",
+		"fields": {
+			"on": {"path": "$.light.on", "write": {"method": "PUT", "body": "{\"on\":{{value}}}"}},
+			"brightness": {"path": "$.light.level"}
+		}
+	}]}`), &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := time.Duration(config.Devices[0].Interval), time.Hour; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	p, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := p.devices[0]
+	if err := p.update(context.Background(), d); err != nil {
+		t.Fatal(err)
+	}
+
+	on := d.characteristics["on"]
+	if is, want := on.Value, true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := d.characteristics["brightness"].Value, 40; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	on.UpdateValueFromConnection(false, characteristic.TestConn)
+	if is, want := <-writes, `{"on":false}`; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestUnknownCharacteristic(t *testing.T) {
+	config := Config{Devices: []Device{{
+		Name:   "Switch",
+		Kind:   KindSwitch,
+		Fields: map[string]Field{"brightness": {Path: "$.level"}},
+	}}}
+
+	if _, err := New(config); err == nil {
+		t.Fatal("expected error")
+	}
+}
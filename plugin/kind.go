@@ -0,0 +1,102 @@
+package plugin
+
+import (
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/service"
+
+	"fmt"
+	"sort"
+)
+
+// Kinds of accessories which are created from declarative configs (see NewAccessory)
+const (
+	KindSwitch            = "switch"
+	KindOutlet            = "outlet"
+	KindLightbulb         = "lightbulb"
+	KindTemperatureSensor = "temperature-sensor"
+	KindHumiditySensor    = "humidity-sensor"
+	KindContactSensor     = "contact-sensor"
+	KindMotionSensor      = "motion-sensor"
+)
+
+// NewAccessory returns an accessory of a kind and its characteristics by name,
+// e.g. "on" and "brightness" for KindLightbulb.
+func NewAccessory(kind string, info accessory.Info) (*accessory.Accessory, map[string]*characteristic.Characteristic, error) {
+	var a *accessory.Accessory
+	var s *service.Service
+	var chars map[string]*characteristic.Characteristic
+	switch kind {
+	case KindSwitch:
+		svc := service.NewSwitch()
+		a, s = accessory.New(info, accessory.TypeSwitch), svc.Service
+		chars = map[string]*characteristic.Characteristic{"on": svc.On.Characteristic}
+	case KindOutlet:
+		svc := service.NewOutlet()
+		a, s = accessory.New(info, accessory.TypeOutlet), svc.Service
+		chars = map[string]*characteristic.Characteristic{
+			"on":     svc.On.Characteristic,
+			"in_use": svc.OutletInUse.Characteristic,
+		}
+	case KindLightbulb:
+		svc := service.NewLightbulb()
+		a, s = accessory.New(info, accessory.TypeLightbulb), svc.Service
+		chars = map[string]*characteristic.Characteristic{
+			"on":         svc.On.Characteristic,
+			"brightness": svc.Brightness.Characteristic,
+			"hue":        svc.Hue.Characteristic,
+			"saturation": svc.Saturation.Characteristic,
+		}
+	case KindTemperatureSensor:
+		svc := service.NewTemperatureSensor()
+		a, s = accessory.New(info, accessory.TypeSensor), svc.Service
+		chars = map[string]*characteristic.Characteristic{"temperature": svc.CurrentTemperature.Characteristic}
+	case KindHumiditySensor:
+		svc := service.NewHumiditySensor()
+		a, s = accessory.New(info, accessory.TypeSensor), svc.Service
+		chars = map[string]*characteristic.Characteristic{"humidity": svc.CurrentRelativeHumidity.Characteristic}
+	case KindContactSensor:
+		svc := service.NewContactSensor()
+		a, s = accessory.New(info, accessory.TypeSensor), svc.Service
+		chars = map[string]*characteristic.Characteristic{"contact": svc.ContactSensorState.Characteristic}
+	case KindMotionSensor:
+		svc := service.NewMotionSensor()
+		a, s = accessory.New(info, accessory.TypeSensor), svc.Service
+		chars = map[string]*characteristic.Characteristic{"motion": svc.MotionDetected.Characteristic}
+	default:
+		return nil, nil, fmt.Errorf("unsupported kind %s", kind)
+	}
+
+	a.AddService(s)
+
+	return a, chars, nil
+}
+
+// Characteristics returns the names of the characteristics of a kind.
+func Characteristics(kind string) []string {
+	_, chars, err := NewAccessory(kind, accessory.Info{})
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for name := range chars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Convert converts a boolean value to a number for characteristics with a numeric format,
+// e.g. to set the contact sensor state from a JSON boolean.
+func Convert(v interface{}, c *characteristic.Characteristic) interface{} {
+	if b, ok := v.(bool); ok == true && c.Format != characteristic.FormatBool {
+		if b == true {
+			return 1
+		}
+		return 0
+	}
+
+	return v
+}
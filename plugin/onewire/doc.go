@@ -0,0 +1,3 @@
+// Package onewire provides temperature sensor accessories for DS18B20 1-Wire sensors,
+// which are read with the Linux w1-therm driver (/sys/bus/w1/devices), e.g. on a Raspberry Pi.
+package onewire
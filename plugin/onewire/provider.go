@@ -0,0 +1,139 @@
+package onewire
+
+import (
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/log"
+
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultRoot is the path of the 1-Wire devices.
+const DefaultRoot = "/sys/bus/w1/devices"
+
+// defaultInterval is the interval in which sensors are read
+const defaultInterval = time.Minute
+
+// Sensor is a DS18B20 temperature sensor.
+type Sensor struct {
+	Name string
+
+	// ID is the id of the sensor, e.g. "28-0316a2795aff". It is used as serial number.
+	ID string
+}
+
+// sensor is a configured sensor and its accessory.
+type sensor struct {
+	config    Sensor
+	accessory *accessory.Thermometer
+}
+
+// Provider provides temperature sensor accessories and implements plugin.AccessoryProvider.
+type Provider struct {
+	// Root is the path of the 1-Wire devices. When empty, DefaultRoot is used.
+	Root string
+
+	// Interval is the interval in which the sensors are read. When zero, the sensors are read every minute.
+	Interval time.Duration
+
+	sensors []*sensor
+}
+
+// New returns a provider for sensors.
+func New(sensors []Sensor) *Provider {
+	p := &Provider{}
+	for _, s := range sensors {
+		acc := accessory.NewTemperatureSensor(accessory.Info{Name: s.Name, SerialNumber: s.ID}, 0, -55, 125, 0.1)
+		p.sensors = append(p.sensors, &sensor{config: s, accessory: acc})
+	}
+
+	return p
+}
+
+// Accessories returns the accessories of the sensors.
+func (p *Provider) Accessories() []*accessory.Accessory {
+	var as []*accessory.Accessory
+	for _, s := range p.sensors {
+		as = append(as, s.accessory.Accessory)
+	}
+
+	return as
+}
+
+// Start reads the sensors until ctx is done.
+func (p *Provider) Start(ctx context.Context) error {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, s := range p.sensors {
+			if err := p.update(s); err != nil {
+				log.Printf("[WARN] Reading sensor %s failed: %v\n", s.config.ID, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// update reads the temperature of s and updates the accessory.
+func (p *Provider) update(s *sensor) error {
+	root := p.Root
+	if len(root) == 0 {
+		root = DefaultRoot
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(root, s.config.ID, "w1_slave"))
+	if err != nil {
+		return err
+	}
+
+	temp, err := parseTemperature(string(b))
+	if err != nil {
+		return err
+	}
+
+	s.accessory.TempSensor.CurrentTemperature.SetValue(temp)
+	return nil
+}
+
+// parseTemperature returns the temperature in °C of the w1_slave output of a sensor.
+//
+//	72 01 4b 46 7f ff 0e 10 57 : crc=57 YES
+//	72 01 4b 46 7f ff 0e 10 57 t=23125
+func parseTemperature(s string) (float64, error) {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	if len(lines) != 2 {
+		return 0, fmt.Errorf("invalid sensor output %q", s)
+	}
+
+	if strings.HasSuffix(strings.TrimSpace(lines[0]), "YES") == false {
+		return 0, fmt.Errorf("invalid crc")
+	}
+
+	i := strings.Index(lines[1], "t=")
+	if i < 0 {
+		return 0, fmt.Errorf("missing temperature in %q", lines[1])
+	}
+
+	millis, err := strconv.Atoi(strings.TrimSpace(lines[1][i+2:]))
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(millis) / 1000, nil
+}
@@ -0,0 +1,52 @@
+package onewire
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTemperature(t *testing.T) {
+	temp, err := parseTemperature("72 01 4b 46 7f ff 0e 10 57 : crc=57 YES\n72 01 4b 46 7f ff 0e 10 57 t=23125\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := temp, 23.125; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if _, err := parseTemperature("72 01 4b 46 7f ff 0e 10 57 : crc=57 NO\n72 01 4b 46 7f ff 0e 10 57 t=23125\n"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	root, err := ioutil.TempDir("", "w1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	id := "28-0316a2795aff"
+	if err := os.MkdirAll(filepath.Join(root, id), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	output := "50 05 4b 46 7f ff 0c 10 1c : crc=1c YES\n50 05 4b 46 7f ff 0c 10 1c t=-1500\n"
+	if err := ioutil.WriteFile(filepath.Join(root, id, "w1_slave"), []byte(output), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := New([]Sensor{{Name: "Outside", ID: id}})
+	p.Root = root
+
+	if err := p.update(p.sensors[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := p.sensors[0].accessory.TempSensor.CurrentTemperature.GetValue(), -1.5; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
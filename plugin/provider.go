@@ -0,0 +1,154 @@
+package plugin
+
+import (
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/util"
+	"github.com/brutella/log"
+
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// AccessoryProvider provides bridged accessories.
+type AccessoryProvider interface {
+	// Accessories returns the accessories of the provider.
+	// An accessory is identified by its serial number within the provider.
+	Accessories() []*accessory.Accessory
+
+	// Start runs the provider until ctx is done, e.g. to connect to the devices
+	// and update the characteristics. When Start returns an error, it is called again
+	// after Host.RestartDelay.
+	Start(ctx context.Context) error
+}
+
+// aidsKey is the storage key of the accessory ids
+const aidsKey = "plugin.aids"
+
+// firstAID is the first accessory id of a provider. The accessory id 1 is used by the bridge.
+const firstAID = 2
+
+// defaultRestartDelay is the delay after which a failed provider is restarted
+const defaultRestartDelay = 10 * time.Second
+
+type namedProvider struct {
+	name     string
+	provider AccessoryProvider
+}
+
+// Host manages the lifecycle of providers and allocates their accessory ids.
+type Host struct {
+	// RestartDelay is the delay after which a provider is started again when
+	// it returned an error. When zero, the delay is 10 seconds.
+	RestartDelay time.Duration
+
+	storage   util.Storage
+	providers []namedProvider
+}
+
+// NewHost returns a host which stores the accessory ids in storage.
+func NewHost(storage util.Storage) *Host {
+	return &Host{storage: storage}
+}
+
+// Add adds the provider p with a unique name. The name is part of the stored
+// accessory ids and must not change.
+func (h *Host) Add(name string, p AccessoryProvider) error {
+	for _, np := range h.providers {
+		if np.name == name {
+			return fmt.Errorf("provider %s already exists", name)
+		}
+	}
+
+	h.providers = append(h.providers, namedProvider{name, p})
+	return nil
+}
+
+// Accessories returns the accessories of all providers with stable accessory ids.
+// An accessory gets the same id as before when its provider and serial number are
+// the same. New accessories get ids which were never used before.
+func (h *Host) Accessories() ([]*accessory.Accessory, error) {
+	aids := map[string]int64{}
+	if b, err := h.storage.Get(aidsKey); err == nil && len(b) > 0 {
+		if err := json.Unmarshal(b, &aids); err != nil {
+			return nil, err
+		}
+	}
+
+	next := int64(firstAID)
+	for _, aid := range aids {
+		if aid >= next {
+			next = aid + 1
+		}
+	}
+
+	var as []*accessory.Accessory
+	used := map[string]bool{}
+	for _, np := range h.providers {
+		for _, a := range np.provider.Accessories() {
+			key := np.name + "/" + a.Info.SerialNumber.GetValue()
+			if used[key] == true {
+				return nil, fmt.Errorf("provider %s has multiple accessories with serial number %s", np.name, a.Info.SerialNumber.GetValue())
+			}
+			used[key] = true
+
+			aid, ok := aids[key]
+			if ok == false {
+				aid = next
+				next++
+				aids[key] = aid
+			}
+
+			a.SetID(aid)
+			as = append(as, a)
+		}
+	}
+
+	b, err := json.Marshal(aids)
+	if err != nil {
+		return nil, err
+	}
+
+	return as, h.storage.Set(aidsKey, b)
+}
+
+// Run starts all providers and blocks until ctx is done and all providers returned.
+func (h *Host) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, np := range h.providers {
+		wg.Add(1)
+		go func(np namedProvider) {
+			defer wg.Done()
+			h.run(ctx, np)
+		}(np)
+	}
+
+	wg.Wait()
+}
+
+// run starts the provider np again until ctx is done.
+func (h *Host) run(ctx context.Context, np namedProvider) {
+	delay := h.RestartDelay
+	if delay <= 0 {
+		delay = defaultRestartDelay
+	}
+
+	for {
+		err := np.provider.Start(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			log.Printf("[WARN] Provider %s failed: %v\n", np.name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/util"
+)
+
+type testProvider struct {
+	serials []string
+	starts  chan int
+	count   int
+}
+
+func (p *testProvider) Accessories() []*accessory.Accessory {
+	var as []*accessory.Accessory
+	for _, serial := range p.serials {
+		as = append(as, accessory.NewSwitch(accessory.Info{Name: serial, SerialNumber: serial}).Accessory)
+	}
+
+	return as
+}
+
+func (p *testProvider) Start(ctx context.Context) error {
+	p.count++
+	p.starts <- p.count
+	if p.count == 1 {
+		return fmt.Errorf("failed")
+	}
+
+	<-ctx.Done()
+	return nil
+}
+
+func newTestStorage(t *testing.T) util.Storage {
+	storage, err := util.NewTempFileStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return storage
+}
+
+func aids(as []*accessory.Accessory) []int64 {
+	var ids []int64
+	for _, a := range as {
+		ids = append(ids, a.ID)
+	}
+
+	return ids
+}
+
+func TestStableAccessoryIDs(t *testing.T) {
+	storage := newTestStorage(t)
+
+	host := NewHost(storage)
+	host.Add("zigbee", &testProvider{serials: []string{"A", "B"}})
+	host.Add("zwave", &testProvider{serials: []string{"A"}})
+
+	as, err := host.Accessories()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := fmt.Sprint(aids(as)), "[2 3 4]"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// The zigbee provider lost accessory A and found accessory C
+	host = NewHost(storage)
+	host.Add("zwave", &testProvider{serials: []string{"A"}})
+	host.Add("zigbee", &testProvider{serials: []string{"C", "B"}})
+
+	as, err = host.Accessories()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := fmt.Sprint(aids(as)), "[4 5 3]"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestDuplicateProvider(t *testing.T) {
+	host := NewHost(newTestStorage(t))
+	if err := host.Add("zigbee", &testProvider{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := host.Add("zigbee", &testProvider{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestDuplicateSerialNumber(t *testing.T) {
+	host := NewHost(newTestStorage(t))
+	host.Add("zigbee", &testProvider{serials: []string{"A", "A"}})
+
+	if _, err := host.Accessories(); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestRestartFailedProvider(t *testing.T) {
+	p := &testProvider{starts: make(chan int, 2)}
+	host := NewHost(newTestStorage(t))
+	host.RestartDelay = time.Millisecond
+	host.Add("zigbee", p)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		host.Run(ctx)
+		close(done)
+	}()
+
+	<-p.starts
+	if is, want := <-p.starts, 2; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	cancel()
+	<-done
+}
+
+func TestCharacteristics(t *testing.T) {
+	if is, want := fmt.Sprint(Characteristics(KindOutlet)), "[in_use on]"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if _, _, err := NewAccessory("toaster", accessory.Info{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
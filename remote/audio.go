@@ -0,0 +1,78 @@
+package remote
+
+import (
+	"github.com/brutella/hc/camera"
+)
+
+// TLV8 tags of the audio stream configurations
+const (
+	tagAudioCodecConfiguration = 0x01
+	tagAudioComfortNoise       = 0x02
+	tagAudioCodecType          = 0x01
+	tagAudioCodecParameters    = 0x02
+	tagAudioChannels           = 0x01
+	tagAudioBitrateMode        = 0x02
+	tagAudioSampleRate         = 0x03
+	tagAudioRTPTime            = 0x04
+)
+
+// DataSendTypeSiri is the type of data streams which send Siri audio
+const DataSendTypeSiri = "audio.siri"
+
+// An AudioSource provides the Siri audio as Opus frames (mono, 16 kHz).
+type AudioSource interface {
+	// ReadFrame returns the next frame and the root mean square of its samples.
+	// It returns io.EOF when the user stopped talking.
+	ReadFrame() (frame []byte, rms float64, err error)
+}
+
+// AudioFormat is the audio format selected by the controller.
+type AudioFormat struct {
+	CodecType   byte
+	Channels    byte
+	BitrateMode byte
+	SampleRate  byte
+
+	// RTPTime is the duration of audio in one frame in ms
+	RTPTime byte
+}
+
+// supportedAudioConfiguration returns the value of the Supported Audio Stream Configuration characteristic.
+// Siri audio is always encoded with Opus.
+func supportedAudioConfiguration() []byte {
+	params := tlv8(tagAudioChannels, []byte{1})
+	params = append(params, tlv8(tagAudioBitrateMode, []byte{camera.AudioBitrateVariable})...)
+	params = append(params, tlv8(tagAudioSampleRate, []byte{camera.AudioSampleRate16Khz})...)
+
+	codec := tlv8(tagAudioCodecType, []byte{camera.AudioCodecTypeOpus})
+	codec = append(codec, tlv8(tagAudioCodecParameters, params)...)
+
+	b := tlv8(tagAudioCodecConfiguration, codec)
+	return append(b, tlv8(tagAudioComfortNoise, []byte{0})...)
+}
+
+// parseAudioFormat returns the audio format of the Selected Audio Stream Configuration characteristic.
+func parseAudioFormat(b []byte) (AudioFormat, error) {
+	var f AudioFormat
+	c, err := tlv8Container(b)
+	if err != nil {
+		return f, err
+	}
+
+	codec, err := tlv8Container(c.GetBytes(tagAudioCodecConfiguration))
+	if err != nil {
+		return f, err
+	}
+	f.CodecType = codec.GetByte(tagAudioCodecType)
+
+	params, err := tlv8Container(codec.GetBytes(tagAudioCodecParameters))
+	if err != nil {
+		return f, err
+	}
+	f.Channels = params.GetByte(tagAudioChannels)
+	f.BitrateMode = params.GetByte(tagAudioBitrateMode)
+	f.SampleRate = params.GetByte(tagAudioSampleRate)
+	f.RTPTime = params.GetByte(tagAudioRTPTime)
+
+	return f, nil
+}
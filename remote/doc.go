@@ -0,0 +1,9 @@
+// Package remote implements a Siri remote which controls an Apple TV.
+//
+// The Apple TV manages its targets via the Target Control Management service. When the
+// user selects a target, the Active Identifier characteristic of the Target Control service
+// is set. Button presses are then sent as events of the Button Event characteristic.
+//
+// Siri audio is sent to the controller over a HomeKit Data Stream (see package hds).
+// The audio format is negotiated via the Audio Stream Management service.
+package remote
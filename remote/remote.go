@@ -0,0 +1,329 @@
+package remote
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/hds"
+	"github.com/brutella/hc/service"
+	"github.com/brutella/log"
+)
+
+// ProtocolTargetControl is the HDS protocol which announces the remote to the controller
+const ProtocolTargetControl = "targetControl"
+
+const topicWhoami = "whoami"
+
+// TLV8 tags of the Button Event characteristic
+const (
+	tagEventButtonID         = 0x01
+	tagEventButtonState      = 0x02
+	tagEventTimestamp        = 0x03
+	tagEventActiveIdentifier = 0x04
+)
+
+const (
+	buttonStateUp   byte = 0
+	buttonStateDown byte = 1
+)
+
+var (
+	// ErrNoTarget is returned when no target is active.
+	ErrNoTarget = errors.New("remote: no active target")
+
+	// ErrNoSession is returned when there is no data stream to send Siri audio.
+	ErrNoSession = errors.New("remote: no data stream session")
+
+	// ErrNoSiriButton is returned when the remote has no Siri button.
+	ErrNoSiriButton = errors.New("remote: no siri button")
+)
+
+// Remote is a Siri remote which controls an Apple TV.
+//
+// The services of the remote must be added to the accessory. To send Siri audio,
+// the accessory also needs a Data Stream Transport Management service, which is
+// set up by a hds.Server. The remote must be registered at the server.
+//
+//	remote.Serve(server)
+type Remote struct {
+	Management  *service.TargetControlManagement
+	Control     *service.TargetControl
+	AudioStream *service.AudioStreamManagement
+	Siri        *service.Siri
+
+	conf  Configuration
+	start time.Time
+
+	mutex   sync.Mutex
+	targets []Target
+	session *hds.Session
+	audio   AudioFormat
+}
+
+// NewRemote returns a remote with the buttons of conf.
+func NewRemote(conf Configuration) *Remote {
+	r := Remote{
+		Management:  service.NewTargetControlManagement(),
+		Control:     service.NewTargetControl(),
+		AudioStream: service.NewAudioStreamManagement(),
+		Siri:        service.NewSiri(),
+		conf:        conf,
+		start:       time.Now(),
+	}
+
+	r.Management.TargetControlSupportedConfiguration.SetValue(conf.supportedConfiguration())
+	r.AudioStream.SupportedAudioStreamConfiguration.SetValue(supportedAudioConfiguration())
+	r.Siri.SiriInputType.SetValue(characteristic.SiriInputTypePushButtonTriggeredAppleTV)
+
+	r.Management.TargetControlList.OnValueUpdateFromConn(func(conn net.Conn, c *characteristic.Characteristic, newValue, oldValue interface{}) {
+		str, _ := newValue.(string)
+		b, err := base64.StdEncoding.DecodeString(str)
+		if err == nil {
+			b, err = r.updateTargets(b)
+		}
+
+		if err != nil {
+			log.Println("[ERRO]", err)
+		}
+
+		// The controller reads the response
+		c.Value = base64.StdEncoding.EncodeToString(b)
+	})
+
+	r.AudioStream.SelectedAudioStreamConfiguration.OnValueUpdateFromConn(func(conn net.Conn, c *characteristic.Characteristic, newValue, oldValue interface{}) {
+		str, _ := newValue.(string)
+		b, err := base64.StdEncoding.DecodeString(str)
+		if err != nil {
+			log.Println("[ERRO]", err)
+			return
+		}
+
+		f, err := parseAudioFormat(b)
+		if err != nil {
+			log.Println("[ERRO] Invalid audio configuration", err)
+			return
+		}
+
+		r.mutex.Lock()
+		r.audio = f
+		r.mutex.Unlock()
+	})
+
+	return &r
+}
+
+// Serve announces the remote on every data stream session of s.
+// The latest session is used to send Siri audio.
+func (r *Remote) Serve(s *hds.Server) {
+	s.OnSession(func(session *hds.Session) {
+		r.mutex.Lock()
+		r.session = session
+		r.mutex.Unlock()
+
+		session.OnClose(func(session *hds.Session) {
+			r.mutex.Lock()
+			if r.session == session {
+				r.session = nil
+			}
+			r.mutex.Unlock()
+		})
+
+		body := map[string]interface{}{
+			"identifier": int64(r.Control.ActiveIdentifier.GetValue()),
+		}
+		if err := session.SendEvent(ProtocolTargetControl, topicWhoami, body); err != nil {
+			log.Println("[ERRO] Could not announce remote", err)
+		}
+	})
+}
+
+// Targets returns the targets configured by the controller.
+func (r *Remote) Targets() []Target {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]Target{}, r.targets...)
+}
+
+// AudioFormat returns the audio format selected by the controller.
+func (r *Remote) AudioFormat() AudioFormat {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.audio
+}
+
+// PressButton sends a button down event to the active target.
+func (r *Remote) PressButton(id uint8) error {
+	return r.sendButtonEvent(id, buttonStateDown)
+}
+
+// ReleaseButton sends a button up event to the active target.
+func (r *Remote) ReleaseButton(id uint8) error {
+	return r.sendButtonEvent(id, buttonStateUp)
+}
+
+func (r *Remote) sendButtonEvent(id uint8, state byte) error {
+	active := uint32(r.Control.ActiveIdentifier.GetValue())
+	if active == 0 {
+		return ErrNoTarget
+	}
+
+	ticks := uint64(time.Since(r.start) / (time.Second / ticksPerSecond))
+
+	b := tlv8(tagEventButtonID, []byte{id})
+	b = append(b, tlv8(tagEventButtonState, []byte{state})...)
+	b = append(b, tlv8(tagEventTimestamp, uint64Bytes(ticks))...)
+	b = append(b, tlv8(tagEventActiveIdentifier, uint32Bytes(active))...)
+
+	r.Control.ButtonEvent.SetValue(b)
+
+	return nil
+}
+
+// SendSiriAudio presses the Siri button and sends the audio of src to the controller.
+// The method returns after src returned io.EOF or the controller closed the stream.
+func (r *Remote) SendSiriAudio(src AudioSource) error {
+	id, ok := r.siriButton()
+	if ok == false {
+		return ErrNoSiriButton
+	}
+
+	r.mutex.Lock()
+	session := r.session
+	r.mutex.Unlock()
+
+	if session == nil {
+		return ErrNoSession
+	}
+
+	if err := r.PressButton(id); err != nil {
+		return err
+	}
+	defer r.ReleaseButton(id)
+
+	st, err := session.OpenDataStream(DataSendTypeSiri)
+	if err != nil {
+		return err
+	}
+
+	for seq := int64(0); ; seq++ {
+		select {
+		case <-st.Done():
+			return nil
+		default:
+		}
+
+		frame, rms, err := src.ReadFrame()
+		if err == io.EOF {
+			return st.Close(hds.CloseReasonNormal)
+		}
+
+		if err != nil {
+			st.Close(hds.CloseReasonUnexpectedFailure)
+			return err
+		}
+
+		packet := map[string]interface{}{
+			"data": frame,
+			"metadata": map[string]interface{}{
+				"rms":            rms,
+				"sequenceNumber": seq,
+			},
+		}
+
+		if err := st.SendData([]interface{}{packet}, false); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *Remote) siriButton() (uint8, bool) {
+	for _, btn := range r.conf.Buttons {
+		if btn.Type == ButtonTypeSiri {
+			return btn.ID, true
+		}
+	}
+
+	return 0, false
+}
+
+// updateTargets handles a write request of the Target Control List characteristic
+// and returns the response value.
+func (r *Remote) updateTargets(b []byte) ([]byte, error) {
+	in, err := tlv8Container(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []Target
+	for _, v := range tlv8Values(tagListConfiguration, b) {
+		t, err := parseTarget(v)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, t)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	switch op := in.GetByte(tagListOperation); op {
+	case listOperationList:
+		var configurations [][]byte
+		for _, t := range r.targets {
+			configurations = append(configurations, t.tlv8())
+		}
+
+		return tlv8List(tagListConfiguration, configurations), nil
+	case listOperationAdd:
+		if len(r.targets)+len(targets) > int(r.conf.MaxTargets) {
+			return nil, errors.New("remote: too many targets")
+		}
+		r.targets = append(r.targets, targets...)
+	case listOperationRemove:
+		for _, t := range targets {
+			if i := r.indexOfTarget(t.Identifier); i >= 0 {
+				r.targets = append(r.targets[:i], r.targets[i+1:]...)
+			}
+		}
+	case listOperationReset:
+		r.targets = nil
+	case listOperationUpdate:
+		for _, t := range targets {
+			i := r.indexOfTarget(t.Identifier)
+			if i < 0 {
+				continue
+			}
+
+			if len(t.Name) > 0 {
+				r.targets[i].Name = t.Name
+			}
+			if t.Category != TargetCategoryUndefined {
+				r.targets[i].Category = t.Category
+			}
+			if len(t.Buttons) > 0 {
+				r.targets[i].Buttons = t.Buttons
+			}
+		}
+	default:
+		return nil, errors.New("remote: unsupported target list operation")
+	}
+
+	return []byte{}, nil
+}
+
+func (r *Remote) indexOfTarget(id uint32) int {
+	for i, t := range r.targets {
+		if t.Identifier == id {
+			return i
+		}
+	}
+
+	return -1
+}
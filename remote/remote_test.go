@@ -0,0 +1,121 @@
+package remote
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+)
+
+func TestTargetList(t *testing.T) {
+	r := NewRemote(Configuration{MaxTargets: 1, Buttons: []Button{{ID: 1, Type: ButtonTypeSiri}}})
+
+	target := Target{
+		Identifier: 0xAABB,
+		Name:       "Living Room",
+		Category:   TargetCategoryAppleTV,
+		Buttons: []TargetButton{
+			{ID: 1, Type: ButtonTypeSiri},
+			{ID: 2, Type: ButtonTypeMenu, Name: "Menu"},
+		},
+	}
+
+	add := tlv8(tagListOperation, []byte{listOperationAdd})
+	add = append(add, tlv8(tagListConfiguration, target.tlv8())...)
+	if _, err := r.updateTargets(add); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.updateTargets(add); err == nil {
+		t.Fatal("expected error when exceeding max targets")
+	}
+
+	b, err := r.updateTargets(tlv8(tagListOperation, []byte{listOperationList}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := tlv8Values(tagListConfiguration, b)
+	if is, want := len(values), 1; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	listed, err := parseTarget(values[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := listed.Name, target.Name; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := len(listed.Buttons), 2; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := listed.Buttons[1].Name, "Menu"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	remove := tlv8(tagListOperation, []byte{listOperationRemove})
+	remove = append(remove, tlv8(tagListConfiguration, tlv8(tagTargetIdentifier, uint32Bytes(target.Identifier)))...)
+	if _, err := r.updateTargets(remove); err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := len(r.Targets()), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestButtonEvent(t *testing.T) {
+	r := NewRemote(Configuration{MaxTargets: 1, Buttons: []Button{{ID: 4, Type: ButtonTypeSelect}}})
+
+	if is, want := r.PressButton(4), ErrNoTarget; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	r.Control.ActiveIdentifier.SetValue(7)
+	if err := r.PressButton(4); err != nil {
+		t.Fatal(err)
+	}
+
+	str, _ := r.Control.ButtonEvent.Value.(string)
+	b, err := base64.StdEncoding.DecodeString(str)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := tlv8Container(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := c.GetByte(tagEventButtonID), byte(4); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := c.GetByte(tagEventButtonState), buttonStateDown; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := uintFromBytes(c.GetBytes(tagEventActiveIdentifier)), uint64(7); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestTLV8Values(t *testing.T) {
+	long := bytes.Repeat([]byte{0x01}, 300)
+
+	b := tlv8(0x02, long)
+	b = append(b, 0x00, 0x00)
+	b = append(b, tlv8(0x02, []byte{0x03})...)
+
+	values := tlv8Values(0x02, b)
+	if is, want := len(values), 2; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := len(values[0]), 300; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
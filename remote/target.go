@@ -0,0 +1,165 @@
+package remote
+
+// ButtonType is the type of a button of a remote.
+type ButtonType uint16
+
+// Button types
+const (
+	ButtonTypeUndefined  ButtonType = 0
+	ButtonTypeMenu       ButtonType = 1
+	ButtonTypePlayPause  ButtonType = 2
+	ButtonTypeTVHome     ButtonType = 3
+	ButtonTypeSelect     ButtonType = 4
+	ButtonTypeArrowUp    ButtonType = 5
+	ButtonTypeArrowRight ButtonType = 6
+	ButtonTypeArrowDown  ButtonType = 7
+	ButtonTypeArrowLeft  ButtonType = 8
+	ButtonTypeVolumeUp   ButtonType = 9
+	ButtonTypeVolumeDown ButtonType = 10
+	ButtonTypeSiri       ButtonType = 11
+	ButtonTypePower      ButtonType = 12
+	ButtonTypeGeneric    ButtonType = 13
+)
+
+// TargetCategory is the category of a target.
+type TargetCategory uint16
+
+// Target categories
+const (
+	TargetCategoryUndefined TargetCategory = 0
+	TargetCategoryAppleTV   TargetCategory = 24
+)
+
+// TLV8 tags of the Target Control Supported Configuration characteristic
+const (
+	tagSupportedMaxTargets     = 0x01
+	tagSupportedTicksPerSecond = 0x02
+	tagSupportedButtons        = 0x03
+	tagSupportedType           = 0x04
+	tagButtonID                = 0x01
+	tagButtonType              = 0x02
+	tagButtonName              = 0x03
+)
+
+// TLV8 tags of the Target Control List characteristic
+const (
+	tagListOperation     = 0x01
+	tagListConfiguration = 0x02
+	tagTargetIdentifier  = 0x01
+	tagTargetName        = 0x02
+	tagTargetCategory    = 0x03
+	tagTargetButtons     = 0x04
+)
+
+// Operations of the Target Control List characteristic
+const (
+	listOperationList   byte = 1
+	listOperationAdd    byte = 2
+	listOperationRemove byte = 3
+	listOperationReset  byte = 4
+	listOperationUpdate byte = 5
+)
+
+// remoteTypeHardware is the type of a physical remote
+const remoteTypeHardware byte = 1
+
+// ticksPerSecond is the resolution of button event timestamps
+const ticksPerSecond = 1000
+
+// Button is a button of the remote.
+type Button struct {
+	ID   uint8
+	Type ButtonType
+}
+
+// Configuration describes the remote.
+type Configuration struct {
+	// MaxTargets is the number of targets which can be controlled by the remote.
+	MaxTargets uint8
+	Buttons    []Button
+}
+
+// supportedConfiguration returns the value of the Target Control Supported Configuration characteristic.
+func (c Configuration) supportedConfiguration() []byte {
+	var buttons []byte
+	for _, btn := range c.Buttons {
+		buttons = append(buttons, tlv8(tagButtonID, []byte{btn.ID})...)
+		buttons = append(buttons, tlv8(tagButtonType, uint16Bytes(uint16(btn.Type)))...)
+	}
+
+	b := tlv8(tagSupportedMaxTargets, []byte{c.MaxTargets})
+	b = append(b, tlv8(tagSupportedTicksPerSecond, uint64Bytes(ticksPerSecond))...)
+	b = append(b, tlv8(tagSupportedButtons, buttons)...)
+	b = append(b, tlv8(tagSupportedType, []byte{remoteTypeHardware})...)
+
+	return b
+}
+
+// TargetButton is a button which is supported by a target.
+type TargetButton struct {
+	ID   uint8
+	Type ButtonType
+	Name string
+}
+
+// Target is a device (e.g. an Apple TV) which is controlled by the remote.
+// Targets are configured by the controller.
+type Target struct {
+	Identifier uint32
+	Name       string
+	Category   TargetCategory
+	Buttons    []TargetButton
+}
+
+// tlv8 returns the target configuration.
+func (t Target) tlv8() []byte {
+	var buttons [][]byte
+	for _, btn := range t.Buttons {
+		b := tlv8(tagButtonID, []byte{btn.ID})
+		b = append(b, tlv8(tagButtonType, uint16Bytes(uint16(btn.Type)))...)
+		if len(btn.Name) > 0 {
+			b = append(b, tlv8(tagButtonName, []byte(btn.Name))...)
+		}
+		buttons = append(buttons, b)
+	}
+
+	b := tlv8(tagTargetIdentifier, uint32Bytes(t.Identifier))
+	if len(t.Name) > 0 {
+		b = append(b, tlv8(tagTargetName, []byte(t.Name))...)
+	}
+	b = append(b, tlv8(tagTargetCategory, uint16Bytes(uint16(t.Category)))...)
+	if len(buttons) > 0 {
+		b = append(b, tlv8List(tagTargetButtons, buttons)...)
+	}
+
+	return b
+}
+
+// parseTarget returns the target of a target configuration.
+// The name, category and buttons are optional for some operations.
+func parseTarget(b []byte) (Target, error) {
+	var t Target
+	c, err := tlv8Container(b)
+	if err != nil {
+		return t, err
+	}
+
+	t.Identifier = uint32(uintFromBytes(c.GetBytes(tagTargetIdentifier)))
+	t.Name = c.GetString(tagTargetName)
+	t.Category = TargetCategory(uintFromBytes(c.GetBytes(tagTargetCategory)))
+
+	for _, v := range tlv8Values(tagTargetButtons, b) {
+		btn, err := tlv8Container(v)
+		if err != nil {
+			return t, err
+		}
+
+		t.Buttons = append(t.Buttons, TargetButton{
+			ID:   btn.GetByte(tagButtonID),
+			Type: ButtonType(uintFromBytes(btn.GetBytes(tagButtonType))),
+			Name: btn.GetString(tagButtonName),
+		})
+	}
+
+	return t, nil
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	"github.com/brutella/hc/netio"
+	"github.com/brutella/log"
+)
+
+// Maximum sizes of request bodies, which protect small devices from
+// running out of memory because of misbehaving clients.
+const (
+	// MaxPairingRequestSize is the maximum size of /pair-setup, /pair-verify and /pairings requests.
+	// The largest TLV8 request (SRP public key and proof) is less than 500 bytes.
+	MaxPairingRequestSize = 4 * 1024
+
+	// MaxCharacteristicsRequestSize is the maximum size of PUT /characteristics requests.
+	MaxCharacteristicsRequestSize = 64 * 1024
+
+	// MaxSecureMessageRequestSize is the maximum size of /secure-message requests.
+	MaxSecureMessageRequestSize = 4 * 1024
+
+	// MaxIdentifyRequestSize is the maximum size of /identify requests, which have no body.
+	MaxIdentifyRequestSize = 1024
+)
+
+// limitHandler returns a handler which rejects requests, whose body is larger than
+// maxSize (413 Request Entity Too Large) or whose content type is not one of contentTypes
+// (415 Unsupported Media Type), before they are handled by h.
+// Requests without content type are accepted because some controllers don't send one.
+func limitHandler(h http.Handler, maxSize int64, contentTypes ...string) http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		if request.ContentLength > maxSize {
+			log.Printf("[WARN] %v %s %s request too large (%d bytes)\n", request.RemoteAddr, request.Method, request.URL.Path, request.ContentLength)
+			response.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		if ct := request.Header.Get("Content-Type"); len(ct) > 0 && len(contentTypes) > 0 && hasContentType(ct, contentTypes) == false {
+			log.Printf("[WARN] %v %s %s unsupported content type %s\n", request.RemoteAddr, request.Method, request.URL.Path, ct)
+			response.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+
+		// The content length is unknown for chunked requests
+		if request.Body != nil {
+			b, err := ioutil.ReadAll(http.MaxBytesReader(response, request.Body, maxSize))
+			if err != nil {
+				log.Printf("[WARN] %v %s %s request too large\n", request.RemoteAddr, request.Method, request.URL.Path)
+				response.WriteHeader(http.StatusRequestEntityTooLarge)
+				return
+			}
+			request.Body = ioutil.NopCloser(bytes.NewReader(b))
+		}
+
+		h.ServeHTTP(response, request)
+	})
+}
+
+// hasContentType returns true when the media type of ct is one of types.
+func hasContentType(ct string, types []string) bool {
+	mediaType, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return false
+	}
+
+	for _, t := range types {
+		if mediaType == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// contentTypesJSON are the accepted content types of requests with a JSON body
+var contentTypesJSON = []string{netio.HTTPContentTypeHAPJson, "application/json"}
+
+// contentTypesTLV8 are the accepted content types of requests with a TLV8 body
+var contentTypesTLV8 = []string{netio.HTTPContentTypePairingTLV8}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/brutella/hc/netio"
+)
+
+func newLimitTestHandler(t *testing.T) http.Handler {
+	return limitHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(b)
+	}), 8, contentTypesJSON...)
+}
+
+func TestLimitHandler(t *testing.T) {
+	h := newLimitTestHandler(t)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("PUT", "/characteristics", bytes.NewBufferString("{}"))
+	r.Header.Set("Content-Type", netio.HTTPContentTypeHAPJson+"; charset=utf-8")
+	h.ServeHTTP(w, r)
+
+	if is, want := w.Code, http.StatusOK; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := w.Body.String(), "{}"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestLimitHandlerTooLarge(t *testing.T) {
+	h := newLimitTestHandler(t)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("PUT", "/characteristics", bytes.NewBufferString(`{"characteristics":[]}`)))
+
+	if is, want := w.Code, http.StatusRequestEntityTooLarge; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestLimitHandlerChunkedTooLarge(t *testing.T) {
+	h := newLimitTestHandler(t)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("PUT", "/characteristics", bytes.NewBufferString(`{"characteristics":[]}`))
+	r.ContentLength = -1
+	h.ServeHTTP(w, r)
+
+	if is, want := w.Code, http.StatusRequestEntityTooLarge; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestLimitHandlerUnsupportedContentType(t *testing.T) {
+	h := newLimitTestHandler(t)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("PUT", "/characteristics", bytes.NewBufferString("{}"))
+	r.Header.Set("Content-Type", "text/plain")
+	h.ServeHTTP(w, r)
+
+	if is, want := w.Code, http.StatusUnsupportedMediaType; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,28 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/brutella/hc/netio"
+	"github.com/brutella/log"
+)
+
+// recoverHandler returns a handler which recovers from panics in h, e.g. in callbacks
+// of characteristics. The panic is logged with its stack trace and the request is
+// answered with a HAP error status, so that the connection stays open.
+func recoverHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		defer func() {
+			if v := recover(); v != nil {
+				log.Printf("[ERRO] Panic while handling %s %s: %v\n%s", request.Method, request.URL.Path, v, debug.Stack())
+				response.Header().Set("Content-Type", netio.HTTPContentTypeHAPJson)
+				response.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprintf(response, `{"status":%d}`, netio.StatusServiceCommunicationFailure)
+			}
+		}()
+
+		h.ServeHTTP(response, request)
+	})
+}
@@ -0,0 +1,24 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecoverHandler(t *testing.T) {
+	h := recoverHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("callback failed")
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("PUT", "/characteristics", nil))
+
+	if is, want := w.Code, http.StatusInternalServerError; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := w.Body.String(), `{"status":-70402}`; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"github.com/brutella/hc/accessory"
+	"github.com/brutella/hc/audit"
 	"github.com/brutella/hc/db"
 	"github.com/brutella/hc/event"
 	"github.com/brutella/hc/netio"
@@ -12,7 +13,9 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"strings"
 	"sync"
+	"time"
 )
 
 // Server provides a similar interfaces as http.Server to start and stop a TCP server.
@@ -35,13 +38,66 @@ type Config struct {
 	Device    netio.SecuredDevice
 	Mutex     *sync.Mutex
 	Emitter   event.Emitter
+
+	// TokenProvider provides the software token for MFi software authentication.
+	// When nil, pairing with authentication is not supported.
+	TokenProvider pair.TokenProvider
+
+	// PairSetupProgress is called when pair setup progresses. When nil, the progress is not reported.
+	PairSetupProgress pair.SetupProgressFunc
+
+	// SetupCodeProvider provides a new setup code for every pair setup.
+	// When nil, the pin or setup verifier of the device is used.
+	SetupCodeProvider pair.SetupCodeProvider
+
+	// PairingAvailable returns false when pair setup is not available. When nil, pair setup is always available.
+	PairingAvailable func() bool
+
+	// AuditLog records security-relevant operations. When nil, nothing is recorded.
+	AuditLog *audit.Log
+
+	// ListenAddress is the ip address on which the server listens, e.g. "192.168.1.2" or "::1".
+	// When empty, the server listens on all addresses.
+	ListenAddress string
+
+	// Interface is the name of the network interface on which the server accepts connections, e.g. "eth0".
+	// When empty, the server accepts connections on all interfaces.
+	Interface string
+
+	// CharacteristicTimeout is the deadline of the read and write callbacks of characteristics.
+	// When empty, there is no deadline.
+	CharacteristicTimeout time.Duration
+
+	// SlowCharacteristicThreshold is the duration after which callbacks of characteristics
+	// are reported as slow. When empty, slow callbacks are not reported.
+	SlowCharacteristicThreshold time.Duration
+
+	// SlowCharacteristic is called with the duration of slow callbacks.
+	SlowCharacteristic controller.SlowFunc
+
+	// WriteRetry is the policy of writes to connections which failed with a temporary error.
+	// When nil, netio.DefaultRetryPolicy is used.
+	WriteRetry *netio.RetryPolicy
+
+	// WriteTimeout is the deadline of writes to connections. When empty, writes have no deadline.
+	WriteTimeout time.Duration
+
+	// Listener is used to accept connections instead of listening on the port, e.g. a socket passed by systemd.
+	Listener *net.TCPListener
+
+	// Subscribed is called when a connection enables or disables events of a characteristic.
+	Subscribed func(conn net.Conn, aid, iid int64, enabled bool)
+
+	// ConnState is called when the state of a connection changes (see http.Server.ConnState),
+	// e.g. with http.StateIdle after a response was written.
+	ConnState func(conn net.Conn, state http.ConnState)
 }
 
 type hkServer struct {
 	context  netio.HAPContext
 	database db.Database
 	device   netio.SecuredDevice
-	mux      *http.ServeMux
+	mux      http.Handler
 
 	mutex     *sync.Mutex
 	container *accessory.Container
@@ -50,16 +106,23 @@ type hkServer struct {
 	listener    *net.TCPListener
 	hapListener *netio.HAPTCPListener
 
-	emitter event.Emitter
+	emitter   event.Emitter
+	connState func(net.Conn, http.ConnState)
 }
 
 // NewServer returns a server
 func NewServer(c Config) Server {
 
-	// os gives us a free Port when Port is ""
-	ln, err := net.Listen("tcp", c.Port)
-	if err != nil {
-		log.Fatal(err)
+	var ln net.Listener
+	if c.Listener != nil {
+		ln = c.Listener
+	} else {
+		// os gives us a free Port when Port is ""
+		var err error
+		ln, err = netio.Listen("tcp", net.JoinHostPort(c.ListenAddress, strings.TrimPrefix(c.Port, ":")), c.Interface)
+		if err != nil {
+			log.Fatal(err)
+		}
 	}
 
 	_, port, _ := net.SplitHostPort(ln.Addr().String())
@@ -69,14 +132,30 @@ func NewServer(c Config) Server {
 		database:  c.Database,
 		container: c.Container,
 		device:    c.Device,
-		mux:       http.NewServeMux(),
+		mux:       NewRouter(c),
 		mutex:     c.Mutex,
 		listener:  ln.(*net.TCPListener),
 		port:      port,
 		emitter:   c.Emitter,
+		connState: c.ConnState,
 	}
 
-	s.setupEndpoints()
+	// Use a HAPTCPListener
+	s.hapListener = netio.NewHAPTCPListener(s.listener, c.Context)
+	if c.WriteRetry != nil {
+		s.hapListener.Retry = *c.WriteRetry
+	}
+	s.hapListener.WriteTimeout = c.WriteTimeout
+	s.hapListener.AcceptFailed = func(err error, backoff time.Duration) {
+		log.Printf("[WARN] Accept failed (retry in %v): %v\n", backoff, err)
+		if c.Emitter != nil {
+			c.Emitter.Emit(event.AcceptFailed{
+				Err:                  err,
+				Backoff:              backoff,
+				OutOfFileDescriptors: netio.IsOutOfFileDescriptors(err),
+			})
+		}
+	}
 
 	return &s
 }
@@ -86,9 +165,8 @@ func (s *hkServer) ListenAndServe() error {
 }
 
 func (s *hkServer) Stop() {
-	for _, c := range s.context.ActiveConnections() {
-		c.Close()
-	}
+	s.context.Connections().CloseAll()
+
 	// Stop listener
 	s.hapListener.Close()
 }
@@ -99,27 +177,59 @@ func (s *hkServer) Port() string {
 
 // listenAndServe returns a http.Server to listen on a specific address
 func (s *hkServer) listenAndServe(addr string, handler http.Handler, context netio.HAPContext) error {
-	server := http.Server{Addr: addr, Handler: handler}
-	// Use a HAPTCPListener
-	listener := netio.NewHAPTCPListener(s.listener, context)
-	s.hapListener = listener
-	return server.Serve(listener)
+	server := http.Server{Addr: addr, Handler: handler, ConnContext: netio.WithConnection, ConnState: s.connState}
+	return server.Serve(s.hapListener)
 }
 
 func (s *hkServer) addrString() string {
 	return ":" + s.port
 }
 
-// setupEndpoints creates controller objects to handle HAP endpoints
-func (s *hkServer) setupEndpoints() {
-	containerController := controller.NewContainerController(s.container)
-	characteristicsController := controller.NewCharacteristicController(s.container)
-	pairingController := pair.NewPairingController(s.database)
-
-	s.mux.Handle("/pair-setup", endpoint.NewPairSetup(s.context, s.device, s.database, s.emitter))
-	s.mux.Handle("/pair-verify", endpoint.NewPairVerify(s.context, s.database))
-	s.mux.Handle("/accessories", endpoint.NewAccessories(containerController, s.mutex))
-	s.mux.Handle("/characteristics", endpoint.NewCharacteristics(s.context, characteristicsController, s.mutex))
-	s.mux.Handle("/pairings", endpoint.NewPairing(pairingController, s.emitter))
-	s.mux.Handle("/identify", endpoint.NewIdentify(containerController))
+// NewCharacteristicController returns the controller which handles reads and writes of characteristics.
+func NewCharacteristicController(c Config) *controller.CharacteristicController {
+	ctr := controller.NewCharacteristicController(c.Container)
+	ctr.AuditLog = c.AuditLog
+	ctr.Timeout = c.CharacteristicTimeout
+	ctr.SlowThreshold = c.SlowCharacteristicThreshold
+	ctr.Slow = c.SlowCharacteristic
+	ctr.IsSubscribed = c.Context.Connections().IsSubscribed
+	ctr.Subscribed = func(conn net.Conn, aid, iid int64, enabled bool) {
+		c.Context.Connections().SetSubscribed(conn, aid, iid, enabled)
+		if c.Subscribed != nil {
+			c.Subscribed(conn, aid, iid, enabled)
+		}
+	}
+
+	return ctr
+}
+
+// NewRouter returns a handler which routes requests to the HAP endpoints.
+// The handler doesn't depend on the transport and is shared by the TCP server
+// and other transports (e.g. CoAP). Requests are associated with a session
+// by the connection in the request context (see netio.WithConnection).
+func NewRouter(c Config) http.Handler {
+	containerController := controller.NewContainerController(c.Container)
+	characteristicsController := NewCharacteristicController(c)
+	pairingController := pair.NewPairingController(c.Database)
+
+	pairSetup := endpoint.NewPairSetup(c.Context, c.Device, c.Database, c.Emitter)
+	pairSetup.TokenProvider = c.TokenProvider
+	pairSetup.Progress = c.PairSetupProgress
+	pairSetup.Available = c.PairingAvailable
+	pairSetup.SetupCodes = c.SetupCodeProvider
+	pairSetup.AuditLog = c.AuditLog
+
+	pairings := endpoint.NewPairing(pairingController, c.Emitter)
+	pairings.AuditLog = c.AuditLog
+
+	mux := http.NewServeMux()
+	mux.Handle("/pair-setup", limitHandler(pairSetup, MaxPairingRequestSize, contentTypesTLV8...))
+	mux.Handle("/pair-verify", limitHandler(endpoint.NewPairVerify(c.Context, c.Database), MaxPairingRequestSize, contentTypesTLV8...))
+	mux.Handle("/accessories", endpoint.NewAccessories(containerController, c.Mutex))
+	mux.Handle("/characteristics", limitHandler(endpoint.NewCharacteristics(c.Context, characteristicsController, c.Mutex), MaxCharacteristicsRequestSize, contentTypesJSON...))
+	mux.Handle("/pairings", limitHandler(pairings, MaxPairingRequestSize, contentTypesTLV8...))
+	mux.Handle("/identify", limitHandler(endpoint.NewIdentify(containerController), MaxIdentifyRequestSize, contentTypesJSON...))
+	mux.Handle("/secure-message", limitHandler(endpoint.NewSecureMessage(c.Context, pair.NewTokenController(c.TokenProvider)), MaxSecureMessageRequestSize, netio.HTTPContentTypeOctetStream))
+
+	return recoverHandler(mux)
 }
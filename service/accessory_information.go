@@ -10,11 +10,12 @@ const TypeAccessoryInformation = "3E"
 type AccessoryInformation struct {
 	*Service
 
-	Identify     *characteristic.Identify
-	Manufacturer *characteristic.Manufacturer
-	Model        *characteristic.Model
-	Name         *characteristic.Name
-	SerialNumber *characteristic.SerialNumber
+	Identify         *characteristic.Identify
+	Manufacturer     *characteristic.Manufacturer
+	Model            *characteristic.Model
+	Name             *characteristic.Name
+	SerialNumber     *characteristic.SerialNumber
+	FirmwareRevision *characteristic.FirmwareRevision
 }
 
 func NewAccessoryInformation() *AccessoryInformation {
@@ -36,5 +37,8 @@ func NewAccessoryInformation() *AccessoryInformation {
 	svc.SerialNumber = characteristic.NewSerialNumber()
 	svc.AddCharacteristic(svc.SerialNumber.Characteristic)
 
+	svc.FirmwareRevision = characteristic.NewFirmwareRevision()
+	svc.AddCharacteristic(svc.FirmwareRevision.Characteristic)
+
 	return &svc
 }
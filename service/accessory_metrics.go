@@ -0,0 +1,24 @@
+// THIS FILE IS AUTO-GENERATED
+package service
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+const TypeAccessoryMetrics = "270"
+
+type AccessoryMetrics struct {
+	*Service
+
+	Active *characteristic.Active
+}
+
+func NewAccessoryMetrics() *AccessoryMetrics {
+	svc := AccessoryMetrics{}
+	svc.Service = New(TypeAccessoryMetrics)
+
+	svc.Active = characteristic.NewActive()
+	svc.AddCharacteristic(svc.Active.Characteristic)
+
+	return &svc
+}
@@ -0,0 +1,24 @@
+// THIS FILE IS AUTO-GENERATED
+package service
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+const TypeAccessoryRuntimeInformation = "239"
+
+type AccessoryRuntimeInformation struct {
+	*Service
+
+	Ping *characteristic.Ping
+}
+
+func NewAccessoryRuntimeInformation() *AccessoryRuntimeInformation {
+	svc := AccessoryRuntimeInformation{}
+	svc.Service = New(TypeAccessoryRuntimeInformation)
+
+	svc.Ping = characteristic.NewPing()
+	svc.AddCharacteristic(svc.Ping.Characteristic)
+
+	return &svc
+}
@@ -0,0 +1,28 @@
+// THIS FILE IS AUTO-GENERATED
+package service
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+const TypeAudioStreamManagement = "127"
+
+type AudioStreamManagement struct {
+	*Service
+
+	SupportedAudioStreamConfiguration *characteristic.SupportedAudioStreamConfiguration
+	SelectedAudioStreamConfiguration  *characteristic.SelectedAudioStreamConfiguration
+}
+
+func NewAudioStreamManagement() *AudioStreamManagement {
+	svc := AudioStreamManagement{}
+	svc.Service = New(TypeAudioStreamManagement)
+
+	svc.SupportedAudioStreamConfiguration = characteristic.NewSupportedAudioStreamConfiguration()
+	svc.AddCharacteristic(svc.SupportedAudioStreamConfiguration.Characteristic)
+
+	svc.SelectedAudioStreamConfiguration = characteristic.NewSelectedAudioStreamConfiguration()
+	svc.AddCharacteristic(svc.SelectedAudioStreamConfiguration.Characteristic)
+
+	return &svc
+}
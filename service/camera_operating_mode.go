@@ -0,0 +1,28 @@
+// THIS FILE IS AUTO-GENERATED
+package service
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+const TypeCameraOperatingMode = "21A"
+
+type CameraOperatingMode struct {
+	*Service
+
+	EventSnapshotsActive *characteristic.EventSnapshotsActive
+	HomeKitCameraActive  *characteristic.HomeKitCameraActive
+}
+
+func NewCameraOperatingMode() *CameraOperatingMode {
+	svc := CameraOperatingMode{}
+	svc.Service = New(TypeCameraOperatingMode)
+
+	svc.EventSnapshotsActive = characteristic.NewEventSnapshotsActive()
+	svc.AddCharacteristic(svc.EventSnapshotsActive.Characteristic)
+
+	svc.HomeKitCameraActive = characteristic.NewHomeKitCameraActive()
+	svc.AddCharacteristic(svc.HomeKitCameraActive.Characteristic)
+
+	return &svc
+}
@@ -0,0 +1,44 @@
+// THIS FILE IS AUTO-GENERATED
+package service
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+const TypeCameraRTPStreamManagement = "110"
+
+type CameraRTPStreamManagement struct {
+	*Service
+
+	SupportedVideoStreamConfiguration *characteristic.SupportedVideoStreamConfiguration
+	SupportedAudioStreamConfiguration *characteristic.SupportedAudioStreamConfiguration
+	SupportedRTPConfiguration         *characteristic.SupportedRTPConfiguration
+	SelectedRTPStreamConfiguration    *characteristic.SelectedRTPStreamConfiguration
+	SetupEndpoints                    *characteristic.SetupEndpoints
+	StreamingStatus                   *characteristic.StreamingStatus
+}
+
+func NewCameraRTPStreamManagement() *CameraRTPStreamManagement {
+	svc := CameraRTPStreamManagement{}
+	svc.Service = New(TypeCameraRTPStreamManagement)
+
+	svc.SupportedVideoStreamConfiguration = characteristic.NewSupportedVideoStreamConfiguration()
+	svc.AddCharacteristic(svc.SupportedVideoStreamConfiguration.Characteristic)
+
+	svc.SupportedAudioStreamConfiguration = characteristic.NewSupportedAudioStreamConfiguration()
+	svc.AddCharacteristic(svc.SupportedAudioStreamConfiguration.Characteristic)
+
+	svc.SupportedRTPConfiguration = characteristic.NewSupportedRTPConfiguration()
+	svc.AddCharacteristic(svc.SupportedRTPConfiguration.Characteristic)
+
+	svc.SelectedRTPStreamConfiguration = characteristic.NewSelectedRTPStreamConfiguration()
+	svc.AddCharacteristic(svc.SelectedRTPStreamConfiguration.Characteristic)
+
+	svc.SetupEndpoints = characteristic.NewSetupEndpoints()
+	svc.AddCharacteristic(svc.SetupEndpoints.Characteristic)
+
+	svc.StreamingStatus = characteristic.NewStreamingStatus()
+	svc.AddCharacteristic(svc.StreamingStatus.Characteristic)
+
+	return &svc
+}
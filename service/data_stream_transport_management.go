@@ -0,0 +1,32 @@
+// THIS FILE IS AUTO-GENERATED
+package service
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+const TypeDataStreamTransportManagement = "129"
+
+type DataStreamTransportManagement struct {
+	*Service
+
+	SupportedDataStreamTransportConfiguration *characteristic.SupportedDataStreamTransportConfiguration
+	SetupDataStreamTransport                  *characteristic.SetupDataStreamTransport
+	Version                                   *characteristic.Version
+}
+
+func NewDataStreamTransportManagement() *DataStreamTransportManagement {
+	svc := DataStreamTransportManagement{}
+	svc.Service = New(TypeDataStreamTransportManagement)
+
+	svc.SupportedDataStreamTransportConfiguration = characteristic.NewSupportedDataStreamTransportConfiguration()
+	svc.AddCharacteristic(svc.SupportedDataStreamTransportConfiguration.Characteristic)
+
+	svc.SetupDataStreamTransport = characteristic.NewSetupDataStreamTransport()
+	svc.AddCharacteristic(svc.SetupDataStreamTransport.Characteristic)
+
+	svc.Version = characteristic.NewVersion()
+	svc.AddCharacteristic(svc.Version.Characteristic)
+
+	return &svc
+}
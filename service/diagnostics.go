@@ -0,0 +1,24 @@
+// THIS FILE IS AUTO-GENERATED
+package service
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+const TypeDiagnostics = "237"
+
+type Diagnostics struct {
+	*Service
+
+	SupportedDiagnosticsSnapshot *characteristic.SupportedDiagnosticsSnapshot
+}
+
+func NewDiagnostics() *Diagnostics {
+	svc := Diagnostics{}
+	svc.Service = New(TypeDiagnostics)
+
+	svc.SupportedDiagnosticsSnapshot = characteristic.NewSupportedDiagnosticsSnapshot()
+	svc.AddCharacteristic(svc.SupportedDiagnosticsSnapshot.Characteristic)
+
+	return &svc
+}
@@ -0,0 +1,28 @@
+// THIS FILE IS AUTO-GENERATED
+package service
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+const TypeFirmwareUpdate = "236"
+
+type FirmwareUpdate struct {
+	*Service
+
+	FirmwareUpdateReadiness *characteristic.FirmwareUpdateReadiness
+	FirmwareUpdateStatus    *characteristic.FirmwareUpdateStatus
+}
+
+func NewFirmwareUpdate() *FirmwareUpdate {
+	svc := FirmwareUpdate{}
+	svc.Service = New(TypeFirmwareUpdate)
+
+	svc.FirmwareUpdateReadiness = characteristic.NewFirmwareUpdateReadiness()
+	svc.AddCharacteristic(svc.FirmwareUpdateReadiness.Characteristic)
+
+	svc.FirmwareUpdateStatus = characteristic.NewFirmwareUpdateStatus()
+	svc.AddCharacteristic(svc.FirmwareUpdateStatus.Characteristic)
+
+	return &svc
+}
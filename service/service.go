@@ -1,6 +1,8 @@
 package service
 
 import (
+	"encoding/json"
+
 	"github.com/brutella/hc/characteristic"
 )
 
@@ -9,6 +11,20 @@ type Service struct {
 	ID              int64                            `json:"iid"`
 	Type            string                           `json:"type"`
 	Characteristics []*characteristic.Characteristic `json:"characteristics"`
+
+	// Primary is true for the main service of the accessory, which is
+	// used by clients to choose the icon of the accessory.
+	Primary bool `json:"primary,omitempty"`
+
+	// Hidden is true for services which should not be visible to the user,
+	// e.g. services which are only used for configuration.
+	Hidden bool `json:"hidden,omitempty"`
+
+	// Linked contains the ids of linked services.
+	// The value is set when the service is encoded as json.
+	Linked []int64 `json:"linked,omitempty"`
+
+	linked []*Service
 }
 
 // New returns a new service.
@@ -31,6 +47,17 @@ func (s *Service) GetID() int64 {
 	return s.ID
 }
 
+// SetPrimary marks the service as the primary service of the accessory.
+// An accessory should have only one primary service.
+func (s *Service) SetPrimary(primary bool) {
+	s.Primary = primary
+}
+
+// SetHidden hides the service from the user.
+func (s *Service) SetHidden(hidden bool) {
+	s.Hidden = hidden
+}
+
 // GetCharacteristics returns the characteristics which represent the service.
 func (s *Service) GetCharacteristics() []*characteristic.Characteristic {
 	var result []*characteristic.Characteristic
@@ -40,6 +67,18 @@ func (s *Service) GetCharacteristics() []*characteristic.Characteristic {
 	return result
 }
 
+// CharacteristicByType returns the first characteristic of type typ (e.g. characteristic.TypeOn),
+// or nil if there is none.
+func (s *Service) CharacteristicByType(typ string) *characteristic.Characteristic {
+	for _, c := range s.Characteristics {
+		if c.Type == typ {
+			return c
+		}
+	}
+
+	return nil
+}
+
 // Equal returns true when receiver has the same characteristics, service id and service type as the argument.
 func (s *Service) Equal(other interface{}) bool {
 	if service, ok := other.(*Service); ok == true {
@@ -64,3 +103,43 @@ func (s *Service) Equal(other interface{}) bool {
 func (s *Service) AddCharacteristic(c *characteristic.Characteristic) {
 	s.Characteristics = append(s.Characteristics, c)
 }
+
+// SetLocalizedName sets the name of the service for a locale, e.g. "de".
+// The service must have a Name characteristic.
+func (s *Service) SetLocalizedName(locale, name string) {
+	if c := s.CharacteristicByType(characteristic.TypeName); c != nil {
+		c.SetLocalizedValue(locale, name)
+	}
+}
+
+// AddLinkedService links other to the service, e.g. an input source to a television.
+// Both services must be part of the same accessory.
+func (s *Service) AddLinkedService(other *Service) {
+	s.linked = append(s.linked, other)
+}
+
+// LinkedServices returns the linked services.
+func (s *Service) LinkedServices() []*Service {
+	var result []*Service
+	for _, l := range s.linked {
+		result = append(result, l)
+	}
+	return result
+}
+
+// MarshalJSON returns the json of the service with the ids of the linked services.
+// The ids are resolved when encoding because they are set when the services are added to an accessory.
+func (s *Service) MarshalJSON() ([]byte, error) {
+	// Use an alias to avoid recursive calls of this method
+	type alias Service
+	a := alias(*s)
+
+	if len(s.linked) > 0 {
+		a.Linked = []int64{}
+		for _, l := range s.linked {
+			a.Linked = append(a.Linked, l.ID)
+		}
+	}
+
+	return json.Marshal(a)
+}
@@ -0,0 +1,24 @@
+// THIS FILE IS AUTO-GENERATED
+package service
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+const TypeSiri = "133"
+
+type Siri struct {
+	*Service
+
+	SiriInputType *characteristic.SiriInputType
+}
+
+func NewSiri() *Siri {
+	svc := Siri{}
+	svc.Service = New(TypeSiri)
+
+	svc.SiriInputType = characteristic.NewSiriInputType()
+	svc.AddCharacteristic(svc.SiriInputType.Characteristic)
+
+	return &svc
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Spec describes the characteristics of a service type.
+type Spec struct {
+	Name     string
+	Required []CharacteristicSpec
+	Optional []CharacteristicSpec
+}
+
+// CharacteristicSpec describes a characteristic of a service type.
+type CharacteristicSpec struct {
+	Type string
+	Name string
+}
+
+// SpecForType returns the spec of the service type typ (e.g. TypeLightbulb).
+// The second return value is false for unknown types, e.g. vendor services.
+func SpecForType(typ string) (Spec, bool) {
+	spec, ok := specs[typ]
+	return spec, ok
+}
+
+// IsOptional returns true when the characteristic type typ is an optional characteristic of the spec.
+func (spec Spec) IsOptional(typ string) bool {
+	for _, c := range spec.Optional {
+		if c.Type == typ {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate returns an error when s misses required characteristics, or contains
+// characteristics which are not defined for its type or more than once.
+// Services of unknown types are not validated.
+func Validate(s *Service) error {
+	spec, ok := SpecForType(s.Type)
+	if ok == false {
+		return nil
+	}
+
+	count := map[string]int{}
+	for _, c := range s.Characteristics {
+		count[c.Type]++
+	}
+
+	var problems []string
+	if missing := missingCharacteristics(s, spec); len(missing) > 0 {
+		problems = append(problems, "missing required characteristics "+strings.Join(missing, ", "))
+	}
+
+	var duplicates []string
+	for _, c := range append(spec.Required, spec.Optional...) {
+		if count[c.Type] > 1 {
+			duplicates = append(duplicates, c.Name)
+		}
+		delete(count, c.Type)
+	}
+	if len(duplicates) > 0 {
+		problems = append(problems, "duplicate characteristics "+strings.Join(duplicates, ", "))
+	}
+
+	var unknown []string
+	for _, c := range s.Characteristics {
+		if _, ok := count[c.Type]; ok == true {
+			unknown = append(unknown, characteristicName(c.Type))
+			delete(count, c.Type)
+		}
+	}
+	if len(unknown) > 0 {
+		problems = append(problems, "characteristics which are not defined for the service: "+strings.Join(unknown, ", "))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s service: %s", spec.Name, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// ValidateRequired returns an error when s misses required characteristics.
+// Unlike Validate, additional characteristics (e.g. vendor characteristics) are allowed.
+func ValidateRequired(s *Service) error {
+	spec, ok := SpecForType(s.Type)
+	if ok == false {
+		return nil
+	}
+
+	if missing := missingCharacteristics(s, spec); len(missing) > 0 {
+		return fmt.Errorf("%s service: missing required characteristics %s", spec.Name, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// missingCharacteristics returns the names of the required characteristics of spec, which s doesn't contain.
+func missingCharacteristics(s *Service, spec Spec) []string {
+	var missing []string
+	for _, c := range spec.Required {
+		if s.CharacteristicByType(c.Type) == nil {
+			missing = append(missing, c.Name)
+		}
+	}
+
+	return missing
+}
+
+// characteristicName returns the name of the characteristic type typ, which is
+// looked up in the specs of all services.
+func characteristicName(typ string) string {
+	for _, spec := range specs {
+		for _, c := range append(spec.Required, spec.Optional...) {
+			if c.Type == typ {
+				return c.Name
+			}
+		}
+	}
+
+	return "type " + typ
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/brutella/hc/characteristic"
+)
+
+func TestValidateGeneratedServices(t *testing.T) {
+	for _, s := range []*Service{NewLightbulb().Service, NewThermostat().Service, NewAccessoryInformation().Service} {
+		if err := Validate(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := New(TypeLightbulb)
+	s.AddCharacteristic(characteristic.NewBrightness().Characteristic)
+	s.AddCharacteristic(characteristic.NewBrightness().Characteristic)
+	s.AddCharacteristic(characteristic.NewCurrentTemperature().Characteristic)
+
+	err := Validate(s)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	want := "Lightbulb service: missing required characteristics On; duplicate characteristics Brightness; characteristics which are not defined for the service: Current Temperature"
+	if is := err.Error(); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestValidateUnknownType(t *testing.T) {
+	s := New("E863F007-079E-48FF-8F27-9C2605A29F52")
+	s.AddCharacteristic(characteristic.NewOn().Characteristic)
+
+	if err := Validate(s); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,592 @@
+// THIS FILE IS AUTO-GENERATED
+package service
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+var specs = map[string]Spec{
+	TypeAccessoryInformation: Spec{
+		Name: "Accessory Information",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeIdentify, "Identify"},
+			{characteristic.TypeManufacturer, "Manufacturer"},
+			{characteristic.TypeModel, "Model"},
+			{characteristic.TypeName, "Name"},
+			{characteristic.TypeSerialNumber, "Serial Number"},
+			{characteristic.TypeFirmwareRevision, "Firmware Revision"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeHardwareRevision, "Hardware Revision"},
+			{characteristic.TypeSoftwareRevision, "Software Revision"},
+			{characteristic.TypeHardwareFinish, "Hardware Finish"},
+			{characteristic.TypeConfiguredName, "Configured Name"},
+		},
+	},
+	TypeAccessoryMetrics: Spec{
+		Name: "Accessory Metrics",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeActive, "Active"},
+		},
+		Optional: []CharacteristicSpec{},
+	},
+	TypeAccessoryRuntimeInformation: Spec{
+		Name: "Accessory Runtime Information",
+		Required: []CharacteristicSpec{
+			{characteristic.TypePing, "Ping"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeActivityInterval, "Activity Interval"},
+			{characteristic.TypeHeartBeat, "Heart Beat"},
+			{characteristic.TypeSleepInterval, "Sleep Interval"},
+		},
+	},
+	TypeAirQualitySensor: Spec{
+		Name: "Air Quality Sensor",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeAirQuality, "Air Quality"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeAirParticulateDensity, "Air Particulate Density"},
+			{characteristic.TypeAirParticulateSize, "Air Particulate Size"},
+			{characteristic.TypeStatusActive, "Status Active"},
+			{characteristic.TypeStatusFault, "Status Fault"},
+			{characteristic.TypeStatusTampered, "Status Tampered"},
+			{characteristic.TypeStatusLowBattery, "Status Low Battery"},
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeAudioStreamManagement: Spec{
+		Name: "Audio Stream Management",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeSupportedAudioStreamConfiguration, "Supported Audio Stream Configuration"},
+			{characteristic.TypeSelectedAudioStreamConfiguration, "Selected Audio Stream Configuration"},
+		},
+		Optional: []CharacteristicSpec{},
+	},
+	TypeBatteryService: Spec{
+		Name: "Battery Service",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeBatteryLevel, "Battery Level"},
+			{characteristic.TypeChargingState, "Charging State"},
+			{characteristic.TypeStatusLowBattery, "Status Low Battery"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeBridgeConfiguration: Spec{
+		Name: "Bridge Configuration",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeConfigureBridgedAccessoryStatus, "Configure Bridged Accessory Status"},
+			{characteristic.TypeDiscoverBridgedAccessories, "Discover Bridged Accessories"},
+			{characteristic.TypeDiscoveredBridgedAccessories, "Discovered Bridged Accessories"},
+			{characteristic.TypeConfigureBridgedAccessory, "Configure Bridged Accessory"},
+		},
+		Optional: []CharacteristicSpec{},
+	},
+	TypeBridgingState: Spec{
+		Name: "Bridging State",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeReachable, "Reachable"},
+			{characteristic.TypeLinkQuality, "Link Quality"},
+			{characteristic.TypeAccessoryIdentifier, "Accessory Identifier"},
+			{characteristic.TypeCategory, "Category"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeCameraOperatingMode: Spec{
+		Name: "Camera Operating Mode",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeEventSnapshotsActive, "Event Snapshots Active"},
+			{characteristic.TypeHomeKitCameraActive, "HomeKit Camera Active"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeCameraOperatingModeIndicator, "Camera Operating Mode Indicator"},
+			{characteristic.TypePeriodicSnapshotsActive, "Periodic Snapshots Active"},
+			{characteristic.TypeManuallyDisabled, "Manually Disabled"},
+			{characteristic.TypeThirdPartyCameraActive, "Third Party Camera Active"},
+		},
+	},
+	TypeCameraRTPStreamManagement: Spec{
+		Name: "Camera RTP Stream Management",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeSupportedVideoStreamConfiguration, "Supported Video Stream Configuration"},
+			{characteristic.TypeSupportedAudioStreamConfiguration, "Supported Audio Stream Configuration"},
+			{characteristic.TypeSupportedRTPConfiguration, "Supported RTP Configuration"},
+			{characteristic.TypeSelectedRTPStreamConfiguration, "Selected RTP Stream Configuration"},
+			{characteristic.TypeSetupEndpoints, "Setup Endpoints"},
+			{characteristic.TypeStreamingStatus, "Streaming Status"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeActive, "Active"},
+		},
+	},
+	TypeCameraRecordingManagement: Spec{
+		Name: "Camera Recording Management",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeActive, "Active"},
+			{characteristic.TypeSupportedCameraRecordingConfiguration, "Supported Camera Recording Configuration"},
+			{characteristic.TypeSupportedVideoRecordingConfiguration, "Supported Video Recording Configuration"},
+			{characteristic.TypeSupportedAudioRecordingConfiguration, "Supported Audio Recording Configuration"},
+			{characteristic.TypeSelectedCameraRecordingConfiguration, "Selected Camera Recording Configuration"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeRecordingAudioActive, "Recording Audio Active"},
+		},
+	},
+	TypeCarbonDioxideSensor: Spec{
+		Name: "Carbon Dioxide Sensor",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeCarbonDioxideDetected, "Carbon Dioxide Detected"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeStatusActive, "Status Active"},
+			{characteristic.TypeStatusFault, "Status Fault"},
+			{characteristic.TypeStatusLowBattery, "Status Low Battery"},
+			{characteristic.TypeStatusTampered, "Status Tampered"},
+			{characteristic.TypeCarbonDioxideLevel, "Carbon Dioxide Level"},
+			{characteristic.TypeCarbonDioxidePeakLevel, "Carbon Dioxide Peak Level"},
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeCarbonMonoxideSensor: Spec{
+		Name: "Carbon Monoxide Sensor",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeCarbonMonoxideDetected, "Carbon Monoxide Detected"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeStatusActive, "Status Active"},
+			{characteristic.TypeStatusFault, "Status Fault"},
+			{characteristic.TypeStatusLowBattery, "Status Low Battery"},
+			{characteristic.TypeStatusTampered, "Status Tampered"},
+			{characteristic.TypeCarbonMonoxideLevel, "Carbon Monoxide Level"},
+			{characteristic.TypeCarbonMonoxidePeakLevel, "Carbon Monoxide Peak Level"},
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeContactSensor: Spec{
+		Name: "Contact Sensor",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeContactSensorState, "Contact Sensor State"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeStatusActive, "Status Active"},
+			{characteristic.TypeStatusFault, "Status Fault"},
+			{characteristic.TypeStatusTampered, "Status Tampered"},
+			{characteristic.TypeStatusLowBattery, "Status Low Battery"},
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeDataStreamTransportManagement: Spec{
+		Name: "Data Stream Transport Management",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeSupportedDataStreamTransportConfiguration, "Supported Data Stream Transport Configuration"},
+			{characteristic.TypeSetupDataStreamTransport, "Setup Data Stream Transport"},
+			{characteristic.TypeVersion, "Version"},
+		},
+		Optional: []CharacteristicSpec{},
+	},
+	TypeDiagnostics: Spec{
+		Name: "Diagnostics",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeSupportedDiagnosticsSnapshot, "Supported Diagnostics Snapshot"},
+		},
+		Optional: []CharacteristicSpec{},
+	},
+	TypeDoor: Spec{
+		Name: "Door",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeCurrentPosition, "Current Position"},
+			{characteristic.TypePositionState, "Position State"},
+			{characteristic.TypeTargetPosition, "Target Position"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeHoldPosition, "Hold Position"},
+			{characteristic.TypeObstructionDetected, "Obstruction Detected"},
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeFan: Spec{
+		Name: "Fan",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeOn, "On"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeRotationDirection, "Rotation Direction"},
+			{characteristic.TypeRotationSpeed, "Rotation Speed"},
+			{characteristic.TypeName, "Name"},
+			{characteristic.TypeConfiguredName, "Configured Name"},
+		},
+	},
+	TypeFirmwareUpdate: Spec{
+		Name: "Firmware Update",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeFirmwareUpdateReadiness, "Firmware Update Readiness"},
+			{characteristic.TypeFirmwareUpdateStatus, "Firmware Update Status"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeStagedFirmwareVersion, "Staged Firmware Version"},
+		},
+	},
+	TypeGarageDoorOpener: Spec{
+		Name: "Garage Door Opener",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeCurrentDoorState, "Current Door State"},
+			{characteristic.TypeTargetDoorState, "Target Door State"},
+			{characteristic.TypeObstructionDetected, "Obstruction Detected"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeLockCurrentState, "Lock Current State"},
+			{characteristic.TypeLockTargetState, "Lock Target State"},
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeHumiditySensor: Spec{
+		Name: "Humidity Sensor",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeCurrentRelativeHumidity, "Current Relative Humidity"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeStatusActive, "Status Active"},
+			{characteristic.TypeStatusFault, "Status Fault"},
+			{characteristic.TypeStatusTampered, "Status Tampered"},
+			{characteristic.TypeStatusLowBattery, "Status Low Battery"},
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeLeakSensor: Spec{
+		Name: "Leak Sensor",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeLeakDetected, "Leak Detected"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeStatusActive, "Status Active"},
+			{characteristic.TypeStatusFault, "Status Fault"},
+			{characteristic.TypeStatusTampered, "Status Tampered"},
+			{characteristic.TypeStatusLowBattery, "Status Low Battery"},
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeLightSensor: Spec{
+		Name: "Light Sensor",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeCurrentAmbientLightLevel, "Current Ambient Light Level"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeStatusActive, "Status Active"},
+			{characteristic.TypeStatusFault, "Status Fault"},
+			{characteristic.TypeStatusTampered, "Status Tampered"},
+			{characteristic.TypeStatusLowBattery, "Status Low Battery"},
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeLightbulb: Spec{
+		Name: "Lightbulb",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeOn, "On"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeBrightness, "Brightness"},
+			{characteristic.TypeHue, "Hue"},
+			{characteristic.TypeSaturation, "Saturation"},
+			{characteristic.TypeName, "Name"},
+			{characteristic.TypeConfiguredName, "Configured Name"},
+		},
+	},
+	TypeLockManagement: Spec{
+		Name: "Lock Management",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeLockControlPoint, "Lock Control Point"},
+			{characteristic.TypeVersion, "Version"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeLogs, "Logs"},
+			{characteristic.TypeAudioFeedback, "Audio Feedback"},
+			{characteristic.TypeLockManagementAutoSecurityTimeout, "Lock Management Auto Security Timeout"},
+			{characteristic.TypeAdministratorOnlyAccess, "Administrator Only Access"},
+			{characteristic.TypeLockLastKnownAction, "Lock Last Known Action"},
+			{characteristic.TypeCurrentDoorState, "Current Door State"},
+			{characteristic.TypeMotionDetected, "Motion Detected"},
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeLockMechanism: Spec{
+		Name: "Lock Mechanism",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeLockCurrentState, "Lock Current State"},
+			{characteristic.TypeLockTargetState, "Lock Target State"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeMicrophone: Spec{
+		Name: "Microphone",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeMute, "Mute"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeVolume, "Volume"},
+		},
+	},
+	TypeMotionSensor: Spec{
+		Name: "Motion Sensor",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeMotionDetected, "Motion Detected"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeStatusActive, "Status Active"},
+			{characteristic.TypeStatusFault, "Status Fault"},
+			{characteristic.TypeStatusTampered, "Status Tampered"},
+			{characteristic.TypeStatusLowBattery, "Status Low Battery"},
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeOccupancySensor: Spec{
+		Name: "Occupancy Sensor",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeOccupancyDetected, "Occupancy Detected"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeStatusActive, "Status Active"},
+			{characteristic.TypeStatusFault, "Status Fault"},
+			{characteristic.TypeStatusTampered, "Status Tampered"},
+			{characteristic.TypeStatusLowBattery, "Status Low Battery"},
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeOutlet: Spec{
+		Name: "Outlet",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeOn, "On"},
+			{characteristic.TypeOutletInUse, "Outlet In Use"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeName, "Name"},
+			{characteristic.TypeServiceLabelIndex, "Service Label Index"},
+			{characteristic.TypeConfiguredName, "Configured Name"},
+		},
+	},
+	TypeSecuritySystem: Spec{
+		Name: "Security System",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeSecuritySystemCurrentState, "Security System Current State"},
+			{characteristic.TypeSecuritySystemTargetState, "Security System Target State"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeStatusFault, "Status Fault"},
+			{characteristic.TypeStatusTampered, "Status Tampered"},
+			{characteristic.TypeSecuritySystemAlarmType, "Security System Alarm Type"},
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeServiceLabel: Spec{
+		Name: "Service Label",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeServiceLabelNamespace, "Service Label Namespace"},
+		},
+		Optional: []CharacteristicSpec{},
+	},
+	TypeSiri: Spec{
+		Name: "Siri",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeSiriInputType, "Siri Input Type"},
+		},
+		Optional: []CharacteristicSpec{},
+	},
+	TypeSmokeSensor: Spec{
+		Name: "Smoke Sensor",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeSmokeDetected, "Smoke Detected"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeStatusActive, "Status Active"},
+			{characteristic.TypeStatusFault, "Status Fault"},
+			{characteristic.TypeStatusTampered, "Status Tampered"},
+			{characteristic.TypeStatusLowBattery, "Status Low Battery"},
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeSpeaker: Spec{
+		Name: "Speaker",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeMute, "Mute"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeVolume, "Volume"},
+		},
+	},
+	TypeStatefulProgrammableSwitch: Spec{
+		Name: "Stateful Programmable Switch",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeProgrammableSwitchEvent, "Programmable Switch Event"},
+			{characteristic.TypeProgrammableSwitchOutputState, "Programmable Switch Output State"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeStatelessProgrammableSwitch: Spec{
+		Name: "Stateless Programmable Switch",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeProgrammableSwitchEvent, "Programmable Switch Event"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeName, "Name"},
+			{characteristic.TypeServiceLabelIndex, "Service Label Index"},
+			{characteristic.TypeConfiguredName, "Configured Name"},
+		},
+	},
+	TypeSwitch: Spec{
+		Name: "Switch",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeOn, "On"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeName, "Name"},
+			{characteristic.TypeServiceLabelIndex, "Service Label Index"},
+			{characteristic.TypeConfiguredName, "Configured Name"},
+		},
+	},
+	TypeTargetControl: Spec{
+		Name: "Target Control",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeActiveIdentifier, "Active Identifier"},
+			{characteristic.TypeActive, "Active"},
+			{characteristic.TypeButtonEvent, "Button Event"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeTargetControlManagement: Spec{
+		Name: "Target Control Management",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeTargetControlSupportedConfiguration, "Target Control Supported Configuration"},
+			{characteristic.TypeTargetControlList, "Target Control List"},
+		},
+		Optional: []CharacteristicSpec{},
+	},
+	TypeTemperatureSensor: Spec{
+		Name: "Temperature Sensor",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeCurrentTemperature, "Current Temperature"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeStatusActive, "Status Active"},
+			{characteristic.TypeStatusFault, "Status Fault"},
+			{characteristic.TypeStatusLowBattery, "Status Low Battery"},
+			{characteristic.TypeStatusTampered, "Status Tampered"},
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeThermostat: Spec{
+		Name: "Thermostat",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeCurrentHeatingCoolingState, "Current Heating Cooling State"},
+			{characteristic.TypeTargetHeatingCoolingState, "Target Heating Cooling State"},
+			{characteristic.TypeCurrentTemperature, "Current Temperature"},
+			{characteristic.TypeTargetTemperature, "Target Temperature"},
+			{characteristic.TypeTemperatureDisplayUnits, "Temperature Display Units"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeCurrentRelativeHumidity, "Current Relative Humidity"},
+			{characteristic.TypeTargetRelativeHumidity, "Target Relative Humidity"},
+			{characteristic.TypeCoolingThresholdTemperature, "Cooling Threshold Temperature"},
+			{characteristic.TypeHeatingThresholdTemperature, "Heating Threshold Temperature"},
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeThreadTransport: Spec{
+		Name: "Thread Transport",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeCurrentTransport, "Current Transport"},
+			{characteristic.TypeThreadControlPoint, "Thread Control Point"},
+			{characteristic.TypeThreadNodeCapabilities, "Thread Node Capabilities"},
+			{characteristic.TypeThreadStatus, "Thread Status"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeThreadOpenThreadVersion, "Thread OpenThread Version"},
+		},
+	},
+	TypeTimeInformation: Spec{
+		Name: "Time Information",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeCurrentTime, "Current Time"},
+			{characteristic.TypeDayOfTheWeek, "Day of the Week"},
+			{characteristic.TypeTimeUpdate, "Time Update"},
+		},
+		Optional: []CharacteristicSpec{},
+	},
+	TypeTunneledBTLEAccessoryService: Spec{
+		Name: "Tunneled BTLE Accessory Service",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeName, "Name"},
+			{characteristic.TypeAccessoryIdentifier, "Accessory Identifier"},
+			{characteristic.TypeTunneledAccessoryStateNumber, "Tunneled Accessory State Number"},
+			{characteristic.TypeTunneledAccessoryConnected, "Tunneled Accessory Connected"},
+			{characteristic.TypeTunneledAccessoryAdvertising, "Tunneled Accessory Advertising"},
+			{characteristic.TypeTunnelConnectionTimeout, "Tunnel Connection Timeout "},
+		},
+		Optional: []CharacteristicSpec{},
+	},
+	TypeValve: Spec{
+		Name: "Valve",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeActive, "Active"},
+			{characteristic.TypeInUse, "In Use"},
+			{characteristic.TypeValveType, "Valve Type"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeSetDuration, "Set Duration"},
+			{characteristic.TypeRemainingDuration, "Remaining Duration"},
+			{characteristic.TypeIsConfigured, "Is Configured"},
+			{characteristic.TypeStatusFault, "Status Fault"},
+			{characteristic.TypeName, "Name"},
+			{characteristic.TypeConfiguredName, "Configured Name"},
+		},
+	},
+	TypeWiFiTransport: Spec{
+		Name: "Wi-Fi Transport",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeCurrentTransport, "Current Transport"},
+			{characteristic.TypeWiFiCapabilities, "Wi-Fi Capabilities"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeWiFiConfigurationControl, "Wi-Fi Configuration Control"},
+		},
+	},
+	TypeWindow: Spec{
+		Name: "Window",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeCurrentPosition, "Current Position"},
+			{characteristic.TypeTargetPosition, "Target Position"},
+			{characteristic.TypePositionState, "Position State"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeHoldPosition, "Hold Position"},
+			{characteristic.TypeObstructionDetected, "Obstruction Detected"},
+			{characteristic.TypeName, "Name"},
+		},
+	},
+	TypeWindowCovering: Spec{
+		Name: "Window Covering",
+		Required: []CharacteristicSpec{
+			{characteristic.TypeCurrentPosition, "Current Position"},
+			{characteristic.TypeTargetPosition, "Target Position"},
+			{characteristic.TypePositionState, "Position State"},
+		},
+		Optional: []CharacteristicSpec{
+			{characteristic.TypeHoldPosition, "Hold Position"},
+			{characteristic.TypeTargetHorizontalTiltAngle, "Target Horizontal Tilt Angle"},
+			{characteristic.TypeTargetVerticalTiltAngle, "Target Vertical Tilt Angle"},
+			{characteristic.TypeCurrentHorizontalTiltAngle, "Current Horizontal Tilt Angle"},
+			{characteristic.TypeCurrentVerticalTiltAngle, "Current Vertical Tilt Angle"},
+			{characteristic.TypeObstructionDetected, "Obstruction Detected"},
+			{characteristic.TypeName, "Name"},
+		},
+	},
+}
@@ -0,0 +1,32 @@
+// THIS FILE IS AUTO-GENERATED
+package service
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+const TypeTargetControl = "125"
+
+type TargetControl struct {
+	*Service
+
+	ActiveIdentifier *characteristic.ActiveIdentifier
+	Active           *characteristic.Active
+	ButtonEvent      *characteristic.ButtonEvent
+}
+
+func NewTargetControl() *TargetControl {
+	svc := TargetControl{}
+	svc.Service = New(TypeTargetControl)
+
+	svc.ActiveIdentifier = characteristic.NewActiveIdentifier()
+	svc.AddCharacteristic(svc.ActiveIdentifier.Characteristic)
+
+	svc.Active = characteristic.NewActive()
+	svc.AddCharacteristic(svc.Active.Characteristic)
+
+	svc.ButtonEvent = characteristic.NewButtonEvent()
+	svc.AddCharacteristic(svc.ButtonEvent.Characteristic)
+
+	return &svc
+}
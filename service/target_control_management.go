@@ -0,0 +1,28 @@
+// THIS FILE IS AUTO-GENERATED
+package service
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+const TypeTargetControlManagement = "122"
+
+type TargetControlManagement struct {
+	*Service
+
+	TargetControlSupportedConfiguration *characteristic.TargetControlSupportedConfiguration
+	TargetControlList                   *characteristic.TargetControlList
+}
+
+func NewTargetControlManagement() *TargetControlManagement {
+	svc := TargetControlManagement{}
+	svc.Service = New(TypeTargetControlManagement)
+
+	svc.TargetControlSupportedConfiguration = characteristic.NewTargetControlSupportedConfiguration()
+	svc.AddCharacteristic(svc.TargetControlSupportedConfiguration.Characteristic)
+
+	svc.TargetControlList = characteristic.NewTargetControlList()
+	svc.AddCharacteristic(svc.TargetControlList.Characteristic)
+
+	return &svc
+}
@@ -0,0 +1,36 @@
+// THIS FILE IS AUTO-GENERATED
+package service
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+const TypeThreadTransport = "701"
+
+type ThreadTransport struct {
+	*Service
+
+	CurrentTransport       *characteristic.CurrentTransport
+	ThreadControlPoint     *characteristic.ThreadControlPoint
+	ThreadNodeCapabilities *characteristic.ThreadNodeCapabilities
+	ThreadStatus           *characteristic.ThreadStatus
+}
+
+func NewThreadTransport() *ThreadTransport {
+	svc := ThreadTransport{}
+	svc.Service = New(TypeThreadTransport)
+
+	svc.CurrentTransport = characteristic.NewCurrentTransport()
+	svc.AddCharacteristic(svc.CurrentTransport.Characteristic)
+
+	svc.ThreadControlPoint = characteristic.NewThreadControlPoint()
+	svc.AddCharacteristic(svc.ThreadControlPoint.Characteristic)
+
+	svc.ThreadNodeCapabilities = characteristic.NewThreadNodeCapabilities()
+	svc.AddCharacteristic(svc.ThreadNodeCapabilities.Characteristic)
+
+	svc.ThreadStatus = characteristic.NewThreadStatus()
+	svc.AddCharacteristic(svc.ThreadStatus.Characteristic)
+
+	return &svc
+}
@@ -0,0 +1,28 @@
+// THIS FILE IS AUTO-GENERATED
+package service
+
+import (
+	"github.com/brutella/hc/characteristic"
+)
+
+const TypeWiFiTransport = "22A"
+
+type WiFiTransport struct {
+	*Service
+
+	CurrentTransport *characteristic.CurrentTransport
+	WiFiCapabilities *characteristic.WiFiCapabilities
+}
+
+func NewWiFiTransport() *WiFiTransport {
+	svc := WiFiTransport{}
+	svc.Service = New(TypeWiFiTransport)
+
+	svc.CurrentTransport = characteristic.NewCurrentTransport()
+	svc.AddCharacteristic(svc.CurrentTransport.Characteristic)
+
+	svc.WiFiCapabilities = characteristic.NewWiFiCapabilities()
+	svc.AddCharacteristic(svc.WiFiCapabilities.Characteristic)
+
+	return &svc
+}
@@ -0,0 +1,84 @@
+package systemd
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// listenFDsStart is the first file descriptor passed by systemd (SD_LISTEN_FDS_START)
+const listenFDsStart = 3
+
+// Files returns the files of the sockets which are passed by systemd.
+// The files are only returned once because the environment variables are unset.
+// The caller must close the files.
+// When the process was not started by socket activation, no files are returned.
+func Files() []*os.File {
+	defer unsetListenEnv()
+
+	n := listenFDs(os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS"), os.Getpid())
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+
+	var files []*os.File
+	for fd := listenFDsStart; fd < listenFDsStart+n; fd++ {
+		name := "LISTEN_FD_" + strconv.Itoa(fd)
+		if i := fd - listenFDsStart; i < len(names) && len(names[i]) > 0 {
+			name = names[i]
+		}
+		files = append(files, os.NewFile(uintptr(fd), name))
+	}
+
+	return files
+}
+
+// Listeners returns the stream sockets (e.g. TCP) which are passed by systemd.
+// Other sockets are closed.
+func Listeners() []net.Listener {
+	var lns []net.Listener
+	for _, f := range Files() {
+		if ln, err := net.FileListener(f); err == nil {
+			lns = append(lns, ln)
+		}
+		f.Close()
+	}
+
+	return lns
+}
+
+// PacketConns returns the datagram sockets (e.g. UDP) which are passed by systemd.
+// Other sockets are closed.
+func PacketConns() []net.PacketConn {
+	var conns []net.PacketConn
+	for _, f := range Files() {
+		if c, err := net.FilePacketConn(f); err == nil {
+			conns = append(conns, c)
+		}
+		f.Close()
+	}
+
+	return conns
+}
+
+// listenFDs returns the number of passed file descriptors.
+// The descriptors are only meant for the process when pid equals the process id.
+func listenFDs(pid, fds string, getpid int) int {
+	if p, err := strconv.Atoi(pid); err != nil || p != getpid {
+		return 0
+	}
+
+	n, err := strconv.Atoi(fds)
+	if err != nil || n < 0 {
+		return 0
+	}
+
+	return n
+}
+
+// unsetListenEnv unsets the environment variables of socket activation,
+// so that child processes don't use the sockets.
+func unsetListenEnv() {
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+}
@@ -0,0 +1,7 @@
+// Package systemd implements socket activation and readiness notification
+// of systemd services.
+//
+// A service which is started by a socket unit receives its listening sockets
+// from systemd (see Listeners and PacketConns). A service of Type=notify reports
+// its readiness and watchdog keep-alives with Notify.
+package systemd
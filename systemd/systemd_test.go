@@ -0,0 +1,105 @@
+package systemd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/brutella/hc/util"
+)
+
+func TestListenFDs(t *testing.T) {
+	var tests = []struct {
+		pid  string
+		fds  string
+		want int
+	}{
+		{"100", "2", 2},
+		{"101", "2", 0},
+		{"", "2", 0},
+		{"100", "", 0},
+		{"100", "-1", 0},
+	}
+
+	for _, test := range tests {
+		if is, want := listenFDs(test.pid, test.fds, 100), test.want; is != want {
+			t.Fatalf("%v is=%v want=%v", test, is, want)
+		}
+	}
+}
+
+func TestNoFiles(t *testing.T) {
+	os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()+1))
+	os.Setenv("LISTEN_FDS", "1")
+
+	if is, want := len(Files()), 0; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := os.Getenv("LISTEN_FDS"), ""; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	dir := filepath.Join(os.TempDir(), util.RandomHexString())
+	os.MkdirAll(dir, 0777)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	os.Setenv("NOTIFY_SOCKET", path)
+	defer os.Unsetenv("NOTIFY_SOCKET")
+
+	sent, err := Notify(StateReady)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := sent, true; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	buf := make([]byte, 64)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := string(buf[:n]), StateReady; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestNotifyWithoutSocket(t *testing.T) {
+	os.Unsetenv("NOTIFY_SOCKET")
+
+	if sent, err := Notify(StateReady); sent == true || err != nil {
+		t.Fatal(sent, err)
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	os.Setenv("WATCHDOG_USEC", "3000000")
+	defer os.Unsetenv("WATCHDOG_USEC")
+
+	if is, want := WatchdogInterval(), 3*time.Second; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	os.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1))
+	defer os.Unsetenv("WATCHDOG_PID")
+
+	if is, want := WatchdogInterval(), time.Duration(0); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
@@ -0,0 +1,34 @@
+package util
+
+import (
+	"time"
+)
+
+// Clock provides the current time and timers.
+// Tests use a fake clock to control the time (see haptest.Clock).
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+
+	// AfterFunc calls f after the duration d
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a timer of a clock.
+type Timer interface {
+	// Stop stops the timer and returns false when the timer already expired or was stopped
+	Stop() bool
+}
+
+type systemClock struct{}
+
+// SystemClock is the clock of the system.
+var SystemClock Clock = systemClock{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
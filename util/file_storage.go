@@ -2,6 +2,10 @@ package util
 
 import (
 	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
 	"io/ioutil"
 	"os"
 	"path"
@@ -9,8 +13,15 @@ import (
 	"strings"
 )
 
+// ErrCorrupted is returned when the file of a key is corrupted and cannot be recovered from its shadow copy.
+var ErrCorrupted = errors.New("Corrupted storage file")
+
+// ErrReadOnly is returned when writing to a read-only storage.
+var ErrReadOnly = errors.New("Storage is read-only")
+
 type fileStorage struct {
-	dirPath string
+	dirPath  string
+	readOnly bool
 }
 
 // NewTempFileStorage returns a new storage inside temporary folder.
@@ -21,6 +32,10 @@ func NewTempFileStorage() (Storage, error) {
 
 // NewFileStorage create a file storage for the specified directory.
 // The folder is created if necessary. Every key-value pair is stored in a seperate file.
+//
+// Files are written atomically by writing to a temporary file, which is synced and renamed.
+// Every file has a hidden shadow copy which includes a checksum of the value.
+// A corrupted file is restored from its shadow copy when it is read.
 func NewFileStorage(dir string) (Storage, error) {
 	path, err := filepath.Abs(dir)
 	if err != nil {
@@ -33,18 +48,39 @@ func NewFileStorage(dir string) (Storage, error) {
 	return &fileStorage{dirPath: path}, err
 }
 
+// NewReadOnlyFileStorage returns a file storage for the specified directory, which is never written to.
+// The directory must exist and may be on a read-only file system.
+func NewReadOnlyFileStorage(dir string) (Storage, error) {
+	path, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() == false {
+		return nil, fmt.Errorf("%s is not a directory", path)
+	}
+
+	return &fileStorage{dirPath: path, readOnly: true}, nil
+}
+
 // Set sets the value for a specific key.
 func (f *fileStorage) Set(key string, value []byte) error {
-	file, err := f.fileForWrite(key)
+	if f.readOnly == true {
+		return ErrReadOnly
+	}
 
-	if err != nil {
+	// The shadow copy is written first. If the write of the file is interrupted,
+	// the new value is restored from the shadow copy.
+	if err := f.writeFile(f.filePathToShadow(key), shadow(value)); err != nil {
 		return err
 	}
 
-	defer file.Close()
-
-	_, err = file.Write(value)
-	return err
+	return f.writeFile(f.filePathToFile(key), value)
 }
 
 // Get returns the value for a specific key.
@@ -68,11 +104,19 @@ func (f *fileStorage) Get(key string) ([]byte, error) {
 		}
 	}
 
-	return b.Bytes(), nil
+	return f.verify(key, b.Bytes())
 }
 
 // Delete removes the file for the corresponding key.
 func (f *fileStorage) Delete(key string) error {
+	if f.readOnly == true {
+		return ErrReadOnly
+	}
+
+	if err := os.Remove(f.filePathToShadow(key)); err != nil && os.IsNotExist(err) == false {
+		return err
+	}
+
 	return os.Remove(f.filePathToFile(key))
 }
 
@@ -81,6 +125,11 @@ func (f *fileStorage) KeysWithSuffix(suffix string) (keys []string, err error) {
 
 	if infos, err = ioutil.ReadDir(f.dir()); err == nil {
 		for _, info := range infos {
+			// Hidden files are shadow copies, temporary files or locks
+			if strings.HasPrefix(info.Name(), ".") == true {
+				continue
+			}
+
 			if info.IsDir() == false && strings.HasSuffix(info.Name(), suffix) == true {
 				keys = append(keys, info.Name())
 			}
@@ -100,14 +149,94 @@ func (f *fileStorage) filePathToFile(file string) string {
 	return filepath.Join(f.dir(), fname)
 }
 
-func (f *fileStorage) fileForWrite(key string) (*os.File, error) {
-	return os.OpenFile(f.filePathToFile(key), os.O_WRONLY|os.O_CREATE, 0666)
+func (f *fileStorage) filePathToShadow(file string) string {
+	fname := removeInvalidFileNameCharacters(file)
+	return filepath.Join(f.dir(), "."+fname+".shadow")
+}
+
+// verify checks the value of a key against the checksum in the shadow copy.
+// If they don't match, the value is restored from the shadow copy.
+func (f *fileStorage) verify(key string, value []byte) ([]byte, error) {
+	b, err := ioutil.ReadFile(f.filePathToShadow(key))
+	if err != nil {
+		// Files written by previous versions don't have a shadow copy
+		return value, nil
+	}
+
+	if len(b) < 4 {
+		return value, nil
+	}
+
+	sum := binary.BigEndian.Uint32(b[:4])
+	shadowValue := b[4:]
+	if crc32.ChecksumIEEE(value) == sum {
+		return value, nil
+	}
+
+	if crc32.ChecksumIEEE(shadowValue) != sum {
+		return nil, ErrCorrupted
+	}
+
+	if f.readOnly == true {
+		return shadowValue, nil
+	}
+
+	if err := f.writeFile(f.filePathToFile(key), shadowValue); err != nil {
+		return nil, err
+	}
+
+	return shadowValue, nil
+}
+
+// writeFile atomically writes data to a file at path.
+// The data is written to a temporary file, which is synced to disk and renamed.
+func (f *fileStorage) writeFile(path string, data []byte) error {
+	tmp := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	file, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+
+	if _, err = file.Write(data); err == nil {
+		err = file.Sync()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	// Sync the directory so that the rename is persisted.
+	// This is not supported on every platform, which is why errors are ignored.
+	if dir, err := os.Open(f.dir()); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+
+	return nil
 }
 
 func (f *fileStorage) fileForRead(key string) (*os.File, error) {
 	return os.OpenFile(f.filePathToFile(key), os.O_RDONLY, 0666)
 }
 
+// shadow returns the shadow copy of value, which is prefixed with its checksum.
+func shadow(value []byte) []byte {
+	b := make([]byte, 4+len(value))
+	binary.BigEndian.PutUint32(b, crc32.ChecksumIEEE(value))
+	copy(b[4:], value)
+	return b
+}
+
 // Returns a string where invalid characters (e.g. colon ":" which is not allowed in file names on Window) are removed from fname
 func removeInvalidFileNameCharacters(fname string) string {
 	return strings.Replace(fname, ":", "", -1)
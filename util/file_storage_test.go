@@ -1,6 +1,7 @@
 package util
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -103,3 +104,85 @@ func TestKeysWithSuffix(t *testing.T) {
 		t.Fatalf("is=%v want=%v", is, want)
 	}
 }
+
+func TestOverwriteWithShorterValue(t *testing.T) {
+	storage, err := NewTempFileStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storage.Set("test", []byte("ASDFASDF"))
+	storage.Set("test", []byte("QWER"))
+
+	read, err := storage.Get("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := read, []byte("QWER"); reflect.DeepEqual(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestRecoverCorruptedFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	storage, err := NewFileStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := storage.Set("keypair", []byte("ASDF")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a truncated file
+	if err := ioutil.WriteFile(filepath.Join(dir, "keypair"), []byte("AS"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := storage.Get("keypair")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := read, []byte("ASDF"); reflect.DeepEqual(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	// The file is restored
+	b, _ := ioutil.ReadFile(filepath.Join(dir, "keypair"))
+	if is, want := b, []byte("ASDF"); reflect.DeepEqual(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	keys, _ := storage.KeysWithSuffix("")
+	if is, want := keys, []string{"keypair"}; reflect.DeepEqual(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestCorruptedFileAndShadow(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	storage, err := NewFileStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storage.Set("keypair", []byte("ASDF"))
+	ioutil.WriteFile(filepath.Join(dir, "keypair"), []byte("AS"), 0666)
+	ioutil.WriteFile(filepath.Join(dir, ".keypair.shadow"), []byte("0000QW"), 0666)
+
+	if _, err := storage.Get("keypair"); err != ErrCorrupted {
+		t.Fatalf("is=%v want=%v", err, ErrCorrupted)
+	}
+}
@@ -0,0 +1,74 @@
+package util
+
+import (
+	"os"
+	"sort"
+)
+
+type layeredStorage struct {
+	provisioning Storage
+	state        Storage
+}
+
+// NewLayeredStorage returns a storage which writes to state and reads from
+// state and provisioning. Values in state override values in provisioning.
+//
+// This allows to keep immutable provisioning data (e.g. the identity and
+// setup verifier of the accessory) on a read-only file system, while
+// pairings and other state are stored in a small writable storage.
+func NewLayeredStorage(provisioning, state Storage) Storage {
+	return &layeredStorage{provisioning: provisioning, state: state}
+}
+
+// Set sets the value for a specific key in the state storage.
+func (s *layeredStorage) Set(key string, value []byte) error {
+	return s.state.Set(key, value)
+}
+
+// Get returns the value for a specific key from the state storage, or from
+// the provisioning storage if the key is not found in the state storage.
+func (s *layeredStorage) Get(key string) ([]byte, error) {
+	if b, err := s.state.Get(key); err == nil {
+		return b, nil
+	}
+
+	return s.provisioning.Get(key)
+}
+
+// Delete removes the value for a specific key from the state storage.
+// Values in the provisioning storage cannot be deleted.
+func (s *layeredStorage) Delete(key string) error {
+	err := s.state.Delete(key)
+	if os.IsNotExist(err) == true {
+		if _, provErr := s.provisioning.Get(key); provErr == nil {
+			return ErrReadOnly
+		}
+	}
+
+	return err
+}
+
+// KeysWithSuffix returns the keys of both storages.
+func (s *layeredStorage) KeysWithSuffix(suffix string) ([]string, error) {
+	state, err := s.state.KeysWithSuffix(suffix)
+	if err != nil {
+		return nil, err
+	}
+
+	provisioning, err := s.provisioning.KeysWithSuffix(suffix)
+	if err != nil {
+		return nil, err
+	}
+
+	set := map[string]bool{}
+	var keys []string
+	for _, k := range append(state, provisioning...) {
+		if set[k] == false {
+			set[k] = true
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
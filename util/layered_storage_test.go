@@ -0,0 +1,57 @@
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestLayeredStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "provisioning")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rw, _ := NewFileStorage(dir)
+	rw.Set("uuid", []byte("AA:BB"))
+	rw.Set("a.entity", []byte("A"))
+
+	provisioning, err := NewReadOnlyFileStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is, want := provisioning.Set("uuid", []byte("CC:DD")), ErrReadOnly; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	state, _ := NewTempFileStorage()
+	storage := NewLayeredStorage(provisioning, state)
+	storage.Set("b.entity", []byte("B"))
+
+	b, err := storage.Get("uuid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if is, want := b, []byte("AA:BB"); reflect.DeepEqual(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	keys, err := storage.KeysWithSuffix(".entity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if is, want := keys, []string{"a.entity", "b.entity"}; reflect.DeepEqual(is, want) == false {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if is, want := storage.Delete("a.entity"), ErrReadOnly; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if err := storage.Delete("b.entity"); err != nil {
+		t.Fatal(err)
+	}
+}
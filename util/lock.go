@@ -0,0 +1,83 @@
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// lockFileName is the name of the lock file inside a locked directory
+const lockFileName = ".lock"
+
+// DirLock is an advisory lock of a directory, which prevents that
+// more than one process uses the same storage directory.
+type DirLock struct {
+	file *os.File
+	path string
+}
+
+// LockedError is returned when a directory is already locked by another process.
+type LockedError struct {
+	Dir string
+	PID int
+}
+
+func (e *LockedError) Error() string {
+	if e.PID > 0 {
+		return fmt.Sprintf("Storage %s is used by another process (pid %d)", e.Dir, e.PID)
+	}
+
+	return fmt.Sprintf("Storage %s is used by another process", e.Dir)
+}
+
+// LockDir locks the directory dir. If the directory is already locked, a *LockedError is returned.
+// The lock is released when Unlock is called or the process exits.
+func LockDir(dir string) (*DirLock, error) {
+	path := filepath.Join(dir, lockFileName)
+	f, err := lockFile(path)
+	if err == errLocked {
+		return nil, &LockedError{Dir: dir, PID: lockPID(path)}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Store the pid of the process, which holds the lock, for error messages
+	f.Truncate(0)
+	f.WriteString(strconv.Itoa(os.Getpid()))
+	f.Sync()
+
+	return &DirLock{file: f, path: path}, nil
+}
+
+// Unlock releases the lock.
+func (l *DirLock) Unlock() error {
+	return unlockFile(l.file, l.path)
+}
+
+// ForceUnlock removes the lock of the directory dir, e.g. when a stale lock remains after a crash.
+// The lock must only be removed if no other process uses the directory.
+func ForceUnlock(dir string) error {
+	err := os.Remove(filepath.Join(dir, lockFileName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+// lockPID returns the pid which is stored in the lock file at path, or 0.
+func lockPID(path string) int {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(b)))
+
+	return pid
+}
@@ -0,0 +1,63 @@
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestLockDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	lock, err := LockDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = LockDir(dir)
+	locked, ok := err.(*LockedError)
+	if ok == false {
+		t.Fatal(err)
+	}
+	if is, want := locked.PID, os.Getpid(); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	if err := lock.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err = LockDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock.Unlock()
+}
+
+func TestForceUnlock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	lock, err := LockDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lock.Unlock()
+
+	if err := ForceUnlock(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := LockDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Unlock()
+}
@@ -0,0 +1,31 @@
+//go:build windows
+// +build windows
+
+package util
+
+import (
+	"errors"
+	"os"
+)
+
+var errLocked = errors.New("locked")
+
+// lockFile exclusively creates the file at path. The file remains when the process
+// crashes, which requires to remove the lock with ForceUnlock.
+func lockFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+	if os.IsExist(err) {
+		return nil, errLocked
+	}
+
+	return f, err
+}
+
+// unlockFile closes and removes the lock file.
+func unlockFile(f *os.File, path string) error {
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
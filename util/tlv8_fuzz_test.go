@@ -0,0 +1,30 @@
+//go:build go1.18
+// +build go1.18
+
+package util
+
+import (
+	"bytes"
+	"testing"
+)
+
+// FuzzTLV8 checks that malformed tlv8 data is rejected without panic and
+// that decoded containers are encoded again.
+func FuzzTLV8(f *testing.F) {
+	c := NewTLV8Container()
+	c.SetByte(0x06, 0x01)
+	c.SetBytes(0x03, bytes.Repeat([]byte{0xAA}, 300))
+	f.Add(c.BytesBuffer().Bytes())
+	f.Add([]byte{0x06, 0x05, 0x01})
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		c, err := NewTLV8ContainerFromReader(bytes.NewReader(b))
+		if err != nil {
+			return
+		}
+
+		if _, err := NewTLV8ContainerFromReader(c.BytesBuffer()); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
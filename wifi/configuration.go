@@ -0,0 +1,146 @@
+package wifi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/brutella/hc/util"
+)
+
+// Security modes of a station configuration
+const (
+	SecurityModeNone    byte = 0x00
+	SecurityModeWPA2PSK byte = 0x01
+)
+
+// Operation types of the Wi-Fi Configuration Control characteristic
+const (
+	operationRead   byte = 0x01
+	operationUpdate byte = 0x02
+)
+
+// Update status of the Wi-Fi Configuration Control characteristic
+const (
+	// StatusSuccess is the status when the accessory joined the network.
+	StatusSuccess byte = 0x00
+
+	// StatusPending is the status while the accessory joins the network.
+	StatusPending byte = 0x01
+
+	// StatusFailed is the status when the accessory could not join the network.
+	StatusFailed byte = 0x02
+)
+
+// TLV8 tags of the Wi-Fi Configuration Control characteristic
+const (
+	tagOperationType    = 0x01
+	tagCookie           = 0x02
+	tagUpdateStatus     = 0x03
+	tagOperationTimeout = 0x04
+	tagCountryCode      = 0x05
+	tagStation          = 0x06
+
+	tagStationSSID         = 0x01
+	tagStationSecurityMode = 0x02
+	tagStationPSK          = 0x03
+)
+
+var errInvalidOperation = errors.New("Invalid operation")
+var errMissingSSID = errors.New("Missing SSID")
+var errMissingPSK = errors.New("Missing PSK")
+
+// Configuration is the Wi-Fi station configuration of the accessory.
+type Configuration struct {
+	SSID         string
+	SecurityMode byte
+	PSK          string
+
+	// CountryCode is the ISO 3166-1 country code of the regulatory domain, e.g. "US".
+	CountryCode string
+
+	// Timeout is the duration in which the accessory must join the network.
+	// When the accessory can't join the network in time, it should restore the previous configuration.
+	Timeout time.Duration
+}
+
+// request is a write to the Wi-Fi Configuration Control characteristic.
+type request struct {
+	operation byte
+	cookie    uint16
+	config    Configuration
+}
+
+func parseRequest(b []byte) (*request, error) {
+	tlv, err := util.NewTLV8ContainerFromReader(bytes.NewBuffer(b))
+	if err != nil {
+		return nil, err
+	}
+
+	req := request{
+		operation: tlv.GetByte(tagOperationType),
+	}
+
+	if cookie := tlv.GetBytes(tagCookie); len(cookie) == 2 {
+		req.cookie = binary.LittleEndian.Uint16(cookie)
+	}
+
+	switch req.operation {
+	case operationRead:
+		return &req, nil
+	case operationUpdate:
+	default:
+		return nil, errInvalidOperation
+	}
+
+	station, err := util.NewTLV8ContainerFromReader(bytes.NewBuffer(tlv.GetBytes(tagStation)))
+	if err != nil {
+		return nil, err
+	}
+
+	req.config = Configuration{
+		SSID:         station.GetString(tagStationSSID),
+		SecurityMode: station.GetByte(tagStationSecurityMode),
+		PSK:          station.GetString(tagStationPSK),
+		CountryCode:  tlv.GetString(tagCountryCode),
+	}
+
+	if timeout := tlv.GetBytes(tagOperationTimeout); len(timeout) == 4 {
+		req.config.Timeout = time.Duration(binary.LittleEndian.Uint32(timeout)) * time.Millisecond
+	}
+
+	if len(req.config.SSID) == 0 {
+		return nil, errMissingSSID
+	}
+
+	if req.config.SecurityMode == SecurityModeWPA2PSK && len(req.config.PSK) == 0 {
+		return nil, errMissingPSK
+	}
+
+	return &req, nil
+}
+
+// response returns the value of the Wi-Fi Configuration Control characteristic.
+// The PSK of the configuration is never returned.
+func response(cookie uint16, status byte, config Configuration) []byte {
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, cookie)
+
+	tlv := util.NewTLV8Container()
+	tlv.SetBytes(tagCookie, b)
+	tlv.SetByte(tagUpdateStatus, status)
+
+	if len(config.CountryCode) > 0 {
+		tlv.SetString(tagCountryCode, config.CountryCode)
+	}
+
+	if len(config.SSID) > 0 {
+		station := util.NewTLV8Container()
+		station.SetString(tagStationSSID, config.SSID)
+		station.SetByte(tagStationSecurityMode, config.SecurityMode)
+		tlv.SetBytes(tagStation, station.BytesBuffer().Bytes())
+	}
+
+	return tlv.BytesBuffer().Bytes()
+}
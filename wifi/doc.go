@@ -0,0 +1,7 @@
+// Package wifi implements the Wi-Fi configuration of headless accessories.
+//
+// A Manager provides the Wi-Fi Transport service with the Wi-Fi Configuration Control
+// characteristic. When a controller writes new credentials during setup, the station
+// configuration is passed to a Provisioner, which joins the network, e.g. by
+// configuring wpa_supplicant.
+package wifi
@@ -0,0 +1,134 @@
+package wifi
+
+import (
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/netio"
+	"github.com/brutella/hc/service"
+	"github.com/brutella/log"
+
+	"encoding/base64"
+	"net"
+	"sync"
+)
+
+// Wi-Fi capabilities of the accessory
+const (
+	Capability2_4GHz      = 0x01
+	Capability5GHz        = 0x02
+	CapabilityWakeOnWLAN  = 0x04
+	CapabilityStationMode = 0x08
+)
+
+// Provisioner joins a Wi-Fi network, e.g. by configuring wpa_supplicant.
+type Provisioner interface {
+	// Configure joins the network of config. The accessory should restore the previous
+	// configuration, if it can't join the network within config.Timeout.
+	Configure(config Configuration) error
+
+	// Configuration returns the current station configuration.
+	Configuration() (Configuration, error)
+}
+
+// Manager configures the Wi-Fi of the accessory via the Wi-Fi Transport service.
+type Manager struct {
+	Service              *service.WiFiTransport
+	ConfigurationControl *characteristic.WiFiConfigurationControl
+
+	provisioner Provisioner
+	mutex       sync.Mutex
+	status      byte
+	cookie      uint16
+}
+
+// NewManager returns a manager which passes new station configurations to p.
+// The capabilities are a combination of Capability2_4GHz, Capability5GHz, ...
+func NewManager(p Provisioner, capabilities int) *Manager {
+	m := Manager{
+		Service:              service.NewWiFiTransport(),
+		ConfigurationControl: characteristic.NewWiFiConfigurationControl(),
+		provisioner:          p,
+		status:               StatusSuccess,
+	}
+
+	m.Service.AddCharacteristic(m.ConfigurationControl.Characteristic)
+	m.Service.CurrentTransport.SetValue(true)
+	m.Service.WiFiCapabilities.SetValue(capabilities)
+
+	m.ConfigurationControl.OnBeforeRemoteUpdate(func(value interface{}) error {
+		if _, err := parseRequest(decode(value)); err != nil {
+			log.Println("[WARN] Invalid Wi-Fi configuration", err)
+			return characteristic.NewStatusError(netio.StatusInvalidValueInRequest, err.Error())
+		}
+
+		return nil
+	})
+
+	m.ConfigurationControl.OnValueUpdateFromConn(func(conn net.Conn, c *characteristic.Characteristic, newValue, oldValue interface{}) {
+		req, err := parseRequest(decode(newValue))
+		if err != nil {
+			return
+		}
+
+		m.handle(req)
+	})
+
+	return &m
+}
+
+// Status returns the status of the last update.
+func (m *Manager) Status() byte {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.status
+}
+
+func (m *Manager) handle(req *request) {
+	switch req.operation {
+	case operationRead:
+		m.mutex.Lock()
+		status, cookie := m.status, m.cookie
+		m.mutex.Unlock()
+
+		config, err := m.provisioner.Configuration()
+		if err != nil {
+			log.Println("[ERRO] Could not read Wi-Fi configuration", err)
+		}
+
+		m.ConfigurationControl.SetValue(response(cookie, status, config))
+	case operationUpdate:
+		m.mutex.Lock()
+		m.status = StatusPending
+		m.cookie = req.cookie
+		m.mutex.Unlock()
+
+		m.ConfigurationControl.SetValue(response(req.cookie, StatusPending, Configuration{}))
+
+		// Joining a network closes the connection to the controller
+		go m.configure(req.cookie, req.config)
+	}
+}
+
+func (m *Manager) configure(cookie uint16, config Configuration) {
+	log.Println("[INFO] Joining Wi-Fi network", config.SSID)
+
+	status := StatusSuccess
+	if err := m.provisioner.Configure(config); err != nil {
+		log.Println("[ERRO] Could not join Wi-Fi network", err)
+		status = StatusFailed
+	}
+
+	m.mutex.Lock()
+	if m.cookie == cookie {
+		m.status = status
+	}
+	m.mutex.Unlock()
+}
+
+// decode returns the bytes of a base64 encoded tlv8 value.
+func decode(value interface{}) []byte {
+	str, _ := value.(string)
+	b, _ := base64.StdEncoding.DecodeString(str)
+
+	return b
+}
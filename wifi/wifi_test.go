@@ -0,0 +1,94 @@
+package wifi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/brutella/hc/characteristic"
+	"github.com/brutella/hc/util"
+)
+
+type testProvisioner struct {
+	configs chan Configuration
+	err     error
+}
+
+func (p *testProvisioner) Configure(config Configuration) error {
+	p.configs <- config
+	return p.err
+}
+
+func (p *testProvisioner) Configuration() (Configuration, error) {
+	return Configuration{SSID: "Home", SecurityMode: SecurityModeWPA2PSK, PSK: "secret"}, nil
+}
+
+func updateRequest(cookie uint16, ssid, psk string) string {
+	station := util.NewTLV8Container()
+	station.SetString(tagStationSSID, ssid)
+	station.SetByte(tagStationSecurityMode, SecurityModeWPA2PSK)
+	if len(psk) > 0 {
+		station.SetString(tagStationPSK, psk)
+	}
+
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, cookie)
+
+	tlv := util.NewTLV8Container()
+	tlv.SetByte(tagOperationType, operationUpdate)
+	tlv.SetBytes(tagCookie, b)
+	tlv.SetString(tagCountryCode, "US")
+	tlv.SetBytes(tagStation, station.BytesBuffer().Bytes())
+
+	return base64.StdEncoding.EncodeToString(tlv.BytesBuffer().Bytes())
+}
+
+func TestUpdateConfiguration(t *testing.T) {
+	p := &testProvisioner{configs: make(chan Configuration, 1)}
+	m := NewManager(p, Capability2_4GHz|CapabilityStationMode)
+
+	conn, _ := net.Pipe()
+	if err := m.ConfigurationControl.UpdateValueFromConnection(updateRequest(7, "Home", "secret"), conn); err != nil {
+		t.Fatal(err)
+	}
+
+	config := <-p.configs
+	if is, want := config.SSID, "Home"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := config.PSK, "secret"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+	if is, want := config.CountryCode, "US"; is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+
+	tlv, err := util.NewTLV8ContainerFromReader(bytes.NewBuffer(m.ConfigurationControl.GetValue()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if is, want := binary.LittleEndian.Uint16(tlv.GetBytes(tagCookie)), uint16(7); is != want {
+		t.Fatalf("is=%v want=%v", is, want)
+	}
+}
+
+func TestRejectInvalidConfiguration(t *testing.T) {
+	p := &testProvisioner{configs: make(chan Configuration, 1), err: errors.New("failed")}
+	m := NewManager(p, Capability2_4GHz)
+
+	conn, _ := net.Pipe()
+	err := m.ConfigurationControl.UpdateValueFromConnection(updateRequest(1, "Home", ""), conn)
+	if _, ok := err.(*characteristic.StatusError); ok == false {
+		t.Fatal(err)
+	}
+}
+
+func TestResponseWithoutPSK(t *testing.T) {
+	b := response(1, StatusSuccess, Configuration{SSID: "Home", SecurityMode: SecurityModeWPA2PSK, PSK: "secret"})
+	if bytes.Contains(b, []byte("secret")) == true {
+		t.Fatal("response contains PSK")
+	}
+}